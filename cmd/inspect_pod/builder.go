@@ -0,0 +1,119 @@
+package inspect_pod
+
+import (
+	"io"
+	"os"
+
+	"github.com/Dynatrace/dynatrace-operator/cmd/config"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	use                    = "inspect-pod [pod name]"
+	filenameFlagName       = "filename"
+	filenameFlagShorthand  = "f"
+	namespaceFlagName      = "namespace"
+	namespaceFlagShorthand = "n"
+	outputFlagName         = "output"
+	outputFlagShorthand    = "o"
+	stdinFilename          = "-"
+	defaultPodNamespace    = "default"
+)
+
+var (
+	filenameFlagValue  string
+	namespaceFlagValue string
+	outputFlagValue    string
+)
+
+type CommandBuilder struct {
+	configProvider config.Provider
+}
+
+func NewCommandBuilder() CommandBuilder {
+	return CommandBuilder{}
+}
+
+func (builder CommandBuilder) SetConfigProvider(provider config.Provider) CommandBuilder {
+	builder.configProvider = provider
+	return builder
+}
+
+func (builder CommandBuilder) Build() *cobra.Command {
+	cmd := &cobra.Command{
+		Use: use,
+		Long: "Show what the webhook would inject into a pod and why, without creating it. " +
+			"The pod is either read from a file or referenced by name, in which case the pod template of its controller is inspected. " +
+			"The pod is created with a server-side dry-run, so the permission to create it is needed.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: builder.buildRun(),
+	}
+
+	addFlags(cmd)
+
+	return cmd
+}
+
+func addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&filenameFlagValue, filenameFlagName, filenameFlagShorthand, "", "File containing the pod to inspect, - for stdin.")
+	cmd.PersistentFlags().StringVarP(&namespaceFlagValue, namespaceFlagName, namespaceFlagShorthand, defaultPodNamespace, "Namespace of the pod, used for the pod of the file if it doesn't set one.")
+	cmd.PersistentFlags().StringVarP(&outputFlagValue, outputFlagName, outputFlagShorthand, outputText, "Output format, one of: text, json.")
+}
+
+func (builder CommandBuilder) buildRun() func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		printer, err := newPrinter(outputFlagValue, cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+		if (len(args) == 0) == (filenameFlagValue == "") {
+			return errors.Errorf("either a pod name or --%s must be given", filenameFlagName)
+		}
+
+		kubeConfig, err := builder.configProvider.GetConfig()
+		if err != nil {
+			return err
+		}
+
+		var pod *corev1.Pod
+		if filenameFlagValue != "" {
+			pod, err = readPod(cmd.InOrStdin(), filenameFlagValue)
+		} else {
+			pod, err = getPod(cmd.Context(), kubeConfig, args[0], namespaceFlagValue)
+		}
+		if err != nil {
+			return err
+		}
+		if pod.Namespace == "" {
+			pod.Namespace = namespaceFlagValue
+		}
+
+		result, err := requestDryRun(cmd.Context(), kubeConfig, pod)
+		if err != nil {
+			return err
+		}
+		return printer.print(pod, result)
+	}
+}
+
+func readPod(stdin io.Reader, filename string) (*corev1.Pod, error) {
+	var rawPod []byte
+	var err error
+	if filename == stdinFilename {
+		rawPod, err = io.ReadAll(stdin)
+	} else {
+		rawPod, err = os.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var pod corev1.Pod
+	if err := yaml.UnmarshalStrict(rawPod, &pod); err != nil {
+		return nil, errors.WithMessage(err, "failed to decode pod")
+	}
+	return &pod, nil
+}
@@ -0,0 +1,57 @@
+package inspect_pod
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/cmd/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandBuilder(t *testing.T) {
+	t.Run("build command", func(t *testing.T) {
+		cmd := NewCommandBuilder().SetConfigProvider(&config.MockProvider{}).Build()
+
+		assert.NotNil(t, cmd)
+		assert.Equal(t, use, cmd.Use)
+		assert.NotNil(t, cmd.RunE)
+	})
+	t.Run("pod name or filename is required", func(t *testing.T) {
+		cmd := NewCommandBuilder().SetConfigProvider(&config.MockProvider{}).Build()
+		cmd.SetArgs([]string{})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), filenameFlagName)
+	})
+}
+
+func TestReadPod(t *testing.T) {
+	t.Run("read pod from stdin", func(t *testing.T) {
+		pod, err := readPod(bytes.NewBufferString("metadata:\n  name: app\n  namespace: shop\n"), stdinFilename)
+
+		require.NoError(t, err)
+		assert.Equal(t, "app", pod.Name)
+		assert.Equal(t, "shop", pod.Namespace)
+	})
+	t.Run("read pod from file", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "pod.json")
+		require.NoError(t, os.WriteFile(filename, []byte(`{"metadata": {"name": "app"}}`), 0600))
+
+		pod, err := readPod(nil, filename)
+
+		require.NoError(t, err)
+		assert.Equal(t, "app", pod.Name)
+	})
+	t.Run("invalid pod", func(t *testing.T) {
+		_, err := readPod(bytes.NewBufferString("spec: [oops"), stdinFilename)
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,152 @@
+package inspect_pod
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod_mutator"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const admissionWarningCode = 299
+
+// dryRunResult is the pod as the api-server would create it, with the decisions of the webhook
+type dryRunResult struct {
+	Decisions []pod_mutator.PreviewDecision `json:"decisions"`
+	Changes   []string                      `json:"changes"`
+	Pod       *corev1.Pod                   `json:"pod"`
+}
+
+// warningCollector keeps the warnings of the api-server responses, the webhook explains its decisions with them
+type warningCollector struct {
+	warnings []string
+}
+
+func (collector *warningCollector) HandleWarningHeader(code int, _ string, text string) {
+	// the api-server sends the warnings of admission webhooks with the code 299
+	if code == admissionWarningCode {
+		collector.warnings = append(collector.warnings, text)
+	}
+}
+
+// requestDryRun creates the pod with a server-side dry-run, which runs the admission webhooks without persisting the pod
+func requestDryRun(ctx context.Context, kubeConfig *rest.Config, pod *corev1.Pod) (*dryRunResult, error) {
+	collector := &warningCollector{}
+	dryRunConfig := rest.CopyConfig(kubeConfig)
+	dryRunConfig.WarningHandler = collector
+
+	clientset, err := kubernetes.NewForConfig(dryRunConfig)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	createdPod, err := clientset.CoreV1().Pods(pod.Namespace).Create(ctx, newDryRunPod(pod), metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create the pod with a dry-run")
+	}
+	return newDryRunResult(pod, createdPod, collector.warnings), nil
+}
+
+// newDryRunPod strips the state of an existing pod, its name is generated to avoid the conflict with it
+func newDryRunPod(pod *corev1.Pod) *corev1.Pod {
+	dryRunPod := pod.DeepCopy()
+	if dryRunPod.Name != "" {
+		dryRunPod.GenerateName = dryRunPod.Name + "-"
+		dryRunPod.Name = ""
+	}
+	dryRunPod.UID = ""
+	dryRunPod.ResourceVersion = ""
+	dryRunPod.CreationTimestamp = metav1.Time{}
+	dryRunPod.ManagedFields = nil
+	dryRunPod.Status = corev1.PodStatus{}
+	return dryRunPod
+}
+
+func newDryRunResult(pod *corev1.Pod, createdPod *corev1.Pod, warnings []string) *dryRunResult {
+	result := &dryRunResult{
+		Changes: diffPods(pod, createdPod),
+		Pod:     createdPod,
+	}
+	for _, warning := range warnings {
+		if decision, isPreview := pod_mutator.ParsePreviewWarning(warning); isPreview {
+			result.Decisions = append(result.Decisions, decision)
+		}
+	}
+	return result
+}
+
+// diffPods lists what was added to the pod on its admission, the defaults of the api-server for existing fields are left out.
+// Besides the changes of the webhook, it includes the ones of other admission plugins, like the volume of the service account token.
+func diffPods(pod *corev1.Pod, createdPod *corev1.Pod) []string {
+	var changes []string
+
+	for _, name := range addedKeys(pod.Annotations, createdPod.Annotations) {
+		changes = append(changes, fmt.Sprintf("annotation %s: %s", name, createdPod.Annotations[name]))
+	}
+	for _, name := range addedKeys(pod.Labels, createdPod.Labels) {
+		changes = append(changes, fmt.Sprintf("label %s: %s", name, createdPod.Labels[name]))
+	}
+	for _, volume := range createdPod.Spec.Volumes {
+		if !slices.ContainsFunc(pod.Spec.Volumes, func(existing corev1.Volume) bool { return existing.Name == volume.Name }) {
+			changes = append(changes, "volume "+volume.Name)
+		}
+	}
+	changes = append(changes, diffContainerLists("init container", pod.Spec.InitContainers, createdPod.Spec.InitContainers)...)
+	changes = append(changes, diffContainerLists("container", pod.Spec.Containers, createdPod.Spec.Containers)...)
+	return changes
+}
+
+func diffContainerLists(kind string, containers []corev1.Container, createdContainers []corev1.Container) []string {
+	var changes []string
+	for i := range createdContainers {
+		createdContainer := &createdContainers[i]
+		container := findContainer(containers, createdContainer.Name)
+		if container == nil {
+			changes = append(changes, kind+" "+createdContainer.Name)
+		} else {
+			changes = append(changes, diffContainers(kind, container, createdContainer)...)
+		}
+	}
+	return changes
+}
+
+func diffContainers(kind string, container *corev1.Container, createdContainer *corev1.Container) []string {
+	var changes []string
+	for _, env := range createdContainer.Env {
+		if !slices.ContainsFunc(container.Env, func(existing corev1.EnvVar) bool { return existing.Name == env.Name }) {
+			changes = append(changes, fmt.Sprintf("env %s of %s %s", env.Name, kind, container.Name))
+		}
+	}
+	for _, mount := range createdContainer.VolumeMounts {
+		if !slices.ContainsFunc(container.VolumeMounts, func(existing corev1.VolumeMount) bool { return existing.MountPath == mount.MountPath }) {
+			changes = append(changes, fmt.Sprintf("volume mount %s at %s of %s %s", mount.Name, mount.MountPath, kind, container.Name))
+		}
+	}
+	return changes
+}
+
+func findContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+func addedKeys(values map[string]string, createdValues map[string]string) []string {
+	var keys []string
+	for key := range createdValues {
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,100 @@
+package inspect_pod
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod_mutator"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWarningCollector(t *testing.T) {
+	t.Run("only admission warnings are collected", func(t *testing.T) {
+		collector := &warningCollector{}
+
+		collector.HandleWarningHeader(admissionWarningCode, "", "first")
+		collector.HandleWarningHeader(199, "", "other")
+		collector.HandleWarningHeader(admissionWarningCode, "", "second")
+
+		assert.Equal(t, []string{"first", "second"}, collector.warnings)
+	})
+}
+
+func TestNewDryRunPod(t *testing.T) {
+	t.Run("state of existing pod is stripped", func(t *testing.T) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "app",
+				Namespace:       "shop",
+				UID:             "uid",
+				ResourceVersion: "42",
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+
+		dryRunPod := newDryRunPod(pod)
+
+		assert.Empty(t, dryRunPod.Name)
+		assert.Equal(t, "app-", dryRunPod.GenerateName)
+		assert.Equal(t, "shop", dryRunPod.Namespace)
+		assert.Empty(t, dryRunPod.UID)
+		assert.Empty(t, dryRunPod.ResourceVersion)
+		assert.Empty(t, dryRunPod.Status)
+		assert.Equal(t, "app", pod.Name, "the pod itself is not changed")
+	})
+	t.Run("generated name is kept", func(t *testing.T) {
+		dryRunPod := newDryRunPod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{GenerateName: "app-"}})
+
+		assert.Equal(t, "app-", dryRunPod.GenerateName)
+	})
+}
+
+func TestNewDryRunResult(t *testing.T) {
+	t.Run("decisions are parsed from the warnings of the webhook", func(t *testing.T) {
+		decision := pod_mutator.PreviewDecision{Step: "outcome", Reason: "injected"}
+
+		result := newDryRunResult(&corev1.Pod{}, &corev1.Pod{}, []string{"spec.containers[0]: deprecated", decision.Warning()})
+
+		assert.Equal(t, []pod_mutator.PreviewDecision{decision}, result.Decisions)
+	})
+}
+
+func TestDiffPods(t *testing.T) {
+	t.Run("additions are listed", func(t *testing.T) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"existing": "true"}},
+			Spec: corev1.PodSpec{
+				Volumes:        []corev1.Volume{{Name: "data"}},
+				InitContainers: []corev1.Container{{Name: "init"}},
+				Containers: []corev1.Container{{
+					Name:         "app",
+					Env:          []corev1.EnvVar{{Name: "EXISTING"}},
+					VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+				}},
+			},
+		}
+		createdPod := pod.DeepCopy()
+		createdPod.Annotations["oneagent.dynatrace.com/injected"] = "true"
+		createdPod.Spec.Volumes = append(createdPod.Spec.Volumes, corev1.Volume{Name: "oneagent-bin"})
+		createdPod.Spec.InitContainers = append(createdPod.Spec.InitContainers, corev1.Container{Name: "install-oneagent"})
+		createdPod.Spec.Containers[0].Env = append(createdPod.Spec.Containers[0].Env, corev1.EnvVar{Name: "LD_PRELOAD"})
+		createdPod.Spec.Containers[0].VolumeMounts = append(createdPod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "oneagent-bin", MountPath: "/opt/dynatrace/oneagent-paas"})
+		createdPod.Spec.Containers[0].TerminationMessagePath = corev1.TerminationMessagePathDefault
+
+		changes := diffPods(pod, createdPod)
+
+		assert.Equal(t, []string{
+			"annotation oneagent.dynatrace.com/injected: true",
+			"volume oneagent-bin",
+			"init container install-oneagent",
+			"env LD_PRELOAD of container app",
+			"volume mount oneagent-bin at /opt/dynatrace/oneagent-paas of container app",
+		}, changes)
+	})
+	t.Run("unchanged pod has no changes", func(t *testing.T) {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}}
+
+		assert.Empty(t, diffPods(pod, pod.DeepCopy()))
+	})
+}
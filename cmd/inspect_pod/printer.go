@@ -0,0 +1,65 @@
+package inspect_pod
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	outputText = "text"
+	outputJson = "json"
+)
+
+type printer struct {
+	format string
+	out    io.Writer
+}
+
+func newPrinter(format string, out io.Writer) (printer, error) {
+	if format != outputText && format != outputJson {
+		return printer{}, errors.Errorf("unknown output format '%s', must be one of: %s, %s", format, outputText, outputJson)
+	}
+	return printer{format: format, out: out}, nil
+}
+
+func (printer printer) print(pod *corev1.Pod, result *dryRunResult) error {
+	if printer.format == outputJson {
+		return printer.printJson(result)
+	}
+	printer.printText(pod, result)
+	return nil
+}
+
+func (printer printer) printJson(result *dryRunResult) error {
+	encoder := json.NewEncoder(printer.out)
+	encoder.SetIndent("", "  ")
+	return errors.WithStack(encoder.Encode(result))
+}
+
+func (printer printer) printText(pod *corev1.Pod, result *dryRunResult) {
+	fmt.Fprintf(printer.out, "Pod: %s/%s\n", pod.Namespace, kubeobjects.GetPodName(*pod))
+
+	fmt.Fprintln(printer.out, "\nDecisions:")
+	if len(result.Decisions) == 0 {
+		fmt.Fprintln(printer.out, "  none, the webhook didn't handle the pod, its namespace is probably not monitored by a DynaKube")
+	}
+	for _, decision := range result.Decisions {
+		fmt.Fprintf(printer.out, "  [%s] %s\n", decision.Step, decision.Reason)
+	}
+
+	if len(result.Changes) == 0 {
+		fmt.Fprintln(printer.out, "\nNo changes, the pod would be created as it is.")
+		return
+	}
+
+	fmt.Fprintln(printer.out, "\nAdded on admission:")
+	for _, change := range result.Changes {
+		fmt.Fprintf(printer.out, "  %s\n", change)
+	}
+	fmt.Fprintln(printer.out, "\nUse --output json to see the pod as it would be created.")
+}
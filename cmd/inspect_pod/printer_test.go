@@ -0,0 +1,81 @@
+package inspect_pod
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod_mutator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	testPod    = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "shop"}}
+	testResult = &dryRunResult{
+		Decisions: []pod_mutator.PreviewDecision{
+			{Step: "dynakube", Reason: "namespace shop is monitored by DynaKube dynakube"},
+			{Step: "outcome", Reason: "injected"},
+		},
+		Changes: []string{"volume oneagent-bin", "init container install-oneagent"},
+		Pod:     &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "app-x7k2p", Namespace: "shop"}},
+	}
+)
+
+func TestPrinter(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer, err := newPrinter(outputText, out)
+		require.NoError(t, err)
+
+		require.NoError(t, printer.print(testPod, testResult))
+
+		assert.Equal(t, `Pod: shop/app
+
+Decisions:
+  [dynakube] namespace shop is monitored by DynaKube dynakube
+  [outcome] injected
+
+Added on admission:
+  volume oneagent-bin
+  init container install-oneagent
+
+Use --output json to see the pod as it would be created.
+`, out.String())
+	})
+	t.Run("text without decisions and changes", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer, err := newPrinter(outputText, out)
+		require.NoError(t, err)
+
+		require.NoError(t, printer.print(testPod, &dryRunResult{Pod: testPod}))
+
+		assert.Equal(t, `Pod: shop/app
+
+Decisions:
+  none, the webhook didn't handle the pod, its namespace is probably not monitored by a DynaKube
+
+No changes, the pod would be created as it is.
+`, out.String())
+	})
+	t.Run("json", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer, err := newPrinter(outputJson, out)
+		require.NoError(t, err)
+
+		require.NoError(t, printer.print(testPod, testResult))
+
+		var result dryRunResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+		assert.Equal(t, testResult.Decisions, result.Decisions)
+		assert.Equal(t, testResult.Changes, result.Changes)
+		assert.Equal(t, testResult.Pod.Name, result.Pod.Name)
+	})
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := newPrinter("yaml", &bytes.Buffer{})
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,108 @@
+package inspect_pod
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var injectedAnnotations = []string{
+	dtwebhook.AnnotationDynatraceInjected,
+	dtwebhook.AnnotationOneAgentInjected,
+	dtwebhook.AnnotationDataIngestInjected,
+	dtwebhook.AnnotationOTelInjected,
+}
+
+func getPod(ctx context.Context, kubeConfig *rest.Config, podName, namespace string) (*corev1.Pod, error) {
+	kubeClient, err := client.New(kubeConfig, client.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return getTemplatePod(ctx, kubeClient, podName, namespace)
+}
+
+// getTemplatePod returns the pod as the webhook gets it on creation.
+// A running pod was already mutated by the webhook, so the pod template of its controller is used instead.
+func getTemplatePod(ctx context.Context, kubeClient client.Reader, podName, namespace string) (*corev1.Pod, error) {
+	pod, err := kubeobjects.GetPod(ctx, kubeClient, podName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		template, err := getPodTemplate(ctx, kubeClient, owner, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if template != nil {
+			return newPodFromTemplate(pod, owner, template), nil
+		}
+	}
+
+	if isInjected(pod) {
+		return nil, errors.Errorf("pod %s was already mutated by the webhook and has no controller with a pod template, use --%s with the manifest of the pod instead", podName, filenameFlagName)
+	}
+	return pod, nil
+}
+
+// getPodTemplate returns nil if the controller of the pod isn't a known workload
+func getPodTemplate(ctx context.Context, kubeClient client.Reader, owner *metav1.OwnerReference, namespace string) (*corev1.PodTemplateSpec, error) {
+	var workload client.Object
+	var template *corev1.PodTemplateSpec
+
+	switch owner.APIVersion + "/" + owner.Kind {
+	case appsv1.SchemeGroupVersion.String() + "/ReplicaSet":
+		replicaSet := &appsv1.ReplicaSet{}
+		workload, template = replicaSet, &replicaSet.Spec.Template
+	case appsv1.SchemeGroupVersion.String() + "/StatefulSet":
+		statefulSet := &appsv1.StatefulSet{}
+		workload, template = statefulSet, &statefulSet.Spec.Template
+	case appsv1.SchemeGroupVersion.String() + "/DaemonSet":
+		daemonSet := &appsv1.DaemonSet{}
+		workload, template = daemonSet, &daemonSet.Spec.Template
+	case batchv1.SchemeGroupVersion.String() + "/Job":
+		job := &batchv1.Job{}
+		workload, template = job, &job.Spec.Template
+	default:
+		return nil, nil
+	}
+
+	if err := kubeClient.Get(ctx, client.ObjectKey{Name: owner.Name, Namespace: namespace}, workload); err != nil {
+		return nil, errors.WithMessagef(err, "failed to get the %s %s controlling the pod", owner.Kind, owner.Name)
+	}
+	return template, nil
+}
+
+func newPodFromTemplate(pod *corev1.Pod, owner *metav1.OwnerReference, template *corev1.PodTemplateSpec) *corev1.Pod {
+	controllerRef := *owner
+	// blocking the deletion of the owner needs the permission to update its finalizers, which isn't needed for the inspection
+	controllerRef.BlockOwnerDeletion = nil
+
+	templatePod := &corev1.Pod{
+		ObjectMeta: *template.ObjectMeta.DeepCopy(),
+		Spec:       *template.Spec.DeepCopy(),
+	}
+	templatePod.Name = pod.Name
+	templatePod.GenerateName = pod.GenerateName
+	templatePod.Namespace = pod.Namespace
+	templatePod.OwnerReferences = []metav1.OwnerReference{controllerRef}
+	return templatePod
+}
+
+func isInjected(pod *corev1.Pod) bool {
+	for _, annotation := range injectedAnnotations {
+		if kubeobjects.GetFieldBool(pod.Annotations, annotation, false) {
+			return true
+		}
+	}
+	return false
+}
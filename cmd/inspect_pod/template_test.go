@@ -0,0 +1,127 @@
+package inspect_pod
+
+import (
+	"context"
+	"testing"
+
+	dtfake "github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/address"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testNamespace      = "shop"
+	testPodName        = "app-7d9f8-x7k2p"
+	testReplicaSetName = "app-7d9f8"
+)
+
+func TestGetTemplatePod(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pod template of the controller is used for an injected pod", func(t *testing.T) {
+		clt := dtfake.NewClient(createTestReplicaSet(), createTestInjectedPod(createTestControllerRef()))
+
+		pod, err := getTemplatePod(ctx, clt, testPodName, testNamespace)
+
+		require.NoError(t, err)
+		assert.Equal(t, testPodName, pod.Name)
+		assert.Equal(t, testReplicaSetName+"-", pod.GenerateName)
+		assert.Equal(t, testNamespace, pod.Namespace)
+		assert.Equal(t, map[string]string{"app": "shop"}, pod.Labels)
+		assert.Empty(t, pod.Annotations)
+		assert.Empty(t, pod.Spec.InitContainers)
+		assert.Empty(t, pod.Spec.Volumes)
+		assert.Equal(t, []corev1.Container{{Name: "app", Image: "shop:latest"}}, pod.Spec.Containers)
+		require.Len(t, pod.OwnerReferences, 1)
+		assert.Equal(t, testReplicaSetName, pod.OwnerReferences[0].Name)
+		assert.Nil(t, pod.OwnerReferences[0].BlockOwnerDeletion)
+	})
+	t.Run("pod without controller is used as it is", func(t *testing.T) {
+		clt := dtfake.NewClient(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: testPodName, Namespace: testNamespace}})
+
+		pod, err := getTemplatePod(ctx, clt, testPodName, testNamespace)
+
+		require.NoError(t, err)
+		assert.Equal(t, testPodName, pod.Name)
+	})
+	t.Run("injected pod without controller is rejected", func(t *testing.T) {
+		clt := dtfake.NewClient(createTestInjectedPod(nil))
+
+		_, err := getTemplatePod(ctx, clt, testPodName, testNamespace)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), filenameFlagName)
+	})
+	t.Run("injected pod of unknown controller is rejected", func(t *testing.T) {
+		controllerRef := createTestControllerRef()
+		controllerRef.APIVersion = "example.com/v1"
+		clt := dtfake.NewClient(createTestInjectedPod(controllerRef))
+
+		_, err := getTemplatePod(ctx, clt, testPodName, testNamespace)
+
+		require.Error(t, err)
+	})
+	t.Run("missing controller is an error", func(t *testing.T) {
+		clt := dtfake.NewClient(createTestInjectedPod(createTestControllerRef()))
+
+		_, err := getTemplatePod(ctx, clt, testPodName, testNamespace)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), testReplicaSetName)
+	})
+}
+
+func createTestControllerRef() *metav1.OwnerReference {
+	return &metav1.OwnerReference{
+		APIVersion:         appsv1.SchemeGroupVersion.String(),
+		Kind:               "ReplicaSet",
+		Name:               testReplicaSetName,
+		UID:                "replica-set-uid",
+		Controller:         address.Of(true),
+		BlockOwnerDeletion: address.Of(true),
+	}
+}
+
+func createTestReplicaSet() *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: testReplicaSetName, Namespace: testNamespace},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "shop"}},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "shop:latest"}},
+				},
+			},
+		},
+	}
+}
+
+func createTestInjectedPod(controllerRef *metav1.OwnerReference) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:         testPodName,
+			GenerateName: testReplicaSetName + "-",
+			Namespace:    testNamespace,
+			Labels:       map[string]string{"app": "shop"},
+			Annotations:  map[string]string{dtwebhook.AnnotationDynatraceInjected: "true"},
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: dtwebhook.InstallContainerName, Image: "operator"}},
+			Containers: []corev1.Container{{
+				Name:  "app",
+				Image: "shop:latest",
+				Env:   []corev1.EnvVar{{Name: "LD_PRELOAD"}},
+			}},
+			Volumes: []corev1.Volume{{Name: "oneagent-bin"}},
+		},
+	}
+	if controllerRef != nil {
+		pod.OwnerReferences = []metav1.OwnerReference{*controllerRef}
+	}
+	return pod
+}
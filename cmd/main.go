@@ -21,7 +21,9 @@ import (
 	csiInit "github.com/Dynatrace/dynatrace-operator/cmd/csi/init"
 	csiProvisioner "github.com/Dynatrace/dynatrace-operator/cmd/csi/provisioner"
 	csiServer "github.com/Dynatrace/dynatrace-operator/cmd/csi/server"
+	"github.com/Dynatrace/dynatrace-operator/cmd/inspect_pod"
 	"github.com/Dynatrace/dynatrace-operator/cmd/operator"
+	"github.com/Dynatrace/dynatrace-operator/cmd/plan"
 	"github.com/Dynatrace/dynatrace-operator/cmd/standalone"
 	"github.com/Dynatrace/dynatrace-operator/cmd/startup_probe"
 	"github.com/Dynatrace/dynatrace-operator/cmd/support_archive"
@@ -88,6 +90,16 @@ func createSupportArchiveCommandBuilder() support_archive.CommandBuilder {
 		SetConfigProvider(cmdConfig.NewKubeConfigProvider())
 }
 
+func createPlanCommandBuilder() plan.CommandBuilder {
+	return plan.NewCommandBuilder().
+		SetConfigProvider(cmdConfig.NewKubeConfigProvider())
+}
+
+func createInspectPodCommandBuilder() inspect_pod.CommandBuilder {
+	return inspect_pod.NewCommandBuilder().
+		SetConfigProvider(cmdConfig.NewKubeConfigProvider())
+}
+
 func createStartupProbe() startup_probe.CommandBuilder {
 	return startup_probe.NewCommandBuilder()
 }
@@ -110,6 +122,8 @@ func main() {
 		createSupportArchiveCommandBuilder().Build(),
 		createStartupProbe().Build(),
 		createCsiInitCommandBuilder().Build(),
+		createPlanCommandBuilder().Build(),
+		createInspectPodCommandBuilder().Build(),
 	)
 
 	err := cmd.Execute()
@@ -8,6 +8,7 @@ import (
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/config"
@@ -42,6 +43,10 @@ func (mgr *TestManager) GetLogger() logr.Logger {
 	return logger.Factory.GetLogger("test-manager")
 }
 
+func (mgr *TestManager) GetEventRecorderFor(string) record.EventRecorder {
+	return &record.FakeRecorder{}
+}
+
 func (mgr *TestManager) GetRESTMapper() meta.RESTMapper {
 	return nil
 }
@@ -12,10 +12,14 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/edgeconnect"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/nodes"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth" // important for running operator locally
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -126,6 +130,7 @@ func (provider operatorManagerProvider) createOptions(namespace string) ctrl.Opt
 			DefaultNamespaces: map[string]cache.Config{
 				namespace: {},
 			},
+			ByObject: clusterWideCacheObjects(namespace),
 		},
 		Scheme: scheme.Scheme,
 		Metrics: server.Options{
@@ -143,6 +148,28 @@ func (provider operatorManagerProvider) createOptions(namespace string) ctrl.Opt
 	}
 }
 
+// clusterWideCacheObjects are cached in all namespaces, the injection status counts the pods of every monitored namespace
+// and the rollout of code modules upgrades looks for outdated pods.
+// Outside of the namespace of the operator only the injection state of the pods is kept, to keep the memory usage low on big clusters.
+func clusterWideCacheObjects(namespace string) map[client.Object]cache.ByObject {
+	return map[client.Object]cache.ByObject{
+		&corev1.Pod{}: {
+			Namespaces: map[string]cache.Config{
+				namespace:           {Transform: stripManagedFields},
+				cache.AllNamespaces: {Transform: stripToInjectionState},
+			},
+		},
+	}
+}
+
+// stripManagedFields keeps the managed fields out of the cache, they aren't read by the operator but take up most of the memory
+func stripManagedFields(obj any) (any, error) {
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+	return obj, nil
+}
+
 // managerBuilder is used for testing the createManager functions in the providers
 type managerBuilder struct {
 	mgr manager.Manager
@@ -175,3 +202,39 @@ func getTimeFromEnvWithDefault(envName string, defaultValue int64) *time.Duratio
 	}
 	return &duration
 }
+
+// stripToInjectionState keeps only the fields of a pod read by the injection status and the rollout of code modules upgrades
+func stripToInjectionState(obj any) (any, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return obj, nil
+	}
+
+	initContainerStatuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses))
+	for _, containerStatus := range pod.Status.InitContainerStatuses {
+		initContainerStatuses = append(initContainerStatuses, corev1.ContainerStatus{
+			Name:                 containerStatus.Name,
+			State:                containerStatus.State,
+			LastTerminationState: containerStatus.LastTerminationState,
+		})
+	}
+
+	return &corev1.Pod{
+		TypeMeta: pod.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              pod.Name,
+			Namespace:         pod.Namespace,
+			UID:               pod.UID,
+			ResourceVersion:   pod.ResourceVersion,
+			CreationTimestamp: pod.CreationTimestamp,
+			DeletionTimestamp: pod.DeletionTimestamp,
+			Labels:            pod.Labels,
+			Annotations:       pod.Annotations,
+			OwnerReferences:   pod.OwnerReferences,
+		},
+		Status: corev1.PodStatus{
+			Phase:                 pod.Status.Phase,
+			InitContainerStatuses: initContainerStatuses,
+		},
+	}, nil
+}
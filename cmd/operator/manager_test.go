@@ -9,9 +9,11 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 )
 
 func TestOperatorManagerProvider(t *testing.T) {
@@ -27,6 +29,11 @@ func TestOperatorManagerProvider(t *testing.T) {
 		assert.NotNil(t, options)
 
 		assert.Contains(t, options.Cache.DefaultNamespaces, "namespace")
+		assert.Len(t, options.Cache.ByObject, 1)
+		for _, byObject := range options.Cache.ByObject {
+			assert.Contains(t, byObject.Namespaces, cache.AllNamespaces)
+			assert.Contains(t, byObject.Namespaces, "namespace")
+		}
 		assert.Equal(t, scheme.Scheme, options.Scheme)
 		assert.Equal(t, metricsBindAddress, options.Metrics.BindAddress)
 
@@ -47,6 +54,59 @@ func TestOperatorManagerProvider(t *testing.T) {
 	})
 }
 
+func TestStripToInjectionState(t *testing.T) {
+	t.Run("keeps only the injection state of pods", func(t *testing.T) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "app",
+				Namespace:       "app-namespace",
+				ResourceVersion: "1",
+				Annotations:     map[string]string{"oneagent.dynatrace.com/injected": "true"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "app"}},
+				ManagedFields:   []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app", Image: "app:latest"}},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				InitContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "install-oneagent",
+					Image: "busybox",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+				}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "app"}},
+			},
+		}
+
+		transformed, err := stripToInjectionState(pod)
+		require.NoError(t, err)
+
+		strippedPod, ok := transformed.(*corev1.Pod)
+		require.True(t, ok)
+		assert.Equal(t, pod.Name, strippedPod.Name)
+		assert.Equal(t, pod.Namespace, strippedPod.Namespace)
+		assert.Equal(t, pod.ResourceVersion, strippedPod.ResourceVersion)
+		assert.Equal(t, pod.Annotations, strippedPod.Annotations)
+		assert.Equal(t, pod.OwnerReferences, strippedPod.OwnerReferences)
+		assert.Empty(t, strippedPod.ManagedFields)
+		assert.Empty(t, strippedPod.Spec.Containers)
+		assert.Empty(t, strippedPod.Status.ContainerStatuses)
+		assert.Equal(t, corev1.PodRunning, strippedPod.Status.Phase)
+		require.Len(t, strippedPod.Status.InitContainerStatuses, 1)
+		assert.Equal(t, "install-oneagent", strippedPod.Status.InitContainerStatuses[0].Name)
+		assert.Empty(t, strippedPod.Status.InitContainerStatuses[0].Image)
+		assert.Equal(t, pod.Status.InitContainerStatuses[0].State, strippedPod.Status.InitContainerStatuses[0].State)
+	})
+	t.Run("other objects are unchanged", func(t *testing.T) {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app-namespace"}}
+
+		transformed, err := stripToInjectionState(namespace)
+		require.NoError(t, err)
+		assert.Equal(t, namespace, transformed)
+	})
+}
+
 func TestBootstrapManagerProvider(t *testing.T) {
 	t.Run("implements interface", func(t *testing.T) {
 		bootstrapProvider := NewBootstrapManagerProvider()
@@ -0,0 +1,118 @@
+package plan
+
+import (
+	"io"
+	"os"
+
+	"github.com/Dynatrace/dynatrace-operator/cmd/config"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubesystem"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	use                    = "plan"
+	filenameFlagName       = "filename"
+	filenameFlagShorthand  = "f"
+	namespaceFlagName      = "namespace"
+	namespaceFlagShorthand = "n"
+	outputFlagName         = "output"
+	outputFlagShorthand    = "o"
+	stdinFilename          = "-"
+)
+
+var (
+	filenameFlagValue  string
+	namespaceFlagValue string
+	outputFlagValue    string
+)
+
+type CommandBuilder struct {
+	configProvider config.Provider
+}
+
+func NewCommandBuilder() CommandBuilder {
+	return CommandBuilder{}
+}
+
+func (builder CommandBuilder) SetConfigProvider(provider config.Provider) CommandBuilder {
+	builder.configProvider = provider
+	return builder
+}
+
+func (builder CommandBuilder) Build() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:  use,
+		Long: "Show the changes the operator would make to the cluster for a DynaKube, without applying them",
+		RunE: builder.buildRun(),
+	}
+
+	addFlags(cmd)
+
+	return cmd
+}
+
+func addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&filenameFlagValue, filenameFlagName, filenameFlagShorthand, "", "File containing the DynaKube to plan, - for stdin.")
+	cmd.PersistentFlags().StringVarP(&namespaceFlagValue, namespaceFlagName, namespaceFlagShorthand, kubeobjects.DefaultNamespace(), "Namespace of the operator, also used for the DynaKube if it doesn't set one.")
+	cmd.PersistentFlags().StringVarP(&outputFlagValue, outputFlagName, outputFlagShorthand, outputText, "Output format, one of: text, json.")
+	_ = cmd.MarkPersistentFlagRequired(filenameFlagName)
+}
+
+func (builder CommandBuilder) buildRun() func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		printer, err := newPrinter(outputFlagValue, cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+
+		rawDynakube, err := readFile(cmd.InOrStdin(), filenameFlagValue)
+		if err != nil {
+			return err
+		}
+
+		dk, err := decodeDynakube(rawDynakube)
+		if err != nil {
+			return err
+		}
+		if dk.Namespace == "" {
+			dk.Namespace = namespaceFlagValue
+		}
+
+		kubeConfig, err := builder.configProvider.GetConfig()
+		if err != nil {
+			return err
+		}
+
+		kubeClient, err := client.New(kubeConfig, client.Options{Scheme: scheme.Scheme})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		clusterID, err := kubesystem.GetUID(cmd.Context(), kubeClient)
+		if err != nil {
+			return err
+		}
+
+		changes, planErr := dynakube.NewPlanController(kubeClient, kubeConfig, namespaceFlagValue, string(clusterID)).Plan(cmd.Context(), dk)
+
+		err = printer.print(changes)
+		if err != nil {
+			return err
+		}
+		return errors.WithMessage(planErr, "reconciliation failed, the plan is incomplete")
+	}
+}
+
+func readFile(stdin io.Reader, filename string) ([]byte, error) {
+	if filename == stdinFilename {
+		content, err := io.ReadAll(stdin)
+		return content, errors.WithStack(err)
+	}
+	content, err := os.ReadFile(filename)
+	return content, errors.WithStack(err)
+}
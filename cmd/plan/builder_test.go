@@ -0,0 +1,38 @@
+package plan
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/cmd/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandBuilder(t *testing.T) {
+	t.Run("build command", func(t *testing.T) {
+		cmd := NewCommandBuilder().SetConfigProvider(&config.MockProvider{}).Build()
+
+		assert.NotNil(t, cmd)
+		assert.Equal(t, use, cmd.Use)
+		assert.NotNil(t, cmd.RunE)
+	})
+	t.Run("filename is required", func(t *testing.T) {
+		cmd := NewCommandBuilder().SetConfigProvider(&config.MockProvider{}).Build()
+		cmd.SetArgs([]string{})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), filenameFlagName)
+	})
+}
+
+func TestReadFile(t *testing.T) {
+	content, err := readFile(bytes.NewBufferString("from stdin"), stdinFilename)
+
+	require.NoError(t, err)
+	assert.Equal(t, "from stdin", string(content))
+}
@@ -0,0 +1,30 @@
+package plan
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// decodeDynakube accepts every served version of the DynaKube and converts it to the one the controller works with
+func decodeDynakube(rawDynakube []byte) (*dynatracev1beta1.DynaKube, error) {
+	obj, _, err := serializer.NewCodecFactory(scheme.Scheme).UniversalDeserializer().Decode(rawDynakube, nil, nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to decode DynaKube")
+	}
+
+	switch dk := obj.(type) {
+	case *dynatracev1beta1.DynaKube:
+		return dk, nil
+	case conversion.Convertible:
+		var hub dynatracev1beta1.DynaKube
+		if err := dk.ConvertTo(&hub); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return &hub, nil
+	default:
+		return nil, errors.Errorf("expected a DynaKube but got %s", obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+}
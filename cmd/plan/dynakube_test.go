@@ -0,0 +1,51 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeDynakube(t *testing.T) {
+	t.Run("v1beta1", func(t *testing.T) {
+		dk, err := decodeDynakube([]byte(`
+apiVersion: dynatrace.com/v1beta1
+kind: DynaKube
+metadata:
+  name: dynakube
+  namespace: dynatrace
+spec:
+  apiUrl: https://test/api
+`))
+
+		require.NoError(t, err)
+		assert.Equal(t, "dynakube", dk.Name)
+		assert.Equal(t, "dynatrace", dk.Namespace)
+		assert.Equal(t, "https://test/api", dk.Spec.APIURL)
+	})
+	t.Run("other versions are converted", func(t *testing.T) {
+		dk, err := decodeDynakube([]byte(`
+apiVersion: dynatrace.com/v1beta2
+kind: DynaKube
+metadata:
+  name: dynakube
+spec:
+  apiUrl: https://test/api
+`))
+
+		require.NoError(t, err)
+		assert.Equal(t, "dynakube", dk.Name)
+		assert.Equal(t, "https://test/api", dk.Spec.APIURL)
+	})
+	t.Run("error if not a dynakube", func(t *testing.T) {
+		_, err := decodeDynakube([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: dynakube
+`))
+
+		assert.Error(t, err)
+	})
+}
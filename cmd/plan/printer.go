@@ -0,0 +1,72 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/plan"
+	"github.com/pkg/errors"
+)
+
+const (
+	outputText = "text"
+	outputJson = "json"
+)
+
+var actionSymbols = map[plan.Action]string{
+	plan.ActionCreate: "+",
+	plan.ActionUpdate: "~",
+	plan.ActionDelete: "-",
+}
+
+type printer struct {
+	format string
+	out    io.Writer
+}
+
+func newPrinter(format string, out io.Writer) (printer, error) {
+	if format != outputText && format != outputJson {
+		return printer{}, errors.Errorf("unknown output format '%s', must be one of: %s, %s", format, outputText, outputJson)
+	}
+	return printer{format: format, out: out}, nil
+}
+
+func (printer printer) print(changes []plan.Change) error {
+	if printer.format == outputJson {
+		return printer.printJson(changes)
+	}
+	printer.printText(changes)
+	return nil
+}
+
+func (printer printer) printJson(changes []plan.Change) error {
+	encoder := json.NewEncoder(printer.out)
+	encoder.SetIndent("", "  ")
+	return errors.WithStack(encoder.Encode(changes))
+}
+
+func (printer printer) printText(changes []plan.Change) {
+	if len(changes) == 0 {
+		fmt.Fprintln(printer.out, "No changes, the cluster already matches the DynaKube.")
+		return
+	}
+
+	counts := make(map[plan.Action]int)
+
+	for _, change := range changes {
+		counts[change.Action]++
+
+		fmt.Fprintf(printer.out, "%s %s\n", actionSymbols[change.Action], change)
+		for _, line := range strings.SplitAfter(change.Diff, "\n") {
+			if line != "" {
+				fmt.Fprint(printer.out, "    "+line)
+			}
+		}
+		fmt.Fprintln(printer.out)
+	}
+
+	fmt.Fprintf(printer.out, "Plan: %d to create, %d to update, %d to delete.\n",
+		counts[plan.ActionCreate], counts[plan.ActionUpdate], counts[plan.ActionDelete])
+}
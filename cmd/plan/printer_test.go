@@ -0,0 +1,66 @@
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testChanges = []plan.Change{
+	{Action: plan.ActionCreate, Kind: "Secret", Namespace: "dynatrace", Name: "dynakube-pull-secret", Diff: "--- current\n+++ planned\n+kind: Secret\n"},
+	{Action: plan.ActionUpdate, Kind: "Namespace", Name: "app", Diff: "--- current\n+++ planned\n"},
+	{Action: plan.ActionCreate, Kind: "Setting", Name: "builtin:cloud.kubernetes/dynakube"},
+}
+
+func TestPrinter(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer, err := newPrinter(outputText, out)
+		require.NoError(t, err)
+
+		require.NoError(t, printer.print(testChanges))
+
+		assert.Equal(t, `+ create Secret 'dynatrace:dynakube-pull-secret'
+    --- current
+    +++ planned
+    +kind: Secret
+
+~ update Namespace 'app'
+    --- current
+    +++ planned
+
++ create Setting 'builtin:cloud.kubernetes/dynakube'
+
+Plan: 2 to create, 1 to update, 0 to delete.
+`, out.String())
+	})
+	t.Run("text without changes", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer, err := newPrinter(outputText, out)
+		require.NoError(t, err)
+
+		require.NoError(t, printer.print(nil))
+
+		assert.Equal(t, "No changes, the cluster already matches the DynaKube.\n", out.String())
+	})
+	t.Run("json", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer, err := newPrinter(outputJson, out)
+		require.NoError(t, err)
+
+		require.NoError(t, printer.print(testChanges))
+
+		var printed []plan.Change
+		require.NoError(t, json.Unmarshal(out.Bytes(), &printed))
+		assert.Equal(t, testChanges, printed)
+	})
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := newPrinter("xml", nil)
+
+		assert.Error(t, err)
+	})
+}
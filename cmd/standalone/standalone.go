@@ -1,21 +1,31 @@
 package standalone
 
 import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/startup"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"golang.org/x/sys/unix"
+	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 const (
 	use = "init"
 )
 
+var (
+	refreshFlagValue = false
+)
+
 func NewStandaloneCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:  use,
 		RunE: startStandAloneInit,
 	}
+
+	cmd.PersistentFlags().BoolVar(&refreshFlagValue, consts.RefreshFlag, false, "keep the configuration up to date instead of installing, used by the native sidecar")
+
+	return cmd
 }
 
 func startStandAloneInit(_ *cobra.Command, _ []string) error {
@@ -24,5 +34,8 @@ func startStandAloneInit(_ *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
+	if refreshFlagValue {
+		return standaloneRunner.RunRefresh(ctrl.SetupSignalHandler(), startup.RefreshInterval)
+	}
 	return standaloneRunner.Run()
 }
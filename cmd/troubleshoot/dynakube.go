@@ -98,18 +98,19 @@ func determineSelectedDynakubeError(namespaceName, dynakubeName string, err erro
 func checkIfDynatraceApiSecretHasApiToken(ctx context.Context, baseLog logr.Logger, apiReader client.Reader, dynakube *dynatracev1beta1.DynaKube) (token.Tokens, error) {
 	log := baseLog.WithName(dynakubeCheckLoggerName)
 
+	tokenSource := token.NewTokenSource(apiReader, dynakube)
 	tokenReader := token.NewReader(apiReader, dynakube)
 	tokens, err := tokenReader.ReadTokens(ctx)
 	if err != nil {
-		return nil, errors.Wrapf(err, "'%s:%s' secret is missing or invalid", dynakube.Namespace, dynakube.Tokens())
+		return nil, errors.Wrapf(err, "tokens of %s are missing or invalid", tokenSource)
 	}
 
 	_, hasApiToken := tokens[dtclient.DynatraceApiToken]
 	if !hasApiToken {
-		return nil, errors.New(fmt.Sprintf("'%s' token is missing in '%s:%s' secret", dtclient.DynatraceApiToken, dynakube.Namespace, dynakube.Tokens()))
+		return nil, errors.New(fmt.Sprintf("'%s' token is missing in %s", dtclient.DynatraceApiToken, tokenSource))
 	}
 
-	logInfof(log, "secret token 'apiToken' exists")
+	logInfof(log, "token 'apiToken' exists in %s", tokenSource)
 	return tokens, nil
 }
 
@@ -147,12 +148,14 @@ func checkDynatraceApiTokenScopes(ctx context.Context, baseLog logr.Logger, apiR
 
 	tokens := dynatraceApiSecretTokens.SetScopesForDynakube(*dynakube)
 
+	tokenSource := token.NewTokenSource(apiReader, dynakube)
+
 	if err = tokens.VerifyValues(); err != nil {
-		return errors.Wrapf(err, "invalid '%s:%s' secret", dynakube.Namespace, dynakube.Tokens())
+		return errors.Wrapf(err, "invalid tokens in %s", tokenSource)
 	}
 
 	if err = tokens.VerifyScopes(dtc); err != nil {
-		return errors.Wrapf(err, "invalid '%s:%s' secret", dynakube.Namespace, dynakube.Tokens())
+		return errors.Wrapf(err, "invalid tokens in %s", tokenSource)
 	}
 
 	logInfof(log, "token scopes are valid")
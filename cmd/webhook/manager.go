@@ -4,10 +4,12 @@ import (
 	"crypto/tls"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha1/injectionpolicy"
 	"github.com/pkg/errors"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -67,6 +69,14 @@ func (provider Provider) createOptions(namespace string) ctrl.Options {
 			DefaultNamespaces: map[string]cache.Config{
 				namespace: {},
 			},
+			ByObject: map[client.Object]cache.ByObject{
+				// read on every admission, from the namespace of the pod
+				&injectionpolicy.InjectionPolicy{}: {
+					Namespaces: map[string]cache.Config{
+						cache.AllNamespaces: {},
+					},
+				},
+			},
 		},
 		WebhookServer: webhook.NewServer(webhook.Options{
 			Port: port,
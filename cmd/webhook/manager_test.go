@@ -5,9 +5,11 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/cmd/manager"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha1/injectionpolicy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
@@ -27,6 +29,11 @@ func TestCreateOptions(t *testing.T) {
 
 		assert.NotNil(t, options)
 		assert.Contains(t, options.Cache.DefaultNamespaces, "test-namespace")
+		require.Len(t, options.Cache.ByObject, 1)
+		for object, byObject := range options.Cache.ByObject {
+			assert.IsType(t, &injectionpolicy.InjectionPolicy{}, object)
+			assert.Contains(t, byObject.Namespaces, cache.AllNamespaces)
+		}
 		assert.Equal(t, scheme.Scheme, options.Scheme)
 		assert.Equal(t, metricsBindAddress, options.Metrics.BindAddress)
 
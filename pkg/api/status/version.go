@@ -2,7 +2,11 @@
 // +k8s:openapi-gen=true
 package status
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 type VersionSource string
 
@@ -13,6 +17,8 @@ const (
 	PublicRegistryVersionSource VersionSource = "public-registry"
 
 	ImmutableImageType = "immutable"
+	// ArtifactImageType marks code modules packaged as OCI artifact instead of as container image
+	ArtifactImageType = "artifact"
 )
 
 type VersionStatus struct {
@@ -26,4 +32,68 @@ type VersionStatus struct {
 	Type string `json:"type,omitempty"`
 	// Indicates when the last check for a new version was performed
 	LastProbeTimestamp *metav1.Time `json:"lastProbeTimestamp,omitempty"`
+	// Digests of the images per architecture, if the image is a multi-arch image
+	Platforms []PlatformImage `json:"platforms,omitempty"`
+	// Image ID of a newer version that is held back until the next update window opens
+	PendingImageID string `json:"pendingImageID,omitempty"`
+	// Newer version that is held back until the next update window opens
+	PendingVersion string `json:"pendingVersion,omitempty"`
+}
+
+type PlatformImage struct {
+	// Architecture of the image, as used by the kubernetes.io/arch node label
+	Architecture string `json:"architecture"`
+	// Digest of the image for the architecture
+	Digest string `json:"digest"`
+}
+
+// IsEmpty checks if no version was determined yet
+func (versionStatus *VersionStatus) IsEmpty() bool {
+	return versionStatus.Source == "" &&
+		versionStatus.ImageID == "" &&
+		versionStatus.Version == "" &&
+		versionStatus.Type == "" &&
+		versionStatus.LastProbeTimestamp == nil
+}
+
+// ImageIDForArch returns the image for the given architecture (kubernetes.io/arch) pinned to its digest,
+// it falls back to the ImageID if there is no image for the architecture
+func (versionStatus *VersionStatus) ImageIDForArch(arch string) string {
+	for _, platform := range versionStatus.Platforms {
+		if platform.Architecture == arch {
+			repository, _, _ := strings.Cut(versionStatus.ImageID, "@")
+			return repository + "@" + platform.Digest
+		}
+	}
+	return versionStatus.ImageID
+}
+
+// Architectures returns the architectures the image is available for, it is empty if the image isn't a multi-arch image
+func (versionStatus *VersionStatus) Architectures() []string {
+	architectures := make([]string, 0, len(versionStatus.Platforms))
+	for _, platform := range versionStatus.Platforms {
+		architectures = append(architectures, platform.Architecture)
+	}
+	return architectures
+}
+
+// HasPendingUpdate checks if a newer version is held back by an update window
+func (versionStatus *VersionStatus) HasPendingUpdate() bool {
+	return versionStatus.PendingImageID != "" || versionStatus.PendingVersion != ""
+}
+
+// HoldBackUpdate keeps the new image and version as pending and restores the previous ones
+func (versionStatus *VersionStatus) HoldBackUpdate(previous VersionStatus) {
+	versionStatus.PendingImageID = versionStatus.ImageID
+	versionStatus.PendingVersion = versionStatus.Version
+	versionStatus.ImageID = previous.ImageID
+	versionStatus.Version = previous.Version
+	versionStatus.Type = previous.Type
+	versionStatus.Platforms = previous.Platforms
+}
+
+// ClearPendingUpdate forgets about a held back update
+func (versionStatus *VersionStatus) ClearPendingUpdate() {
+	versionStatus.PendingImageID = ""
+	versionStatus.PendingVersion = ""
 }
@@ -19,6 +19,21 @@ limitations under the License.
 
 package status
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlatformImage) DeepCopyInto(out *PlatformImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlatformImage.
+func (in *PlatformImage) DeepCopy() *PlatformImage {
+	if in == nil {
+		return nil
+	}
+	out := new(PlatformImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VersionStatus) DeepCopyInto(out *VersionStatus) {
 	*out = *in
@@ -26,6 +41,11 @@ func (in *VersionStatus) DeepCopyInto(out *VersionStatus) {
 		in, out := &in.LastProbeTimestamp, &out.LastProbeTimestamp
 		*out = (*in).DeepCopy()
 	}
+	if in.Platforms != nil {
+		in, out := &in.Platforms, &out.Platforms
+		*out = make([]PlatformImage, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionStatus.
@@ -34,6 +34,9 @@ type EdgeConnectSpec struct { //nolint:revive
 	// +kubebuilder:default:=true
 	AutoUpdate bool `json:"autoUpdate"`
 
+	// Restricts automatic updates to recurring maintenance windows, newer versions found outside of a window are reported in the status
+	UpdateWindow *UpdateWindowSpec `json:"updateWindow,omitempty"`
+
 	// Pull secret for your private registry
 	CustomPullSecret string `json:"customPullSecret,omitempty"`
 
@@ -84,6 +87,20 @@ type ImageRefSpec struct {
 	Tag string `json:"tag,omitempty"`
 }
 
+type UpdateWindowSpec struct {
+	// Cron schedule (minute hour day-of-month month day-of-week) at which the update window opens
+	// +kubebuilder:validation:Required
+	// +kubebuilder:example:="0 22 * * mon-fri"
+	Schedule string `json:"schedule"`
+
+	// How long the update window stays open after the schedule fired
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+
+	// IANA time zone the schedule is evaluated in, for example Europe/Vienna (the default value is: UTC)
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
 // EdgeConnectStatus defines the observed state of EdgeConnect
 type EdgeConnectStatus struct { //nolint:revive
 	// Defines the current state (Running, Updating, Error, ...)
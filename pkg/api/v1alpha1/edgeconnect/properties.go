@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/updatewindow"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -28,6 +29,14 @@ func (edgeConnect *EdgeConnect) IsCustomImage() bool {
 	return edgeConnect.Spec.ImageRef.Repository != ""
 }
 
+// UpdateWindow returns the window automatic updates are restricted to, it is nil if updates are allowed at any time
+func (edgeConnect *EdgeConnect) UpdateWindow() (*updatewindow.Window, error) {
+	if edgeConnect.Spec.UpdateWindow == nil {
+		return nil, nil
+	}
+	return updatewindow.New(edgeConnect.Spec.UpdateWindow.Schedule, edgeConnect.Spec.UpdateWindow.Duration.Duration, edgeConnect.Spec.UpdateWindow.TimeZone)
+}
+
 func (edgeConnect *EdgeConnect) PullSecretWithoutData() corev1.Secret {
 	return corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
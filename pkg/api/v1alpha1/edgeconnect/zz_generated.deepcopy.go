@@ -89,6 +89,11 @@ func (in *EdgeConnectSpec) DeepCopyInto(out *EdgeConnectSpec) {
 	*out = *in
 	out.OAuth = in.OAuth
 	out.ImageRef = in.ImageRef
+	if in.UpdateWindow != nil {
+		in, out := &in.UpdateWindow, &out.UpdateWindow
+		*out = new(UpdateWindowSpec)
+		**out = **in
+	}
 	if in.Annotations != nil {
 		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
@@ -202,3 +207,19 @@ func (in *OAuthSpec) DeepCopy() *OAuthSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateWindowSpec) DeepCopyInto(out *UpdateWindowSpec) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateWindowSpec.
+func (in *UpdateWindowSpec) DeepCopy() *UpdateWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,78 @@
+// +kubebuilder:object:generate=true
+// +groupName=dynatrace.com
+// +versionName=v1alpha1
+// +kubebuilder:validation:Optional
+package injectionpolicy
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// InjectionPolicySpec defines which pods and containers of a namespace are injected and how
+type InjectionPolicySpec struct { //nolint:revive
+	// Policies are evaluated in descending order of their priority, the first policy matching a pod is applied (the default value is: 0)
+	// +kubebuilder:default:=0
+	Priority int32 `json:"priority,omitempty"`
+
+	// Selects the pods the policy applies to, an empty selector matches all pods of the namespace
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// Selects the containers of matching pods that get the OneAgent injected, all other containers are skipped
+	Containers *ContainerSelector `json:"containers,omitempty"`
+
+	// Comma-separated list of code module technologies to download, overrides the default of "all"
+	// +kubebuilder:example:="java,nodejs"
+	Technologies string `json:"technologies,omitempty"`
+
+	// Code modules flavor to download
+	// +kubebuilder:example:="musl"
+	Flavor string `json:"flavor,omitempty"`
+
+	// Directory the OneAgent will be available from in the injected containers
+	InstallPath string `json:"installPath,omitempty"`
+
+	// Controls what the init container does on failures
+	// +kubebuilder:validation:Enum=silent;fail
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+}
+
+// ContainerSelector selects containers by their name or image, a container has to match both lists if both are set
+type ContainerSelector struct {
+	// Names of the containers to inject
+	Names []string `json:"names,omitempty"`
+
+	// Shell patterns the container image has to match, like "docker.io/library/*" or "*/java-app:*"
+	ImagePatterns []string `json:"imagePatterns,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InjectionPolicy is the Schema for the InjectionPolicy API
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=injectionpolicies,scope=Namespaced,categories=dynatrace
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:storageversion
+type InjectionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec InjectionPolicySpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InjectionPolicyList contains a list of InjectionPolicy
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+type InjectionPolicyList struct { //nolint:revive
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InjectionPolicy `json:"items"`
+}
+
+func init() {
+	v1alpha1.SchemeBuilder.Register(&InjectionPolicy{}, &InjectionPolicyList{})
+}
@@ -0,0 +1,54 @@
+package injectionpolicy
+
+import (
+	"path"
+	"sort"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// SortByPriority sorts the policies in the order they are evaluated, ties are resolved by name to keep the order stable
+func SortByPriority(policies []InjectionPolicy) {
+	sort.SliceStable(policies, func(i, j int) bool {
+		if policies[i].Spec.Priority != policies[j].Spec.Priority {
+			return policies[i].Spec.Priority > policies[j].Spec.Priority
+		}
+		return policies[i].Name < policies[j].Name
+	})
+}
+
+// MatchesPod returns true if the pod labels match the pod selector and at least one container of the pod is selected
+func (policy *InjectionPolicy) MatchesPod(pod corev1.Pod) (bool, error) {
+	if policy.Spec.PodSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(policy.Spec.PodSelector)
+		if err != nil {
+			return false, errors.WithMessagef(err, "invalid pod selector of injection policy %s", policy.Name)
+		}
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			return false, nil
+		}
+	}
+	return slices.ContainsFunc(pod.Spec.Containers, policy.MatchesContainer), nil
+}
+
+// MatchesContainer returns true if the container is selected for injection by the policy
+func (policy *InjectionPolicy) MatchesContainer(container corev1.Container) bool {
+	selector := policy.Spec.Containers
+	if selector == nil {
+		return true
+	}
+	if len(selector.Names) > 0 && !slices.Contains(selector.Names, container.Name) {
+		return false
+	}
+	if len(selector.ImagePatterns) > 0 {
+		return slices.ContainsFunc(selector.ImagePatterns, func(pattern string) bool {
+			matches, err := path.Match(pattern, container.Image)
+			return err == nil && matches
+		})
+	}
+	return true
+}
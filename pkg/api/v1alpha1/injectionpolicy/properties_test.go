@@ -0,0 +1,103 @@
+package injectionpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSortByPriority(t *testing.T) {
+	policies := []InjectionPolicy{
+		{ObjectMeta: metav1.ObjectMeta{Name: "low"}, Spec: InjectionPolicySpec{Priority: -1}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b-default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "high"}, Spec: InjectionPolicySpec{Priority: 10}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "a-default"}},
+	}
+
+	SortByPriority(policies)
+
+	names := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		names = append(names, policy.Name)
+	}
+	assert.Equal(t, []string{"high", "a-default", "b-default", "low"}, names)
+}
+
+func TestMatchesPod(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "shop"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "app", Image: "registry.example.com/shop/app:1.0"},
+			{Name: "proxy", Image: "docker.io/envoyproxy/envoy:v1.28"},
+		}},
+	}
+
+	t.Run("policy without selectors matches every pod", func(t *testing.T) {
+		policy := InjectionPolicy{}
+
+		matches, err := policy.MatchesPod(pod)
+		require.NoError(t, err)
+		assert.True(t, matches)
+	})
+	t.Run("pod selector has to match pod labels", func(t *testing.T) {
+		policy := InjectionPolicy{Spec: InjectionPolicySpec{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "shop"}}}}
+		matches, err := policy.MatchesPod(pod)
+		require.NoError(t, err)
+		assert.True(t, matches)
+
+		policy.Spec.PodSelector.MatchLabels["app"] = "other"
+		matches, err = policy.MatchesPod(pod)
+		require.NoError(t, err)
+		assert.False(t, matches)
+	})
+	t.Run("pod without selected container does not match", func(t *testing.T) {
+		policy := InjectionPolicy{Spec: InjectionPolicySpec{Containers: &ContainerSelector{Names: []string{"missing"}}}}
+
+		matches, err := policy.MatchesPod(pod)
+		require.NoError(t, err)
+		assert.False(t, matches)
+	})
+	t.Run("invalid pod selector is an error", func(t *testing.T) {
+		policy := InjectionPolicy{Spec: InjectionPolicySpec{PodSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Invalid"}},
+		}}}
+
+		_, err := policy.MatchesPod(pod)
+		require.Error(t, err)
+	})
+}
+
+func TestMatchesContainer(t *testing.T) {
+	app := corev1.Container{Name: "app", Image: "registry.example.com/shop/app:1.0"}
+	proxy := corev1.Container{Name: "proxy", Image: "docker.io/envoyproxy/envoy:v1.28"}
+
+	t.Run("select by name", func(t *testing.T) {
+		policy := InjectionPolicy{Spec: InjectionPolicySpec{Containers: &ContainerSelector{Names: []string{"app"}}}}
+
+		assert.True(t, policy.MatchesContainer(app))
+		assert.False(t, policy.MatchesContainer(proxy))
+	})
+	t.Run("select by image pattern", func(t *testing.T) {
+		policy := InjectionPolicy{Spec: InjectionPolicySpec{Containers: &ContainerSelector{ImagePatterns: []string{"registry.example.com/*/*"}}}}
+
+		assert.True(t, policy.MatchesContainer(app))
+		assert.False(t, policy.MatchesContainer(proxy))
+	})
+	t.Run("name and image pattern have to match", func(t *testing.T) {
+		policy := InjectionPolicy{Spec: InjectionPolicySpec{Containers: &ContainerSelector{
+			Names:         []string{"app", "proxy"},
+			ImagePatterns: []string{"docker.io/envoyproxy/*"},
+		}}}
+
+		assert.False(t, policy.MatchesContainer(app))
+		assert.True(t, policy.MatchesContainer(proxy))
+	})
+	t.Run("invalid image pattern matches nothing", func(t *testing.T) {
+		policy := InjectionPolicy{Spec: InjectionPolicySpec{Containers: &ContainerSelector{ImagePatterns: []string{"["}}}}
+
+		assert.False(t, policy.MatchesContainer(app))
+	})
+}
@@ -45,11 +45,70 @@ type DynaKubeStatus struct { // nolint:revive
 
 	// Observed state of Dynatrace API
 	DynatraceApi DynatraceApiStatus `json:"dynatraceApi,omitempty"`
+
+	// Observed state of the mirror
+	Mirror MirrorStatus `json:"mirror,omitempty"`
+
+	// Observed state of the application injection
+	Injection InjectionStatus `json:"injection,omitempty"`
+}
+
+type InjectionStatus struct {
+	// Number of namespaces monitored by the DynaKube
+	Namespaces int32 `json:"namespaces,omitempty"`
+
+	// Injection outcome of the running pods in the monitored namespaces
+	Pods InjectionCounts `json:"pods,omitempty"`
+
+	// Monitored namespaces with at least one failed injection
+	FailedNamespaces []string `json:"failedNamespaces,omitempty"`
+}
+
+type InjectionCounts struct {
+	// Number of injected pods
+	Injected int32 `json:"injected"`
+
+	// Number of injected pods whose init container failed
+	Failed int32 `json:"failed"`
+
+	// Number of pods that were not injected, by reason
+	Skipped map[string]int32 `json:"skipped,omitempty"`
+}
+
+type MirrorStatus struct {
+	// OneAgent image in the mirror
+	OneAgent MirroredArtifact `json:"oneAgent,omitempty"`
+
+	// Code modules image in the mirror
+	CodeModules MirroredArtifact `json:"codeModules,omitempty"`
+
+	// ActiveGate image in the mirror
+	ActiveGate MirroredArtifact `json:"activeGate,omitempty"`
+
+	// OneAgent installers in the mirror, one tag per architecture (<image>-<architecture>)
+	Installer MirroredArtifact `json:"installer,omitempty"`
+
+	// Time of the last successful sync of the mirror
+	LastSyncTimestamp *metav1.Time `json:"lastSyncTimestamp,omitempty"`
+}
+
+type MirroredArtifact struct {
+	// Image or version the artifact was mirrored from
+	Source string `json:"source,omitempty"`
+
+	// Reference of the artifact in the mirror
+	Image string `json:"image,omitempty"`
 }
 
 type DynatraceApiStatus struct {
 	// Time of the last token request
 	LastTokenScopeRequest metav1.Time `json:"lastTokenScopeRequest,omitempty"`
+
+	// Hash of the tokens, used to detect their rotation
+	TokenHash string `json:"tokenHash,omitempty"`
+
+	// Time of the last token rotation
+	LastTokenRotation metav1.Time `json:"lastTokenRotation,omitempty"`
 }
 
 func GetCacheValidMessage(functionName string, lastRequestTimestamp metav1.Time, timeout time.Duration) string {
@@ -115,6 +174,24 @@ type OneAgentStatus struct {
 	// Time of the last instance status update
 	LastInstanceStatusUpdate *metav1.Time `json:"lastInstanceStatusUpdate,omitempty"`
 
+	// Dynatrace hosts of the nodes with a OneAgent instance, nodes without a host in Dynatrace have an empty entityId
+	Hosts map[string]OneAgentHostStatus `json:"hosts,omitempty"`
+
+	// Number of nodes with a host in Dynatrace, out of the nodes with a OneAgent instance, e.g. 2/3
+	MonitoredHosts string `json:"monitoredHosts,omitempty"`
+
+	// Time of the last host status update
+	LastHostStatusUpdate *metav1.Time `json:"lastHostStatusUpdate,omitempty"`
+
+	// Number of failed host status updates in a row, the update is retried with an increasing delay
+	HostStatusFailures int `json:"hostStatusFailures,omitempty"`
+
+	// Nodes without a running OneAgent, with the reason why the OneAgent DaemonSet doesn't cover them
+	UncoveredNodes []OneAgentUncoveredNode `json:"uncoveredNodes,omitempty"`
+
+	// State of the OneAgent DaemonSets of the node profiles
+	NodeProfiles []OneAgentNodeProfileStatus `json:"nodeProfiles,omitempty"`
+
 	// Information about OneAgent's connections
 	ConnectionInfoStatus OneAgentConnectionInfoStatus `json:"connectionInfoStatus,omitempty"`
 
@@ -123,6 +200,53 @@ type OneAgentStatus struct {
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Healthcheck *containerv1.HealthConfig `json:"healthcheck,omitempty"`
+
+	// State of the staged rollout of the last OneAgent version upgrade
+	Rollout *OneAgentRolloutStatus `json:"rollout,omitempty"`
+}
+
+type OneAgentRolloutPhase string
+
+const (
+	// OneAgentRolloutCanary means the canary OneAgents are updated and not yet ready
+	OneAgentRolloutCanary OneAgentRolloutPhase = "Canary"
+	// OneAgentRolloutBaking means the canary OneAgents are ready and have to stay healthy for the bake time
+	OneAgentRolloutBaking OneAgentRolloutPhase = "Baking"
+	// OneAgentRolloutCompleted means the new version is rolled out to all nodes
+	OneAgentRolloutCompleted OneAgentRolloutPhase = "Completed"
+	// OneAgentRolloutRolledBack means the canary OneAgents failed and all nodes run the previous version
+	OneAgentRolloutRolledBack OneAgentRolloutPhase = "RolledBack"
+)
+
+type OneAgentRolloutStatus struct {
+	// Current phase of the rollout
+	Phase OneAgentRolloutPhase `json:"phase,omitempty"`
+
+	// Version which is rolled out
+	TargetVersion string `json:"targetVersion,omitempty"`
+
+	// Version which ran before the rollout, used for the rollback
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
+	// Image which ran before the rollout, used for the rollback
+	PreviousImage string `json:"previousImage,omitempty"`
+
+	// Nodes which got the new version first
+	CanaryNodes []string `json:"canaryNodes,omitempty"`
+
+	// Time the rollout started
+	StartTimestamp *metav1.Time `json:"startTimestamp,omitempty"`
+
+	// Time all canary OneAgents became ready, the bake time starts then
+	CanaryReadyTimestamp *metav1.Time `json:"canaryReadyTimestamp,omitempty"`
+
+	// Reason of the rollback
+	Message string `json:"message,omitempty"`
+}
+
+// InProgress is true while the canary OneAgents are updated or baking
+func (rollout *OneAgentRolloutStatus) InProgress() bool {
+	return rollout != nil && (rollout.Phase == OneAgentRolloutCanary || rollout.Phase == OneAgentRolloutBaking)
 }
 
 type OneAgentInstance struct {
@@ -133,6 +257,55 @@ type OneAgentInstance struct {
 	IPAddress string `json:"ipAddress,omitempty"`
 }
 
+type OneAgentNodeProfileStatus struct {
+	// Name of the node profile
+	Name string `json:"name"`
+
+	// Name of the OneAgent DaemonSet of the node profile
+	DaemonSet string `json:"daemonSet"`
+
+	// Number of nodes of the node profile that should run a OneAgent
+	DesiredNodes int32 `json:"desiredNodes"`
+
+	// Number of nodes of the node profile with a ready OneAgent
+	ReadyNodes int32 `json:"readyNodes"`
+}
+
+type OneAgentCoverageGapReason string
+
+const (
+	// CoverageGapUntoleratedTaint means the node has a taint the OneAgent DaemonSet doesn't tolerate
+	CoverageGapUntoleratedTaint OneAgentCoverageGapReason = "UntoleratedTaint"
+	// CoverageGapSelectorMismatch means the node doesn't match the node selector or node affinity of the OneAgent DaemonSet
+	CoverageGapSelectorMismatch OneAgentCoverageGapReason = "SelectorMismatch"
+	// CoverageGapInsufficientResources means the OneAgent pod can't be scheduled because the node lacks resources
+	CoverageGapInsufficientResources OneAgentCoverageGapReason = "InsufficientResources"
+	// CoverageGapPodPending means the OneAgent pod of the node isn't running for other reasons, or doesn't exist
+	CoverageGapPodPending OneAgentCoverageGapReason = "PodPending"
+)
+
+type OneAgentUncoveredNode struct {
+	// Name of the node
+	NodeName string `json:"nodeName"`
+
+	// Why the node isn't covered
+	Reason OneAgentCoverageGapReason `json:"reason"`
+
+	// Details, like the untolerated taint or the scheduling error
+	Message string `json:"message,omitempty"`
+}
+
+type OneAgentHostStatus struct {
+	// Entity ID of the host in Dynatrace
+	EntityID string `json:"entityId,omitempty"`
+
+	// Version of the OneAgent running on the host, as reported by Dynatrace
+	AgentVersion string `json:"agentVersion,omitempty"`
+
+	// Time Dynatrace received data from the host the last time
+	LastSeen *metav1.Time `json:"lastSeen,omitempty"`
+}
+
 type SyntheticStatus struct {
 	status.VersionStatus `json:",inline"`
 }
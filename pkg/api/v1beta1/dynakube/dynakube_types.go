@@ -20,6 +20,12 @@ const (
 
 	// DataIngestTokenConditionType identifies the DataIngest Token validity condition
 	DataIngestTokenConditionType string = "DataIngestToken"
+
+	// TokenRotationConditionType identifies the condition recording the last rotation of the tokens
+	TokenRotationConditionType string = "TokenRotation"
+
+	// OneAgentCoverageConditionType identifies the condition telling if the OneAgent DaemonSet covers all nodes
+	OneAgentCoverageConditionType string = "OneAgentCoverage"
 )
 
 // Possible reasons for ApiToken and PaaSToken conditions
@@ -29,6 +35,18 @@ const (
 
 	// ReasonTokenError is set when an unknown error has been found when verifying the token
 	ReasonTokenError string = "TokenError"
+
+	// ReasonTokensRotated is set when the tokens changed and the components using them are being updated
+	ReasonTokensRotated string = "TokensRotated"
+)
+
+// Possible reasons for the OneAgentCoverage condition
+const (
+	// ReasonAllNodesCovered is set when a OneAgent is running on every node
+	ReasonAllNodesCovered string = "AllNodesCovered"
+
+	// ReasonNodesNotCovered is set when nodes are left without a running OneAgent
+	ReasonNodesNotCovered string = "NodesNotCovered"
 )
 
 type DynaKubeProxy struct { // nolint:revive
@@ -43,6 +61,125 @@ type DynaKubeProxy struct { // nolint:revive
 	ValueFrom string `json:"valueFrom,omitempty"`
 }
 
+type TokenSourceSpec struct {
+	// Reads the tokens from files mounted into the pods of the Dynatrace Operator, for example by the Secrets Store CSI driver or a Vault agent.
+	// Changes of the files are picked up without restarting the Dynatrace Operator.
+	// +nullable
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Token files",order=34,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	File *FileTokenSource `json:"file,omitempty"`
+}
+
+type FileTokenSource struct {
+	// Directory containing one file per token (apiToken, paasToken, dataIngestToken), relative to /var/run/dynatrace/tokens.
+	// If empty, the files are read from /var/run/dynatrace/tokens directly.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Token directory",order=35,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	Path string `json:"path,omitempty"`
+}
+
+type MirrorSpec struct {
+	// Repository in an in-cluster OCI registry the images and installers are mirrored to.
+	// Init containers fall back to the installers in the mirror without credentials, so they have to be readable anonymously.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:example:="registry.dynatrace-mirror:5000/dynatrace"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mirror registry",order=41,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	Registry string `json:"registry"`
+
+	// Name of a secret of type kubernetes.io/dockerconfigjson with the credentials for the mirror.
+	// The credentials are added to the pull secret generated for the DynaKube, a custom pull secret has to contain them itself.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mirror credentials secret",order=42,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:io.kubernetes:Secret"}
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// Connect to the mirror via plain http.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Insecure mirror",order=43,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Insecure bool `json:"insecure,omitempty"`
+
+	// Restricts syncing the mirror to a recurring time window, for example when the connection to Dynatrace is available.
+	// The mirror is synced whenever a new version is found, if not set.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mirror sync window",order=44,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	SyncWindow *UpdateWindowSpec `json:"syncWindow,omitempty"`
+}
+
+type MetadataEnrichmentSpec struct {
+	// Rules selecting the namespace labels, pod labels and pod annotations which are added as attributes.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Metadata enrichment rules",order=46,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Rules []EnrichmentRule `json:"rules,omitempty"`
+}
+
+type EnrichmentRuleType string
+
+const (
+	EnrichmentNamespaceLabelRule EnrichmentRuleType = "NamespaceLabel"
+	EnrichmentPodLabelRule       EnrichmentRuleType = "PodLabel"
+	EnrichmentPodAnnotationRule  EnrichmentRuleType = "PodAnnotation"
+)
+
+type EnrichmentRule struct {
+	// Source of the attributes, either NamespaceLabel, PodLabel or PodAnnotation.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=NamespaceLabel;PodLabel;PodAnnotation
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enrichment rule type",order=47,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Type EnrichmentRuleType `json:"type"`
+
+	// Keys of the labels or annotations which are added, all others are ignored.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:example:={"team","cost-center"}
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enrichment rule keys",order=48,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Keys []string `json:"keys"`
+
+	// Prefix of the attribute names, the key is appended to it.
+	// Defaults to k8s.namespace.label., k8s.pod.label. or k8s.pod.annotation. depending on the type.
+	// +optional
+	// +kubebuilder:example:="k8s.pod.label."
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enrichment rule prefix",order=49,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	Prefix string `json:"prefix,omitempty"`
+}
+
+type RolloutOnUpgradeSpec struct {
+	// Namespaces in which workloads are restarted, injected pods in other namespaces keep running the old code modules.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:example:={"shop","payment"}
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rollout namespaces",order=50,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Namespaces []string `json:"namespaces"`
+
+	// Maximum number of workloads restarted at the same time, the next workloads are restarted once their rollout finished (the default value is: 1).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=1
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max concurrent rollouts",order=51,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:number"}
+	MaxConcurrentRollouts int32 `json:"maxConcurrentRollouts,omitempty"`
+}
+
+type OpenTelemetrySpec struct {
+	// Exports through the ActiveGate deployed by the DynaKube, instead of exporting to the tenant directly.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Export through ActiveGate",order=52,xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	UseActiveGate bool `json:"useActiveGate,omitempty"`
+}
+
+type UpdateWindowSpec struct {
+	// Cron schedule (minute hour day-of-month month day-of-week) at which the update window opens.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:example:="0 22 * * mon-fri"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Update window schedule",order=36,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	Schedule string `json:"schedule"`
+
+	// How long the update window stays open after the schedule fired.
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Update window duration",order=37,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	Duration metav1.Duration `json:"duration"`
+
+	// IANA time zone the schedule is evaluated in, for example Europe/Vienna (the default value is: UTC).
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Update window time zone",order=38,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
 type DynaKubeValueSource struct { // nolint:revive
 	// Custom properties value.
 	// +nullable
@@ -65,6 +202,7 @@ type DynaKubeValueSource struct { // nolint:revive
 // +kubebuilder:resource:path=dynakubes,scope=Namespaced,categories=dynatrace
 // +kubebuilder:printcolumn:name="ApiUrl",type=string,JSONPath=`.spec.apiUrl`
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Monitored Hosts",type=string,JSONPath=`.status.oneAgent.monitoredHosts`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +operator-sdk:csv:customresourcedefinitions:displayName="Dynatrace DynaKube"
 // +operator-sdk:csv:customresourcedefinitions:resources={{StatefulSet,v1,},{DaemonSet,v1,},{Pod,v1,}}
@@ -92,6 +230,11 @@ type DynaKubeSpec struct { // nolint:revive
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tenant specific secrets",order=2,xDescriptors="urn:alm:descriptor:io.kubernetes:Secret"
 	Tokens string `json:"tokens,omitempty"`
 
+	// Alternative source of the tokens used for connecting to Dynatrace, takes precedence over tokens.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Token source",order=2,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	TokenSource *TokenSourceSpec `json:"tokenSource,omitempty"`
+
 	// Disable certificate check for the connection between Dynatrace Operator and the Dynatrace Cluster.
 	// Set to true if you want to skip certification validation checks.
 	// +optional
@@ -134,6 +277,37 @@ type DynaKubeSpec struct { // nolint:revive
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace Selector",order=17,xDescriptors="urn:alm:descriptor:com.tectonic.ui:selector:core:v1:Namespace"
 	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 
+	// Restricts automatic updates of OneAgent, code modules and ActiveGate to recurring maintenance windows.
+	// Newer versions found outside of a window are reported in the status and rolled out once the next window opens.
+	// Custom images and versions are applied right away.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Update window",order=18,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	UpdateWindow *UpdateWindowSpec `json:"updateWindow,omitempty"`
+
+	// Mirrors the OneAgent, code modules and ActiveGate images and the OneAgent installers into an in-cluster registry,
+	// for clusters which can reach Dynatrace only from time to time. The deployed components are pulled from the mirror.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mirror",order=19,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Mirror *MirrorSpec `json:"mirror,omitempty"`
+
+	// Adds selected namespace labels, pod labels and pod annotations to the metadata enrichment files
+	// and the OneAgent container configuration of injected pods.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Metadata enrichment",order=20,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	MetadataEnrichment *MetadataEnrichmentSpec `json:"metadataEnrichment,omitempty"`
+
+	// Restarts the Deployments, StatefulSets and DaemonSets of injected pods once a new code modules version is rolled out,
+	// so the pods don't keep running the old code modules until they are restarted otherwise.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rollout on upgrade",order=21,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	RolloutOnUpgrade *RolloutOnUpgradeSpec `json:"rolloutOnUpgrade,omitempty"`
+
+	// Configures the OTLP exporter of OpenTelemetry SDKs in injected pods to export traces, metrics and logs to Dynatrace.
+	// Pods can opt out with the otel.dynatrace.com/inject annotation.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OpenTelemetry",order=22,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	OpenTelemetry *OpenTelemetrySpec `json:"openTelemetry,omitempty"`
+
 	// General configuration about OneAgent instances.
 	// You can't enable more than one module (classicFullStack, cloudNativeFullStack, hostMonitoring, or applicationMonitoring).
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OneAgent",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
@@ -92,6 +92,7 @@ const (
 	AnnotationFeatureLabelVersionDetection = AnnotationFeaturePrefix + "label-version-detection"
 	AnnotationInjectionFailurePolicy       = AnnotationFeaturePrefix + "injection-failure-policy"
 	AnnotationFeatureInitContainerSeccomp  = AnnotationFeaturePrefix + "init-container-seccomp-profile"
+	AnnotationFeatureNativeSidecar         = AnnotationFeaturePrefix + "injection-native-sidecar"
 
 	// CSI
 	AnnotationFeatureMaxFailedCsiMountAttempts = AnnotationFeaturePrefix + "max-csi-mount-attempts"
@@ -121,12 +122,16 @@ const (
 const (
 	DefaultMaxFailedCsiMountAttempts  = 10
 	DefaultMinRequestThresholdMinutes = 15
+	DefaultOneAgentMaxUnavailable     = 1
+	DefaultInjectionFailurePolicy     = silentPhrase
+	DefaultSyntheticNodeType          = SyntheticNodeS
+	DefaultSyntheticReplicas          = 1
 )
 
 var (
 	log = logger.Factory.GetLogger("dynakube-api")
 
-	defaultSyntheticReplicas = int32(1)
+	defaultSyntheticReplicas = int32(DefaultSyntheticReplicas)
 )
 
 func (dk *DynaKube) getDisableFlagWithDeprecatedAnnotation(annotation string, deprecatedAnnotation string) bool {
@@ -184,7 +189,7 @@ func (dk *DynaKube) FeatureApiRequestThreshold() time.Duration {
 
 // FeatureOneAgentMaxUnavailable is a feature flag to configure maxUnavailable on the OneAgent DaemonSets rolling upgrades.
 func (dk *DynaKube) FeatureOneAgentMaxUnavailable() int {
-	return dk.getFeatureFlagInt(AnnotationFeatureOneAgentMaxUnavailable, 1)
+	return dk.getFeatureFlagInt(AnnotationFeatureOneAgentMaxUnavailable, DefaultOneAgentMaxUnavailable)
 }
 
 // FeatureDisableWebhookReinvocationPolicy disables the reinvocation for the Operator's webhooks.
@@ -335,7 +340,7 @@ func (dk *DynaKube) FeatureReadOnlyCsiVolume() bool {
 func (dk *DynaKube) FeatureSyntheticNodeType() string {
 	node := dk.getFeatureFlagRaw(AnnotationFeatureSyntheticNodeType)
 	if node == "" {
-		return SyntheticNodeS
+		return DefaultSyntheticNodeType
 	}
 	return node
 }
@@ -351,7 +356,7 @@ func (dk *DynaKube) FeatureInjectionFailurePolicy() string {
 	if dk.getFeatureFlagRaw(AnnotationInjectionFailurePolicy) == forcePhrase {
 		return forcePhrase
 	}
-	return silentPhrase
+	return DefaultInjectionFailurePolicy
 }
 
 func (dk *DynaKube) FeaturePublicRegistry() bool {
@@ -375,3 +380,9 @@ func (dk *DynaKube) FeatureSyntheticReplicas() int32 {
 func (dk *DynaKube) FeatureInitContainerSeccomp() bool {
 	return dk.getFeatureFlagRaw(AnnotationFeatureInitContainerSeccomp) == truePhrase
 }
+
+// FeatureNativeSidecar is a feature flag to additionally inject a restartable init container (native sidecar),
+// that keeps the configuration of the injected containers up to date. Only takes effect on kubernetes 1.29+.
+func (dk *DynaKube) FeatureNativeSidecar() bool {
+	return dk.getFeatureFlagRaw(AnnotationFeatureNativeSidecar) == truePhrase
+}
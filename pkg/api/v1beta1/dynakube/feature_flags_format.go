@@ -0,0 +1,126 @@
+package dynakube
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+var ErrUnknownFeatureFlag = errors.New("unknown feature-flag")
+
+type featureFlagFormat func(value string) error
+
+var featureFlagFormats = map[string]featureFlagFormat{
+	AnnotationFeaturePublicRegistry: boolFormat,
+
+	AnnotationFeatureDisableActiveGateUpdates:             boolFormat,
+	AnnotationFeatureDisableActiveGateRawImage:            boolFormat,
+	AnnotationFeatureActiveGateUpdates:                    boolFormat,
+	AnnotationFeatureActiveGateRawImage:                   boolFormat,
+	AnnotationFeatureActiveGateAuthToken:                  boolFormat,
+	AnnotationFeatureActiveGateAppArmor:                   boolFormat,
+	AnnotationFeatureActiveGateReadOnlyFilesystem:         boolFormat,
+	AnnotationFeatureAutomaticK8sApiMonitoring:            boolFormat,
+	AnnotationFeatureAutomaticK8sApiMonitoringClusterName: stringFormat,
+	AnnotationFeatureK8sAppEnabled:                        boolFormat,
+	AnnotationFeatureActiveGateIgnoreProxy:                boolFormat,
+	AnnotationFeatureCustomSyntheticImage:                 stringFormat,
+
+	AnnotationFeatureDisableHostsRequests: boolFormat,
+	AnnotationFeatureHostsRequests:        boolFormat,
+	AnnotationFeatureNoProxy:              stringFormat,
+	AnnotationFeatureApiRequestThreshold:  nonNegativeIntFormat,
+
+	AnnotationFeatureDisableReadOnlyOneAgent:        boolFormat,
+	AnnotationFeatureReadOnlyOneAgent:               boolFormat,
+	AnnotationFeatureMultipleOsAgentsOnNode:         boolFormat,
+	AnnotationFeatureOneAgentMaxUnavailable:         positiveIntFormat,
+	AnnotationFeatureOneAgentIgnoreProxy:            boolFormat,
+	AnnotationFeatureOneAgentInitialConnectRetry:    intFormat,
+	AnnotationFeatureRunOneAgentContainerPrivileged: boolFormat,
+	AnnotationFeatureOneAgentSecCompProfile:         stringFormat,
+
+	AnnotationFeatureDisableWebhookReinvocationPolicy: boolFormat,
+	AnnotationFeatureDisableMetadataEnrichment:        boolFormat,
+	AnnotationFeatureWebhookReinvocationPolicy:        boolFormat,
+	AnnotationFeatureMetadataEnrichment:               boolFormat,
+	AnnotationFeatureIgnoreUnknownState:               boolFormat,
+	AnnotationFeatureIgnoredNamespaces:                stringListFormat,
+	AnnotationFeatureAutomaticInjection:               boolFormat,
+	AnnotationFeatureLabelVersionDetection:            boolFormat,
+	AnnotationInjectionFailurePolicy:                  oneOfFormat(silentPhrase, failPhrase, forcePhrase),
+	AnnotationFeatureInitContainerSeccomp:             boolFormat,
+	AnnotationFeatureNativeSidecar:                    boolFormat,
+
+	AnnotationFeatureMaxFailedCsiMountAttempts: nonNegativeIntFormat,
+	AnnotationFeatureReadOnlyCsiVolume:         boolFormat,
+
+	AnnotationFeatureSyntheticLocationEntityId: stringFormat,
+	AnnotationFeatureSyntheticNodeType:         stringFormat, // validated together with the synthetic capability
+	AnnotationFeatureSyntheticReplicas:         nonNegativeIntFormat,
+}
+
+// ValidateFeatureFlag checks that the given annotation is a known feature-flag and that its value can be parsed.
+// ErrUnknownFeatureFlag is returned for annotations that are not known to the operator.
+func ValidateFeatureFlag(annotation string, value string) error {
+	format, ok := featureFlagFormats[annotation]
+	if !ok {
+		return ErrUnknownFeatureFlag
+	}
+	return format(value)
+}
+
+func boolFormat(value string) error {
+	if value != truePhrase && value != falsePhrase {
+		return errors.Errorf("expected '%s' or '%s', got '%s'", truePhrase, falsePhrase, value)
+	}
+	return nil
+}
+
+func stringFormat(_ string) error {
+	return nil
+}
+
+func intFormat(value string) error {
+	_, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.Errorf("expected an integer, got '%s'", value)
+	}
+	return nil
+}
+
+func nonNegativeIntFormat(value string) error {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return errors.Errorf("expected a non-negative integer, got '%s'", value)
+	}
+	return nil
+}
+
+func positiveIntFormat(value string) error {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		return errors.Errorf("expected a positive integer, got '%s'", value)
+	}
+	return nil
+}
+
+func stringListFormat(value string) error {
+	var list []string
+	if err := json.Unmarshal([]byte(value), &list); err != nil {
+		return errors.Errorf("expected a JSON list of strings, got '%s'", value)
+	}
+	return nil
+}
+
+func oneOfFormat(allowed ...string) featureFlagFormat {
+	return func(value string) error {
+		for _, allowedValue := range allowed {
+			if value == allowedValue {
+				return nil
+			}
+		}
+		return errors.Errorf("expected one of %v, got '%s'", allowed, value)
+	}
+}
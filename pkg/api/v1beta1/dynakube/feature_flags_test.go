@@ -299,6 +299,7 @@ func TestDefaultEnabledFeatureFlags(t *testing.T) {
 	assert.False(t, dynakube.FeatureDisableWebhookReinvocationPolicy())
 	assert.False(t, dynakube.FeatureDisableMetadataEnrichment())
 	assert.False(t, dynakube.FeatureLabelVersionDetection())
+	assert.False(t, dynakube.FeatureNativeSidecar())
 }
 
 func TestInjectionFailurePolicy(t *testing.T) {
@@ -319,3 +320,26 @@ func TestInjectionFailurePolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateFeatureFlag(t *testing.T) {
+	t.Run("unknown feature-flag", func(t *testing.T) {
+		err := ValidateFeatureFlag(AnnotationFeaturePrefix+"oneagent-max-unavailble", "1")
+		require.ErrorIs(t, err, ErrUnknownFeatureFlag)
+	})
+	t.Run("valid values", func(t *testing.T) {
+		require.NoError(t, ValidateFeatureFlag(AnnotationFeatureAutomaticInjection, "false"))
+		require.NoError(t, ValidateFeatureFlag(AnnotationFeatureOneAgentMaxUnavailable, "3"))
+		require.NoError(t, ValidateFeatureFlag(AnnotationFeatureOneAgentInitialConnectRetry, "-1"))
+		require.NoError(t, ValidateFeatureFlag(AnnotationFeatureIgnoredNamespaces, `["^kube-.*"]`))
+		require.NoError(t, ValidateFeatureFlag(AnnotationInjectionFailurePolicy, "force"))
+		require.NoError(t, ValidateFeatureFlag(AnnotationFeatureNoProxy, ""))
+	})
+	t.Run("malformed values", func(t *testing.T) {
+		assert.Error(t, ValidateFeatureFlag(AnnotationFeatureAutomaticInjection, "False"))
+		assert.Error(t, ValidateFeatureFlag(AnnotationFeatureOneAgentMaxUnavailable, "0"))
+		assert.Error(t, ValidateFeatureFlag(AnnotationFeatureMaxFailedCsiMountAttempts, "-1"))
+		assert.Error(t, ValidateFeatureFlag(AnnotationFeatureSyntheticReplicas, "two"))
+		assert.Error(t, ValidateFeatureFlag(AnnotationFeatureIgnoredNamespaces, "kube-system"))
+		assert.Error(t, ValidateFeatureFlag(AnnotationInjectionFailurePolicy, "ignore"))
+	})
+}
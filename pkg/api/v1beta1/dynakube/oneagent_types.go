@@ -2,6 +2,7 @@ package dynakube
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type OneAgentMode string
@@ -27,8 +28,94 @@ type OneAgentSpec struct {
 	// Doesn't inject into application pods.
 	// +nullable
 	HostMonitoring *HostInjectSpec `json:"hostMonitoring,omitempty"`
+
+	// Stages OneAgent version upgrades, the new version is deployed to canary nodes first and only rolled out to all nodes
+	// if the canary OneAgents stay healthy for the bake time, otherwise it is rolled back to the previous version.
+	// +nullable
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rollout",order=28,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	Rollout *OneAgentRolloutSpec `json:"rollout,omitempty"`
+
+	// Node pools with their own OneAgent configuration, each profile gets a separate OneAgent DaemonSet.
+	// A node uses the first profile whose node selector it matches, nodes matching no profile use the settings of the OneAgent mode.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Node profiles",order=29,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:hidden"}
+	NodeProfiles []OneAgentNodeProfile `json:"nodeProfiles,omitempty"`
+}
+
+type OneAgentNodeProfile struct {
+	// Name of the profile, it is appended to the name of the OneAgent DaemonSet of the profile.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=32
+	Name string `json:"name"`
+
+	// Nodes with all of these labels use the profile.
+	// +kubebuilder:validation:MinProperties=1
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// Resource settings for the OneAgent container on the nodes of the profile, they replace the oneAgentResources of the OneAgent mode.
+	// +optional
+	OneAgentResources *corev1.ResourceRequirements `json:"oneAgentResources,omitempty"`
+
+	// Arguments added to the OneAgent installer arguments of the OneAgent mode on the nodes of the profile.
+	// +optional
+	// +listType=set
+	Args []string `json:"args,omitempty"`
+
+	// Environment variables for the OneAgent pods on the nodes of the profile, they take precedence over the env of the OneAgent mode.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Tolerations added to the tolerations of the OneAgent mode on the nodes of the profile.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+type OneAgentRolloutSpec struct {
+	// Nodes which get a new OneAgent version first.
+	// +kubebuilder:validation:Required
+	Canary OneAgentCanarySpec `json:"canary"`
+
+	// How long the canary OneAgents have to stay healthy before the new version is rolled out to all nodes.
+	// Defaults to 10m.
+	// +optional
+	BakeTime *metav1.Duration `json:"bakeTime,omitempty"`
+
+	// How long the canary OneAgents may take to become ready before the new version is rolled back.
+	// Defaults to 15m.
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+}
+
+type OneAgentCanarySpec struct {
+	// Selects the canary nodes by their labels.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Selects the given percentage of the nodes running a OneAgent as canary nodes, at least one node is selected.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Percentage int `json:"percentage,omitempty"`
 }
 
+// WellKnownTaint names a group of commonly used node taints the OneAgent can tolerate automatically
+// +kubebuilder:validation:Enum=ControlPlane;GPU;Spot;CriticalAddonsOnly
+type WellKnownTaint string
+
+const (
+	// WellKnownTaintControlPlane tolerates the taints of control plane nodes
+	WellKnownTaintControlPlane WellKnownTaint = "ControlPlane"
+	// WellKnownTaintGPU tolerates the taints of GPU node pools
+	WellKnownTaintGPU WellKnownTaint = "GPU"
+	// WellKnownTaintSpot tolerates the taints of spot and preemptible node pools
+	WellKnownTaintSpot WellKnownTaint = "Spot"
+	// WellKnownTaintCriticalAddonsOnly tolerates the taint of node pools reserved for critical add-ons
+	WellKnownTaintCriticalAddonsOnly WellKnownTaint = "CriticalAddonsOnly"
+)
+
 type CloudNativeFullStackSpec struct {
 	HostInjectSpec   `json:",inline"`
 	AppInjectionSpec `json:",inline"`
@@ -51,6 +138,12 @@ type HostInjectSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tolerations",order=18,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:hidden"}
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// Adds tolerations for well-known taints to the OneAgent DaemonSet, so nodes carrying them are monitored as well.
+	// Supported are ControlPlane, GPU, Spot and CriticalAddonsOnly.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Automatic tolerations",order=19,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:hidden"}
+	AutoTolerations []WellKnownTaint `json:"autoTolerations,omitempty"`
+
 	// Disables automatic restarts of OneAgent pods in case a new version is available (https://www.dynatrace.com/support/help/setup-and-configuration/setup-on-container-platforms/kubernetes/get-started-with-kubernetes-monitoring#disable-auto).
 	// Enabled by default.
 	// +optional
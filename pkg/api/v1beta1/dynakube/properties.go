@@ -20,9 +20,12 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/updatewindow"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,6 +43,9 @@ const (
 
 	defaultActiveGateImage = "/linux/activegate:latest"
 	defaultSyntheticImage  = "linux/dynatrace-synthetic"
+
+	defaultOneAgentRolloutBakeTime         = 10 * time.Minute
+	defaultOneAgentRolloutProgressDeadline = 15 * time.Minute
 )
 
 // ApiUrl is a getter for dk.Spec.APIURL
@@ -69,22 +75,22 @@ func (dk *DynaKube) NeedsActiveGate() bool {
 
 // ApplicationMonitoringMode returns true when application only section is used.
 func (dk *DynaKube) ApplicationMonitoringMode() bool {
-	return dk.Spec.OneAgent != OneAgentSpec{} && dk.Spec.OneAgent.ApplicationMonitoring != nil
+	return dk.Spec.OneAgent.ApplicationMonitoring != nil
 }
 
 // CloudNativeFullstackMode returns true when cloud native fullstack section is used.
 func (dk *DynaKube) CloudNativeFullstackMode() bool {
-	return dk.Spec.OneAgent != OneAgentSpec{} && dk.Spec.OneAgent.CloudNativeFullStack != nil
+	return dk.Spec.OneAgent.CloudNativeFullStack != nil
 }
 
 // HostMonitoringMode returns true when host monitoring section is used.
 func (dk *DynaKube) HostMonitoringMode() bool {
-	return dk.Spec.OneAgent != OneAgentSpec{} && dk.Spec.OneAgent.HostMonitoring != nil
+	return dk.Spec.OneAgent.HostMonitoring != nil
 }
 
 // ClassicFullStackMode returns true when host monitoring section is used.
 func (dk *DynaKube) ClassicFullStackMode() bool {
-	return dk.Spec.OneAgent != OneAgentSpec{} && dk.Spec.OneAgent.ClassicFullStack != nil
+	return dk.Spec.OneAgent.ClassicFullStack != nil
 }
 
 // NeedsOneAgent returns true when a feature requires OneAgent instances.
@@ -96,6 +102,20 @@ func (dk *DynaKube) OneAgentDaemonsetName() string {
 	return fmt.Sprintf("%s-%s", dk.Name, PodNameOsAgent)
 }
 
+// OneAgentNodeProfileDaemonsetName is the name of the OneAgent DaemonSet of a node profile
+func (dk *DynaKube) OneAgentNodeProfileDaemonsetName(profileName string) string {
+	return fmt.Sprintf("%s-%s", dk.OneAgentDaemonsetName(), profileName)
+}
+
+// OneAgentDaemonsetNames returns the names of all OneAgent DaemonSets, the one for the nodes without a node profile comes first
+func (dk *DynaKube) OneAgentDaemonsetNames() []string {
+	names := []string{dk.OneAgentDaemonsetName()}
+	for _, profile := range dk.Spec.OneAgent.NodeProfiles {
+		names = append(names, dk.OneAgentNodeProfileDaemonsetName(profile.Name))
+	}
+	return names
+}
+
 func (dk *DynaKube) DeprecatedActiveGateMode() bool {
 	return dk.Spec.KubernetesMonitoring.Enabled || dk.Spec.Routing.Enabled
 }
@@ -181,6 +201,14 @@ func (dk *DynaKube) ShouldAutoUpdateOneAgent() bool {
 	}
 }
 
+// UpdateWindow returns the window automatic updates are restricted to, it is nil if updates are allowed at any time.
+func (dk *DynaKube) UpdateWindow() (*updatewindow.Window, error) {
+	if dk.Spec.UpdateWindow == nil {
+		return nil, nil
+	}
+	return updatewindow.New(dk.Spec.UpdateWindow.Schedule, dk.Spec.UpdateWindow.Duration.Duration, dk.Spec.UpdateWindow.TimeZone)
+}
+
 // ActivegateTenantSecret returns the name of the secret containing tenant UUID, token and communication endpoints for ActiveGate
 func (dk *DynaKube) ActivegateTenantSecret() string {
 	return dk.Name + ActiveGateTenantSecretSuffix
@@ -267,10 +295,31 @@ func (dk *DynaKube) NodeSelector() map[string]string {
 	return nil
 }
 
-// ActiveGateImage provides the image reference set in Status for the ActiveGate.
+// NeedsStagedOneAgentRollout returns true when OneAgent version upgrades are rolled out to canary nodes first.
+func (dk *DynaKube) NeedsStagedOneAgentRollout() bool {
+	return dk.NeedsOneAgent() && dk.Spec.OneAgent.Rollout != nil
+}
+
+// OneAgentRolloutBakeTime is the time the canary OneAgents have to stay healthy, defaults to 10 minutes.
+func (dk *DynaKube) OneAgentRolloutBakeTime() time.Duration {
+	if dk.Spec.OneAgent.Rollout == nil || dk.Spec.OneAgent.Rollout.BakeTime == nil {
+		return defaultOneAgentRolloutBakeTime
+	}
+	return dk.Spec.OneAgent.Rollout.BakeTime.Duration
+}
+
+// OneAgentRolloutProgressDeadline is the time the canary OneAgents may take to become ready, defaults to 15 minutes.
+func (dk *DynaKube) OneAgentRolloutProgressDeadline() time.Duration {
+	if dk.Spec.OneAgent.Rollout == nil || dk.Spec.OneAgent.Rollout.ProgressDeadline == nil {
+		return defaultOneAgentRolloutProgressDeadline
+	}
+	return dk.Spec.OneAgent.Rollout.ProgressDeadline.Duration
+}
+
+// ActiveGateImage provides the image reference set in Status for the ActiveGate, pointing to the mirror once it is mirrored.
 // Format: repo@sha256:digest
 func (dk *DynaKube) ActiveGateImage() string {
-	return dk.Status.ActiveGate.ImageID
+	return dk.mirroredImage(dk.Status.ActiveGate.ImageID, dk.Status.Mirror.ActiveGate)
 }
 
 // DefaultActiveGateImage provides the image reference for the ActiveGate from tenant registry.
@@ -334,10 +383,23 @@ func (dk *DynaKube) CodeModulesVersion() string {
 	return dk.Status.CodeModules.Version
 }
 
-// CodeModulesImage provides the image reference set in Status for the CodeModules.
+// CodeModulesImage provides the image reference set in Status for the CodeModules, pointing to the mirror once it is mirrored.
 // Format: repo@sha256:digest
 func (dk *DynaKube) CodeModulesImage() string {
-	return dk.Status.CodeModules.ImageID
+	return dk.mirroredImage(dk.Status.CodeModules.ImageID, dk.Status.Mirror.CodeModules)
+}
+
+// CodeModulesImageForArch provides the CodeModules image for the given architecture (kubernetes.io/arch), if it is a multi-arch image.
+func (dk *DynaKube) CodeModulesImageForArch(arch string) string {
+	codeModules := dk.Status.CodeModules.VersionStatus
+	// the mirror holds the whole manifest list, so the per architecture digests apply to the mirrored image as well
+	codeModules.ImageID = dk.CodeModulesImage()
+	return codeModules.ImageIDForArch(arch)
+}
+
+// IsCodeModulesArtifact checks if the CodeModules are packaged as OCI artifact instead of as container image.
+func (dk *DynaKube) IsCodeModulesArtifact() bool {
+	return dk.Status.CodeModules.Type == status.ArtifactImageType
 }
 
 // CustomCodeModulesImage provides the image reference for the CodeModules provided in the Spec.
@@ -358,10 +420,80 @@ func (dk *DynaKube) CustomCodeModulesVersion() string {
 	return dk.CustomOneAgentVersion()
 }
 
-// OneAgentImage provides the image reference set in Status for the OneAgent.
+// OneAgentImage provides the image reference set in Status for the OneAgent, pointing to the mirror once it is mirrored.
 // Format: repo@sha256:digest
 func (dk *DynaKube) OneAgentImage() string {
-	return dk.Status.OneAgent.ImageID
+	return dk.mirroredImage(dk.Status.OneAgent.ImageID, dk.Status.Mirror.OneAgent)
+}
+
+// mirroredImage replaces the image with its copy in the mirror, as long as the mirror holds the current image
+func (dk *DynaKube) mirroredImage(imageID string, mirrored MirroredArtifact) string {
+	if dk.Spec.Mirror == nil || imageID == "" || mirrored.Source != imageID || mirrored.Image == "" {
+		return imageID
+	}
+	return mirrored.Image
+}
+
+// MirroredInstaller provides the OneAgent installers in the mirror for the current code modules version, it is empty if they aren't mirrored (yet).
+// The installer for an architecture is tagged with the architecture as suffix.
+func (dk *DynaKube) MirroredInstaller() string {
+	installer := dk.Status.Mirror.Installer
+	if dk.Spec.Mirror == nil || dk.CodeModulesVersion() == "" || installer.Source != dk.CodeModulesVersion() {
+		return ""
+	}
+	return installer.Image
+}
+
+// MirrorSyncWindow returns the window syncing the mirror is restricted to, it is nil if the mirror can be synced at any time.
+func (dk *DynaKube) MirrorSyncWindow() (*updatewindow.Window, error) {
+	if dk.Spec.Mirror == nil || dk.Spec.Mirror.SyncWindow == nil {
+		return nil, nil
+	}
+	syncWindow := dk.Spec.Mirror.SyncWindow
+	return updatewindow.New(syncWindow.Schedule, syncWindow.Duration.Duration, syncWindow.TimeZone)
+}
+
+// MetadataEnrichmentAttributes provides the attributes selected by the metadataEnrichment rules from the labels of the namespace and the labels and annotations of the pod.
+func (dk *DynaKube) MetadataEnrichmentAttributes(namespace corev1.Namespace, pod corev1.Pod) map[string]string {
+	if dk.Spec.MetadataEnrichment == nil {
+		return nil
+	}
+
+	attributes := make(map[string]string)
+	for _, rule := range dk.Spec.MetadataEnrichment.Rules {
+		var source map[string]string
+		switch rule.Type {
+		case EnrichmentNamespaceLabelRule:
+			source = namespace.Labels
+		case EnrichmentPodLabelRule:
+			source = pod.Labels
+		case EnrichmentPodAnnotationRule:
+			source = pod.Annotations
+		}
+
+		for _, key := range rule.Keys {
+			if value, ok := source[key]; ok {
+				attributes[rule.AttributePrefix()+key] = value
+			}
+		}
+	}
+	return attributes
+}
+
+// AttributePrefix provides the prefix of the attribute names of the rule, it defaults to a prefix depending on the type of the rule.
+func (rule EnrichmentRule) AttributePrefix() string {
+	if rule.Prefix != "" {
+		return rule.Prefix
+	}
+	switch rule.Type {
+	case EnrichmentNamespaceLabelRule:
+		return "k8s.namespace.label."
+	case EnrichmentPodLabelRule:
+		return "k8s.pod.label."
+	case EnrichmentPodAnnotationRule:
+		return "k8s.pod.annotation."
+	}
+	return ""
 }
 
 // OneAgentVersion provides version set in Status for the OneAgent.
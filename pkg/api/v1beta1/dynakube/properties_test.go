@@ -29,7 +29,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-const testAPIURL = "http://test-endpoint/api"
+const (
+	testAPIURL    = "http://test-endpoint/api"
+	testName      = "test-name"
+	testNamespace = "test-namespace"
+)
 
 func TestDefaultActiveGateImage(t *testing.T) {
 	t.Run(`ActiveGateImage with no API URL`, func(t *testing.T) {
@@ -277,6 +281,52 @@ func TestCodeModulesVersion(t *testing.T) {
 	})
 }
 
+func TestIsCodeModulesArtifact(t *testing.T) {
+	dk := DynaKube{}
+	assert.False(t, dk.IsCodeModulesArtifact())
+
+	dk.Status.CodeModules.Type = status.ArtifactImageType
+	assert.True(t, dk.IsCodeModulesArtifact())
+}
+
+func TestMetadataEnrichmentAttributes(t *testing.T) {
+	namespace := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"team": "platform", "cost-center": "1234"},
+		},
+	}
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{"team": "checkout", "app": "shop"},
+			Annotations: map[string]string{"owner": "jane", "ignored": "value"},
+		},
+	}
+
+	t.Run(`no rules`, func(t *testing.T) {
+		dk := DynaKube{}
+		assert.Empty(t, dk.MetadataEnrichmentAttributes(namespace, pod))
+	})
+	t.Run(`only allowed keys with prefixes`, func(t *testing.T) {
+		dk := DynaKube{
+			Spec: DynaKubeSpec{
+				MetadataEnrichment: &MetadataEnrichmentSpec{
+					Rules: []EnrichmentRule{
+						{Type: EnrichmentNamespaceLabelRule, Keys: []string{"team", "cost-center"}},
+						{Type: EnrichmentPodLabelRule, Keys: []string{"team", "missing"}},
+						{Type: EnrichmentPodAnnotationRule, Keys: []string{"owner"}, Prefix: "dt.owner."},
+					},
+				},
+			},
+		}
+		assert.Equal(t, map[string]string{
+			"k8s.namespace.label.team":        "platform",
+			"k8s.namespace.label.cost-center": "1234",
+			"k8s.pod.label.team":              "checkout",
+			"dt.owner.owner":                  "jane",
+		}, dk.MetadataEnrichmentAttributes(namespace, pod))
+	})
+}
+
 func TestGetRawImageTag(t *testing.T) {
 	t.Run(`with tag`, func(t *testing.T) {
 		expectedTag := "test"
@@ -344,12 +344,42 @@ func (in *DynaKubeProxy) DeepCopy() *DynaKubeProxy {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynaKubeSpec) DeepCopyInto(out *DynaKubeSpec) {
 	*out = *in
+	if in.TokenSource != nil {
+		in, out := &in.TokenSource, &out.TokenSource
+		*out = new(TokenSourceSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Proxy != nil {
 		in, out := &in.Proxy, &out.Proxy
 		*out = new(DynaKubeProxy)
 		**out = **in
 	}
 	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	if in.UpdateWindow != nil {
+		in, out := &in.UpdateWindow, &out.UpdateWindow
+		*out = new(UpdateWindowSpec)
+		**out = **in
+	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(MirrorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetadataEnrichment != nil {
+		in, out := &in.MetadataEnrichment, &out.MetadataEnrichment
+		*out = new(MetadataEnrichmentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloutOnUpgrade != nil {
+		in, out := &in.RolloutOnUpgrade, &out.RolloutOnUpgrade
+		*out = new(RolloutOnUpgradeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpenTelemetry != nil {
+		in, out := &in.OpenTelemetry, &out.OpenTelemetry
+		*out = new(OpenTelemetrySpec)
+		**out = **in
+	}
 	in.OneAgent.DeepCopyInto(&out.OneAgent)
 	in.ActiveGate.DeepCopyInto(&out.ActiveGate)
 	in.Routing.DeepCopyInto(&out.Routing)
@@ -386,6 +416,8 @@ func (in *DynaKubeStatus) DeepCopyInto(out *DynaKubeStatus) {
 	in.CodeModules.DeepCopyInto(&out.CodeModules)
 	in.Synthetic.DeepCopyInto(&out.Synthetic)
 	in.DynatraceApi.DeepCopyInto(&out.DynatraceApi)
+	in.Mirror.DeepCopyInto(&out.Mirror)
+	in.Injection.DeepCopyInto(&out.Injection)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynaKubeStatus.
@@ -417,6 +449,7 @@ func (in *DynaKubeValueSource) DeepCopy() *DynaKubeValueSource {
 func (in *DynatraceApiStatus) DeepCopyInto(out *DynatraceApiStatus) {
 	*out = *in
 	in.LastTokenScopeRequest.DeepCopyInto(&out.LastTokenScopeRequest)
+	in.LastTokenRotation.DeepCopyInto(&out.LastTokenRotation)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynatraceApiStatus.
@@ -429,6 +462,41 @@ func (in *DynatraceApiStatus) DeepCopy() *DynatraceApiStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnrichmentRule) DeepCopyInto(out *EnrichmentRule) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnrichmentRule.
+func (in *EnrichmentRule) DeepCopy() *EnrichmentRule {
+	if in == nil {
+		return nil
+	}
+	out := new(EnrichmentRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileTokenSource) DeepCopyInto(out *FileTokenSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileTokenSource.
+func (in *FileTokenSource) DeepCopy() *FileTokenSource {
+	if in == nil {
+		return nil
+	}
+	out := new(FileTokenSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostInjectSpec) DeepCopyInto(out *HostInjectSpec) {
 	*out = *in
@@ -439,6 +507,11 @@ func (in *HostInjectSpec) DeepCopyInto(out *HostInjectSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AutoTolerations != nil {
+		in, out := &in.AutoTolerations, &out.AutoTolerations
+		*out = make([]WellKnownTaint, len(*in))
+		copy(*out, *in)
+	}
 	if in.AutoUpdate != nil {
 		in, out := &in.AutoUpdate, &out.AutoUpdate
 		*out = new(bool)
@@ -490,6 +563,49 @@ func (in *HostInjectSpec) DeepCopy() *HostInjectSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectionCounts) DeepCopyInto(out *InjectionCounts) {
+	*out = *in
+	if in.Skipped != nil {
+		in, out := &in.Skipped, &out.Skipped
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectionCounts.
+func (in *InjectionCounts) DeepCopy() *InjectionCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectionCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectionStatus) DeepCopyInto(out *InjectionStatus) {
+	*out = *in
+	in.Pods.DeepCopyInto(&out.Pods)
+	if in.FailedNamespaces != nil {
+		in, out := &in.FailedNamespaces, &out.FailedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectionStatus.
+func (in *InjectionStatus) DeepCopy() *InjectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubernetesMonitoringSpec) DeepCopyInto(out *KubernetesMonitoringSpec) {
 	*out = *in
@@ -506,6 +622,108 @@ func (in *KubernetesMonitoringSpec) DeepCopy() *KubernetesMonitoringSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataEnrichmentSpec) DeepCopyInto(out *MetadataEnrichmentSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]EnrichmentRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataEnrichmentSpec.
+func (in *MetadataEnrichmentSpec) DeepCopy() *MetadataEnrichmentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataEnrichmentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirrorSpec) DeepCopyInto(out *MirrorSpec) {
+	*out = *in
+	if in.SyncWindow != nil {
+		in, out := &in.SyncWindow, &out.SyncWindow
+		*out = new(UpdateWindowSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorSpec.
+func (in *MirrorSpec) DeepCopy() *MirrorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MirrorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirrorStatus) DeepCopyInto(out *MirrorStatus) {
+	*out = *in
+	out.OneAgent = in.OneAgent
+	out.CodeModules = in.CodeModules
+	out.ActiveGate = in.ActiveGate
+	out.Installer = in.Installer
+	if in.LastSyncTimestamp != nil {
+		in, out := &in.LastSyncTimestamp, &out.LastSyncTimestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorStatus.
+func (in *MirrorStatus) DeepCopy() *MirrorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MirrorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroredArtifact) DeepCopyInto(out *MirroredArtifact) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroredArtifact.
+func (in *MirroredArtifact) DeepCopy() *MirroredArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(MirroredArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OneAgentCanarySpec) DeepCopyInto(out *OneAgentCanarySpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OneAgentCanarySpec.
+func (in *OneAgentCanarySpec) DeepCopy() *OneAgentCanarySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OneAgentCanarySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OneAgentConnectionInfoStatus) DeepCopyInto(out *OneAgentConnectionInfoStatus) {
 	*out = *in
@@ -527,6 +745,25 @@ func (in *OneAgentConnectionInfoStatus) DeepCopy() *OneAgentConnectionInfoStatus
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OneAgentHostStatus) DeepCopyInto(out *OneAgentHostStatus) {
+	*out = *in
+	if in.LastSeen != nil {
+		in, out := &in.LastSeen, &out.LastSeen
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OneAgentHostStatus.
+func (in *OneAgentHostStatus) DeepCopy() *OneAgentHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OneAgentHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OneAgentInstance) DeepCopyInto(out *OneAgentInstance) {
 	*out = *in
@@ -542,6 +779,121 @@ func (in *OneAgentInstance) DeepCopy() *OneAgentInstance {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OneAgentNodeProfile) DeepCopyInto(out *OneAgentNodeProfile) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.OneAgentResources != nil {
+		in, out := &in.OneAgentResources, &out.OneAgentResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OneAgentNodeProfile.
+func (in *OneAgentNodeProfile) DeepCopy() *OneAgentNodeProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(OneAgentNodeProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OneAgentNodeProfileStatus) DeepCopyInto(out *OneAgentNodeProfileStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OneAgentNodeProfileStatus.
+func (in *OneAgentNodeProfileStatus) DeepCopy() *OneAgentNodeProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OneAgentNodeProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OneAgentRolloutSpec) DeepCopyInto(out *OneAgentRolloutSpec) {
+	*out = *in
+	in.Canary.DeepCopyInto(&out.Canary)
+	if in.BakeTime != nil {
+		in, out := &in.BakeTime, &out.BakeTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OneAgentRolloutSpec.
+func (in *OneAgentRolloutSpec) DeepCopy() *OneAgentRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OneAgentRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OneAgentRolloutStatus) DeepCopyInto(out *OneAgentRolloutStatus) {
+	*out = *in
+	if in.CanaryNodes != nil {
+		in, out := &in.CanaryNodes, &out.CanaryNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.CanaryReadyTimestamp != nil {
+		in, out := &in.CanaryReadyTimestamp, &out.CanaryReadyTimestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OneAgentRolloutStatus.
+func (in *OneAgentRolloutStatus) DeepCopy() *OneAgentRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OneAgentRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OneAgentSpec) DeepCopyInto(out *OneAgentSpec) {
 	*out = *in
@@ -565,6 +917,18 @@ func (in *OneAgentSpec) DeepCopyInto(out *OneAgentSpec) {
 		*out = new(HostInjectSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(OneAgentRolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeProfiles != nil {
+		in, out := &in.NodeProfiles, &out.NodeProfiles
+		*out = make([]OneAgentNodeProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OneAgentSpec.
@@ -592,7 +956,33 @@ func (in *OneAgentStatus) DeepCopyInto(out *OneAgentStatus) {
 		in, out := &in.LastInstanceStatusUpdate, &out.LastInstanceStatusUpdate
 		*out = (*in).DeepCopy()
 	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make(map[string]OneAgentHostStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.LastHostStatusUpdate != nil {
+		in, out := &in.LastHostStatusUpdate, &out.LastHostStatusUpdate
+		*out = (*in).DeepCopy()
+	}
+	if in.UncoveredNodes != nil {
+		in, out := &in.UncoveredNodes, &out.UncoveredNodes
+		*out = make([]OneAgentUncoveredNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeProfiles != nil {
+		in, out := &in.NodeProfiles, &out.NodeProfiles
+		*out = make([]OneAgentNodeProfileStatus, len(*in))
+		copy(*out, *in)
+	}
 	in.ConnectionInfoStatus.DeepCopyInto(&out.ConnectionInfoStatus)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(OneAgentRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OneAgentStatus.
@@ -605,6 +995,56 @@ func (in *OneAgentStatus) DeepCopy() *OneAgentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OneAgentUncoveredNode) DeepCopyInto(out *OneAgentUncoveredNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OneAgentUncoveredNode.
+func (in *OneAgentUncoveredNode) DeepCopy() *OneAgentUncoveredNode {
+	if in == nil {
+		return nil
+	}
+	out := new(OneAgentUncoveredNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenTelemetrySpec) DeepCopyInto(out *OpenTelemetrySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTelemetrySpec.
+func (in *OpenTelemetrySpec) DeepCopy() *OpenTelemetrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenTelemetrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutOnUpgradeSpec) DeepCopyInto(out *RolloutOnUpgradeSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutOnUpgradeSpec.
+func (in *RolloutOnUpgradeSpec) DeepCopy() *RolloutOnUpgradeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutOnUpgradeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoutingSpec) DeepCopyInto(out *RoutingSpec) {
 	*out = *in
@@ -636,3 +1076,39 @@ func (in *SyntheticStatus) DeepCopy() *SyntheticStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenSourceSpec) DeepCopyInto(out *TokenSourceSpec) {
+	*out = *in
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(FileTokenSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenSourceSpec.
+func (in *TokenSourceSpec) DeepCopy() *TokenSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateWindowSpec) DeepCopyInto(out *UpdateWindowSpec) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateWindowSpec.
+func (in *UpdateWindowSpec) DeepCopy() *UpdateWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,322 @@
+package dynakube
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts v1beta2 to v1beta1
+func (src *DynaKube) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*dynatracev1beta1.DynaKube)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	// DynakubeSpec
+	dst.Spec.APIURL = src.Spec.APIURL
+	dst.Spec.Tokens = src.Spec.Tokens
+	dst.Spec.TokenSource = src.Spec.TokenSource.DeepCopy()
+	dst.Spec.SkipCertCheck = src.Spec.SkipCertCheck
+	dst.Spec.Proxy = src.Spec.Proxy.DeepCopy()
+	dst.Spec.TrustedCAs = src.Spec.TrustedCAs
+	dst.Spec.NetworkZone = src.Spec.NetworkZone
+	dst.Spec.CustomPullSecret = src.Spec.CustomPullSecret
+	dst.Spec.EnableIstio = src.Spec.EnableIstio
+	src.Spec.NamespaceSelector.DeepCopyInto(&dst.Spec.NamespaceSelector)
+	dst.Spec.UpdateWindow = src.Spec.UpdateWindow.DeepCopy()
+	dst.Spec.Mirror = src.Spec.Mirror.DeepCopy()
+	dst.Spec.MetadataEnrichment = src.Spec.MetadataEnrichment.DeepCopy()
+	dst.Spec.RolloutOnUpgrade = src.Spec.RolloutOnUpgrade.DeepCopy()
+	dst.Spec.OpenTelemetry = src.Spec.OpenTelemetry.DeepCopy()
+	src.Spec.OneAgent.DeepCopyInto(&dst.Spec.OneAgent)
+	src.Spec.ActiveGate.DeepCopyInto(&dst.Spec.ActiveGate)
+	src.Spec.Routing.DeepCopyInto(&dst.Spec.Routing)
+	src.Spec.KubernetesMonitoring.DeepCopyInto(&dst.Spec.KubernetesMonitoring)
+
+	// Features, the defaults of the spec are the defaults of the annotations too, so a feature set to its default
+	// removes the annotation instead of writing it, a conflicting annotation mustn't take precedence over the feature
+	if src.Spec.Features != nil {
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		features := src.Spec.Features.expand()
+		for _, binding := range featureBindings {
+			value, ok := binding.toAnnotation(features)
+			if !ok {
+				continue
+			}
+			if binding.isDefault(value) {
+				delete(dst.Annotations, binding.annotation)
+			} else {
+				dst.Annotations[binding.annotation] = value
+			}
+		}
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+
+	// Status
+	src.Status.DeepCopyInto(&dst.Status)
+
+	return nil
+}
+
+// ConvertFrom converts v1beta1 to v1beta2
+func (dst *DynaKube) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*dynatracev1beta1.DynaKube)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	// DynakubeSpec
+	dst.Spec.APIURL = src.Spec.APIURL
+	dst.Spec.Tokens = src.Spec.Tokens
+	dst.Spec.TokenSource = src.Spec.TokenSource.DeepCopy()
+	dst.Spec.SkipCertCheck = src.Spec.SkipCertCheck
+	dst.Spec.Proxy = src.Spec.Proxy.DeepCopy()
+	dst.Spec.TrustedCAs = src.Spec.TrustedCAs
+	dst.Spec.NetworkZone = src.Spec.NetworkZone
+	dst.Spec.CustomPullSecret = src.Spec.CustomPullSecret
+	dst.Spec.EnableIstio = src.Spec.EnableIstio
+	src.Spec.NamespaceSelector.DeepCopyInto(&dst.Spec.NamespaceSelector)
+	dst.Spec.UpdateWindow = src.Spec.UpdateWindow.DeepCopy()
+	dst.Spec.Mirror = src.Spec.Mirror.DeepCopy()
+	dst.Spec.MetadataEnrichment = src.Spec.MetadataEnrichment.DeepCopy()
+	dst.Spec.RolloutOnUpgrade = src.Spec.RolloutOnUpgrade.DeepCopy()
+	dst.Spec.OpenTelemetry = src.Spec.OpenTelemetry.DeepCopy()
+	src.Spec.OneAgent.DeepCopyInto(&dst.Spec.OneAgent)
+	src.Spec.ActiveGate.DeepCopyInto(&dst.Spec.ActiveGate)
+	src.Spec.Routing.DeepCopyInto(&dst.Spec.Routing)
+	src.Spec.KubernetesMonitoring.DeepCopyInto(&dst.Spec.KubernetesMonitoring)
+
+	// Features, annotations which can't be represented in the spec are kept as they are.
+	// So are annotations set to the default, as ConvertTo leaves out defaults they would get lost otherwise.
+	features := (*FeaturesSpec)(nil).expand()
+	for _, binding := range featureBindings {
+		value, ok := dst.Annotations[binding.annotation]
+		if !ok || binding.isDefault(value) {
+			continue
+		}
+		if binding.fromAnnotation(features, value) {
+			delete(dst.Annotations, binding.annotation)
+		}
+	}
+	dst.Spec.Features = features.prune()
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	// Status
+	src.Status.DeepCopyInto(&dst.Status)
+
+	return nil
+}
+
+type featureBinding struct {
+	annotation     string
+	toAnnotation   func(features *FeaturesSpec) (string, bool)
+	fromAnnotation func(features *FeaturesSpec, value string) bool
+
+	// defaultValue is the value the operator uses if the annotation isn't set, it's also the default of the field in the spec
+	defaultValue *string
+}
+
+func (binding featureBinding) withDefault(defaultValue string) featureBinding {
+	binding.defaultValue = &defaultValue
+	return binding
+}
+
+func (binding featureBinding) isDefault(value string) bool {
+	return binding.defaultValue != nil && *binding.defaultValue == value
+}
+
+var featureBindings = []featureBinding{
+	boolFeature(dynatracev1beta1.AnnotationFeaturePublicRegistry, func(f *FeaturesSpec) **bool { return &f.PublicRegistry }),
+
+	boolFeature(dynatracev1beta1.AnnotationFeatureActiveGateUpdates, func(f *FeaturesSpec) **bool { return &f.ActiveGate.Updates }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureActiveGateRawImage, func(f *FeaturesSpec) **bool { return &f.ActiveGate.RawImage }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureActiveGateAuthToken, func(f *FeaturesSpec) **bool { return &f.ActiveGate.AuthToken }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureActiveGateAppArmor, func(f *FeaturesSpec) **bool { return &f.ActiveGate.AppArmor }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureActiveGateReadOnlyFilesystem, func(f *FeaturesSpec) **bool { return &f.ActiveGate.ReadOnlyFilesystem }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureActiveGateIgnoreProxy, func(f *FeaturesSpec) **bool { return &f.ActiveGate.IgnoreProxy }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureAutomaticK8sApiMonitoring, func(f *FeaturesSpec) **bool { return &f.ActiveGate.AutomaticKubernetesApiMonitoring }),
+	stringFeature(dynatracev1beta1.AnnotationFeatureAutomaticK8sApiMonitoringClusterName, func(f *FeaturesSpec) **string { return &f.ActiveGate.AutomaticKubernetesApiMonitoringClusterName }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureK8sAppEnabled, func(f *FeaturesSpec) **bool { return &f.ActiveGate.K8sAppEnabled }),
+
+	boolFeature(dynatracev1beta1.AnnotationFeatureHostsRequests, func(f *FeaturesSpec) **bool { return &f.DynatraceApi.HostsRequests }),
+	stringFeature(dynatracev1beta1.AnnotationFeatureNoProxy, func(f *FeaturesSpec) **string { return &f.DynatraceApi.NoProxy }),
+	int32Feature(dynatracev1beta1.AnnotationFeatureApiRequestThreshold, func(f *FeaturesSpec) **int32 { return &f.DynatraceApi.RequestThresholdMinutes }).
+		withDefault(strconv.Itoa(dynatracev1beta1.DefaultMinRequestThresholdMinutes)),
+
+	boolFeature(dynatracev1beta1.AnnotationFeatureReadOnlyOneAgent, func(f *FeaturesSpec) **bool { return &f.OneAgent.ReadOnlyHostFs }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureMultipleOsAgentsOnNode, func(f *FeaturesSpec) **bool { return &f.OneAgent.MultipleOsAgentsOnNode }),
+	int32Feature(dynatracev1beta1.AnnotationFeatureOneAgentMaxUnavailable, func(f *FeaturesSpec) **int32 { return &f.OneAgent.MaxUnavailable }).
+		withDefault(strconv.Itoa(dynatracev1beta1.DefaultOneAgentMaxUnavailable)),
+	boolFeature(dynatracev1beta1.AnnotationFeatureOneAgentIgnoreProxy, func(f *FeaturesSpec) **bool { return &f.OneAgent.IgnoreProxy }),
+	int32Feature(dynatracev1beta1.AnnotationFeatureOneAgentInitialConnectRetry, func(f *FeaturesSpec) **int32 { return &f.OneAgent.InitialConnectRetryMs }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureRunOneAgentContainerPrivileged, func(f *FeaturesSpec) **bool { return &f.OneAgent.Privileged }),
+	stringFeature(dynatracev1beta1.AnnotationFeatureOneAgentSecCompProfile, func(f *FeaturesSpec) **string { return &f.OneAgent.SecCompProfile }),
+
+	boolFeature(dynatracev1beta1.AnnotationFeatureWebhookReinvocationPolicy, func(f *FeaturesSpec) **bool { return &f.Injection.WebhookReinvocationPolicy }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureMetadataEnrichment, func(f *FeaturesSpec) **bool { return &f.Injection.MetadataEnrichment }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureIgnoreUnknownState, func(f *FeaturesSpec) **bool { return &f.Injection.IgnoreUnknownState }),
+	stringListFeature(dynatracev1beta1.AnnotationFeatureIgnoredNamespaces, func(f *FeaturesSpec) *[]string { return &f.Injection.IgnoredNamespaces }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureAutomaticInjection, func(f *FeaturesSpec) **bool { return &f.Injection.AutomaticInjection }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureLabelVersionDetection, func(f *FeaturesSpec) **bool { return &f.Injection.LabelVersionDetection }),
+	stringFeature(dynatracev1beta1.AnnotationInjectionFailurePolicy, func(f *FeaturesSpec) **string { return &f.Injection.FailurePolicy }).
+		withDefault(dynatracev1beta1.DefaultInjectionFailurePolicy),
+	boolFeature(dynatracev1beta1.AnnotationFeatureInitContainerSeccomp, func(f *FeaturesSpec) **bool { return &f.Injection.InitContainerSeccompProfile }),
+	boolFeature(dynatracev1beta1.AnnotationFeatureNativeSidecar, func(f *FeaturesSpec) **bool { return &f.Injection.NativeSidecar }),
+
+	int32Feature(dynatracev1beta1.AnnotationFeatureMaxFailedCsiMountAttempts, func(f *FeaturesSpec) **int32 { return &f.CSI.MaxFailedMountAttempts }).
+		withDefault(strconv.Itoa(dynatracev1beta1.DefaultMaxFailedCsiMountAttempts)),
+	boolFeature(dynatracev1beta1.AnnotationFeatureReadOnlyCsiVolume, func(f *FeaturesSpec) **bool { return &f.CSI.ReadOnlyVolume }),
+
+	stringFeature(dynatracev1beta1.AnnotationFeatureSyntheticLocationEntityId, func(f *FeaturesSpec) **string { return &f.Synthetic.LocationEntityId }),
+	syntheticNodeTypeFeature(dynatracev1beta1.AnnotationFeatureSyntheticNodeType, func(f *FeaturesSpec) **string { return &f.Synthetic.NodeType }).
+		withDefault(dynatracev1beta1.DefaultSyntheticNodeType),
+	int32Feature(dynatracev1beta1.AnnotationFeatureSyntheticReplicas, func(f *FeaturesSpec) **int32 { return &f.Synthetic.Replicas }).
+		withDefault(strconv.Itoa(dynatracev1beta1.DefaultSyntheticReplicas)),
+	stringFeature(dynatracev1beta1.AnnotationFeatureCustomSyntheticImage, func(f *FeaturesSpec) **string { return &f.Synthetic.CustomImage }),
+}
+
+func boolFeature(annotation string, field func(*FeaturesSpec) **bool) featureBinding {
+	return pointerFeature(annotation, field, strconv.FormatBool, strconv.ParseBool)
+}
+
+func stringFeature(annotation string, field func(*FeaturesSpec) **string) featureBinding {
+	return pointerFeature(annotation, field,
+		func(value string) string { return value },
+		func(value string) (string, error) { return value, nil })
+}
+
+func int32Feature(annotation string, field func(*FeaturesSpec) **int32) featureBinding {
+	return pointerFeature(annotation, field,
+		func(value int32) string { return strconv.FormatInt(int64(value), 10) },
+		func(value string) (int32, error) {
+			parsed, err := strconv.ParseInt(value, 10, 32)
+			return int32(parsed), err
+		})
+}
+
+// syntheticNodeTypeFeature only moves the node types allowed by the enum of the spec,
+// other values are validated together with the synthetic capability and stay an annotation.
+func syntheticNodeTypeFeature(annotation string, field func(*FeaturesSpec) **string) featureBinding {
+	binding := stringFeature(annotation, field)
+	fromAnnotation := binding.fromAnnotation
+	binding.fromAnnotation = func(features *FeaturesSpec, value string) bool {
+		switch value {
+		case dynatracev1beta1.SyntheticNodeXs, dynatracev1beta1.SyntheticNodeS, dynatracev1beta1.SyntheticNodeM:
+			return fromAnnotation(features, value)
+		}
+		return false
+	}
+	return binding
+}
+
+func pointerFeature[T any](annotation string, field func(*FeaturesSpec) **T, format func(T) string, parse func(string) (T, error)) featureBinding {
+	return featureBinding{
+		annotation: annotation,
+		toAnnotation: func(features *FeaturesSpec) (string, bool) {
+			value := *field(features)
+			if value == nil {
+				return "", false
+			}
+			return format(*value), true
+		},
+		fromAnnotation: func(features *FeaturesSpec, value string) bool {
+			if dynatracev1beta1.ValidateFeatureFlag(annotation, value) != nil {
+				return false
+			}
+			parsed, err := parse(value)
+			if err != nil {
+				return false
+			}
+			*field(features) = &parsed
+			return true
+		},
+	}
+}
+
+func stringListFeature(annotation string, field func(*FeaturesSpec) *[]string) featureBinding {
+	return featureBinding{
+		annotation: annotation,
+		toAnnotation: func(features *FeaturesSpec) (string, bool) {
+			value := *field(features)
+			if value == nil {
+				return "", false
+			}
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return "", false
+			}
+			return string(raw), true
+		},
+		fromAnnotation: func(features *FeaturesSpec, value string) bool {
+			var parsed []string
+			if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+				return false
+			}
+			// an empty list can't be told apart from an unset field, so it has to stay an annotation
+			if len(parsed) == 0 {
+				return false
+			}
+			*field(features) = parsed
+			return true
+		},
+	}
+}
+
+// expand returns a copy of the features where all sections are set, so the bindings don't have to check for nil.
+func (features *FeaturesSpec) expand() *FeaturesSpec {
+	expanded := features.DeepCopy()
+	if expanded == nil {
+		expanded = &FeaturesSpec{}
+	}
+	if expanded.ActiveGate == nil {
+		expanded.ActiveGate = &ActiveGateFeatures{}
+	}
+	if expanded.DynatraceApi == nil {
+		expanded.DynatraceApi = &DynatraceApiFeatures{}
+	}
+	if expanded.OneAgent == nil {
+		expanded.OneAgent = &OneAgentFeatures{}
+	}
+	if expanded.Injection == nil {
+		expanded.Injection = &InjectionFeatures{}
+	}
+	if expanded.CSI == nil {
+		expanded.CSI = &CSIFeatures{}
+	}
+	if expanded.Synthetic == nil {
+		expanded.Synthetic = &SyntheticFeatures{}
+	}
+	return expanded
+}
+
+// prune removes the empty sections of the features, it returns nil if no feature is set at all.
+func (features *FeaturesSpec) prune() *FeaturesSpec {
+	if reflect.DeepEqual(*features.ActiveGate, ActiveGateFeatures{}) {
+		features.ActiveGate = nil
+	}
+	if reflect.DeepEqual(*features.DynatraceApi, DynatraceApiFeatures{}) {
+		features.DynatraceApi = nil
+	}
+	if reflect.DeepEqual(*features.OneAgent, OneAgentFeatures{}) {
+		features.OneAgent = nil
+	}
+	if reflect.DeepEqual(*features.Injection, InjectionFeatures{}) {
+		features.Injection = nil
+	}
+	if reflect.DeepEqual(*features.CSI, CSIFeatures{}) {
+		features.CSI = nil
+	}
+	if reflect.DeepEqual(*features.Synthetic, SyntheticFeatures{}) {
+		features.Synthetic = nil
+	}
+	if reflect.DeepEqual(*features, FeaturesSpec{}) {
+		return nil
+	}
+	return features
+}
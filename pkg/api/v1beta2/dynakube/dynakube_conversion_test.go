@@ -0,0 +1,320 @@
+package dynakube
+
+import (
+	"testing"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/address"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testNamespace = "test-namespace"
+	testName      = "test-name"
+	testAPIURL    = "http://test-endpoint/api"
+)
+
+func TestConversion_ConvertTo(t *testing.T) {
+	t.Run(`spec and status are copied`, func(t *testing.T) {
+		oldDynakube := &DynaKube{
+			ObjectMeta: prepareObjectMeta(),
+			Spec: DynaKubeSpec{
+				APIURL:      testAPIURL,
+				Tokens:      "test-tokens",
+				NetworkZone: "test-networkzone",
+				OneAgent: dynatracev1beta1.OneAgentSpec{
+					CloudNativeFullStack: &dynatracev1beta1.CloudNativeFullStackSpec{},
+				},
+				UpdateWindow: &dynatracev1beta1.UpdateWindowSpec{
+					Schedule: "0 22 * * mon-fri",
+					Duration: metav1.Duration{Duration: 4 * time.Hour},
+					TimeZone: "Europe/Vienna",
+				},
+				Mirror: &dynatracev1beta1.MirrorSpec{
+					Registry: "registry.dynatrace-mirror:5000/dynatrace",
+				},
+				MetadataEnrichment: &dynatracev1beta1.MetadataEnrichmentSpec{
+					Rules: []dynatracev1beta1.EnrichmentRule{
+						{Type: dynatracev1beta1.EnrichmentPodLabelRule, Keys: []string{"team"}},
+					},
+				},
+				RolloutOnUpgrade: &dynatracev1beta1.RolloutOnUpgradeSpec{
+					Namespaces:            []string{"shop"},
+					MaxConcurrentRollouts: 2,
+				},
+				OpenTelemetry: &dynatracev1beta1.OpenTelemetrySpec{
+					UseActiveGate: true,
+				},
+			},
+			Status: dynatracev1beta1.DynaKubeStatus{
+				Phase: "test-phase",
+			},
+		}
+
+		convertedDynakube := &dynatracev1beta1.DynaKube{}
+		err := oldDynakube.ConvertTo(convertedDynakube)
+		require.NoError(t, err)
+
+		assert.Equal(t, oldDynakube.ObjectMeta, convertedDynakube.ObjectMeta)
+		assert.Equal(t, oldDynakube.Spec.APIURL, convertedDynakube.Spec.APIURL)
+		assert.Equal(t, oldDynakube.Spec.Tokens, convertedDynakube.Spec.Tokens)
+		assert.Equal(t, oldDynakube.Spec.NetworkZone, convertedDynakube.Spec.NetworkZone)
+		assert.Equal(t, oldDynakube.Spec.OneAgent, convertedDynakube.Spec.OneAgent)
+		assert.Equal(t, oldDynakube.Spec.UpdateWindow, convertedDynakube.Spec.UpdateWindow)
+		assert.Equal(t, oldDynakube.Spec.Mirror, convertedDynakube.Spec.Mirror)
+		assert.Equal(t, oldDynakube.Spec.MetadataEnrichment, convertedDynakube.Spec.MetadataEnrichment)
+		assert.Equal(t, oldDynakube.Spec.RolloutOnUpgrade, convertedDynakube.Spec.RolloutOnUpgrade)
+		assert.Equal(t, oldDynakube.Spec.OpenTelemetry, convertedDynakube.Spec.OpenTelemetry)
+		assert.Equal(t, oldDynakube.Status, convertedDynakube.Status)
+	})
+	t.Run(`features are converted to annotations`, func(t *testing.T) {
+		oldDynakube := &DynaKube{
+			ObjectMeta: prepareObjectMeta(),
+			Spec: DynaKubeSpec{
+				APIURL: testAPIURL,
+				Features: &FeaturesSpec{
+					PublicRegistry: address.Of(true),
+					OneAgent: &OneAgentFeatures{
+						MaxUnavailable: address.Of(int32(3)),
+					},
+					Injection: &InjectionFeatures{
+						AutomaticInjection: address.Of(false),
+						IgnoredNamespaces:  []string{"^kube-.*"},
+						FailurePolicy:      address.Of("fail"),
+					},
+				},
+			},
+		}
+
+		convertedDynakube := &dynatracev1beta1.DynaKube{}
+		err := oldDynakube.ConvertTo(convertedDynakube)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{
+			"test-annotation": "test-value",
+			dynatracev1beta1.AnnotationFeaturePublicRegistry:         "true",
+			dynatracev1beta1.AnnotationFeatureOneAgentMaxUnavailable: "3",
+			dynatracev1beta1.AnnotationFeatureAutomaticInjection:     "false",
+			dynatracev1beta1.AnnotationFeatureIgnoredNamespaces:      `["^kube-.*"]`,
+			dynatracev1beta1.AnnotationInjectionFailurePolicy:        "fail",
+		}, convertedDynakube.Annotations)
+		assert.True(t, convertedDynakube.FeaturePublicRegistry())
+		assert.Equal(t, 3, convertedDynakube.FeatureOneAgentMaxUnavailable())
+		assert.False(t, convertedDynakube.FeatureAutomaticInjection())
+		assert.Equal(t, []string{"^kube-.*"}, convertedDynakube.FeatureIgnoredNamespaces())
+		assert.Equal(t, "fail", convertedDynakube.FeatureInjectionFailurePolicy())
+
+		assert.Len(t, oldDynakube.Annotations, 1, "source must not be modified")
+	})
+	t.Run(`defaults of the features are not converted to annotations`, func(t *testing.T) {
+		oldDynakube := &DynaKube{
+			ObjectMeta: prepareObjectMeta(),
+			Spec: DynaKubeSpec{
+				APIURL:   testAPIURL,
+				Features: createDefaultedFeatures(),
+			},
+		}
+
+		convertedDynakube := &dynatracev1beta1.DynaKube{}
+		err := oldDynakube.ConvertTo(convertedDynakube)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"test-annotation": "test-value"}, convertedDynakube.Annotations)
+		assert.Equal(t, dynatracev1beta1.DefaultMinRequestThresholdMinutes*time.Minute, convertedDynakube.FeatureApiRequestThreshold())
+		assert.Equal(t, dynatracev1beta1.DefaultOneAgentMaxUnavailable, convertedDynakube.FeatureOneAgentMaxUnavailable())
+		assert.Equal(t, dynatracev1beta1.DefaultInjectionFailurePolicy, convertedDynakube.FeatureInjectionFailurePolicy())
+		assert.Equal(t, dynatracev1beta1.DefaultMaxFailedCsiMountAttempts, convertedDynakube.FeatureMaxFailedCsiMountAttempts())
+		assert.Equal(t, dynatracev1beta1.DefaultSyntheticNodeType, convertedDynakube.FeatureSyntheticNodeType())
+		assert.Equal(t, int32(dynatracev1beta1.DefaultSyntheticReplicas), convertedDynakube.FeatureSyntheticReplicas())
+	})
+	t.Run(`features take precedence over annotations`, func(t *testing.T) {
+		oldDynakube := &DynaKube{
+			ObjectMeta: prepareObjectMeta(),
+			Spec: DynaKubeSpec{
+				APIURL: testAPIURL,
+				Features: &FeaturesSpec{
+					CSI: &CSIFeatures{
+						MaxFailedMountAttempts: address.Of(int32(5)),
+					},
+				},
+			},
+		}
+		oldDynakube.Annotations[dynatracev1beta1.AnnotationFeatureMaxFailedCsiMountAttempts] = "2"
+
+		convertedDynakube := &dynatracev1beta1.DynaKube{}
+		err := oldDynakube.ConvertTo(convertedDynakube)
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, convertedDynakube.FeatureMaxFailedCsiMountAttempts())
+	})
+	t.Run(`features set to their default take precedence over annotations`, func(t *testing.T) {
+		oldDynakube := &DynaKube{
+			ObjectMeta: prepareObjectMeta(),
+			Spec: DynaKubeSpec{
+				APIURL: testAPIURL,
+				Features: &FeaturesSpec{
+					Injection: &InjectionFeatures{FailurePolicy: address.Of("silent")},
+				},
+			},
+		}
+		oldDynakube.Annotations[dynatracev1beta1.AnnotationInjectionFailurePolicy] = "fail"
+
+		convertedDynakube := &dynatracev1beta1.DynaKube{}
+		err := oldDynakube.ConvertTo(convertedDynakube)
+		require.NoError(t, err)
+
+		assert.Equal(t, dynatracev1beta1.DefaultInjectionFailurePolicy, convertedDynakube.FeatureInjectionFailurePolicy())
+		assert.NotContains(t, convertedDynakube.Annotations, dynatracev1beta1.AnnotationInjectionFailurePolicy)
+		assert.Equal(t, "fail", oldDynakube.Annotations[dynatracev1beta1.AnnotationInjectionFailurePolicy], "source must not be modified")
+	})
+}
+
+func TestConversion_ConvertFrom(t *testing.T) {
+	t.Run(`feature annotations are converted to features`, func(t *testing.T) {
+		oldDynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: prepareObjectMeta(),
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testAPIURL,
+			},
+		}
+		oldDynakube.Annotations[dynatracev1beta1.AnnotationFeatureActiveGateUpdates] = "false"
+		oldDynakube.Annotations[dynatracev1beta1.AnnotationFeatureSyntheticReplicas] = "2"
+		oldDynakube.Annotations[dynatracev1beta1.AnnotationFeatureSyntheticNodeType] = dynatracev1beta1.SyntheticNodeM
+
+		convertedDynakube := &DynaKube{}
+		err := convertedDynakube.ConvertFrom(oldDynakube)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"test-annotation": "test-value"}, convertedDynakube.Annotations)
+		assert.Equal(t, &FeaturesSpec{
+			ActiveGate: &ActiveGateFeatures{
+				Updates: address.Of(false),
+			},
+			Synthetic: &SyntheticFeatures{
+				NodeType: address.Of(dynatracev1beta1.SyntheticNodeM),
+				Replicas: address.Of(int32(2)),
+			},
+		}, convertedDynakube.Spec.Features)
+		assert.Len(t, oldDynakube.Annotations, 4, "source must not be modified")
+	})
+	t.Run(`unknown, deprecated and malformed feature annotations are kept`, func(t *testing.T) {
+		oldDynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testName,
+				Namespace: testNamespace,
+				Annotations: map[string]string{
+					dynatracev1beta1.AnnotationFeaturePrefix + "unknown":                "true",
+					dynatracev1beta1.AnnotationFeatureDisableActiveGateUpdates:          "true",
+					dynatracev1beta1.DeprecatedFeatureFlagPrefix + "activegate-updates": "false",
+					dynatracev1beta1.AnnotationFeatureOneAgentMaxUnavailable:            "many",
+					dynatracev1beta1.AnnotationFeatureIgnoredNamespaces:                 "[]",
+				},
+			},
+		}
+
+		convertedDynakube := &DynaKube{}
+		err := convertedDynakube.ConvertFrom(oldDynakube)
+		require.NoError(t, err)
+
+		assert.Equal(t, oldDynakube.Annotations, convertedDynakube.Annotations)
+		assert.Nil(t, convertedDynakube.Spec.Features)
+	})
+	t.Run(`round trip is lossless`, func(t *testing.T) {
+		oldDynakube := &DynaKube{
+			ObjectMeta: prepareObjectMeta(),
+			Spec: DynaKubeSpec{
+				APIURL: testAPIURL,
+				Features: &FeaturesSpec{
+					ActiveGate: &ActiveGateFeatures{
+						AutomaticKubernetesApiMonitoringClusterName: address.Of("test-cluster"),
+					},
+					DynatraceApi: &DynatraceApiFeatures{
+						NoProxy:                 address.Of(""),
+						RequestThresholdMinutes: address.Of(int32(0)),
+					},
+					OneAgent: &OneAgentFeatures{
+						InitialConnectRetryMs: address.Of(int32(-1)),
+						Privileged:            address.Of(true),
+					},
+				},
+			},
+		}
+
+		hub := &dynatracev1beta1.DynaKube{}
+		require.NoError(t, oldDynakube.ConvertTo(hub))
+
+		convertedDynakube := &DynaKube{}
+		require.NoError(t, convertedDynakube.ConvertFrom(hub))
+
+		assert.Equal(t, oldDynakube, convertedDynakube)
+	})
+	t.Run(`round trip leaves defaults of the features to the API server`, func(t *testing.T) {
+		oldDynakube := &DynaKube{
+			ObjectMeta: prepareObjectMeta(),
+			Spec: DynaKubeSpec{
+				APIURL:   testAPIURL,
+				Features: createDefaultedFeatures(),
+			},
+		}
+
+		hub := &dynatracev1beta1.DynaKube{}
+		require.NoError(t, oldDynakube.ConvertTo(hub))
+
+		convertedDynakube := &DynaKube{}
+		require.NoError(t, convertedDynakube.ConvertFrom(hub))
+
+		assert.Equal(t, oldDynakube.Annotations, convertedDynakube.Annotations)
+		assert.Nil(t, convertedDynakube.Spec.Features)
+	})
+	t.Run(`round trip of annotations set to the default is lossless`, func(t *testing.T) {
+		oldDynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: prepareObjectMeta(),
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testAPIURL,
+			},
+		}
+		oldDynakube.Annotations[dynatracev1beta1.AnnotationFeatureOneAgentMaxUnavailable] = "1"
+		oldDynakube.Annotations[dynatracev1beta1.AnnotationInjectionFailurePolicy] = "silent"
+		oldDynakube.Annotations[dynatracev1beta1.AnnotationFeatureSyntheticReplicas] = "3"
+
+		spoke := &DynaKube{}
+		require.NoError(t, spoke.ConvertFrom(oldDynakube))
+
+		convertedDynakube := &dynatracev1beta1.DynaKube{}
+		require.NoError(t, spoke.ConvertTo(convertedDynakube))
+
+		assert.Equal(t, oldDynakube, convertedDynakube)
+		assert.Equal(t, &FeaturesSpec{
+			Synthetic: &SyntheticFeatures{
+				Replicas: address.Of(int32(3)),
+			},
+		}, spoke.Spec.Features)
+	})
+}
+
+// createDefaultedFeatures returns the features as the API server defaults them, if all sections are set but empty
+func createDefaultedFeatures() *FeaturesSpec {
+	return &FeaturesSpec{
+		DynatraceApi: &DynatraceApiFeatures{RequestThresholdMinutes: address.Of(int32(15))},
+		OneAgent:     &OneAgentFeatures{MaxUnavailable: address.Of(int32(1))},
+		Injection:    &InjectionFeatures{FailurePolicy: address.Of("silent")},
+		CSI:          &CSIFeatures{MaxFailedMountAttempts: address.Of(int32(10))},
+		Synthetic: &SyntheticFeatures{
+			NodeType: address.Of("S"),
+			Replicas: address.Of(int32(1)),
+		},
+	}
+}
+
+func prepareObjectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: testNamespace,
+		Name:      testName,
+		Annotations: map[string]string{
+			"test-annotation": "test-value",
+		},
+	}
+}
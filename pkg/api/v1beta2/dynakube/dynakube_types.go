@@ -0,0 +1,169 @@
+// +kubebuilder:object:generate=true
+// +groupName=dynatrace.com
+// +versionName=v1beta2
+package dynakube
+
+import (
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DynaKube is the Schema for the DynaKube API
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=dynakubes,scope=Namespaced,categories=dynatrace
+// +kubebuilder:printcolumn:name="ApiUrl",type=string,JSONPath=`.spec.apiUrl`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Monitored Hosts",type=string,JSONPath=`.status.oneAgent.monitoredHosts`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +operator-sdk:csv:customresourcedefinitions:displayName="Dynatrace DynaKube"
+// +operator-sdk:csv:customresourcedefinitions:resources={{StatefulSet,v1,},{DaemonSet,v1,},{Pod,v1,}}
+type DynaKube struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DynaKubeSpec                    `json:"spec,omitempty"`
+	Status dynatracev1beta1.DynaKubeStatus `json:"status,omitempty"`
+}
+
+// DynaKubeSpec defines the desired state of DynaKube
+// +k8s:openapi-gen=true
+type DynaKubeSpec struct { // nolint:revive
+	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
+
+	// Dynatrace apiUrl, including the /api path at the end. For SaaS, set YOUR_ENVIRONMENT_ID to your environment ID. For Managed, change the apiUrl address.
+	// For instructions on how to determine the environment ID and how to configure the apiUrl address, see Environment ID (https://www.dynatrace.com/support/help/get-started/monitoring-environment/environment-id).
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="API URL",order=1,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	APIURL string `json:"apiUrl"`
+
+	// Name of the secret holding the tokens used for connecting to Dynatrace.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tenant specific secrets",order=2,xDescriptors="urn:alm:descriptor:io.kubernetes:Secret"
+	Tokens string `json:"tokens,omitempty"`
+
+	// Alternative source of the tokens used for connecting to Dynatrace, takes precedence over tokens.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Token source",order=2,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	TokenSource *dynatracev1beta1.TokenSourceSpec `json:"tokenSource,omitempty"`
+
+	// Disable certificate check for the connection between Dynatrace Operator and the Dynatrace Cluster.
+	// Set to true if you want to skip certification validation checks.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Skip Certificate Check",order=3,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	SkipCertCheck bool `json:"skipCertCheck,omitempty"`
+
+	// Set custom proxy settings either directly or from a secret with the field proxy.
+	// Note: Applies to Dynatrace Operator, ActiveGate, and OneAgents.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Proxy",order=3,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Proxy *dynatracev1beta1.DynaKubeProxy `json:"proxy,omitempty"`
+
+	// Adds custom RootCAs from a configmap. Put the certificate under certs within your configmap.
+	// Note: Applies only to Dynatrace Operator and OneAgent, not to ActiveGate.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Trusted CAs",order=6,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:io.kubernetes:ConfigMap"}
+	TrustedCAs string `json:"trustedCAs,omitempty"`
+
+	// Sets a network zone for the OneAgent and ActiveGate pods.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Network Zone",order=7,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	NetworkZone string `json:"networkZone,omitempty"`
+
+	// Defines a custom pull secret in case you use a private registry when pulling images from the Dynatrace environment.
+	// To define a custom pull secret and learn about the expected behavior, see Configure customPullSecret
+	// (https://www.dynatrace.com/support/help/setup-and-configuration/setup-on-container-platforms/kubernetes/get-started-with-kubernetes-monitoring/dto-config-options-k8s#custompullsecret).
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Custom PullSecret",order=8,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:io.kubernetes:Secret"}
+	CustomPullSecret string `json:"customPullSecret,omitempty"`
+
+	// When enabled, and if Istio is installed on the Kubernetes environment, Dynatrace Operator will create the corresponding
+	// VirtualService and ServiceEntry objects to allow access to the Dynatrace Cluster from the OneAgent or ActiveGate.
+	// Disabled by default.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Istio automatic management",order=9,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	EnableIstio bool `json:"enableIstio,omitempty"`
+
+	// Typed configuration of the features that used to be configured via the feature.dynatrace.com/ annotations.
+	// Each field is converted to its annotation counterpart, if both are set the value in this section takes precedence.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Features",order=10,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
+	Features *FeaturesSpec `json:"features,omitempty"`
+
+	// Applicable only for applicationMonitoring or cloudNativeFullStack configuration types. The namespaces where you want Dynatrace Operator to inject.
+	// For more information, see Configure monitoring for namespaces and pods (https://www.dynatrace.com/support/help/setup-and-configuration/setup-on-container-platforms/kubernetes/get-started-with-kubernetes-monitoring/dto-config-options-k8s#annotate).
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace Selector",order=17,xDescriptors="urn:alm:descriptor:com.tectonic.ui:selector:core:v1:Namespace"
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Restricts automatic updates of OneAgent, code modules and ActiveGate to recurring maintenance windows.
+	// Newer versions found outside of a window are reported in the status and rolled out once the next window opens.
+	// Custom images and versions are applied right away.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Update window",order=18,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	UpdateWindow *dynatracev1beta1.UpdateWindowSpec `json:"updateWindow,omitempty"`
+
+	// Mirrors the OneAgent, code modules and ActiveGate images and the OneAgent installers into an in-cluster registry,
+	// for clusters which can reach Dynatrace only from time to time. The deployed components are pulled from the mirror.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mirror",order=19,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Mirror *dynatracev1beta1.MirrorSpec `json:"mirror,omitempty"`
+
+	// Adds selected namespace labels, pod labels and pod annotations to the metadata enrichment files
+	// and the OneAgent container configuration of injected pods.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Metadata enrichment",order=20,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	MetadataEnrichment *dynatracev1beta1.MetadataEnrichmentSpec `json:"metadataEnrichment,omitempty"`
+
+	// Restarts the Deployments, StatefulSets and DaemonSets of injected pods once a new code modules version is rolled out,
+	// so the pods don't keep running the old code modules until they are restarted otherwise.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rollout on upgrade",order=21,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	RolloutOnUpgrade *dynatracev1beta1.RolloutOnUpgradeSpec `json:"rolloutOnUpgrade,omitempty"`
+
+	// Configures the OTLP exporter of OpenTelemetry SDKs in injected pods to export traces, metrics and logs to Dynatrace.
+	// Pods can opt out with the otel.dynatrace.com/inject annotation.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OpenTelemetry",order=22,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	OpenTelemetry *dynatracev1beta1.OpenTelemetrySpec `json:"openTelemetry,omitempty"`
+
+	// General configuration about OneAgent instances.
+	// You can't enable more than one module (classicFullStack, cloudNativeFullStack, hostMonitoring, or applicationMonitoring).
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OneAgent",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	OneAgent dynatracev1beta1.OneAgentSpec `json:"oneAgent,omitempty"`
+
+	// General configuration about ActiveGate instances.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ActiveGate",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	ActiveGate dynatracev1beta1.ActiveGateSpec `json:"activeGate,omitempty"`
+
+	// Configuration for Routing
+	// +kubebuilder:deprecatedversion:warning="Deprecated property"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Routing"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Routing dynatracev1beta1.RoutingSpec `json:"routing,omitempty"`
+
+	// Configuration for Kubernetes Monitoring
+	// +kubebuilder:deprecatedversion:warning="Deprecated property"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Kubernetes Monitoring"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	KubernetesMonitoring dynatracev1beta1.KubernetesMonitoringSpec `json:"kubernetesMonitoring,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DynaKubeList contains a list of DynaKube
+// +kubebuilder:object:root=true
+type DynaKubeList struct { // nolint:revive
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DynaKube `json:"items"`
+}
+
+func init() {
+	v1beta2.SchemeBuilder.Register(&DynaKube{}, &DynaKubeList{})
+}
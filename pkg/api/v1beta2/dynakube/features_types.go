@@ -0,0 +1,195 @@
+package dynakube
+
+// FeaturesSpec is the typed counterpart of the feature.dynatrace.com/ annotations.
+// Fields which are not set fall back to the annotation, or to the default of the operator if the annotation isn't set either.
+// +k8s:openapi-gen=true
+type FeaturesSpec struct {
+	// Pull the images of the Dynatrace components from the public registry instead of the Dynatrace environment.
+	// +optional
+	PublicRegistry *bool `json:"publicRegistry,omitempty"`
+
+	// Features of the ActiveGate.
+	// +optional
+	ActiveGate *ActiveGateFeatures `json:"activeGate,omitempty"`
+
+	// Features of the communication with the Dynatrace API.
+	// +optional
+	DynatraceApi *DynatraceApiFeatures `json:"dynatraceApi,omitempty"`
+
+	// Features of the OneAgent DaemonSet.
+	// +optional
+	OneAgent *OneAgentFeatures `json:"oneAgent,omitempty"`
+
+	// Features of the injection into application pods.
+	// +optional
+	Injection *InjectionFeatures `json:"injection,omitempty"`
+
+	// Features of the CSI driver.
+	// +optional
+	CSI *CSIFeatures `json:"csi,omitempty"`
+
+	// Features of the synthetic monitoring.
+	// +optional
+	Synthetic *SyntheticFeatures `json:"synthetic,omitempty"`
+}
+
+type ActiveGateFeatures struct {
+	// Enables automatic updates of the ActiveGate.
+	// +optional
+	Updates *bool `json:"updates,omitempty"`
+
+	// Configures tenant UUID, token and communication endpoints of the ActiveGate via the operator instead of using the ones embedded in the image.
+	// +optional
+	RawImage *bool `json:"rawImage,omitempty"`
+
+	// Creates and uses an authToken for the ActiveGate.
+	// +optional
+	AuthToken *bool `json:"authToken,omitempty"`
+
+	// Enables AppArmor for the ActiveGate container.
+	// +optional
+	AppArmor *bool `json:"appArmor,omitempty"`
+
+	// Mounts the filesystem of the ActiveGate container read-only.
+	// +optional
+	ReadOnlyFilesystem *bool `json:"readOnlyFilesystem,omitempty"`
+
+	// Ignores the proxy of the DynaKube for the ActiveGate.
+	// +optional
+	IgnoreProxy *bool `json:"ignoreProxy,omitempty"`
+
+	// Ensures that the settings for monitoring the Kubernetes API of the cluster exist in Dynatrace.
+	// +optional
+	AutomaticKubernetesApiMonitoring *bool `json:"automaticKubernetesApiMonitoring,omitempty"`
+
+	// Custom cluster name used for the automatic Kubernetes API monitoring.
+	// +optional
+	AutomaticKubernetesApiMonitoringClusterName *string `json:"automaticKubernetesApiMonitoringClusterName,omitempty"`
+
+	// Enables the cluster for the Kubernetes app.
+	// +optional
+	K8sAppEnabled *bool `json:"k8sAppEnabled,omitempty"`
+}
+
+type DynatraceApiFeatures struct {
+	// Enables requests to the hosts API.
+	// +optional
+	HostsRequests *bool `json:"hostsRequests,omitempty"`
+
+	// NO_PROXY value used by the operator when calling the Dynatrace API.
+	// +optional
+	NoProxy *string `json:"noProxy,omitempty"`
+
+	// Minimal time in minutes between two requests to the Dynatrace API for the same information.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=15
+	// +optional
+	RequestThresholdMinutes *int32 `json:"requestThresholdMinutes,omitempty"`
+}
+
+type OneAgentFeatures struct {
+	// Runs the OneAgent with a read-only host filesystem, the CSI driver provides the writable volumes.
+	// +optional
+	ReadOnlyHostFs *bool `json:"readOnlyHostFs,omitempty"`
+
+	// Allows multiple OneAgents running on the same node.
+	// +optional
+	MultipleOsAgentsOnNode *bool `json:"multipleOsAgentsOnNode,omitempty"`
+
+	// maxUnavailable of the rolling update of the OneAgent DaemonSet.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+
+	// Ignores the proxy of the DynaKube for the OneAgent.
+	// +optional
+	IgnoreProxy *bool `json:"ignoreProxy,omitempty"`
+
+	// Initial connect retry delay of the OneAgent in milliseconds.
+	// +optional
+	InitialConnectRetryMs *int32 `json:"initialConnectRetryMs,omitempty"`
+
+	// Runs the OneAgent container privileged.
+	// +optional
+	Privileged *bool `json:"privileged,omitempty"`
+
+	// Name of the seccomp profile used by the OneAgent container.
+	// +optional
+	SecCompProfile *string `json:"secCompProfile,omitempty"`
+}
+
+type InjectionFeatures struct {
+	// Enables the reinvocation policy of the webhooks, so containers added by other webhooks get instrumented as well.
+	// +optional
+	WebhookReinvocationPolicy *bool `json:"webhookReinvocationPolicy,omitempty"`
+
+	// Enables the metadata enrichment of application pods.
+	// +optional
+	MetadataEnrichment *bool `json:"metadataEnrichment,omitempty"`
+
+	// Injects into application pods even if the DynaKube is in an UNKNOWN state.
+	// +optional
+	IgnoreUnknownState *bool `json:"ignoreUnknownState,omitempty"`
+
+	// Regular expressions of namespaces that are never injected.
+	// +optional
+	IgnoredNamespaces []string `json:"ignoredNamespaces,omitempty"`
+
+	// Injects into all pods of the selected namespaces, if disabled pods need to opt in one by one.
+	// +optional
+	AutomaticInjection *bool `json:"automaticInjection,omitempty"`
+
+	// Adds environment variables for version detection based on the labels of the pods.
+	// +optional
+	LabelVersionDetection *bool `json:"labelVersionDetection,omitempty"`
+
+	// Behaviour of the init-container if the injection fails.
+	// +kubebuilder:validation:Enum=silent;fail;force
+	// +kubebuilder:default=silent
+	// +optional
+	FailurePolicy *string `json:"failurePolicy,omitempty"`
+
+	// Sets the RuntimeDefault seccomp profile for the init-container.
+	// +optional
+	InitContainerSeccompProfile *bool `json:"initContainerSeccompProfile,omitempty"`
+
+	// Additionally injects a restartable init container (native sidecar), which keeps the configuration of the injected containers up to date.
+	// Requires Kubernetes 1.29+.
+	// +optional
+	NativeSidecar *bool `json:"nativeSidecar,omitempty"`
+}
+
+type CSIFeatures struct {
+	// Number of failed mount attempts after which the CSI driver gives up and provides an empty volume.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=10
+	// +optional
+	MaxFailedMountAttempts *int32 `json:"maxFailedMountAttempts,omitempty"`
+
+	// Mounts the CSI volume of the injected pods read-only.
+	// +optional
+	ReadOnlyVolume *bool `json:"readOnlyVolume,omitempty"`
+}
+
+type SyntheticFeatures struct {
+	// Entity ID of the synthetic location.
+	// +optional
+	LocationEntityId *string `json:"locationEntityId,omitempty"`
+
+	// Node type of the synthetic location.
+	// +kubebuilder:validation:Enum=XS;S;M
+	// +kubebuilder:default=S
+	// +optional
+	NodeType *string `json:"nodeType,omitempty"`
+
+	// Number of replicas of the synthetic location.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Custom image of the synthetic location.
+	// +optional
+	CustomImage *string `json:"customImage,omitempty"`
+}
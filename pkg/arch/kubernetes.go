@@ -0,0 +1,27 @@
+package arch
+
+import "github.com/pkg/errors"
+
+// Values of the kubernetes.io/arch node label, they are the same as the architectures of the OCI image platforms
+const (
+	KubernetesArchAMD64   = "amd64"
+	KubernetesArchARM64   = "arm64"
+	KubernetesArchPPC64LE = "ppc64le"
+	KubernetesArchS390X   = "s390x"
+)
+
+// ForKubernetesArch returns the architecture and flavor of the OneAgent installer for the kubernetes.io/arch label of a node
+func ForKubernetesArch(kubernetesArch string) (string, string, error) {
+	switch kubernetesArch {
+	case KubernetesArchAMD64:
+		return ArchX86, FlavorMultidistro, nil
+	case KubernetesArchARM64:
+		return ArchARM, FlavorDefault, nil
+	case KubernetesArchPPC64LE:
+		return ArchPPCLE, FlavorDefault, nil
+	case KubernetesArchS390X:
+		return ArchS390, FlavorDefault, nil
+	default:
+		return "", "", errors.Errorf("unsupported node architecture '%s'", kubernetesArch)
+	}
+}
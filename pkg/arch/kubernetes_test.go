@@ -0,0 +1,36 @@
+package arch
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForKubernetesArch(t *testing.T) {
+	t.Run(`maps node architectures to installer architectures and flavors`, func(t *testing.T) {
+		expected := map[string][2]string{
+			KubernetesArchAMD64:   {ArchX86, FlavorMultidistro},
+			KubernetesArchARM64:   {ArchARM, FlavorDefault},
+			KubernetesArchPPC64LE: {ArchPPCLE, FlavorDefault},
+			KubernetesArchS390X:   {ArchS390, FlavorDefault},
+		}
+		for kubernetesArch, installer := range expected {
+			installerArch, flavor, err := ForKubernetesArch(kubernetesArch)
+			require.NoError(t, err)
+			assert.Equal(t, installer[0], installerArch, kubernetesArch)
+			assert.Equal(t, installer[1], flavor, kubernetesArch)
+		}
+	})
+	t.Run(`matches the architecture of the binary`, func(t *testing.T) {
+		installerArch, flavor, err := ForKubernetesArch(runtime.GOARCH)
+		require.NoError(t, err)
+		assert.Equal(t, Arch, installerArch)
+		assert.Equal(t, Flavor, flavor)
+	})
+	t.Run(`unsupported architecture`, func(t *testing.T) {
+		_, _, err := ForKubernetesArch("riscv64")
+		require.Error(t, err)
+	})
+}
@@ -33,7 +33,7 @@ func (dtc *dynatraceClient) GetActiveGateAuthToken(dynakubeName string) (*Active
 		return nil, errors.WithStack(err)
 	}
 
-	response, err := dtc.httpClient.Do(request)
+	response, err := dtc.do(request)
 	defer utils.CloseBodyAfterRequest(response)
 
 	if err != nil {
@@ -23,6 +23,27 @@ func (dtc *dynatraceClient) GetEntityIDForIP(ip string) (string, error) {
 	return hostInfo.entityID, nil
 }
 
+// GetHostInfoForIP returns the entity id, the agent version and the last seen time of the host with the given IP address.
+func (dtc *dynatraceClient) GetHostInfoForIP(ip string) (HostInfo, error) {
+	if len(ip) == 0 {
+		return HostInfo{}, errors.New("ip is invalid")
+	}
+
+	hostInfo, err := dtc.getHostInfoForIP(ip)
+	if err != nil {
+		return HostInfo{}, err
+	}
+	if hostInfo.entityID == "" {
+		return HostInfo{}, HostNotFoundErr{IP: ip}
+	}
+
+	return HostInfo{
+		EntityID:     hostInfo.entityID,
+		AgentVersion: hostInfo.version,
+		LastSeen:     hostInfo.lastSeen,
+	}, nil
+}
+
 // GetLatestAgent gets the latest agent package for the given OS and installer type.
 func (dtc *dynatraceClient) GetLatestAgent(os, installerType, flavor, arch string, technologies []string, skipMetadata bool, writer io.Writer) error {
 	if len(os) == 0 || len(installerType) == 0 {
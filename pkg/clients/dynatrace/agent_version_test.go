@@ -121,6 +121,51 @@ func TestGetEntityIDForIP(t *testing.T) {
 	assert.Empty(t, id)
 }
 
+func TestGetHostInfoForIP(t *testing.T) {
+	lastSeen := time.Now().UTC().Truncate(time.Second)
+	dtc := dynatraceClient{}
+	require.NoError(t, dtc.setHostCacheFromResponse([]byte(
+		fmt.Sprintf(`[
+	{
+		"entityId": "HOST-42",
+		"lastSeenTimestamp": %v,
+		"ipAddresses": [
+			"1.1.1.1"
+		],
+		"networkZoneId": "default",
+		"agentVersion": {
+			"major": 1,
+			"minor": 195,
+			"revision": 0,
+			"timestamp": "20200515-045253"
+		}
+	},
+	{
+		"entityId": "",
+		"lastSeenTimestamp": %v,
+		"ipAddresses": [
+			"3.3.3.3"
+		],
+		"networkZoneId": "default"
+	}
+]`, lastSeen.Unix()*1000, lastSeen.Unix()*1000))))
+
+	hostInfo, err := dtc.GetHostInfoForIP("1.1.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, "HOST-42", hostInfo.EntityID)
+	assert.Equal(t, "1.195.0.20200515-045253", hostInfo.AgentVersion)
+	assert.True(t, lastSeen.Equal(hostInfo.LastSeen))
+
+	_, err = dtc.GetHostInfoForIP("2.2.2.2")
+	assert.ErrorAs(t, err, &HostNotFoundErr{})
+
+	_, err = dtc.GetHostInfoForIP("3.3.3.3")
+	assert.ErrorAs(t, err, &HostNotFoundErr{})
+
+	_, err = dtc.GetHostInfoForIP("")
+	assert.Error(t, err)
+}
+
 func testAgentVersionGetLatestAgentVersion(t *testing.T, dynatraceClient Client) {
 	{
 		_, err := dynatraceClient.GetLatestAgentVersion("", InstallerTypeDefault)
@@ -59,6 +59,11 @@ type Client interface {
 	// Returns an error in case the lookup failed.
 	GetEntityIDForIP(ip string) (string, error)
 
+	// GetHostInfoForIP returns the entity id, the agent version and the last seen time of the host with the given IP address.
+	//
+	// Returns an error in case the lookup failed.
+	GetHostInfoForIP(ip string) (HostInfo, error)
+
 	// GetTokenScopes returns the list of scopes assigned to a token if successful.
 	GetTokenScopes(token string) (TokenScopes, error)
 
@@ -110,13 +115,15 @@ const (
 
 // Known token scopes
 const (
-	TokenScopeInstallerDownload     = "InstallerDownload"
-	TokenScopeDataExport            = "DataExport"
-	TokenScopeMetricsIngest         = "metrics.ingest"
-	TokenScopeEntitiesRead          = "entities.read"
-	TokenScopeSettingsRead          = "settings.read"
-	TokenScopeSettingsWrite         = "settings.write"
-	TokenScopeActiveGateTokenCreate = "activeGateTokenManagement.create"
+	TokenScopeInstallerDownload        = "InstallerDownload"
+	TokenScopeDataExport               = "DataExport"
+	TokenScopeMetricsIngest            = "metrics.ingest"
+	TokenScopeLogsIngest               = "logs.ingest"
+	TokenScopeOpenTelemetryTraceIngest = "openTelemetryTrace.ingest"
+	TokenScopeEntitiesRead             = "entities.read"
+	TokenScopeSettingsRead             = "settings.read"
+	TokenScopeSettingsWrite            = "settings.write"
+	TokenScopeActiveGateTokenCreate    = "activeGateTokenManagement.create"
 )
 
 // NewClient creates a REST client for the given API base URL and authentication tokens.
@@ -2,8 +2,30 @@ package dynatrace
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var (
 	log = logger.Factory.GetLogger("dtclient")
+
+	requestsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "api_client",
+		Name:      "requests_total",
+		Help:      "Number of requests sent to the Dynatrace API by endpoint, method and status code",
+	}, []string{"endpoint", "method", "code"})
+
+	requestDurationMetric = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "dynatrace",
+		Subsystem: "api_client",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of the requests sent to the Dynatrace API by endpoint and method",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"endpoint", "method"})
 )
+
+func init() {
+	metrics.Registry.MustRegister(requestsMetric)
+	metrics.Registry.MustRegister(requestDurationMetric)
+}
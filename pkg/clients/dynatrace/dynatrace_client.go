@@ -25,6 +25,14 @@ func (e HostNotFoundErr) Error() string {
 type hostInfo struct {
 	version  string
 	entityID string
+	lastSeen time.Time
+}
+
+// HostInfo describes a host entity in Dynatrace.
+type HostInfo struct {
+	EntityID     string
+	AgentVersion string
+	LastSeen     time.Time
 }
 
 // client implements the Client interface.
@@ -75,14 +83,14 @@ func (dtc *dynatraceClient) makeRequest(url string, tokenType tokenType) (*http.
 		}
 		authHeader = fmt.Sprintf("Api-Token %s", dtc.paasToken)
 	case installerUrlToken:
-		return dtc.httpClient.Do(req)
+		return dtc.do(req)
 	default:
 		return nil, errors.Errorf("unknown token type (%d), unable to determine token to set in headers", tokenType)
 	}
 
 	req.Header.Add("Authorization", authHeader)
 
-	return dtc.httpClient.Do(req)
+	return dtc.do(req)
 }
 
 func createBaseRequest(url, method, apiToken string, body io.Reader) (*http.Request, error) {
@@ -228,7 +236,8 @@ func (dtc *dynatraceClient) setHostCacheFromResponse(response []byte) error {
 	var inactive []string
 	for _, info := range hostInfoResponses {
 		// If we haven't seen this host in the last 30 minutes, ignore it.
-		if tm := time.Unix(info.LastSeenTimestamp/1000, 0).UTC(); tm.Before(now.Add(-30 * time.Minute)) {
+		lastSeen := time.Unix(info.LastSeenTimestamp/1000, 0).UTC()
+		if lastSeen.Before(now.Add(-30 * time.Minute)) {
 			inactive = append(inactive, info.EntityID)
 			continue
 		}
@@ -236,7 +245,7 @@ func (dtc *dynatraceClient) setHostCacheFromResponse(response []byte) error {
 		nz := info.NetworkZoneID
 
 		if (dtc.networkZone != "" && nz == dtc.networkZone) || (dtc.networkZone == "" && (nz == "default" || nz == "")) {
-			hostInfo := hostInfo{entityID: info.EntityID}
+			hostInfo := hostInfo{entityID: info.EntityID, lastSeen: lastSeen}
 
 			if v := info.AgentVersion; v != nil {
 				hostInfo.version = fmt.Sprintf("%d.%d.%d.%s", v.Major, v.Minor, v.Revision, v.Timestamp)
@@ -57,7 +57,7 @@ func (dtc *dynatraceClient) processLatestImageRequest(url string) (*LatestImageI
 		return nil, errors.WithStack(err)
 	}
 
-	response, err := dtc.httpClient.Do(request)
+	response, err := dtc.do(request)
 	if err != nil {
 		log.Info("failed to retrieve latest image")
 		return nil, err
@@ -99,7 +99,7 @@ func (dtc *dynatraceClient) performCreateOrUpdateKubernetesSetting(body []postKu
 		return "", err
 	}
 
-	res, err := dtc.httpClient.Do(req)
+	res, err := dtc.do(req)
 	if err != nil {
 		return "", fmt.Errorf("error making post request to dynatrace api: %w", err)
 	}
@@ -209,7 +209,7 @@ func (dtc *dynatraceClient) GetMonitoredEntitiesForKubeSystemUUID(kubeSystemUUID
 	q.Add("fields", "+lastSeenTms")
 	req.URL.RawQuery = q.Encode()
 
-	res, err := dtc.httpClient.Do(req)
+	res, err := dtc.do(req)
 
 	if err != nil {
 		log.Info("check if ME exists failed")
@@ -247,7 +247,7 @@ func (dtc *dynatraceClient) GetSettingsForMonitoredEntities(monitoredEntities []
 	q.Add("scopes", strings.Join(scopes, ","))
 	req.URL.RawQuery = q.Encode()
 
-	res, err := dtc.httpClient.Do(req)
+	res, err := dtc.do(req)
 
 	if err != nil {
 		log.Info("failed to retrieve MEs")
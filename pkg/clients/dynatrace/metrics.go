@@ -0,0 +1,58 @@
+package dynatrace
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	externalEndpoint  = "external"
+	requestErrorLabel = "error"
+)
+
+// the installer endpoints contain the requested os, type and version in the path,
+// they are templated so the number of endpoint label values stays bounded
+var endpointTemplates = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`^/v1/deployment/installer/agent/versions/[^/]+/[^/]+$`), "/v1/deployment/installer/agent/versions/{os}/{installerType}"},
+	{regexp.MustCompile(`^/v1/deployment/installer/agent/[^/]+/[^/]+/version/[^/]+$`), "/v1/deployment/installer/agent/{os}/{installerType}/version/{version}"},
+	{regexp.MustCompile(`^/v1/deployment/installer/agent/[^/]+/[^/]+/latest$`), "/v1/deployment/installer/agent/{os}/{installerType}/latest"},
+	{regexp.MustCompile(`^/v1/deployment/installer/agent/[^/]+/[^/]+/latest/metainfo$`), "/v1/deployment/installer/agent/{os}/{installerType}/latest/metainfo"},
+}
+
+// do sends the request and records its outcome and duration in the api client metrics
+func (dtc *dynatraceClient) do(req *http.Request) (*http.Response, error) {
+	endpoint := dtc.endpointLabel(req)
+	start := time.Now()
+
+	resp, err := dtc.httpClient.Do(req)
+
+	requestDurationMetric.WithLabelValues(endpoint, req.Method).Observe(time.Since(start).Seconds())
+	code := requestErrorLabel
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	requestsMetric.WithLabelValues(endpoint, req.Method, code).Inc()
+
+	return resp, err
+}
+
+func (dtc *dynatraceClient) endpointLabel(req *http.Request) string {
+	url := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+	if !strings.HasPrefix(url, dtc.url) {
+		return externalEndpoint
+	}
+
+	endpoint := strings.TrimPrefix(url, dtc.url)
+	for _, template := range endpointTemplates {
+		if template.pattern.MatchString(endpoint) {
+			return template.replacement
+		}
+	}
+	return endpoint
+}
@@ -0,0 +1,68 @@
+package dynatrace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointLabel(t *testing.T) {
+	dtc := &dynatraceClient{url: "https://tenant.live.dynatrace.com/api"}
+
+	testCases := map[string]string{
+		"https://tenant.live.dynatrace.com/api/v1/tokens/lookup":                                                       "/v1/tokens/lookup",
+		"https://tenant.live.dynatrace.com/api/v2/settings/objects?validateOnly=false":                                 "/v2/settings/objects",
+		"https://tenant.live.dynatrace.com/api/v1/deployment/installer/agent/unix/paas/version/1.2.3?flavor=default":   "/v1/deployment/installer/agent/{os}/{installerType}/version/{version}",
+		"https://tenant.live.dynatrace.com/api/v1/deployment/installer/agent/unix/paas/latest?flavor=default":          "/v1/deployment/installer/agent/{os}/{installerType}/latest",
+		"https://tenant.live.dynatrace.com/api/v1/deployment/installer/agent/unix/paas/latest/metainfo?flavor=default": "/v1/deployment/installer/agent/{os}/{installerType}/latest/metainfo",
+		"https://tenant.live.dynatrace.com/api/v1/deployment/installer/agent/versions/unix/paas?flavor=default":        "/v1/deployment/installer/agent/versions/{os}/{installerType}",
+		"https://tenant.live.dynatrace.com/api/v1/deployment/installer/agent/connectioninfo":                           "/v1/deployment/installer/agent/connectioninfo",
+		"https://downloads.example.com/agent.zip":                                                                      externalEndpoint,
+	}
+
+	for url, expected := range testCases {
+		t.Run(url, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, dtc.endpointLabel(req))
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	dtc := &dynatraceClient{url: server.URL, httpClient: server.Client()}
+
+	t.Run("counts requests by status code", func(t *testing.T) {
+		counter := requestsMetric.WithLabelValues("/v1/metrics-test", http.MethodGet, "418")
+		before := testutil.ToFloat64(counter)
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/v1/metrics-test", nil)
+		require.NoError(t, err)
+		resp, err := dtc.do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	})
+	t.Run("counts failed requests as error", func(t *testing.T) {
+		counter := requestsMetric.WithLabelValues(externalEndpoint, http.MethodGet, requestErrorLabel)
+		before := testutil.ToFloat64(counter)
+
+		req, err := http.NewRequest(http.MethodGet, "http://127.0.0.1:0/unreachable", nil)
+		require.NoError(t, err)
+		_, err = dtc.do(req) //nolint:bodyclose
+		require.Error(t, err)
+
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	})
+}
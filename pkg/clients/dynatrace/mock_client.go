@@ -86,6 +86,11 @@ func (o *MockDynatraceClient) GetEntityIDForIP(ip string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+func (o *MockDynatraceClient) GetHostInfoForIP(ip string) (HostInfo, error) {
+	args := o.Called(ip)
+	return args.Get(0).(HostInfo), args.Error(1)
+}
+
 func (o *MockDynatraceClient) GetTokenScopes(token string) (TokenScopes, error) {
 	args := o.Called(token)
 	return args.Get(0).(TokenScopes), args.Error(1)
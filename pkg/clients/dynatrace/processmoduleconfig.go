@@ -141,7 +141,7 @@ func (dtc *dynatraceClient) GetProcessModuleConfig(prevRevision uint) (*ProcessM
 		return nil, err
 	}
 
-	resp, err := dtc.httpClient.Do(req)
+	resp, err := dtc.do(req)
 
 	if dtc.checkProcessModuleConfigRequestStatus(resp) {
 		return &ProcessModuleConfig{}, nil
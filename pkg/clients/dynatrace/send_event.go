@@ -12,6 +12,7 @@ import (
 
 const (
 	MarkedForTerminationEvent = "MARKED_FOR_TERMINATION"
+	CustomInfoEvent           = "CUSTOM_INFO"
 )
 
 // EventData struct which defines what event payload should contain
@@ -22,6 +23,8 @@ type EventData struct {
 	Description   string               `json:"description"`
 	AttachRules   EventDataAttachRules `json:"attachRules"`
 	Source        string               `json:"source"`
+	// CustomProperties are shown as key-value pairs in the details of the event
+	CustomProperties map[string]string `json:"customProperties,omitempty"`
 }
 
 type EventDataAttachRules struct {
@@ -49,7 +52,7 @@ func (dtc *dynatraceClient) SendEvent(eventData *EventData) error {
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Authorization", fmt.Sprintf("Api-Token %s", dtc.apiToken))
 
-	response, err := dtc.httpClient.Do(req)
+	response, err := dtc.do(req)
 
 	if err != nil {
 		return fmt.Errorf("error making post request to dynatrace api: %w", err)
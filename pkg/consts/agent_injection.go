@@ -22,6 +22,9 @@ const (
 	AgentInstallerTechEnv    = "TECHNOLOGIES"
 	AgentInstallerVersionEnv = "VERSION"
 
+	AgentInstallerMirrorEnv         = "INSTALLER_MIRROR"
+	AgentInstallerMirrorInsecureEnv = "INSTALLER_MIRROR_INSECURE"
+
 	AgentInstallPathEnv            = "INSTALLPATH"
 	AgentContainerCountEnv         = "CONTAINERS_COUNT"
 	AgentContainerNameEnvTemplate  = "CONTAINER_%d_NAME"
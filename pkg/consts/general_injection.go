@@ -3,6 +3,12 @@ package consts
 const (
 	InjectionFailurePolicyEnv = "FAILURE_POLICY"
 
+	// InjectionSilentFailureMessage is the termination message of the install container, if an error was masked by the "silent" failure policy
+	InjectionSilentFailureMessage = "injection failed silently"
+
+	// RefreshFlag is the flag of the init command, that keeps the configuration up to date instead of installing the OneAgent
+	RefreshFlag = "refresh"
+
 	K8sNodeNameEnv    = "K8S_NODE_NAME"
 	K8sPodNameEnv     = "K8S_PODNAME"
 	K8sPodUIDEnv      = "K8S_PODUID"
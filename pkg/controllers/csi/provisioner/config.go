@@ -5,6 +5,8 @@ import (
 )
 
 const (
+	controllerName = "csi-provisioner"
+
 	failedInstallAgentVersionEvent = "FailedInstallAgentVersion"
 	installAgentVersionEvent       = "InstallAgentVersion"
 )
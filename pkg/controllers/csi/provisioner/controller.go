@@ -19,10 +19,12 @@ package csiprovisioner
 import (
 	"context"
 	"fmt"
+	"runtime"
 	"time"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
 	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
 	csigc "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/gc"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
@@ -30,6 +32,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/artifact"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/image"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/url"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
@@ -52,6 +55,7 @@ const (
 
 type urlInstallerBuilder func(afero.Fs, dtclient.Client, *url.Properties) installer.Installer
 type imageInstallerBuilder func(afero.Fs, *image.Properties) (installer.Installer, error)
+type artifactInstallerBuilder func(afero.Fs, *artifact.Properties) (installer.Installer, error)
 
 // OneAgentProvisioner reconciles a DynaKube object
 type OneAgentProvisioner struct {
@@ -64,32 +68,34 @@ type OneAgentProvisioner struct {
 	path      metadata.PathResolver
 	gc        reconcile.Reconciler
 
-	dynatraceClientBuilder dynatraceclient.Builder
-	urlInstallerBuilder    urlInstallerBuilder
-	imageInstallerBuilder  imageInstallerBuilder
+	dynatraceClientBuilder   dynatraceclient.Builder
+	urlInstallerBuilder      urlInstallerBuilder
+	imageInstallerBuilder    imageInstallerBuilder
+	artifactInstallerBuilder artifactInstallerBuilder
 }
 
 // NewOneAgentProvisioner returns a new OneAgentProvisioner
 func NewOneAgentProvisioner(mgr manager.Manager, opts dtcsi.CSIOptions, db metadata.Access) *OneAgentProvisioner {
 	return &OneAgentProvisioner{
-		client:                 mgr.GetClient(),
-		apiReader:              mgr.GetAPIReader(),
-		opts:                   opts,
-		fs:                     afero.NewOsFs(),
-		recorder:               mgr.GetEventRecorderFor("OneAgentProvisioner"),
-		db:                     db,
-		path:                   metadata.PathResolver{RootDir: opts.RootDir},
-		gc:                     csigc.NewCSIGarbageCollector(mgr.GetAPIReader(), opts, db),
-		dynatraceClientBuilder: dynatraceclient.NewBuilder(mgr.GetAPIReader()),
-		urlInstallerBuilder:    url.NewUrlInstaller,
-		imageInstallerBuilder:  image.NewImageInstaller,
+		client:                   mgr.GetClient(),
+		apiReader:                mgr.GetAPIReader(),
+		opts:                     opts,
+		fs:                       afero.NewOsFs(),
+		recorder:                 mgr.GetEventRecorderFor("OneAgentProvisioner"),
+		db:                       db,
+		path:                     metadata.PathResolver{RootDir: opts.RootDir},
+		gc:                       csigc.NewCSIGarbageCollector(mgr.GetAPIReader(), opts, db),
+		dynatraceClientBuilder:   dynatraceclient.NewBuilder(mgr.GetAPIReader()),
+		urlInstallerBuilder:      url.NewUrlInstaller,
+		imageInstallerBuilder:    image.NewImageInstaller,
+		artifactInstallerBuilder: artifact.NewArtifactInstaller,
 	}
 }
 
 func (provisioner *OneAgentProvisioner) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&dynatracev1beta1.DynaKube{}).
-		Complete(provisioner)
+		Complete(controllers.WithReconcileMetrics(controllerName, provisioner))
 }
 
 func (provisioner *OneAgentProvisioner) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
@@ -227,8 +233,11 @@ func (provisioner *OneAgentProvisioner) updateAgentInstallation(ctx context.Cont
 		latestProcessModuleConfig.AddProxy(proxy)
 	}
 
+	// the provisioner runs on every node with the image of the architecture of the node
+	nodeArch := runtime.GOARCH
+
 	if dk.CodeModulesImage() != "" {
-		updatedDigest, err := provisioner.installAgentImage(*dk, latestProcessModuleConfigCache)
+		updatedDigest, err := provisioner.installAgentImage(ctx, *dk, nodeArch, latestProcessModuleConfigCache)
 		if err != nil {
 			log.Info("error when updating agent from image", "error", err.Error())
 			// reporting error but not returning it to avoid immediate requeue and subsequently calling the API every few seconds
@@ -238,7 +247,7 @@ func (provisioner *OneAgentProvisioner) updateAgentInstallation(ctx context.Cont
 			dynakubeMetadata.ImageDigest = updatedDigest
 		}
 	} else {
-		updateVersion, err := provisioner.installAgentZip(*dk, dtc, latestProcessModuleConfigCache)
+		updateVersion, err := provisioner.installAgentZip(*dk, dtc, nodeArch, latestProcessModuleConfigCache)
 		if err != nil {
 			log.Info("error when updating agent from zip", "error", err.Error())
 			// reporting error but not returning it to avoid immediate requeue and subsequently calling the API every few seconds
@@ -1,47 +1,66 @@
 package csiprovisioner
 
 import (
+	"context"
+	"strings"
+
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/arch"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/artifact"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/image"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/url"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/processmoduleconfig"
 )
 
-func (provisioner *OneAgentProvisioner) installAgentImage(dynakube dynatracev1beta1.DynaKube, latestProcessModuleConfigCache *processModuleConfigCache) (string, error) {
+func (provisioner *OneAgentProvisioner) installAgentImage(ctx context.Context, dynakube dynatracev1beta1.DynaKube, nodeArch string, latestProcessModuleConfigCache *processModuleConfigCache) (string, error) {
 	tenantUUID, err := dynakube.TenantUUIDFromApiUrl()
 	if err != nil {
 		return "", err
 	}
 
-	if err != nil {
-		return "", err
-	}
-
-	targetImage := dynakube.CodeModulesImage()
+	// multi-arch images are pinned to the image of the architecture of the node
+	targetImage := dynakube.CodeModulesImageForArch(nodeArch)
 	imageDigest, err := image.GetDigest(targetImage)
 	if err != nil {
 		return "", err
 	}
 
-	imageInstaller, err := provisioner.imageInstallerBuilder(provisioner.fs, &image.Properties{
-		ImageUri:     targetImage,
-		ApiReader:    provisioner.apiReader,
-		Dynakube:     &dynakube,
-		PathResolver: provisioner.path,
-		Metadata:     provisioner.db,
-		ImageDigest:  imageDigest,
-	})
+	binaryDirName := imageDigest
+	var agentInstaller installer.Installer
+	if dynakube.IsCodeModulesArtifact() {
+		var technologies []string
+		technologies, err = provisioner.requestedTechnologies(ctx, &dynakube)
+		if err != nil {
+			return "", err
+		}
+		binaryDirName = artifactBinaryDirName(imageDigest, technologies)
+		agentInstaller, err = provisioner.artifactInstallerBuilder(provisioner.fs, &artifact.Properties{
+			ImageUri:     targetImage,
+			ApiReader:    provisioner.apiReader,
+			Dynakube:     &dynakube,
+			PathResolver: provisioner.path,
+			Technologies: technologies,
+		})
+	} else {
+		agentInstaller, err = provisioner.imageInstallerBuilder(provisioner.fs, &image.Properties{
+			ImageUri:     targetImage,
+			ApiReader:    provisioner.apiReader,
+			Dynakube:     &dynakube,
+			PathResolver: provisioner.path,
+			Metadata:     provisioner.db,
+			ImageDigest:  imageDigest,
+		})
+	}
 	if err != nil {
 		return "", err
 	}
 
-	targetDir := provisioner.path.AgentSharedBinaryDirForAgent(imageDigest)
+	targetDir := provisioner.path.AgentSharedBinaryDirForAgent(binaryDirName)
 	targetConfigDir := provisioner.path.AgentConfigDir(tenantUUID)
-	err = provisioner.installAgent(imageInstaller, dynakube, targetDir, targetImage, tenantUUID)
+	err = provisioner.installAgent(agentInstaller, dynakube, targetDir, targetImage, tenantUUID)
 	if err != nil {
 		return "", err
 	}
@@ -50,16 +69,28 @@ func (provisioner *OneAgentProvisioner) installAgentImage(dynakube dynatracev1be
 	if err != nil {
 		return "", err
 	}
-	return imageDigest, err
+	return binaryDirName, err
+}
+
+// artifactBinaryDirName keeps the installations of different technology selections of the same artifact apart
+func artifactBinaryDirName(imageDigest string, technologies []string) string {
+	if len(technologies) == 0 {
+		return imageDigest
+	}
+	return imageDigest + "-" + strings.Join(technologies, "-")
 }
 
-func (provisioner *OneAgentProvisioner) installAgentZip(dynakube dynatracev1beta1.DynaKube, dtc dtclient.Client, latestProcessModuleConfigCache *processModuleConfigCache) (string, error) {
+func (provisioner *OneAgentProvisioner) installAgentZip(dynakube dynatracev1beta1.DynaKube, dtc dtclient.Client, nodeArch string, latestProcessModuleConfigCache *processModuleConfigCache) (string, error) {
 	tenantUUID, err := dynakube.TenantUUIDFromApiUrl()
 	if err != nil {
 		return "", err
 	}
 	targetVersion := dynakube.CodeModulesVersion()
-	urlInstaller := provisioner.urlInstallerBuilder(provisioner.fs, dtc, getUrlProperties(targetVersion, provisioner.path))
+	urlProperties, err := getUrlProperties(targetVersion, provisioner.path, nodeArch)
+	if err != nil {
+		return "", err
+	}
+	urlInstaller := provisioner.urlInstallerBuilder(provisioner.fs, dtc, urlProperties)
 
 	targetDir := provisioner.path.AgentSharedBinaryDirForAgent(targetVersion)
 	targetConfigDir := provisioner.path.AgentConfigDir(tenantUUID)
@@ -91,15 +122,20 @@ func (provisioner *OneAgentProvisioner) installAgent(agentInstaller installer.In
 	return nil
 }
 
-func getUrlProperties(targetVersion string, pathResolver metadata.PathResolver) *url.Properties {
+func getUrlProperties(targetVersion string, pathResolver metadata.PathResolver, nodeArch string) (*url.Properties, error) {
+	installerArch, installerFlavor, err := arch.ForKubernetesArch(nodeArch)
+	if err != nil {
+		return nil, err
+	}
+
 	return &url.Properties{
 		Os:            dtclient.OsUnix,
 		Type:          dtclient.InstallerTypePaaS,
-		Arch:          arch.Arch,
-		Flavor:        arch.Flavor,
+		Arch:          installerArch,
+		Flavor:        installerFlavor,
 		Technologies:  []string{"all"},
 		TargetVersion: targetVersion,
 		SkipMetadata:  true,
 		PathResolver:  pathResolver,
-	}
+	}, nil
 }
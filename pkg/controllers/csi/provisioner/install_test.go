@@ -1,8 +1,10 @@
 package csiprovisioner
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
@@ -11,6 +13,7 @@ import (
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/artifact"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/image"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/url"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/processmoduleconfig"
@@ -40,7 +43,7 @@ func TestUpdateAgent(t *testing.T) {
 			Return(true, nil).Run(mockFsAfterInstall(provisioner, testVersion))
 		provisioner.urlInstallerBuilder = mockUrlInstallerBuilder(installerMock)
 
-		currentVersion, err := provisioner.installAgentZip(dk, &dtclient.MockDynatraceClient{}, &processModuleCache)
+		currentVersion, err := provisioner.installAgentZip(dk, &dtclient.MockDynatraceClient{}, runtime.GOARCH, &processModuleCache)
 		require.NoError(t, err)
 		assert.Equal(t, testVersion, currentVersion)
 		t_utils.AssertEvents(t,
@@ -73,7 +76,7 @@ func TestUpdateAgent(t *testing.T) {
 			Return(true, nil).Run(mockFsAfterInstall(provisioner, newVersion))
 		provisioner.urlInstallerBuilder = mockUrlInstallerBuilder(installerMock)
 
-		currentVersion, err := provisioner.installAgentZip(dk, &dtclient.MockDynatraceClient{}, &processModuleCache)
+		currentVersion, err := provisioner.installAgentZip(dk, &dtclient.MockDynatraceClient{}, runtime.GOARCH, &processModuleCache)
 		require.NoError(t, err)
 		assert.Equal(t, newVersion, currentVersion)
 	})
@@ -92,7 +95,7 @@ func TestUpdateAgent(t *testing.T) {
 			Return(false, nil)
 
 		provisioner.urlInstallerBuilder = mockUrlInstallerBuilder(installerMock)
-		currentVersion, err := provisioner.installAgentZip(dk, &dtclient.MockDynatraceClient{}, &processModuleCache)
+		currentVersion, err := provisioner.installAgentZip(dk, &dtclient.MockDynatraceClient{}, runtime.GOARCH, &processModuleCache)
 
 		require.NoError(t, err)
 		assert.Equal(t, testVersion, currentVersion)
@@ -113,7 +116,7 @@ func TestUpdateAgent(t *testing.T) {
 			Return(nil)
 		provisioner.imageInstallerBuilder = mockImageInstallerBuilder(installerMock)
 
-		currentVersion, err := provisioner.installAgentImage(dk, &processModuleCache)
+		currentVersion, err := provisioner.installAgentImage(context.Background(), dk, runtime.GOARCH, &processModuleCache)
 
 		require.Error(t, err)
 		assert.Equal(t, "", currentVersion)
@@ -141,7 +144,7 @@ func TestUpdateAgent(t *testing.T) {
 			Return(true, nil).Run(mockFsAfterInstall(provisioner, testImageDigest))
 		provisioner.imageInstallerBuilder = mockImageInstallerBuilder(installerMock)
 
-		currentVersion, err := provisioner.installAgentImage(dk, &processModuleCache)
+		currentVersion, err := provisioner.installAgentImage(context.Background(), dk, runtime.GOARCH, &processModuleCache)
 		require.NoError(t, err)
 		assert.Equal(t, testImageDigest, currentVersion)
 	})
@@ -162,7 +165,7 @@ func TestUpdateAgent(t *testing.T) {
 			Return(true, nil).Run(mockFsAfterInstall(provisioner, testImageDigest))
 		provisioner.imageInstallerBuilder = mockImageInstallerBuilder(installerMock)
 
-		currentVersion, err := provisioner.installAgentImage(dk, &processModuleCache)
+		currentVersion, err := provisioner.installAgentImage(context.Background(), dk, runtime.GOARCH, &processModuleCache)
 		require.NoError(t, err)
 		assert.Equal(t, testImageDigest, currentVersion)
 	})
@@ -186,10 +189,73 @@ func TestUpdateAgent(t *testing.T) {
 			Return(true, nil).Run(mockFsAfterInstall(provisioner, testImageDigest))
 		provisioner.imageInstallerBuilder = mockImageInstallerBuilder(installerMock)
 
-		currentVersion, err := provisioner.installAgentImage(dk, &processModuleCache)
+		currentVersion, err := provisioner.installAgentImage(context.Background(), dk, runtime.GOARCH, &processModuleCache)
 		require.NoError(t, err)
 		assert.Equal(t, testImageDigest, currentVersion)
 	})
+	t.Run("codeModulesImage of multi-arch image uses image of node architecture", func(t *testing.T) {
+		armImageDigest := "8ece13a07a20c77a31cc36906a10ebc90bd47970905ee61e8ed491b7f4c5d62f"
+		dockerconfigjsonContent := `{"auths":{}}`
+		var revision uint = 3
+		processModuleCache := createTestProcessModuleConfigCache(revision)
+
+		dk := createTestDynaKubeWithImage(testImageDigest)
+		dk.Status.CodeModules.Platforms = []status.PlatformImage{
+			{Architecture: "arm64", Digest: "sha256:" + armImageDigest},
+		}
+		provisioner := createTestProvisioner(createMockedPullSecret(dk, dockerconfigjsonContent))
+		targetDir := provisioner.path.AgentSharedBinaryDirForAgent(armImageDigest)
+		installerMock := &installer.Mock{}
+		installerMock.
+			On("InstallAgent", targetDir).
+			Return(true, nil).Run(mockFsAfterInstall(provisioner, armImageDigest))
+		var installedImage string
+		provisioner.imageInstallerBuilder = func(_ afero.Fs, props *image.Properties) (installer.Installer, error) {
+			installedImage = props.ImageUri
+			return installerMock, nil
+		}
+
+		currentVersion, err := provisioner.installAgentImage(context.Background(), dk, "arm64", &processModuleCache)
+		require.NoError(t, err)
+		assert.Equal(t, armImageDigest, currentVersion)
+		assert.Equal(t, "some.registry.com/image:1.234.345@sha256:"+armImageDigest, installedImage)
+	})
+	t.Run("codeModulesImage of artifact unpacks requested technologies", func(t *testing.T) {
+		dockerconfigjsonContent := `{"auths":{}}`
+		var revision uint = 3
+		processModuleCache := createTestProcessModuleConfigCache(revision)
+
+		dk := createTestDynaKubeWithImage(testImageDigest)
+		dk.Status.CodeModules.Type = status.ArtifactImageType
+		provisioner := createTestProvisioner(
+			createMockedPullSecret(dk, dockerconfigjsonContent),
+			createTestNamespace("app", dk.Name, "nodejs,java"),
+		)
+		binaryDirName := testImageDigest + "-java-nodejs"
+		targetDir := provisioner.path.AgentSharedBinaryDirForAgent(binaryDirName)
+		installerMock := &installer.Mock{}
+		installerMock.
+			On("InstallAgent", targetDir).
+			Return(true, nil).Run(mockFsAfterInstall(provisioner, binaryDirName))
+		var installedTechnologies []string
+		provisioner.artifactInstallerBuilder = func(_ afero.Fs, props *artifact.Properties) (installer.Installer, error) {
+			installedTechnologies = props.Technologies
+			return installerMock, nil
+		}
+
+		currentVersion, err := provisioner.installAgentImage(context.Background(), dk, runtime.GOARCH, &processModuleCache)
+		require.NoError(t, err)
+		assert.Equal(t, binaryDirName, currentVersion)
+		assert.Equal(t, []string{"java", "nodejs"}, installedTechnologies)
+	})
+	t.Run("zip install fails for unsupported node architecture", func(t *testing.T) {
+		dk := createTestDynaKubeWithZip(testVersion)
+		provisioner := createTestProvisioner()
+		processModuleCache := createTestProcessModuleConfigCache(3)
+
+		_, err := provisioner.installAgentZip(dk, &dtclient.MockDynatraceClient{}, "mips", &processModuleCache)
+		require.Error(t, err)
+	})
 }
 
 func mockFsAfterInstall(provisioner *OneAgentProvisioner, version string) func(mock.Arguments) {
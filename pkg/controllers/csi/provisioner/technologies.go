@@ -0,0 +1,63 @@
+package csiprovisioner
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+)
+
+const allTechnologies = "all"
+
+var technologyNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// requestedTechnologies collects the technologies the monitored namespaces narrow the code modules to with the
+// oneagent.dynatrace.com/technologies annotation, it is nil if all technologies are needed.
+// A monitored namespace without the annotation needs all technologies, as any pod scheduled there could request them.
+func (provisioner *OneAgentProvisioner) requestedTechnologies(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) ([]string, error) {
+	namespaces, err := mapper.GetNamespacesForDynakube(ctx, provisioner.apiReader, dynakube.Name)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var technologies []string
+	for _, namespace := range namespaces {
+		requested := parseTechnologies(namespace.Annotations[dtwebhook.AnnotationTechnologies])
+		if requested == nil {
+			return nil, nil
+		}
+		technologies = appendTechnologies(technologies, requested)
+	}
+	sort.Strings(technologies)
+	return technologies, nil
+}
+
+// parseTechnologies normalizes the comma separated list of the oneagent.dynatrace.com/technologies annotation,
+// it is nil if all technologies are requested.
+func parseTechnologies(value string) []string {
+	var technologies []string
+	for _, technology := range strings.Split(value, ",") {
+		technology = strings.ToLower(strings.TrimSpace(technology))
+		if technology == allTechnologies {
+			return nil
+		} else if technologyNameRegex.MatchString(technology) && !slices.Contains(technologies, technology) {
+			technologies = append(technologies, technology)
+		}
+	}
+	return technologies
+}
+
+func appendTechnologies(technologies []string, requested []string) []string {
+	for _, technology := range requested {
+		if !slices.Contains(technologies, technology) {
+			technologies = append(technologies, technology)
+		}
+	}
+	return technologies
+}
@@ -0,0 +1,93 @@
+package csiprovisioner
+
+import (
+	"context"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testTechnologiesDkName = "test-dk"
+
+func TestRequestedTechnologies(t *testing.T) {
+	ctx := context.Background()
+	dk := &dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: testTechnologiesDkName}}
+
+	t.Run("no monitored namespaces means no restriction", func(t *testing.T) {
+		provisioner := createTestProvisioner()
+
+		technologies, err := provisioner.requestedTechnologies(ctx, dk)
+		require.NoError(t, err)
+		assert.Nil(t, technologies)
+	})
+	t.Run("namespace without annotation requests all technologies", func(t *testing.T) {
+		provisioner := createTestProvisioner(
+			createTestNamespace("java-apps", testTechnologiesDkName, "java"),
+			createTestNamespace("other-apps", testTechnologiesDkName, ""),
+		)
+
+		technologies, err := provisioner.requestedTechnologies(ctx, dk)
+		require.NoError(t, err)
+		assert.Nil(t, technologies)
+	})
+	t.Run("technologies of the namespaces are merged", func(t *testing.T) {
+		provisioner := createTestProvisioner(
+			createTestNamespace("java-apps", testTechnologiesDkName, "Java, php"),
+			createTestNamespace("node-apps", testTechnologiesDkName, "nodejs"),
+		)
+
+		technologies, err := provisioner.requestedTechnologies(ctx, dk)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"java", "nodejs", "php"}, technologies)
+	})
+	t.Run("namespace requesting all technologies lifts the restriction", func(t *testing.T) {
+		provisioner := createTestProvisioner(
+			createTestNamespace("java-apps", testTechnologiesDkName, "java"),
+			createTestNamespace("other-apps", testTechnologiesDkName, "all"),
+		)
+
+		technologies, err := provisioner.requestedTechnologies(ctx, dk)
+		require.NoError(t, err)
+		assert.Nil(t, technologies)
+	})
+	t.Run("namespaces of other dynakubes are ignored", func(t *testing.T) {
+		provisioner := createTestProvisioner(
+			createTestNamespace("java-apps", testTechnologiesDkName, "java"),
+			createTestNamespace("other-apps", "other-dk", ""),
+		)
+
+		technologies, err := provisioner.requestedTechnologies(ctx, dk)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"java"}, technologies)
+	})
+}
+
+func TestParseTechnologies(t *testing.T) {
+	t.Run("empty value means all technologies", func(t *testing.T) {
+		assert.Nil(t, parseTechnologies(""))
+	})
+	t.Run("technologies are normalized", func(t *testing.T) {
+		assert.Equal(t, []string{"nodejs", "java"}, parseTechnologies(" NodeJS,java,, java,../php"))
+	})
+	t.Run("all overrides other technologies", func(t *testing.T) {
+		assert.Nil(t, parseTechnologies("java,all"))
+	})
+}
+
+func createTestNamespace(name string, dkName string, technologies string) *corev1.Namespace {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{dtwebhook.InjectionInstanceLabel: dkName},
+		},
+	}
+	if technologies != "" {
+		namespace.Annotations = map[string]string{dtwebhook.AnnotationTechnologies: technologies}
+	}
+	return namespace
+}
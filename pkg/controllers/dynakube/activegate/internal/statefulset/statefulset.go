@@ -9,6 +9,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/internal/statefulset/builder"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/internal/statefulset/builder/modifiers"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/deploymentmetadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/address"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/prioritymap"
@@ -83,9 +84,12 @@ func (statefulSetBuilder Builder) getBaseSpec() appsv1.StatefulSetSpec {
 		PodManagementPolicy: appsv1.ParallelPodManagement,
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
-				Annotations: map[string]string{
-					consts.AnnotationActiveGateConfigurationHash: statefulSetBuilder.configHash,
-				},
+				Annotations: kubeobjects.MergeMap(
+					map[string]string{
+						consts.AnnotationActiveGateConfigurationHash: statefulSetBuilder.configHash,
+					},
+					token.RotationAnnotations(&statefulSetBuilder.dynakube),
+				),
 			},
 		},
 	}
@@ -1,6 +1,8 @@
 package dynakube
 
 import (
+	"time"
+
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,6 +29,21 @@ func (controller *Controller) setConditionTokenReady(dynakube *dynatracev1beta1.
 	controller.setAndLogCondition(dynakube, tokenErrorCondition)
 }
 
+func (controller *Controller) setConditionTokensRotated(dynakube *dynatracev1beta1.DynaKube) {
+	lastTokenRotation := dynakube.Status.DynatraceApi.LastTokenRotation
+	tokensRotatedCondition := metav1.Condition{
+		Type:               dynatracev1beta1.TokenRotationConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             dynatracev1beta1.ReasonTokensRotated,
+		Message:            "tokens were rotated at " + lastTokenRotation.UTC().Format(time.RFC3339) + ", updating the components using them",
+		LastTransitionTime: lastTokenRotation,
+	}
+
+	// the condition stays true for further rotations, so it is replaced to keep the transition time of the last one
+	meta.RemoveStatusCondition(&dynakube.Status.Conditions, tokensRotatedCondition.Type)
+	meta.SetStatusCondition(&dynakube.Status.Conditions, tokensRotatedCondition)
+}
+
 func (controller *Controller) setAndLogCondition(dynakube *dynatracev1beta1.DynaKube, newCondition metav1.Condition) {
 	controller.removeDeprecatedConditionTypes(dynakube)
 	statusCondition := meta.FindStatusCondition(dynakube.Status.Conditions, newCondition.Type)
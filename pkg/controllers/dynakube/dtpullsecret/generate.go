@@ -1,12 +1,15 @@
 package dtpullsecret
 
 import (
+	"context"
 	b64 "encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/url"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -35,7 +38,7 @@ func newDockerConfigWithAuth(username string, password string, registry string,
 	}
 }
 
-func (r *Reconciler) GenerateData() (map[string][]byte, error) {
+func (r *Reconciler) GenerateData(ctx context.Context) (map[string][]byte, error) {
 	var registryToken string
 
 	registry, err := getImageRegistryFromAPIURL(r.dynakube.Spec.APIURL)
@@ -62,9 +65,42 @@ func (r *Reconciler) GenerateData() (map[string][]byte, error) {
 		registry,
 		r.buildAuthString(tenantUUID, registryToken))
 
+	err = r.addMirrorCredentials(ctx, dockerCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return pullSecretDataFromDockerConfig(dockerCfg)
 }
 
+// addMirrorCredentials adds the credentials for the mirror, so the components can be pulled from it
+func (r *Reconciler) addMirrorCredentials(ctx context.Context, dockerCfg *dockerConfig) error {
+	mirror := r.dynakube.Spec.Mirror
+	if mirror == nil || mirror.CredentialsSecret == "" {
+		return nil
+	}
+
+	var credentialsSecret corev1.Secret
+	err := r.apiReader.Get(ctx, client.ObjectKey{Name: mirror.CredentialsSecret, Namespace: r.dynakube.Namespace}, &credentialsSecret)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to get mirror credentials secret %s", mirror.CredentialsSecret)
+	}
+
+	var mirrorCfg dockerConfig
+	err = json.Unmarshal(credentialsSecret.Data[DockerConfigJson], &mirrorCfg)
+	if err != nil {
+		return errors.WithMessagef(err, "mirror credentials secret %s doesn't contain a valid %s", mirror.CredentialsSecret, DockerConfigJson)
+	}
+
+	for registry, auth := range mirrorCfg.Auths {
+		// the credentials for the tenant registry take precedence
+		if _, exists := dockerCfg.Auths[registry]; !exists {
+			dockerCfg.Auths[registry] = auth
+		}
+	}
+	return nil
+}
+
 func (r *Reconciler) buildAuthString(tenantUUID string, registryToken string) string {
 	auth := fmt.Sprintf("%s:%s", tenantUUID, registryToken)
 	return b64.StdEncoding.EncodeToString([]byte(auth))
@@ -1,16 +1,20 @@
 package dtpullsecret
 
 import (
+	"context"
 	b64 "encoding/base64"
 	"encoding/json"
 	"fmt"
 	"testing"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -46,7 +50,7 @@ func TestReconciler_GenerateData(t *testing.T) {
 		},
 	}
 
-	data, err := r.GenerateData()
+	data, err := r.GenerateData(context.Background())
 
 	assert.NoError(t, err)
 	assert.NotNil(t, data)
@@ -70,3 +74,67 @@ func TestReconciler_GenerateData(t *testing.T) {
 	assert.NotNil(t, actual)
 	assert.Equal(t, expected, actual)
 }
+
+func TestReconciler_GenerateDataWithMirror(t *testing.T) {
+	mirrorAuth := dockerAuthentication{
+		Username: "mirror-user",
+		Password: "mirror-password",
+		Auth:     b64.StdEncoding.EncodeToString([]byte("mirror-user:mirror-password")),
+	}
+	mirrorConfig, err := json.Marshal(dockerConfig{
+		Auths: map[string]dockerAuthentication{
+			"registry.dynatrace-mirror:5000": mirrorAuth,
+			testApiUrlHost:                   {Username: "other-user"},
+		},
+	})
+	require.NoError(t, err)
+
+	dynakube := &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: testApiUrl,
+			Mirror: &dynatracev1beta1.MirrorSpec{
+				Registry:          "registry.dynatrace-mirror:5000/dynatrace",
+				CredentialsSecret: "mirror-credentials",
+			},
+		},
+	}
+	tokens := token.Tokens{
+		dtclient.DynatracePaasToken: token.Token{Value: testPaasToken},
+	}
+
+	t.Run(`adds credentials of mirror`, func(t *testing.T) {
+		r := &Reconciler{
+			dynakube: dynakube,
+			tokens:   tokens,
+			apiReader: fake.NewClient(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "mirror-credentials", Namespace: testNamespace},
+				Data:       map[string][]byte{DockerConfigJson: mirrorConfig},
+			}),
+		}
+
+		data, err := r.GenerateData(context.Background())
+		require.NoError(t, err)
+
+		var actual dockerConfig
+		err = json.Unmarshal(data[DockerConfigJson], &actual)
+		require.NoError(t, err)
+
+		assert.Len(t, actual.Auths, 2)
+		assert.Equal(t, mirrorAuth, actual.Auths["registry.dynatrace-mirror:5000"])
+		assert.Equal(t, testTenant, actual.Auths[testApiUrlHost].Username)
+	})
+	t.Run(`fails if mirror credentials are missing`, func(t *testing.T) {
+		r := &Reconciler{
+			dynakube:  dynakube,
+			tokens:    tokens,
+			apiReader: fake.NewClient(),
+		}
+
+		_, err := r.GenerateData(context.Background())
+		require.Error(t, err)
+	})
+}
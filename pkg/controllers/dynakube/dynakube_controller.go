@@ -9,6 +9,7 @@ import (
 	dynatracestatus "github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/apimonitoring"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo"
@@ -17,13 +18,18 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceapi"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/istio"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/mirror"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/oneagent/hoststatus"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/plan"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/version"
 	dtingestendpoint "github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/ingestendpoint"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/initgeneration"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/injectionstatus"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/rollout"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubesystem"
@@ -36,8 +42,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -58,10 +66,10 @@ func Add(mgr manager.Manager, _ string) error {
 
 // NewController returns a new ReconcileDynaKube
 func NewController(mgr manager.Manager, clusterID string) *Controller {
-	return NewDynaKubeController(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetScheme(), mgr.GetConfig(), clusterID)
+	return NewDynaKubeController(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor(controllerName), clusterID)
 }
 
-func NewDynaKubeController(kubeClient client.Client, apiReader client.Reader, scheme *runtime.Scheme, config *rest.Config, clusterID string) *Controller { //nolint:revive
+func NewDynaKubeController(kubeClient client.Client, apiReader client.Reader, scheme *runtime.Scheme, config *rest.Config, recorder record.EventRecorder, clusterID string) *Controller { //nolint:revive
 	return &Controller{
 		client:                 kubeClient,
 		apiReader:              apiReader,
@@ -70,6 +78,7 @@ func NewDynaKubeController(kubeClient client.Client, apiReader client.Reader, sc
 		dynatraceClientBuilder: dynatraceclient.NewBuilder(apiReader),
 		istioClientBuilder:     istio.NewClient,
 		registryClientBuilder:  registry.NewClient,
+		recorder:               recorder,
 		config:                 config,
 		operatorNamespace:      os.Getenv(kubeobjects.EnvPodNamespace),
 		clusterID:              clusterID,
@@ -77,13 +86,20 @@ func NewDynaKubeController(kubeClient client.Client, apiReader client.Reader, sc
 }
 
 func (controller *Controller) SetupWithManager(mgr ctrl.Manager) error {
+	tokenFileWatcher := token.NewFileWatcher(mgr.GetAPIReader(), controller.operatorNamespace)
+	if err := mgr.Add(tokenFileWatcher); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&dynatracev1beta1.DynaKube{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&appsv1.DaemonSet{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Secret{}).
-		Complete(controller)
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(controller.mapTokenSecretToDynakubes)).
+		WatchesRawSource(tokenFileWatcher.Source(), &handler.EnqueueRequestForObject{}).
+		Complete(controllers.WithReconcileMetrics(controllerName, controller))
 }
 
 // Controller reconciles a DynaKube object
@@ -97,6 +113,10 @@ type Controller struct {
 	dynatraceClientBuilder dynatraceclient.Builder
 	istioClientBuilder     istio.ClientBuilder
 	registryClientBuilder  registry.ClientBuilder
+	recorder               record.EventRecorder
+
+	// set while planning, external writes are recorded with it instead of being made
+	planRecorder *plan.Recorder
 
 	config            *rest.Config
 	operatorNamespace string
@@ -131,6 +151,23 @@ func (controller *Controller) setRequeueAfterIfNewIsShorter(requeueAfter time.Du
 }
 
 func (controller *Controller) reconcile(ctx context.Context, dynaKube *dynatracev1beta1.DynaKube) (reconcile.Result, error) {
+	err := controller.reconcileAndUpdateStatus(ctx, dynaKube)
+
+	switch {
+	case dynatraceapi.IsUnreachable(err):
+		log.Info("dynaTrace API server is unavailable or request limit reached! trying again in one minute",
+			"errorCode", dynatraceapi.StatusCode(err), "errorMessage", dynatraceapi.Message(err))
+		// should we set the phase to error ?
+		return reconcile.Result{RequeueAfter: fastUpdateInterval}, nil
+
+	case err != nil:
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: controller.requeueAfter}, nil
+}
+
+// reconcileAndUpdateStatus reconciles the DynaKube and updates its status, the status is left alone if the Dynatrace API is unreachable
+func (controller *Controller) reconcileAndUpdateStatus(ctx context.Context, dynaKube *dynatracev1beta1.DynaKube) error {
 	oldStatus := *dynaKube.Status.DeepCopy()
 
 	controller.requeueAfter = defaultUpdateInterval
@@ -139,10 +176,7 @@ func (controller *Controller) reconcile(ctx context.Context, dynaKube *dynatrace
 
 	switch {
 	case dynatraceapi.IsUnreachable(err):
-		log.Info("dynaTrace API server is unavailable or request limit reached! trying again in one minute",
-			"errorCode", dynatraceapi.StatusCode(err), "errorMessage", dynatraceapi.Message(err))
-		// should we set the phase to error ?
-		return reconcile.Result{RequeueAfter: fastUpdateInterval}, nil
+		return err
 
 	case err != nil:
 		controller.setRequeueAfterIfNewIsShorter(fastUpdateInterval)
@@ -159,14 +193,10 @@ func (controller *Controller) reconcile(ctx context.Context, dynaKube *dynatrace
 		log.Info("status changed, updating DynaKube")
 		controller.setRequeueAfterIfNewIsShorter(changesUpdateInterval)
 		if errClient := controller.updateDynakubeStatus(ctx, dynaKube); errClient != nil {
-			return reconcile.Result{}, errors.WithMessagef(errClient, "failed to update DynaKube after failure, original error: %s", err)
+			return errors.WithMessagef(errClient, "failed to update DynaKube after failure, original error: %s", err)
 		}
 	}
-
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-	return reconcile.Result{RequeueAfter: controller.requeueAfter}, nil
+	return err
 }
 
 func (controller *Controller) getDynakubeOrUnmap(ctx context.Context, dkName, dkNamespace string) (*dynatracev1beta1.DynaKube, error) {
@@ -250,6 +280,8 @@ func (controller *Controller) reconcileDynaKube(ctx context.Context, dynakube *d
 		return err
 	}
 
+	controller.reconcileTokenRotation(dynakube, tokens)
+
 	dynatraceClientBuilder := controller.dynatraceClientBuilder.
 		SetContext(ctx).
 		SetDynakube(*dynakube).
@@ -267,7 +299,9 @@ func (controller *Controller) reconcileDynaKube(ctx context.Context, dynakube *d
 		return err
 	}
 
+	start := time.Now()
 	err = controller.reconcileConnectionInfo(ctx, dynakube, dynatraceClient)
+	controllers.ObserveReconcile(controllerName, "connectioninfo", start, err)
 	if err != nil {
 		return err
 	}
@@ -281,9 +315,11 @@ func (controller *Controller) reconcileDynaKube(ctx context.Context, dynakube *d
 		}
 	}
 
+	start = time.Now()
 	err = dtpullsecret.
 		NewReconciler(controller.client, controller.apiReader, controller.scheme, dynakube, tokens).
 		Reconcile(ctx)
+	controllers.ObserveReconcile(controllerName, "dtpullsecret", start, err)
 	if err != nil {
 		log.Info("could not reconcile Dynatrace pull secret")
 		return err
@@ -308,12 +344,21 @@ func (controller *Controller) reconcileDynaKube(ctx context.Context, dynakube *d
 		controller.fs,
 		timeprovider.New().Freeze(),
 	)
+	start = time.Now()
 	err = versionReconciler.Reconcile(ctx)
+	controllers.ObserveReconcile(controllerName, "version", start, err)
 	if err != nil {
 		log.Info("could not reconcile component versions")
 		return err
 	}
 
+	if nextUpdateWindow := versionReconciler.NextUpdateWindow(); !nextUpdateWindow.IsZero() {
+		// held back versions are rolled out as soon as the update window opens
+		controller.setRequeueAfterIfNewIsShorter(time.Until(nextUpdateWindow))
+	}
+
+	controller.reconcileMirror(ctx, dynakube, dynatraceClient)
+
 	return controller.reconcileComponents(ctx, dynatraceClient, dynakube)
 }
 
@@ -329,27 +374,104 @@ func (controller *Controller) reconcileConnectionInfo(ctx context.Context, dynak
 	return err
 }
 
+// reconcileMirror doesn't stop the reconciliation on errors, the components keep using the source images until the mirror is in sync
+func (controller *Controller) reconcileMirror(ctx context.Context, dynakube *dynatracev1beta1.DynaKube, dynatraceClient dtclient.Client) {
+	mirrorReconciler := mirror.NewReconciler(dynakube, controller.apiReader, dynatraceClient, controller.fs, timeprovider.New().Freeze())
+	if controller.planRecorder != nil {
+		controller.planMirror(dynakube, mirrorReconciler)
+		return
+	}
+
+	start := time.Now()
+	err := mirrorReconciler.Reconcile(ctx)
+	controllers.ObserveReconcile(controllerName, "mirror", start, err)
+	if err != nil {
+		log.Error(err, "could not sync mirror")
+		controller.setRequeueAfterIfNewIsShorter(fastUpdateInterval)
+		return
+	}
+
+	if nextSyncWindow := mirrorReconciler.NextSyncWindow(); !nextSyncWindow.IsZero() {
+		controller.setRequeueAfterIfNewIsShorter(time.Until(nextSyncWindow))
+	}
+}
+
+// planMirror records the artifacts the mirror sync would copy, the registries and the Dynatrace API are left alone
+func (controller *Controller) planMirror(dynakube *dynatracev1beta1.DynaKube, mirrorReconciler *mirror.Reconciler) {
+	artifacts, err := mirrorReconciler.PendingArtifacts()
+	if err != nil {
+		log.Error(err, "could not plan mirror sync")
+		return
+	}
+	for _, artifact := range artifacts {
+		controller.planRecorder.RecordMirrorSync(dynakube.Spec.Mirror.Registry, artifact)
+	}
+}
+
 func (controller *Controller) reconcileComponents(ctx context.Context, dynatraceClient dtclient.Client, dynakube *dynatracev1beta1.DynaKube) error {
+	start := time.Now()
 	err := controller.reconcileActiveGate(ctx, dynakube, dynatraceClient)
+	controllers.ObserveReconcile(controllerName, "activegate", start, err)
 	if err != nil {
 		log.Info("could not reconcile ActiveGate")
 		return err
 	}
 
+	start = time.Now()
 	err = controller.reconcileOneAgent(ctx, dynakube)
+	controllers.ObserveReconcile(controllerName, "oneagent", start, err)
 	if err != nil {
 		log.Info("could not reconcile OneAgent")
 		return err
 	}
 
+	start = time.Now()
 	err = controller.reconcileAppInjection(ctx, dynakube)
+	controllers.ObserveReconcile(controllerName, "injection", start, err)
 	if err != nil {
 		log.Info("could not reconcile app injection")
 		return err
 	}
+
+	controller.reconcileHostStatus(dynakube, dynatraceClient)
+	controller.reconcileInjectionStatus(ctx, dynakube)
+	controller.reconcileRollout(ctx, dynakube)
 	return nil
 }
 
+// reconcileHostStatus doesn't stop the reconciliation on errors, the host status is only informational
+func (controller *Controller) reconcileHostStatus(dynakube *dynatracev1beta1.DynaKube, dynatraceClient dtclient.Client) {
+	start := time.Now()
+	nextUpdate, err := hoststatus.NewReconciler(dynakube, dynatraceClient, timeprovider.New().Freeze()).Reconcile()
+	controllers.ObserveReconcile(controllerName, "hoststatus", start, err)
+	if err != nil {
+		log.Error(err, "could not update the host status of the OneAgents")
+	}
+	if nextUpdate > 0 {
+		controller.setRequeueAfterIfNewIsShorter(nextUpdate)
+	}
+}
+
+// reconcileInjectionStatus doesn't stop the reconciliation on errors, the injection status is only informational
+func (controller *Controller) reconcileInjectionStatus(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) {
+	start := time.Now()
+	err := injectionstatus.NewReconciler(controller.client, controller.apiReader, dynakube).Reconcile(ctx)
+	controllers.ObserveReconcile(controllerName, "injectionstatus", start, err)
+	if err != nil {
+		log.Error(err, "could not aggregate injection status")
+	}
+}
+
+// reconcileRollout doesn't stop the reconciliation on errors, the remaining workloads are restarted in the next reconciliation
+func (controller *Controller) reconcileRollout(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) {
+	start := time.Now()
+	err := rollout.NewReconciler(controller.client, controller.apiReader, dynakube).Reconcile(ctx)
+	controllers.ObserveReconcile(controllerName, "rollout", start, err)
+	if err != nil {
+		log.Error(err, "could not roll out code modules upgrade")
+	}
+}
+
 func (controller *Controller) reconcileAppInjection(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) error {
 	if dynakube.NeedAppInjection() {
 		return controller.setupAppInjection(ctx, dynakube)
@@ -405,17 +527,29 @@ func (controller *Controller) removeAppInjection(ctx context.Context, dynakube *
 
 func (controller *Controller) reconcileOneAgent(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) error {
 	if !dynakube.NeedsOneAgent() {
+		oneagent.ResetCoverage(dynakube)
 		return controller.removeOneAgentDaemonSet(ctx, dynakube)
 	}
 
-	return oneagent.NewOneAgentReconciler(
-		controller.client, controller.apiReader, controller.scheme, controller.clusterID,
+	err := oneagent.NewOneAgentReconciler(
+		controller.client, controller.apiReader, controller.scheme, controller.recorder, controller.clusterID,
 	).Reconcile(ctx, dynakube)
+	if err != nil {
+		return err
+	}
+
+	if dynakube.Status.OneAgent.Rollout.InProgress() {
+		controller.setRequeueAfterIfNewIsShorter(fastUpdateInterval)
+	}
+	return nil
 }
 
 func (controller *Controller) removeOneAgentDaemonSet(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) error {
 	oneAgentDaemonSet := appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: dynakube.OneAgentDaemonsetName(), Namespace: dynakube.Namespace}}
-	return kubeobjects.Delete(ctx, controller.client, &oneAgentDaemonSet)
+	if err := kubeobjects.Delete(ctx, controller.client, &oneAgentDaemonSet); err != nil {
+		return err
+	}
+	return oneagent.DeleteNodeProfileDaemonSets(ctx, controller.client, dynakube)
 }
 
 func (controller *Controller) reconcileActiveGate(ctx context.Context, dynakube *dynatracev1beta1.DynaKube, dtc dtclient.Client) error {
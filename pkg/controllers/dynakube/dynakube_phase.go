@@ -55,7 +55,23 @@ func (controller *Controller) numberOfMissingOneagentPods(dynakube *dynatracev1b
 	if err != nil {
 		return 0, err
 	}
-	return oneAgentDaemonSet.Status.CurrentNumberScheduled - oneAgentDaemonSet.Status.NumberReady, nil
+	sum := oneAgentDaemonSet.Status.CurrentNumberScheduled - oneAgentDaemonSet.Status.NumberReady
+
+	// a DaemonSet of a node profile which doesn't exist yet has no pods to wait for
+	for _, profile := range dynakube.Spec.OneAgent.NodeProfiles {
+		profileDaemonSet := &appsv1.DaemonSet{}
+		instanceName := dynakube.OneAgentNodeProfileDaemonsetName(profile.Name)
+		err := controller.client.Get(context.TODO(), types.NamespacedName{Name: instanceName, Namespace: dynakube.Namespace}, profileDaemonSet)
+
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		sum += profileDaemonSet.Status.CurrentNumberScheduled - profileDaemonSet.Status.NumberReady
+	}
+	return sum, nil
 }
 
 func (controller *Controller) numberOfMissingActiveGatePods(dynakube *dynatracev1beta1.DynaKube) (int32, error) {
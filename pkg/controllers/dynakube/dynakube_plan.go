@@ -0,0 +1,87 @@
+package dynakube
+
+import (
+	"context"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/istio"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/plan"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewPlanController returns a controller that is only meant to plan the reconciliation of DynaKubes, see Controller.Plan.
+// The events of the reconciliation are discarded.
+func NewPlanController(kubeClient client.Client, config *rest.Config, operatorNamespace, clusterID string) *Controller {
+	return &Controller{
+		client:                 kubeClient,
+		apiReader:              kubeClient,
+		scheme:                 kubeClient.Scheme(),
+		fs:                     afero.Afero{Fs: afero.NewReadOnlyFs(afero.NewOsFs())},
+		dynatraceClientBuilder: dynatraceclient.NewBuilder(kubeClient),
+		istioClientBuilder:     istio.NewClient,
+		registryClientBuilder:  registry.NewClient,
+		recorder:               discardingEventRecorder{},
+		config:                 config,
+		operatorNamespace:      operatorNamespace,
+		clusterID:              clusterID,
+	}
+}
+
+// Plan runs the reconciliation of the given DynaKube as if it was applied to the cluster and returns the changes it would make.
+// Nothing is written, neither to the cluster, the Dynatrace environment nor the mirror registry, the reconciliation is run against a plan.Recorder.
+// The changes recorded until the reconciliation failed are returned together with the error.
+func (controller *Controller) Plan(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) ([]plan.Change, error) {
+	recorder := plan.NewRecorder(controller.client)
+
+	planController := *controller
+	planController.client = recorder
+	planController.apiReader = recorder
+	planController.planRecorder = recorder
+	planController.dynatraceClientBuilder = plan.NewDynatraceClientBuilder(controller.dynatraceClientBuilder, recorder)
+	planController.istioClientBuilder = plan.NewIstioClientBuilder(controller.istioClientBuilder, recorder)
+
+	dynakube = dynakube.DeepCopy()
+
+	// the reconciliation continues from the current status, a planned DynaKube doesn't come with one
+	var current dynatracev1beta1.DynaKube
+	err := controller.apiReader.Get(ctx, client.ObjectKeyFromObject(dynakube), &current)
+	if err == nil {
+		dynakube.Status = current.Status
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, errors.WithStack(err)
+	}
+
+	err = recorder.Apply(ctx, dynakube)
+	if err != nil {
+		return nil, err
+	}
+
+	// unlike a reconciliation, an unreachable Dynatrace API fails the plan, as the changes depending on it are missing
+	reconcileErr := planController.reconcileAndUpdateStatus(ctx, dynakube)
+
+	changes, err := recorder.Changes()
+	if err != nil {
+		return nil, err
+	}
+	return changes, reconcileErr
+}
+
+// discardingEventRecorder drops all events, a plan doesn't emit events for the changes it would make
+type discardingEventRecorder struct{}
+
+var _ record.EventRecorder = discardingEventRecorder{}
+
+func (discardingEventRecorder) Event(runtime.Object, string, string, string) {}
+
+func (discardingEventRecorder) Eventf(runtime.Object, string, string, string, ...any) {}
+
+func (discardingEventRecorder) AnnotatedEventf(runtime.Object, map[string]string, string, string, string, ...any) {
+}
@@ -0,0 +1,154 @@
+package dynakube
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceapi"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/plan"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPlan(t *testing.T) {
+	ctx := context.Background()
+	dynakube := &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: testApiUrl,
+		},
+	}
+	oneAgentDaemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dynakube.OneAgentDaemonsetName(),
+			Namespace: testNamespace,
+		},
+	}
+
+	newPlanController := func(objects ...client.Object) *Controller {
+		mockClient := createDTMockClient(dtclient.TokenScopes{},
+			dtclient.TokenScopes{
+				dtclient.TokenScopeDataExport,
+				dtclient.TokenScopeInstallerDownload,
+				dtclient.TokenScopeActiveGateTokenCreate})
+
+		objects = append(objects,
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+				Data: map[string][]byte{
+					dtclient.DynatraceApiToken: []byte(testAPIToken),
+				},
+			},
+			&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: kubesystem.Namespace,
+					UID:  testUID,
+				},
+			},
+		)
+
+		controller := NewPlanController(fake.NewClient(objects...), nil, testNamespace, testUID)
+		controller.dynatraceClientBuilder = &dynatraceclient.StubBuilder{DynatraceClient: mockClient}
+		controller.registryClientBuilder = createFakeRegistryClientBuilder()
+		return controller
+	}
+
+	t.Run("events are discarded", func(t *testing.T) {
+		controller := NewPlanController(fake.NewClient(), nil, testNamespace, testUID)
+
+		assert.IsType(t, discardingEventRecorder{}, controller.recorder)
+		assert.NotPanics(t, func() {
+			controller.recorder.Eventf(dynakube, corev1.EventTypeNormal, "reason", "message %s", "arg")
+		})
+	})
+	t.Run("changes are returned but not applied", func(t *testing.T) {
+		controller := newPlanController(dynakube.DeepCopy(), oneAgentDaemonSet.DeepCopy())
+
+		changes, err := controller.Plan(ctx, dynakube)
+		require.NoError(t, err)
+
+		assert.Contains(t, changeNames(changes), "update DynaKube 'test-namespace:test-name'")
+		assert.Contains(t, changeNames(changes), "delete DaemonSet 'test-namespace:test-name-oneagent'")
+
+		var daemonSet appsv1.DaemonSet
+		err = controller.client.Get(ctx, client.ObjectKeyFromObject(oneAgentDaemonSet), &daemonSet)
+		assert.NoError(t, err, "daemonset must not be deleted")
+
+		var currentDynakube dynatracev1beta1.DynaKube
+		require.NoError(t, controller.client.Get(ctx, client.ObjectKeyFromObject(dynakube), &currentDynakube))
+		assert.Empty(t, currentDynakube.Status.Phase, "status must not be updated")
+	})
+	t.Run("new dynakube is created", func(t *testing.T) {
+		controller := newPlanController()
+
+		changes, err := controller.Plan(ctx, dynakube)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, changes)
+		assert.Equal(t, "create DynaKube 'test-namespace:test-name'", changes[0].String())
+		assert.NotContains(t, changeNames(changes), "delete DaemonSet 'test-namespace:test-name-oneagent'")
+	})
+	t.Run("unreachable Dynatrace API fails the plan", func(t *testing.T) {
+		controller := newPlanController(dynakube.DeepCopy())
+		unreachableClient := &dtclient.MockDynatraceClient{}
+		unreachableClient.On("GetTokenScopes", mock.Anything).Return(dtclient.TokenScopes{
+			dtclient.TokenScopeDataExport,
+			dtclient.TokenScopeInstallerDownload,
+			dtclient.TokenScopeActiveGateTokenCreate,
+		}, nil)
+		unreachableClient.On("GetActiveGateConnectionInfo").Return(&dtclient.ActiveGateConnectionInfo{}, dtclient.ServerError{Code: http.StatusServiceUnavailable})
+		controller.dynatraceClientBuilder = &dynatraceclient.StubBuilder{DynatraceClient: unreachableClient}
+
+		_, err := controller.Plan(ctx, dynakube)
+		require.Error(t, err)
+		assert.True(t, dynatraceapi.IsUnreachable(err))
+	})
+	t.Run("mirror sync is recorded instead of made", func(t *testing.T) {
+		var registryRequests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			registryRequests.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+		registryHost := strings.TrimPrefix(server.URL, "http://")
+
+		mirroredDynakube := dynakube.DeepCopy()
+		mirroredDynakube.Spec.OneAgent.ClassicFullStack = &dynatracev1beta1.HostInjectSpec{Image: registryHost + "/dynatrace/oneagent:1.0"}
+		mirroredDynakube.Spec.Mirror = &dynatracev1beta1.MirrorSpec{Registry: registryHost + "/mirror", Insecure: true}
+		controller := newPlanController(mirroredDynakube.DeepCopy())
+
+		changes, err := controller.Plan(ctx, mirroredDynakube)
+		require.NoError(t, err)
+
+		assert.Contains(t, changeNames(changes), "update MirroredArtifact '"+registryHost+"/mirror/oneagent'")
+		assert.Zero(t, registryRequests.Load())
+	})
+}
+
+func changeNames(changes []plan.Change) []string {
+	names := make([]string, 0, len(changes))
+	for _, change := range changes {
+		names = append(names, change.String())
+	}
+	return names
+}
@@ -65,8 +65,33 @@ func (dynatraceClientBuilder builder) getTokens() token.Tokens {
 	return dynatraceClientBuilder.tokens
 }
 
+// readTokens reads the tokens from the token source of the DynaKube, unless they were set explicitly.
+func (dynatraceClientBuilder builder) readTokens() (token.Tokens, error) {
+	if dynatraceClientBuilder.tokens != nil {
+		return dynatraceClientBuilder.tokens, nil
+	}
+
+	if dynatraceClientBuilder.apiReader == nil {
+		return nil, errors.New("no tokens set and no client to read them")
+	}
+
+	tokens, err := token.NewTokenSource(dynatraceClientBuilder.apiReader, &dynatraceClientBuilder.dynakube).ReadTokens(dynatraceClientBuilder.context())
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
 // Build creates a new Dynatrace client using the settings configured on the given instance.
+// If no tokens were set, they are read from the token source of the DynaKube.
 func (dynatraceClientBuilder builder) Build() (dtclient.Client, error) {
+	tokens, err := dynatraceClientBuilder.readTokens()
+	if err != nil {
+		return nil, err
+	}
+
+	dynatraceClientBuilder.tokens = tokens
 	namespace := dynatraceClientBuilder.dynakube.Namespace
 	apiReader := dynatraceClientBuilder.apiReader
 
@@ -75,7 +100,7 @@ func (dynatraceClientBuilder builder) Build() (dtclient.Client, error) {
 	opts.appendNetworkZone(dynatraceClientBuilder.dynakube.Spec.NetworkZone)
 	opts.appendDisableHostsRequests(dynatraceClientBuilder.dynakube.FeatureDisableHostsRequests())
 
-	err := opts.appendProxySettings(apiReader, &dynatraceClientBuilder.dynakube)
+	err = opts.appendProxySettings(apiReader, &dynatraceClientBuilder.dynakube)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -96,6 +121,13 @@ func (dynatraceClientBuilder builder) Build() (dtclient.Client, error) {
 }
 
 func (dynatraceClientBuilder builder) BuildWithTokenVerification(dynaKubeStatus *dynatracev1beta1.DynaKubeStatus) (dtclient.Client, error) {
+	tokens, err := dynatraceClientBuilder.readTokens()
+	if err != nil {
+		return nil, err
+	}
+
+	dynatraceClientBuilder.tokens = tokens
+
 	dynatraceClient, err := dynatraceClientBuilder.Build()
 	if err != nil {
 		return nil, err
@@ -8,6 +8,7 @@ import (
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -80,6 +81,33 @@ func TestBuildDynatraceClient(t *testing.T) {
 		assert.Nil(t, dtc)
 		assert.Error(t, err)
 	})
+	t.Run(`BuildDynatraceClient reads tokens from token source if none are set`, func(t *testing.T) {
+		instance := &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testKey,
+				Namespace: testNamespace,
+			},
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testEndpoint,
+			}}
+		fakeClient := fake.NewClient(instance, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testKey,
+				Namespace: testNamespace,
+			},
+			Data: map[string][]byte{
+				dtclient.DynatraceApiToken: []byte(testValue),
+			},
+		})
+		dynatraceClientBuilder := builder{
+			apiReader: fakeClient,
+			dynakube:  *instance,
+		}
+		dtc, err := dynatraceClientBuilder.Build()
+
+		assert.NoError(t, err)
+		assert.NotNil(t, dtc)
+	})
 	t.Run(`BuildDynatraceClient handles missing proxy secret`, func(t *testing.T) {
 		instance := &dynatracev1beta1.DynaKube{
 			ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,9 @@
+package mirror
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/logger"
+)
+
+var (
+	log = logger.Factory.GetLogger("dynakube-mirror")
+)
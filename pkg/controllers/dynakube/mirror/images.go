@@ -0,0 +1,74 @@
+package mirror
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+)
+
+// mirrorImage copies the image, or the whole manifest list of a multi-arch image, into the mirror and returns the mirrored image pinned to its digest
+func (reconciler *Reconciler) mirrorImage(ctx context.Context, imageID string) (string, error) {
+	source, err := name.ParseReference(imageID)
+	if err != nil {
+		return "", errors.WithMessagef(err, "failed to parse image %s", imageID)
+	}
+
+	descriptor, err := remote.Get(source, reconciler.sourceOptions...)
+	if err != nil {
+		return "", errors.WithMessagef(err, "failed to get image %s", imageID)
+	}
+
+	target, err := reconciler.targetReference(imageID, descriptor.Digest.String())
+	if err != nil {
+		return "", err
+	}
+
+	if descriptor.MediaType.IsIndex() {
+		imageIndex, err := descriptor.ImageIndex()
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		err = remote.WriteIndex(target, imageIndex, reconciler.mirrorOptions...)
+		if err != nil {
+			return "", errors.WithMessagef(err, "failed to push image %s", target)
+		}
+	} else {
+		image, err := descriptor.Image()
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		err = remote.Write(target, image, reconciler.mirrorOptions...)
+		if err != nil {
+			return "", errors.WithMessagef(err, "failed to push image %s", target)
+		}
+	}
+
+	if _, isDigest := target.(name.Digest); isDigest {
+		return target.String(), nil
+	}
+	return target.String() + registry.DigestDelimiter + descriptor.Digest.String(), nil
+}
+
+// targetReference keeps the repository path and the tag of the image in the mirror,
+// e.g. tenant.live.dynatrace.com/linux/oneagent:1.2.3@sha256:... becomes <mirror>/linux/oneagent:1.2.3
+func (reconciler *Reconciler) targetReference(imageID string, imageDigest string) (name.Reference, error) {
+	taggedImage, _, _ := strings.Cut(imageID, registry.DigestDelimiter)
+	source, err := name.NewTag(taggedImage, name.WithDefaultTag(""))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to parse image %s", imageID)
+	}
+
+	repository, err := name.NewRepository(reconciler.dynakube.Spec.Mirror.Registry+"/"+source.RepositoryStr(), reconciler.nameOptions...)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid mirror registry %s", reconciler.dynakube.Spec.Mirror.Registry)
+	}
+
+	if source.TagStr() == "" {
+		return repository.Digest(imageDigest), nil
+	}
+	return repository.Tag(source.TagStr()), nil
+}
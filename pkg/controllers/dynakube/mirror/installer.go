@@ -0,0 +1,93 @@
+package mirror
+
+import (
+	"context"
+	"io"
+	"sort"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/arch"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const installerRepository = "oneagent-installer"
+
+// mirrorInstallers pushes the OneAgent installers for the architectures of the nodes as OCI artifacts into the mirror,
+// each architecture is tagged as <version>-<architecture>
+func (reconciler *Reconciler) mirrorInstallers(ctx context.Context, version string) (string, error) {
+	repository, err := name.NewRepository(reconciler.dynakube.Spec.Mirror.Registry+"/"+installerRepository, reconciler.nameOptions...)
+	if err != nil {
+		return "", errors.WithMessagef(err, "invalid mirror registry %s", reconciler.dynakube.Spec.Mirror.Registry)
+	}
+	installer := repository.Tag(version)
+
+	nodeArches, err := reconciler.nodeArches(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, nodeArch := range nodeArches {
+		err = reconciler.mirrorInstaller(ctx, repository.Tag(version+"-"+nodeArch), version, nodeArch)
+		if err != nil {
+			return "", err
+		}
+	}
+	return installer.String(), nil
+}
+
+func (reconciler *Reconciler) mirrorInstaller(ctx context.Context, target name.Tag, version string, nodeArch string) error {
+	installerArch, installerFlavor, err := arch.ForKubernetesArch(nodeArch)
+	if err != nil {
+		log.Info("skipping installer for unsupported node architecture", "arch", nodeArch)
+		return nil
+	}
+
+	tmpFile, err := afero.TempFile(reconciler.fs, "", "installer")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		_ = tmpFile.Close()
+		_ = reconciler.fs.Remove(tmpFile.Name())
+	}()
+
+	log.Info("downloading installer", "version", version, "arch", installerArch, "flavor", installerFlavor)
+	// the init containers download the installers with metadata
+	err = reconciler.dtClient.GetAgent(dtclient.OsUnix, dtclient.InstallerTypePaaS, installerFlavor, installerArch, version, []string{"all"}, false, tmpFile)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to download installer for %s", nodeArch)
+	}
+
+	open := func() (io.ReadCloser, error) {
+		return reconciler.fs.Open(tmpFile.Name())
+	}
+	_, err = registry.PushArtifact(ctx, target, open, registry.InstallerMediaType, reconciler.mirrorOptions...)
+	return err
+}
+
+// nodeArches returns the architectures (kubernetes.io/arch) of the nodes of the cluster
+func (reconciler *Reconciler) nodeArches(ctx context.Context) ([]string, error) {
+	var nodes corev1.NodeList
+	err := reconciler.apiReader.List(ctx, &nodes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list nodes")
+	}
+
+	arches := make(map[string]bool)
+	for _, node := range nodes.Items {
+		if nodeArch := node.Labels[corev1.LabelArchStable]; nodeArch != "" {
+			arches[nodeArch] = true
+		}
+	}
+
+	nodeArches := make([]string, 0, len(arches))
+	for nodeArch := range arches {
+		nodeArches = append(nodeArches, nodeArch)
+	}
+	sort.Strings(nodeArches)
+	return nodeArches, nil
+}
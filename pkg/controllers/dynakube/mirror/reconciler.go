@@ -0,0 +1,204 @@
+package mirror
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/dockerkeychain"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/updatewindow"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reconciler copies the images and installers used by a DynaKube into the mirror,
+// once they are mirrored the components are deployed from the mirror
+type Reconciler struct {
+	dynakube     *dynatracev1beta1.DynaKube
+	apiReader    client.Reader
+	dtClient     dtclient.Client
+	fs           afero.Fs
+	timeProvider *timeprovider.Provider
+	syncWindow   *updatewindow.Window
+
+	sourceOptions []remote.Option
+	mirrorOptions []remote.Option
+	nameOptions   []name.Option
+}
+
+func NewReconciler(dynakube *dynatracev1beta1.DynaKube, apiReader client.Reader, dtClient dtclient.Client, fs afero.Fs, timeProvider *timeprovider.Provider) *Reconciler { //nolint:revive // argument-limit doesn't apply to constructors
+	return &Reconciler{
+		dynakube:     dynakube,
+		apiReader:    apiReader,
+		dtClient:     dtClient,
+		fs:           fs,
+		timeProvider: timeProvider,
+	}
+}
+
+// Reconcile syncs the mirror, if it doesn't hold the current versions and the sync window is open
+func (reconciler *Reconciler) Reconcile(ctx context.Context) error {
+	artifacts, err := reconciler.artifactsToSync()
+	if err != nil || len(artifacts) == 0 {
+		return err
+	}
+
+	err = reconciler.prepareRemoteOptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, artifact := range artifacts {
+		log.Info("mirroring artifact", "artifact", artifact.name, "source", artifact.source)
+		image, err := artifact.mirror(ctx)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to mirror %s", artifact.name)
+		}
+		*artifact.status = dynatracev1beta1.MirroredArtifact{
+			Source: artifact.source,
+			Image:  image,
+		}
+		log.Info("mirrored artifact", "artifact", artifact.name, "image", image)
+	}
+	reconciler.dynakube.Status.Mirror.LastSyncTimestamp = reconciler.timeProvider.Now()
+	return nil
+}
+
+// PendingArtifacts returns the names of the artifacts Reconcile would mirror, without calling any registry
+func (reconciler *Reconciler) PendingArtifacts() ([]string, error) {
+	artifacts, err := reconciler.artifactsToSync()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		names = append(names, artifact.name)
+	}
+	return names, nil
+}
+
+func (reconciler *Reconciler) artifactsToSync() ([]artifact, error) {
+	if reconciler.dynakube.Spec.Mirror == nil {
+		reconciler.dynakube.Status.Mirror = dynatracev1beta1.MirrorStatus{}
+		return nil, nil
+	}
+
+	syncWindow, err := reconciler.dynakube.MirrorSyncWindow()
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid mirror sync window")
+	}
+	reconciler.syncWindow = syncWindow
+
+	artifacts := reconciler.outdatedArtifacts()
+	if len(artifacts) == 0 {
+		return nil, nil
+	}
+	if !reconciler.syncWindow.IsOpen(reconciler.timeProvider.Now().Time) {
+		log.Info("mirror is outdated, waiting for the sync window to open", "nextSync", reconciler.syncWindow.NextOpening(reconciler.timeProvider.Now().Time))
+		return nil, nil
+	}
+	return artifacts, nil
+}
+
+// NextSyncWindow returns when the sync window opens next if the mirror is outdated, otherwise it is zero
+func (reconciler *Reconciler) NextSyncWindow() time.Time {
+	if reconciler.dynakube.Spec.Mirror == nil || len(reconciler.outdatedArtifacts()) == 0 {
+		return time.Time{}
+	}
+	return reconciler.syncWindow.NextOpening(reconciler.timeProvider.Now().Time)
+}
+
+type artifact struct {
+	name   string
+	source string
+	status *dynatracev1beta1.MirroredArtifact
+	mirror func(ctx context.Context) (string, error)
+}
+
+// outdatedArtifacts lists the artifacts the mirror doesn't hold the current version of, artifacts which aren't needed anymore are removed from the status
+func (reconciler *Reconciler) outdatedArtifacts() []artifact {
+	dynakube := reconciler.dynakube
+	mirrorStatus := &dynakube.Status.Mirror
+
+	artifacts := []artifact{
+		reconciler.imageArtifact("oneagent", dynakube.NeedsOneAgent(), dynakube.Status.OneAgent.ImageID, &mirrorStatus.OneAgent),
+		reconciler.imageArtifact("codemodules", dynakube.NeedAppInjection(), dynakube.Status.CodeModules.ImageID, &mirrorStatus.CodeModules),
+		reconciler.imageArtifact("activegate", dynakube.NeedsActiveGate(), dynakube.Status.ActiveGate.ImageID, &mirrorStatus.ActiveGate),
+		{
+			name:   "installer",
+			source: reconciler.installerVersion(),
+			status: &mirrorStatus.Installer,
+			mirror: func(ctx context.Context) (string, error) {
+				return reconciler.mirrorInstallers(ctx, reconciler.installerVersion())
+			},
+		},
+	}
+
+	outdated := make([]artifact, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		if artifact.source == "" {
+			*artifact.status = dynatracev1beta1.MirroredArtifact{}
+		} else if artifact.status.Source != artifact.source {
+			outdated = append(outdated, artifact)
+		}
+	}
+	return outdated
+}
+
+func (reconciler *Reconciler) imageArtifact(artifactName string, isNeeded bool, imageID string, status *dynatracev1beta1.MirroredArtifact) artifact {
+	if !isNeeded {
+		imageID = ""
+	}
+	return artifact{
+		name:   artifactName,
+		source: imageID,
+		status: status,
+		mirror: func(ctx context.Context) (string, error) {
+			return reconciler.mirrorImage(ctx, imageID)
+		},
+	}
+}
+
+// installerVersion is the version of the installers the init containers download, they are only needed without the CSI driver
+func (reconciler *Reconciler) installerVersion() string {
+	if !reconciler.dynakube.NeedAppInjection() || reconciler.dynakube.NeedsCSIDriver() {
+		return ""
+	}
+	return reconciler.dynakube.CodeModulesVersion()
+}
+
+func (reconciler *Reconciler) prepareRemoteOptions(ctx context.Context) error {
+	keychain, err := dockerkeychain.NewDockerKeychain(ctx, reconciler.apiReader, reconciler.dynakube.PullSecretWithoutData())
+	if err != nil {
+		return errors.WithMessage(err, "failed to read pull secret")
+	}
+
+	sourceTransport, err := registry.PrepareTransportForDynaKube(ctx, reconciler.apiReader, http.DefaultTransport.(*http.Transport).Clone(), reconciler.dynakube)
+	if err != nil {
+		return err
+	}
+
+	reconciler.sourceOptions = []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(keychain),
+		remote.WithTransport(sourceTransport),
+	}
+	// the mirror runs inside the cluster, so the proxy of the DynaKube isn't used for it
+	reconciler.mirrorOptions = []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(keychain),
+		remote.WithTransport(http.DefaultTransport.(*http.Transport).Clone()),
+	}
+	if reconciler.dynakube.Spec.Mirror.Insecure {
+		reconciler.nameOptions = []name.Option{name.Insecure}
+	}
+	return nil
+}
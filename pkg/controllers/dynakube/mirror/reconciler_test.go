@@ -0,0 +1,318 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/arch"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/google/go-containerregistry/pkg/name"
+	testregistry "github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testName      = "test-dynakube"
+	testNamespace = "dynatrace"
+	testVersion   = "1.2.3.4-5"
+)
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+	registryHost := registryHost(t, server)
+
+	t.Run(`mirrors code modules image and installers for the node architectures`, func(t *testing.T) {
+		codeModulesImage := pushImage(t, registryHost+"/linux/codemodules:"+testVersion)
+		dynakube := newApplicationMonitoringDynakube(registryHost)
+		dynakube.Status.CodeModules.ImageID = codeModulesImage
+		dynakube.Status.CodeModules.Version = testVersion
+
+		dtClient := &dtclient.MockDynatraceClient{}
+		dtClient.On("GetAgent", dtclient.OsUnix, dtclient.InstallerTypePaaS, arch.FlavorMultidistro, arch.ArchX86, testVersion, []string{"all"}, mock.Anything).
+			Run(writeInstaller("amd64 installer")).Return(nil)
+		dtClient.On("GetAgent", dtclient.OsUnix, dtclient.InstallerTypePaaS, arch.FlavorDefault, arch.ArchARM, testVersion, []string{"all"}, mock.Anything).
+			Run(writeInstaller("arm64 installer")).Return(nil)
+
+		reconciler := newTestReconciler(dynakube, dtClient, newNode("node-1", "amd64"), newNode("node-2", "arm64"), newNode("node-3", "amd64"))
+		err := reconciler.Reconcile(ctx)
+		require.NoError(t, err)
+
+		mirroredImage := dynakube.Status.Mirror.CodeModules.Image
+		assert.Equal(t, codeModulesImage, dynakube.Status.Mirror.CodeModules.Source)
+		assert.Equal(t, registryHost+"/mirror/linux/codemodules:"+testVersion+"@"+digestOf(t, codeModulesImage), mirroredImage)
+		assert.Equal(t, mirroredImage, dynakube.CodeModulesImage())
+		assertImageExists(t, mirroredImage)
+
+		assert.Equal(t, testVersion, dynakube.Status.Mirror.Installer.Source)
+		assert.Equal(t, registryHost+"/mirror/oneagent-installer:"+testVersion, dynakube.MirroredInstaller())
+		assert.Equal(t, "amd64 installer", pullInstaller(t, dynakube.MirroredInstaller()+"-amd64"))
+		assert.Equal(t, "arm64 installer", pullInstaller(t, dynakube.MirroredInstaller()+"-arm64"))
+
+		assert.NotNil(t, dynakube.Status.Mirror.LastSyncTimestamp)
+		dtClient.AssertNumberOfCalls(t, "GetAgent", 2)
+	})
+	t.Run(`mirrors whole manifest list of multi-arch image`, func(t *testing.T) {
+		oneAgentImage := pushIndex(t, registryHost+"/linux/oneagent:"+testVersion)
+		dynakube := newClassicFullStackDynakube(registryHost)
+		dynakube.Status.OneAgent.ImageID = oneAgentImage
+
+		reconciler := newTestReconciler(dynakube, &dtclient.MockDynatraceClient{})
+		err := reconciler.Reconcile(ctx)
+		require.NoError(t, err)
+
+		mirroredImage := dynakube.OneAgentImage()
+		assert.Equal(t, registryHost+"/mirror/linux/oneagent:"+testVersion+"@"+digestOf(t, oneAgentImage), mirroredImage)
+
+		ref, err := name.ParseReference(mirroredImage)
+		require.NoError(t, err)
+		descriptor, err := remote.Get(ref)
+		require.NoError(t, err)
+		assert.True(t, descriptor.MediaType.IsIndex())
+	})
+	t.Run(`mirrors image without tag by digest`, func(t *testing.T) {
+		taggedImage := pushImage(t, registryHost+"/linux/oneagent:digest-only")
+		imageDigest := digestOf(t, taggedImage)
+		dynakube := newClassicFullStackDynakube(registryHost)
+		dynakube.Status.OneAgent.ImageID = registryHost + "/linux/oneagent@" + imageDigest
+
+		reconciler := newTestReconciler(dynakube, &dtclient.MockDynatraceClient{})
+		err := reconciler.Reconcile(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, registryHost+"/mirror/linux/oneagent@"+imageDigest, dynakube.OneAgentImage())
+		assertImageExists(t, dynakube.OneAgentImage())
+	})
+	t.Run(`nothing to do if mirror is up to date`, func(t *testing.T) {
+		dynakube := newClassicFullStackDynakube(registryHost)
+		dynakube.Status.OneAgent.ImageID = registryHost + "/linux/oneagent:" + testVersion
+		dynakube.Status.Mirror.OneAgent = dynatracev1beta1.MirroredArtifact{
+			Source: dynakube.Status.OneAgent.ImageID,
+			Image:  registryHost + "/mirror/linux/oneagent:" + testVersion,
+		}
+
+		// no pull secret, it is only read when syncing
+		reconciler := NewReconciler(dynakube, fake.NewClient(), &dtclient.MockDynatraceClient{}, afero.NewMemMapFs(), timeprovider.New())
+		err := reconciler.Reconcile(ctx)
+		require.NoError(t, err)
+
+		assert.Nil(t, dynakube.Status.Mirror.LastSyncTimestamp)
+		assert.True(t, reconciler.NextSyncWindow().IsZero())
+	})
+	t.Run(`waits for sync window`, func(t *testing.T) {
+		dynakube := newClassicFullStackDynakube(registryHost)
+		dynakube.Status.OneAgent.ImageID = registryHost + "/linux/oneagent:" + testVersion
+		dynakube.Spec.Mirror.SyncWindow = &dynatracev1beta1.UpdateWindowSpec{
+			Schedule: "0 22 * * *",
+			Duration: metav1.Duration{Duration: time.Hour},
+		}
+
+		timeProvider := timeprovider.New().Freeze()
+		timeProvider.Set(&metav1.Time{Time: time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)})
+		reconciler := NewReconciler(dynakube, fake.NewClient(), &dtclient.MockDynatraceClient{}, afero.NewMemMapFs(), timeProvider)
+		err := reconciler.Reconcile(ctx)
+		require.NoError(t, err)
+
+		assert.Empty(t, dynakube.Status.Mirror.OneAgent)
+		assert.Equal(t, dynakube.Status.OneAgent.ImageID, dynakube.OneAgentImage())
+		assert.True(t, time.Date(2024, 3, 4, 22, 0, 0, 0, time.UTC).Equal(reconciler.NextSyncWindow()))
+	})
+	t.Run(`invalid sync window`, func(t *testing.T) {
+		dynakube := newClassicFullStackDynakube(registryHost)
+		dynakube.Spec.Mirror.SyncWindow = &dynatracev1beta1.UpdateWindowSpec{
+			Schedule: "0 25 * * *",
+			Duration: metav1.Duration{Duration: time.Hour},
+		}
+
+		reconciler := newTestReconciler(dynakube, &dtclient.MockDynatraceClient{})
+		err := reconciler.Reconcile(ctx)
+		require.Error(t, err)
+	})
+	t.Run(`resets status if mirror is disabled`, func(t *testing.T) {
+		dynakube := newClassicFullStackDynakube(registryHost)
+		dynakube.Spec.Mirror = nil
+		dynakube.Status.Mirror.OneAgent = dynatracev1beta1.MirroredArtifact{Source: "source", Image: "image"}
+
+		reconciler := newTestReconciler(dynakube, &dtclient.MockDynatraceClient{})
+		err := reconciler.Reconcile(ctx)
+		require.NoError(t, err)
+
+		assert.Empty(t, dynakube.Status.Mirror)
+	})
+	t.Run(`removes artifacts which aren't needed anymore`, func(t *testing.T) {
+		dynakube := newClassicFullStackDynakube(registryHost)
+		dynakube.Status.OneAgent.ImageID = registryHost + "/linux/oneagent:" + testVersion
+		dynakube.Status.Mirror.OneAgent = dynatracev1beta1.MirroredArtifact{
+			Source: dynakube.Status.OneAgent.ImageID,
+			Image:  registryHost + "/mirror/linux/oneagent:" + testVersion,
+		}
+		dynakube.Status.Mirror.ActiveGate = dynatracev1beta1.MirroredArtifact{Source: "source", Image: "image"}
+
+		reconciler := newTestReconciler(dynakube, &dtclient.MockDynatraceClient{})
+		err := reconciler.Reconcile(ctx)
+		require.NoError(t, err)
+
+		assert.Empty(t, dynakube.Status.Mirror.ActiveGate)
+		assert.NotEmpty(t, dynakube.Status.Mirror.OneAgent)
+	})
+}
+
+func TestPendingArtifacts(t *testing.T) {
+	t.Run(`lists outdated artifacts without calling the registry`, func(t *testing.T) {
+		dynakube := newClassicFullStackDynakube("unreachable.registry")
+		dynakube.Status.OneAgent.ImageID = "unreachable.registry/linux/oneagent:" + testVersion
+
+		// no pull secret, it is only read when syncing
+		reconciler := NewReconciler(dynakube, fake.NewClient(), &dtclient.MockDynatraceClient{}, afero.NewMemMapFs(), timeprovider.New())
+		artifacts, err := reconciler.PendingArtifacts()
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"oneagent"}, artifacts)
+		assert.Empty(t, dynakube.Status.Mirror.OneAgent)
+	})
+	t.Run(`nothing pending outside of the sync window`, func(t *testing.T) {
+		dynakube := newClassicFullStackDynakube("unreachable.registry")
+		dynakube.Status.OneAgent.ImageID = "unreachable.registry/linux/oneagent:" + testVersion
+		dynakube.Spec.Mirror.SyncWindow = &dynatracev1beta1.UpdateWindowSpec{
+			Schedule: "0 22 * * *",
+			Duration: metav1.Duration{Duration: time.Hour},
+		}
+
+		timeProvider := timeprovider.New().Freeze()
+		timeProvider.Set(&metav1.Time{Time: time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)})
+		reconciler := NewReconciler(dynakube, fake.NewClient(), &dtclient.MockDynatraceClient{}, afero.NewMemMapFs(), timeProvider)
+		artifacts, err := reconciler.PendingArtifacts()
+		require.NoError(t, err)
+
+		assert.Empty(t, artifacts)
+	})
+}
+
+func newTestReconciler(dynakube *dynatracev1beta1.DynaKube, dtClient dtclient.Client, objects ...client.Object) *Reconciler {
+	pullSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dynakube.PullSecretName(),
+			Namespace: dynakube.Namespace,
+		},
+		Data: map[string][]byte{
+			".dockerconfigjson": []byte(`{"auths":{}}`),
+		},
+	}
+	return NewReconciler(dynakube, fake.NewClient(append(objects, pullSecret)...), dtClient, afero.NewMemMapFs(), timeprovider.New())
+}
+
+func newClassicFullStackDynakube(registryHost string) *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ClassicFullStack: &dynatracev1beta1.HostInjectSpec{},
+			},
+			Mirror: &dynatracev1beta1.MirrorSpec{
+				Registry: registryHost + "/mirror",
+			},
+		},
+	}
+}
+
+func newApplicationMonitoringDynakube(registryHost string) *dynatracev1beta1.DynaKube {
+	useCSIDriver := false
+	dynakube := newClassicFullStackDynakube(registryHost)
+	dynakube.Spec.OneAgent = dynatracev1beta1.OneAgentSpec{
+		ApplicationMonitoring: &dynatracev1beta1.ApplicationMonitoringSpec{
+			UseCSIDriver: &useCSIDriver,
+		},
+	}
+	return dynakube
+}
+
+func newNode(nodeName, nodeArch string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   nodeName,
+			Labels: map[string]string{corev1.LabelArchStable: nodeArch},
+		},
+	}
+}
+
+func registryHost(t *testing.T, server *httptest.Server) string {
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return serverUrl.Host
+}
+
+func writeInstaller(content string) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		writer := args.Get(6).(io.Writer)
+		_, _ = writer.Write([]byte(content))
+	}
+}
+
+// pushImage pushes a random image and returns it pinned to its digest
+func pushImage(t *testing.T, image string) string {
+	ref, err := name.NewTag(image)
+	require.NoError(t, err)
+	randomImage, err := random.Image(64, 1)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, randomImage))
+
+	imageDigest, err := randomImage.Digest()
+	require.NoError(t, err)
+	return image + registry.DigestDelimiter + imageDigest.String()
+}
+
+// pushIndex pushes a random manifest list and returns it pinned to its digest
+func pushIndex(t *testing.T, image string) string {
+	ref, err := name.NewTag(image)
+	require.NoError(t, err)
+	randomIndex, err := random.Index(64, 1, 2)
+	require.NoError(t, err)
+	require.NoError(t, remote.WriteIndex(ref, randomIndex))
+
+	indexDigest, err := randomIndex.Digest()
+	require.NoError(t, err)
+	return image + registry.DigestDelimiter + indexDigest.String()
+}
+
+func digestOf(t *testing.T, image string) string {
+	ref, err := name.NewDigest(image)
+	require.NoError(t, err)
+	return ref.DigestStr()
+}
+
+func assertImageExists(t *testing.T, image string) {
+	ref, err := name.ParseReference(image)
+	require.NoError(t, err)
+	_, err = remote.Head(ref)
+	require.NoError(t, err)
+}
+
+func pullInstaller(t *testing.T, image string) string {
+	ref, err := name.ParseReference(image)
+	require.NoError(t, err)
+
+	var installer bytes.Buffer
+	require.NoError(t, registry.PullArtifact(context.Background(), ref, &installer))
+	return installer.String()
+}
@@ -0,0 +1,301 @@
+package oneagent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var failingContainerReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+}
+
+// canaryRollout stages OneAgent version upgrades, the canary nodes get the new version first
+// and all other nodes only get it once the canary OneAgents stayed healthy for the bake time.
+// While the canaries are updated the DaemonSet uses the OnDelete strategy, so only the pods deleted by the operator get the new version.
+type canaryRollout struct {
+	client   client.Client
+	dynakube *dynatracev1beta1.DynaKube
+	now      *metav1.Time
+}
+
+type canaryHealth struct {
+	ready   bool
+	failure string
+}
+
+func newCanaryRollout(clt client.Client, dynakube *dynatracev1beta1.DynaKube, now *metav1.Time) *canaryRollout {
+	return &canaryRollout{
+		client:   clt,
+		dynakube: dynakube,
+		now:      now,
+	}
+}
+
+// advance starts the rollout of a new version and moves it to the next phase depending on the health of the canary OneAgents
+func (rollout *canaryRollout) advance(ctx context.Context) error {
+	status := rollout.dynakube.Status.OneAgent.Rollout
+	if status == nil || status.TargetVersion != rollout.dynakube.OneAgentVersion() {
+		return rollout.start(ctx)
+	}
+	if !status.InProgress() {
+		return nil
+	}
+
+	pods, err := rollout.listPods(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(status.CanaryNodes) == 0 {
+		status.CanaryNodes, err = rollout.selectCanaryNodes(ctx, pods)
+		if err != nil {
+			return err
+		}
+		if len(status.CanaryNodes) == 0 {
+			rollout.skip()
+			return nil
+		}
+	}
+
+	health := rollout.canaryHealth(pods)
+
+	switch {
+	case health.failure != "":
+		rollout.rollBack(health.failure)
+	case status.Phase == dynatracev1beta1.OneAgentRolloutCanary && health.ready:
+		log.Info("canary OneAgents are ready, baking", "version", status.TargetVersion, "bakeTime", rollout.dynakube.OneAgentRolloutBakeTime())
+		status.Phase = dynatracev1beta1.OneAgentRolloutBaking
+		status.CanaryReadyTimestamp = rollout.now
+	case status.Phase == dynatracev1beta1.OneAgentRolloutCanary && timeprovider.TimeoutReached(status.StartTimestamp, rollout.now, rollout.dynakube.OneAgentRolloutProgressDeadline()):
+		rollout.rollBack(fmt.Sprintf("canary OneAgents did not become ready within %s", rollout.dynakube.OneAgentRolloutProgressDeadline()))
+	case status.Phase == dynatracev1beta1.OneAgentRolloutBaking && !health.ready:
+		rollout.rollBack("canary OneAgents became unready during the bake time")
+	case status.Phase == dynatracev1beta1.OneAgentRolloutBaking && timeprovider.TimeoutReached(status.CanaryReadyTimestamp, rollout.now, rollout.dynakube.OneAgentRolloutBakeTime()):
+		log.Info("canary OneAgents stayed healthy, rolling out to all nodes", "version", status.TargetVersion)
+		status.Phase = dynatracev1beta1.OneAgentRolloutCompleted
+	}
+	return nil
+}
+
+func (rollout *canaryRollout) start(ctx context.Context) error {
+	targetVersion := rollout.dynakube.OneAgentVersion()
+
+	var daemonSet appsv1.DaemonSet
+	err := rollout.client.Get(ctx, client.ObjectKey{Name: rollout.dynakube.OneAgentDaemonsetName(), Namespace: rollout.dynakube.Namespace}, &daemonSet)
+	if k8serrors.IsNotFound(err) {
+		// nothing to upgrade on the first deployment
+		return nil
+	} else if err != nil {
+		return errors.WithStack(err)
+	}
+
+	previousVersion := daemonSet.Labels[kubeobjects.AppVersionLabel]
+	if previousVersion == "" || targetVersion == "" || previousVersion == targetVersion || len(daemonSet.Spec.Template.Spec.Containers) == 0 {
+		return nil
+	}
+	previousImage := daemonSet.Spec.Template.Spec.Containers[0].Image
+
+	// the canaries of an interrupted rollout run an unproven version, so the rollback has to go further back
+	if currentRollout := rollout.dynakube.Status.OneAgent.Rollout; currentRollout.InProgress() {
+		previousVersion = currentRollout.PreviousVersion
+		previousImage = currentRollout.PreviousImage
+	}
+
+	pods, err := rollout.listPods(ctx)
+	if err != nil {
+		return err
+	}
+
+	canaryNodes, err := rollout.selectCanaryNodes(ctx, pods)
+	if err != nil {
+		return err
+	}
+	if len(canaryNodes) == 0 {
+		rollout.skip()
+		return nil
+	}
+
+	log.Info("starting staged OneAgent rollout", "previousVersion", previousVersion, "targetVersion", targetVersion, "canaryNodes", canaryNodes)
+	rollout.dynakube.Status.OneAgent.Rollout = &dynatracev1beta1.OneAgentRolloutStatus{
+		Phase:           dynatracev1beta1.OneAgentRolloutCanary,
+		TargetVersion:   targetVersion,
+		PreviousVersion: previousVersion,
+		PreviousImage:   previousImage,
+		CanaryNodes:     canaryNodes,
+		StartTimestamp:  rollout.now,
+	}
+	return nil
+}
+
+// skip leaves the upgrade to the DaemonSet, without a OneAgent running there is no node to try the new version on
+func (rollout *canaryRollout) skip() {
+	log.Info("no OneAgent is running, skipping the canary stage of the staged OneAgent rollout", "targetVersion", rollout.dynakube.OneAgentVersion())
+	rollout.dynakube.Status.OneAgent.Rollout = nil
+}
+
+func (rollout *canaryRollout) rollBack(message string) {
+	status := rollout.dynakube.Status.OneAgent.Rollout
+	log.Info("rolling back staged OneAgent rollout", "targetVersion", status.TargetVersion, "previousVersion", status.PreviousVersion, "reason", message)
+	status.Phase = dynatracev1beta1.OneAgentRolloutRolledBack
+	status.Message = message
+}
+
+// deployedDynakube is the DynaKube the OneAgent DaemonSet has to be built from, after a rollback it points to the previous version
+func (rollout *canaryRollout) deployedDynakube() *dynatracev1beta1.DynaKube {
+	status := rollout.dynakube.Status.OneAgent.Rollout
+	if status == nil || status.Phase != dynatracev1beta1.OneAgentRolloutRolledBack {
+		return rollout.dynakube
+	}
+
+	previous := rollout.dynakube.DeepCopy()
+	previous.Status.OneAgent.Version = status.PreviousVersion
+	previous.Status.OneAgent.ImageID = status.PreviousImage
+	return previous
+}
+
+// updateCanaryPods deletes the outdated pods on the canary nodes, so they are recreated with the new version
+func (rollout *canaryRollout) updateCanaryPods(ctx context.Context) error {
+	status := rollout.dynakube.Status.OneAgent.Rollout
+	if status == nil || status.Phase != dynatracev1beta1.OneAgentRolloutCanary {
+		return nil
+	}
+
+	pods, err := rollout.listPods(ctx)
+	if err != nil {
+		return err
+	}
+
+	canaryNodes := toSet(status.CanaryNodes)
+	for i := range pods {
+		pod := &pods[i]
+		if !canaryNodes[pod.Spec.NodeName] || pod.Labels[kubeobjects.AppVersionLabel] == status.TargetVersion || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		log.Info("updating canary OneAgent", "pod", pod.Name, "node", pod.Spec.NodeName)
+		if err := rollout.client.Delete(ctx, pod); err != nil && !k8serrors.IsNotFound(err) {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (rollout *canaryRollout) canaryHealth(pods []corev1.Pod) canaryHealth {
+	status := rollout.dynakube.Status.OneAgent.Rollout
+	podsByNode := make(map[string]*corev1.Pod)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Labels[kubeobjects.AppVersionLabel] == status.TargetVersion && pod.DeletionTimestamp == nil {
+			podsByNode[pod.Spec.NodeName] = pod
+		}
+	}
+
+	health := canaryHealth{ready: len(status.CanaryNodes) > 0}
+	for _, node := range status.CanaryNodes {
+		pod, ok := podsByNode[node]
+		if !ok {
+			health.ready = false
+			continue
+		}
+		if failure := podFailure(pod); failure != "" {
+			health.failure = fmt.Sprintf("canary OneAgent pod %s on node %s %s", pod.Name, node, failure)
+			return health
+		}
+		if !isPodReady(pod) {
+			health.ready = false
+		}
+	}
+	return health
+}
+
+// selectCanaryNodes picks the canary nodes from the nodes running a OneAgent, it returns none only if no OneAgent is running.
+// A node selector matching none of the nodes running a OneAgent fails, the rollout waits until it's fixed.
+func (rollout *canaryRollout) selectCanaryNodes(ctx context.Context, pods []corev1.Pod) ([]string, error) {
+	nodeSet := make(map[string]bool)
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			nodeSet[pod.Spec.NodeName] = true
+		}
+	}
+
+	canary := rollout.dynakube.Spec.OneAgent.Rollout.Canary
+	if len(canary.NodeSelector) > 0 {
+		var nodeList corev1.NodeList
+		if err := rollout.client.List(ctx, &nodeList, client.MatchingLabels(canary.NodeSelector)); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		canaryNodes := make([]string, 0, len(nodeList.Items))
+		for _, node := range nodeList.Items {
+			if nodeSet[node.Name] {
+				canaryNodes = append(canaryNodes, node.Name)
+			}
+		}
+		if len(canaryNodes) == 0 && len(nodeSet) > 0 {
+			return nil, errors.Errorf("the canary node selector %v of the staged OneAgent rollout matches none of the %d nodes running a OneAgent", canary.NodeSelector, len(nodeSet))
+		}
+		sort.Strings(canaryNodes)
+		return canaryNodes, nil
+	}
+
+	nodes := make([]string, 0, len(nodeSet))
+	for node := range nodeSet {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	count := (len(nodes)*canary.Percentage + 99) / 100
+	return nodes[:count], nil
+}
+
+func (rollout *canaryRollout) listPods(ctx context.Context) ([]corev1.Pod, error) {
+	matchLabels := kubeobjects.NewAppLabels(kubeobjects.OneAgentComponentLabel, rollout.dynakube.Name, "", "").BuildMatchLabels()
+
+	var podList corev1.PodList
+	err := rollout.client.List(ctx, &podList, client.InNamespace(rollout.dynakube.Namespace), client.MatchingLabels(matchLabels))
+	return podList.Items, errors.WithStack(err)
+}
+
+func podFailure(pod *corev1.Pod) string {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.RestartCount > 0 {
+			return fmt.Sprintf("restarted %d times", containerStatus.RestartCount)
+		}
+		if waiting := containerStatus.State.Waiting; waiting != nil && failingContainerReasons[waiting.Reason] {
+			return "is in " + waiting.Reason
+		}
+	}
+	return ""
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,359 @@
+package oneagent
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testCanaryNamespace = "dynatrace"
+	testCanaryDynakube  = "dynakube"
+	testPreviousVersion = "1.0.0"
+	testPreviousImage   = "registry/oneagent:1.0.0"
+	testTargetVersion   = "2.0.0"
+	testTargetImage     = "registry/oneagent:2.0.0"
+)
+
+func TestCanaryRolloutStart(t *testing.T) {
+	ctx := context.Background()
+	now := metav1.Now()
+
+	t.Run("no rollout on first deployment", func(t *testing.T) {
+		dynakube := newCanaryDynakube(dynatracev1beta1.OneAgentCanarySpec{Percentage: 50})
+		rollout := newCanaryRollout(fake.NewClient(), dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		assert.Nil(t, dynakube.Status.OneAgent.Rollout)
+	})
+	t.Run("no rollout without version change", func(t *testing.T) {
+		dynakube := newCanaryDynakube(dynatracev1beta1.OneAgentCanarySpec{Percentage: 50})
+		rollout := newCanaryRollout(fake.NewClient(newOneAgentDaemonSet(testTargetVersion, testTargetImage)), dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		assert.Nil(t, dynakube.Status.OneAgent.Rollout)
+	})
+	t.Run("starts rollout on canary percentage", func(t *testing.T) {
+		dynakube := newCanaryDynakube(dynatracev1beta1.OneAgentCanarySpec{Percentage: 50})
+		clt := fake.NewClient(
+			newOneAgentDaemonSet(testPreviousVersion, testPreviousImage),
+			newOneAgentPod("node-c", testPreviousVersion),
+			newOneAgentPod("node-a", testPreviousVersion),
+			newOneAgentPod("node-b", testPreviousVersion))
+		rollout := newCanaryRollout(clt, dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		status := dynakube.Status.OneAgent.Rollout
+		require.NotNil(t, status)
+		assert.Equal(t, dynatracev1beta1.OneAgentRolloutCanary, status.Phase)
+		assert.Equal(t, testTargetVersion, status.TargetVersion)
+		assert.Equal(t, testPreviousVersion, status.PreviousVersion)
+		assert.Equal(t, testPreviousImage, status.PreviousImage)
+		assert.Equal(t, []string{"node-a", "node-b"}, status.CanaryNodes)
+		assert.Equal(t, &now, status.StartTimestamp)
+	})
+	t.Run("starts rollout on canary node selector", func(t *testing.T) {
+		dynakube := newCanaryDynakube(dynatracev1beta1.OneAgentCanarySpec{NodeSelector: map[string]string{"pool": "canary"}})
+		canaryNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{"pool": "canary"}}}
+		canaryNodeWithoutOneAgent := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-x", Labels: map[string]string{"pool": "canary"}}}
+		clt := fake.NewClient(
+			newOneAgentDaemonSet(testPreviousVersion, testPreviousImage),
+			canaryNode, canaryNodeWithoutOneAgent,
+			newOneAgentPod("node-a", testPreviousVersion),
+			newOneAgentPod("node-b", testPreviousVersion))
+		rollout := newCanaryRollout(clt, dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		require.NotNil(t, dynakube.Status.OneAgent.Rollout)
+		assert.Equal(t, []string{"node-b"}, dynakube.Status.OneAgent.Rollout.CanaryNodes)
+	})
+	t.Run("canary node selector without OneAgent fails", func(t *testing.T) {
+		dynakube := newCanaryDynakube(dynatracev1beta1.OneAgentCanarySpec{NodeSelector: map[string]string{"pool": "canary"}})
+		canaryNodeWithoutOneAgent := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-x", Labels: map[string]string{"pool": "canary"}}}
+		clt := fake.NewClient(
+			newOneAgentDaemonSet(testPreviousVersion, testPreviousImage),
+			canaryNodeWithoutOneAgent,
+			newOneAgentPod("node-a", testPreviousVersion))
+		rollout := newCanaryRollout(clt, dynakube, &now)
+
+		err := rollout.advance(ctx)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "matches none of the 1 nodes running a OneAgent")
+		assert.Nil(t, dynakube.Status.OneAgent.Rollout)
+	})
+	t.Run("canary stage is skipped without OneAgent pods", func(t *testing.T) {
+		dynakube := newCanaryDynakube(dynatracev1beta1.OneAgentCanarySpec{Percentage: 50})
+		rollout := newCanaryRollout(fake.NewClient(newOneAgentDaemonSet(testPreviousVersion, testPreviousImage)), dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		assert.Nil(t, dynakube.Status.OneAgent.Rollout)
+	})
+	t.Run("interrupted rollout keeps the version before it", func(t *testing.T) {
+		dynakube := newCanaryDynakube(dynatracev1beta1.OneAgentCanarySpec{Percentage: 100})
+		dynakube.Status.OneAgent.Version = "3.0.0"
+		dynakube.Status.OneAgent.Rollout = &dynatracev1beta1.OneAgentRolloutStatus{
+			Phase:           dynatracev1beta1.OneAgentRolloutBaking,
+			TargetVersion:   testTargetVersion,
+			PreviousVersion: testPreviousVersion,
+			PreviousImage:   testPreviousImage,
+		}
+		clt := fake.NewClient(newOneAgentDaemonSet(testTargetVersion, testTargetImage), newOneAgentPod("node-a", testTargetVersion))
+		rollout := newCanaryRollout(clt, dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		status := dynakube.Status.OneAgent.Rollout
+		require.NotNil(t, status)
+		assert.Equal(t, dynatracev1beta1.OneAgentRolloutCanary, status.Phase)
+		assert.Equal(t, "3.0.0", status.TargetVersion)
+		assert.Equal(t, testPreviousVersion, status.PreviousVersion)
+		assert.Equal(t, testPreviousImage, status.PreviousImage)
+	})
+}
+
+func TestCanaryRolloutAdvance(t *testing.T) {
+	ctx := context.Background()
+	start := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	t.Run("ready canaries start baking", func(t *testing.T) {
+		now := metav1.NewTime(start.Add(time.Minute))
+		dynakube := newRollingOutDynakube(dynatracev1beta1.OneAgentRolloutCanary, &start)
+		rollout := newCanaryRollout(fake.NewClient(newReadyOneAgentPod("node-a", testTargetVersion)), dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		assert.Equal(t, dynatracev1beta1.OneAgentRolloutBaking, dynakube.Status.OneAgent.Rollout.Phase)
+		assert.Equal(t, &now, dynakube.Status.OneAgent.Rollout.CanaryReadyTimestamp)
+	})
+	t.Run("unready canaries are waited for", func(t *testing.T) {
+		now := metav1.NewTime(start.Add(time.Minute))
+		dynakube := newRollingOutDynakube(dynatracev1beta1.OneAgentRolloutCanary, &start)
+		rollout := newCanaryRollout(fake.NewClient(newOneAgentPod("node-a", testTargetVersion)), dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		assert.Equal(t, dynatracev1beta1.OneAgentRolloutCanary, dynakube.Status.OneAgent.Rollout.Phase)
+	})
+	t.Run("unready canaries are rolled back after the progress deadline", func(t *testing.T) {
+		now := metav1.NewTime(start.Add(20 * time.Minute))
+		dynakube := newRollingOutDynakube(dynatracev1beta1.OneAgentRolloutCanary, &start)
+		rollout := newCanaryRollout(fake.NewClient(newOneAgentPod("node-a", testTargetVersion)), dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		assert.Equal(t, dynatracev1beta1.OneAgentRolloutRolledBack, dynakube.Status.OneAgent.Rollout.Phase)
+		assert.Equal(t, "canary OneAgents did not become ready within 15m0s", dynakube.Status.OneAgent.Rollout.Message)
+	})
+	t.Run("crashing canaries are rolled back", func(t *testing.T) {
+		now := metav1.NewTime(start.Add(time.Minute))
+		dynakube := newRollingOutDynakube(dynatracev1beta1.OneAgentRolloutCanary, &start)
+		pod := newOneAgentPod("node-a", testTargetVersion)
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}
+		rollout := newCanaryRollout(fake.NewClient(pod), dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		assert.Equal(t, dynatracev1beta1.OneAgentRolloutRolledBack, dynakube.Status.OneAgent.Rollout.Phase)
+		assert.Equal(t, fmt.Sprintf("canary OneAgent pod %s on node node-a is in CrashLoopBackOff", pod.Name), dynakube.Status.OneAgent.Rollout.Message)
+	})
+	t.Run("restarted canaries are rolled back during the bake time", func(t *testing.T) {
+		now := metav1.NewTime(start.Add(time.Minute))
+		dynakube := newRollingOutDynakube(dynatracev1beta1.OneAgentRolloutBaking, &start)
+		pod := newReadyOneAgentPod("node-a", testTargetVersion)
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{RestartCount: 1}}
+		rollout := newCanaryRollout(fake.NewClient(pod), dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		assert.Equal(t, dynatracev1beta1.OneAgentRolloutRolledBack, dynakube.Status.OneAgent.Rollout.Phase)
+	})
+	t.Run("healthy canaries complete the rollout after the bake time", func(t *testing.T) {
+		now := metav1.NewTime(start.Add(11 * time.Minute))
+		dynakube := newRollingOutDynakube(dynatracev1beta1.OneAgentRolloutBaking, &start)
+		rollout := newCanaryRollout(fake.NewClient(newReadyOneAgentPod("node-a", testTargetVersion)), dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		assert.Equal(t, dynatracev1beta1.OneAgentRolloutCompleted, dynakube.Status.OneAgent.Rollout.Phase)
+	})
+	t.Run("rolled back rollout is not retried for the same version", func(t *testing.T) {
+		now := metav1.NewTime(start.Add(time.Minute))
+		dynakube := newRollingOutDynakube(dynatracev1beta1.OneAgentRolloutRolledBack, &start)
+		rollout := newCanaryRollout(fake.NewClient(newOneAgentDaemonSet(testPreviousVersion, testPreviousImage)), dynakube, &now)
+
+		require.NoError(t, rollout.advance(ctx))
+
+		assert.Equal(t, dynatracev1beta1.OneAgentRolloutRolledBack, dynakube.Status.OneAgent.Rollout.Phase)
+	})
+}
+
+func TestCanaryRolloutDeployedDynakube(t *testing.T) {
+	t.Run("new version while rolling out", func(t *testing.T) {
+		dynakube := newRollingOutDynakube(dynatracev1beta1.OneAgentRolloutCanary, nil)
+
+		deployed := newCanaryRollout(nil, dynakube, nil).deployedDynakube()
+
+		assert.Equal(t, testTargetVersion, deployed.OneAgentVersion())
+		assert.Equal(t, testTargetImage, deployed.OneAgentImage())
+	})
+	t.Run("previous version after rollback", func(t *testing.T) {
+		dynakube := newRollingOutDynakube(dynatracev1beta1.OneAgentRolloutRolledBack, nil)
+
+		deployed := newCanaryRollout(nil, dynakube, nil).deployedDynakube()
+
+		assert.Equal(t, testPreviousVersion, deployed.OneAgentVersion())
+		assert.Equal(t, testPreviousImage, deployed.OneAgentImage())
+		assert.Equal(t, testTargetVersion, dynakube.OneAgentVersion())
+	})
+}
+
+func TestCanaryRolloutUpdateCanaryPods(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("outdated canary pods are deleted", func(t *testing.T) {
+		dynakube := newRollingOutDynakube(dynatracev1beta1.OneAgentRolloutCanary, nil)
+		dynakube.Status.OneAgent.Rollout.CanaryNodes = []string{"node-a", "node-b"}
+		clt := fake.NewClient(
+			newOneAgentPod("node-a", testPreviousVersion),
+			newOneAgentPod("node-b", testTargetVersion),
+			newOneAgentPod("node-c", testPreviousVersion))
+
+		require.NoError(t, newCanaryRollout(clt, dynakube, nil).updateCanaryPods(ctx))
+
+		var pods corev1.PodList
+		require.NoError(t, clt.List(ctx, &pods))
+
+		nodes := make([]string, 0, len(pods.Items))
+		for _, pod := range pods.Items {
+			nodes = append(nodes, pod.Spec.NodeName)
+		}
+		assert.ElementsMatch(t, []string{"node-b", "node-c"}, nodes)
+	})
+	t.Run("pods are left alone while baking", func(t *testing.T) {
+		dynakube := newRollingOutDynakube(dynatracev1beta1.OneAgentRolloutBaking, nil)
+		clt := fake.NewClient(newOneAgentPod("node-a", testPreviousVersion))
+
+		require.NoError(t, newCanaryRollout(clt, dynakube, nil).updateCanaryPods(ctx))
+
+		var pods corev1.PodList
+		require.NoError(t, clt.List(ctx, &pods))
+		assert.Len(t, pods.Items, 1)
+	})
+}
+
+func TestReconcileRollout_StagedRollout(t *testing.T) {
+	ctx := context.Background()
+	dynakube := newCanaryDynakube(dynatracev1beta1.OneAgentCanarySpec{Percentage: 50})
+	dynakube.UID = "dynakube-uid"
+	clt := fake.NewClient(
+		newOneAgentDaemonSet(testPreviousVersion, testPreviousImage),
+		newOneAgentPod("node-a", testPreviousVersion),
+		newOneAgentPod("node-b", testPreviousVersion))
+	reconciler := NewOneAgentReconciler(clt, clt, clt.Scheme(), record.NewFakeRecorder(10), testClusterID)
+
+	require.NoError(t, reconciler.reconcileRollout(ctx, dynakube))
+
+	var daemonSet appsv1.DaemonSet
+	require.NoError(t, clt.Get(ctx, client.ObjectKey{Name: dynakube.OneAgentDaemonsetName(), Namespace: testCanaryNamespace}, &daemonSet))
+	assert.Equal(t, appsv1.OnDeleteDaemonSetStrategyType, daemonSet.Spec.UpdateStrategy.Type)
+	assert.Equal(t, testTargetImage, daemonSet.Spec.Template.Spec.Containers[0].Image)
+
+	var pods corev1.PodList
+	require.NoError(t, clt.List(ctx, &pods))
+	require.Len(t, pods.Items, 1)
+	assert.Equal(t, "node-b", pods.Items[0].Spec.NodeName)
+}
+
+func newCanaryDynakube(canary dynatracev1beta1.OneAgentCanarySpec) *dynatracev1beta1.DynaKube {
+	dynakube := &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testCanaryDynakube,
+			Namespace: testCanaryNamespace,
+		},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ClassicFullStack: &dynatracev1beta1.HostInjectSpec{},
+				Rollout: &dynatracev1beta1.OneAgentRolloutSpec{
+					Canary: canary,
+				},
+			},
+		},
+	}
+	dynakube.Status.OneAgent.Version = testTargetVersion
+	dynakube.Status.OneAgent.ImageID = testTargetImage
+	return dynakube
+}
+
+func newRollingOutDynakube(phase dynatracev1beta1.OneAgentRolloutPhase, start *metav1.Time) *dynatracev1beta1.DynaKube {
+	dynakube := newCanaryDynakube(dynatracev1beta1.OneAgentCanarySpec{Percentage: 50})
+	dynakube.Status.OneAgent.Rollout = &dynatracev1beta1.OneAgentRolloutStatus{
+		Phase:                phase,
+		TargetVersion:        testTargetVersion,
+		PreviousVersion:      testPreviousVersion,
+		PreviousImage:        testPreviousImage,
+		CanaryNodes:          []string{"node-a"},
+		StartTimestamp:       start,
+		CanaryReadyTimestamp: start,
+	}
+	return dynakube
+}
+
+func newOneAgentDaemonSet(version, image string) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testCanaryDynakube + "-oneagent",
+			Namespace: testCanaryNamespace,
+			Labels:    map[string]string{kubeobjects.AppVersionLabel: version},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: kubeobjects.NewAppLabels(kubeobjects.OneAgentComponentLabel, testCanaryDynakube, "", "").BuildMatchLabels(),
+			},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Image: image}},
+				},
+			},
+		},
+	}
+}
+
+func newOneAgentPod(node, version string) *corev1.Pod {
+	labels := kubeobjects.NewAppLabels(kubeobjects.OneAgentComponentLabel, testCanaryDynakube, "", version).BuildLabels()
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("oneagent-%s-%s", node, version),
+			Namespace: testCanaryNamespace,
+			Labels:    labels,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+		},
+	}
+}
+
+func newReadyOneAgentPod(node, version string) *corev1.Pod {
+	pod := newOneAgentPod(node, version)
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	return pod
+}
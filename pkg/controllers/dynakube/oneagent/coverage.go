@@ -0,0 +1,269 @@
+package oneagent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// pods and nodes younger than the grace period aren't reported, the DaemonSet controller and the scheduler may still be at it
+	coverageGracePeriod = 5 * time.Minute
+
+	// caps the uncovered nodes kept in the status, the condition still tells the total
+	maxUncoveredNodes = 50
+
+	// caps the node names listed in the condition message
+	maxUncoveredNodesInMessage = 10
+
+	nodeNotCoveredEvent = "OneAgentNodeNotCovered"
+	nodeCoveredEvent    = "OneAgentNodeCovered"
+)
+
+// taints every DaemonSet pod tolerates, the DaemonSet controller adds the tolerations itself
+var daemonSetTolerated = []string{
+	"node.kubernetes.io/not-ready",
+	"node.kubernetes.io/unreachable",
+	"node.kubernetes.io/disk-pressure",
+	"node.kubernetes.io/memory-pressure",
+	"node.kubernetes.io/pid-pressure",
+	"node.kubernetes.io/unschedulable",
+	"node.kubernetes.io/network-unavailable",
+}
+
+// reconcileCoverage finds the nodes without a running OneAgent and tells why the DaemonSets don't cover them,
+// the result is stored in the status and a condition, changes are announced as events on the DynaKube
+func (r *Reconciler) reconcileCoverage(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) error {
+	var daemonSet appsv1.DaemonSet
+	err := r.client.Get(ctx, types.NamespacedName{Name: dynakube.OneAgentDaemonsetName(), Namespace: dynakube.Namespace}, &daemonSet)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.WithMessage(err, "failed to get OneAgent daemonset")
+	}
+
+	podSpecs := []*corev1.PodSpec{&daemonSet.Spec.Template.Spec}
+	for _, name := range dynakube.OneAgentDaemonsetNames()[1:] {
+		var profileDaemonSet appsv1.DaemonSet
+		err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: dynakube.Namespace}, &profileDaemonSet)
+		if k8serrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.WithMessage(err, "failed to get OneAgent daemonset of node profile")
+		}
+		podSpecs = append(podSpecs, &profileDaemonSet.Spec.Template.Spec)
+	}
+
+	var nodes corev1.NodeList
+	if err := r.client.List(ctx, &nodes); err != nil {
+		return errors.WithMessage(err, "failed to list nodes")
+	}
+
+	// the match labels of the DaemonSet without a node profile are shared by the pods of the node profiles
+	var pods corev1.PodList
+	err = r.client.List(ctx, &pods, client.InNamespace(dynakube.Namespace), client.MatchingLabels(daemonSet.Spec.Selector.MatchLabels))
+	if err != nil {
+		return errors.WithMessage(err, "failed to list OneAgent pods")
+	}
+
+	uncoveredNodes := findUncoveredNodes(nodes.Items, podSpecs, pods.Items, time.Now())
+	r.sendCoverageEvents(dynakube, dynakube.Status.OneAgent.UncoveredNodes, uncoveredNodes)
+	setCoverageCondition(dynakube, len(nodes.Items), uncoveredNodes)
+
+	if len(uncoveredNodes) > maxUncoveredNodes {
+		uncoveredNodes = uncoveredNodes[:maxUncoveredNodes]
+	}
+	dynakube.Status.OneAgent.UncoveredNodes = uncoveredNodes
+	return nil
+}
+
+// ResetCoverage removes the coverage status of DynaKubes without OneAgent
+func ResetCoverage(dynakube *dynatracev1beta1.DynaKube) {
+	dynakube.Status.OneAgent.UncoveredNodes = nil
+	meta.RemoveStatusCondition(&dynakube.Status.Conditions, dynatracev1beta1.OneAgentCoverageConditionType)
+}
+
+// findUncoveredNodes checks the nodes against the pod specs of the OneAgent DaemonSets, the first one is the one without a node profile
+func findUncoveredNodes(nodes []corev1.Node, podSpecs []*corev1.PodSpec, pods []corev1.Pod, now time.Time) []dynatracev1beta1.OneAgentUncoveredNode {
+	podsByNode := make(map[string]*corev1.Pod, len(pods))
+	for i := range pods {
+		if nodeName := podNodeName(&pods[i]); nodeName != "" {
+			podsByNode[nodeName] = &pods[i]
+		}
+	}
+
+	uncoveredNodes := []dynatracev1beta1.OneAgentUncoveredNode{}
+	for i := range nodes {
+		node := &nodes[i]
+		pod := podsByNode[node.Name]
+		if pod != nil && pod.Status.Phase == corev1.PodRunning {
+			continue
+		}
+
+		reason, message := coverageGap(node, podSpecForNode(node, podSpecs), pod, now)
+		if reason != "" {
+			uncoveredNodes = append(uncoveredNodes, dynatracev1beta1.OneAgentUncoveredNode{
+				NodeName: node.Name,
+				Reason:   reason,
+				Message:  message,
+			})
+		}
+	}
+
+	sort.Slice(uncoveredNodes, func(i, j int) bool {
+		return uncoveredNodes[i].NodeName < uncoveredNodes[j].NodeName
+	})
+	return uncoveredNodes
+}
+
+// podSpecForNode returns the pod spec of the DaemonSet responsible for the node, if no DaemonSet matches the node it's the first one
+func podSpecForNode(node *corev1.Node, podSpecs []*corev1.PodSpec) *corev1.PodSpec {
+	for _, podSpec := range podSpecs {
+		if kubeobjects.NodeMatchesPodSpec(node, podSpec) {
+			return podSpec
+		}
+	}
+	return podSpecs[0]
+}
+
+// coverageGap tells why a node has no running OneAgent pod, nodes and pods still within the grace period have no gap
+func coverageGap(node *corev1.Node, podSpec *corev1.PodSpec, pod *corev1.Pod, now time.Time) (dynatracev1beta1.OneAgentCoverageGapReason, string) {
+	if !kubeobjects.NodeMatchesPodSpec(node, podSpec) {
+		return dynatracev1beta1.CoverageGapSelectorMismatch, "node doesn't match the node selector or node affinity of the OneAgent DaemonSet"
+	}
+	if taint := untoleratedTaint(node, podSpec.Tolerations); taint != nil {
+		return dynatracev1beta1.CoverageGapUntoleratedTaint, fmt.Sprintf("taint %s isn't tolerated", taint.ToString())
+	}
+
+	if pod == nil {
+		if now.Sub(node.CreationTimestamp.Time) < coverageGracePeriod {
+			return "", ""
+		}
+		return dynatracev1beta1.CoverageGapPodPending, "no OneAgent pod exists for the node"
+	}
+
+	scheduled := findPodCondition(pod, corev1.PodScheduled)
+	if scheduled != nil && scheduled.Status == corev1.ConditionFalse && strings.Contains(scheduled.Message, "Insufficient") {
+		return dynatracev1beta1.CoverageGapInsufficientResources, scheduled.Message
+	}
+	if now.Sub(pod.CreationTimestamp.Time) < coverageGracePeriod {
+		return "", ""
+	}
+	if scheduled != nil && scheduled.Status == corev1.ConditionFalse {
+		return dynatracev1beta1.CoverageGapPodPending, scheduled.Message
+	}
+	return dynatracev1beta1.CoverageGapPodPending, fmt.Sprintf("pod %s is %s", pod.Name, pod.Status.Phase)
+}
+
+func untoleratedTaint(node *corev1.Node, tolerations []corev1.Toleration) *corev1.Taint {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule || slices.Contains(daemonSetTolerated, taint.Key) {
+			continue
+		}
+
+		tolerated := slices.ContainsFunc(tolerations, func(toleration corev1.Toleration) bool {
+			return toleration.ToleratesTaint(taint)
+		})
+		if !tolerated {
+			return taint
+		}
+	}
+	return nil
+}
+
+// podNodeName returns the node of a DaemonSet pod, pods that aren't scheduled yet are pinned to their node by node affinity
+func podNodeName(pod *corev1.Pod) string {
+	if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName
+	}
+
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, field := range term.MatchFields {
+			if field.Key == "metadata.name" && field.Operator == corev1.NodeSelectorOpIn && len(field.Values) == 1 {
+				return field.Values[0]
+			}
+		}
+	}
+	return ""
+}
+
+func findPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == conditionType {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) sendCoverageEvents(dynakube *dynatracev1beta1.DynaKube, previous, current []dynatracev1beta1.OneAgentUncoveredNode) {
+	previousReasons := make(map[string]dynatracev1beta1.OneAgentCoverageGapReason, len(previous))
+	for _, uncoveredNode := range previous {
+		previousReasons[uncoveredNode.NodeName] = uncoveredNode.Reason
+	}
+
+	// only the first nodes are kept in the status, nodes beyond them may have been reported already
+	previousCapped := len(previous) >= maxUncoveredNodes
+	for _, uncoveredNode := range current {
+		if previousCapped && uncoveredNode.NodeName > previous[len(previous)-1].NodeName {
+			continue
+		}
+		if reason, ok := previousReasons[uncoveredNode.NodeName]; ok {
+			delete(previousReasons, uncoveredNode.NodeName)
+			if reason == uncoveredNode.Reason {
+				continue
+			}
+		}
+		r.recorder.Eventf(dynakube, corev1.EventTypeWarning, nodeNotCoveredEvent,
+			"OneAgent doesn't run on node %s (%s): %s", uncoveredNode.NodeName, uncoveredNode.Reason, uncoveredNode.Message)
+	}
+
+	for nodeName := range previousReasons {
+		r.recorder.Eventf(dynakube, corev1.EventTypeNormal, nodeCoveredEvent, "OneAgent runs on node %s", nodeName)
+	}
+}
+
+func setCoverageCondition(dynakube *dynatracev1beta1.DynaKube, nodeCount int, uncoveredNodes []dynatracev1beta1.OneAgentUncoveredNode) {
+	condition := metav1.Condition{
+		Type:    dynatracev1beta1.OneAgentCoverageConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  dynatracev1beta1.ReasonAllNodesCovered,
+		Message: fmt.Sprintf("OneAgent runs on all %d nodes", nodeCount),
+	}
+
+	if len(uncoveredNodes) > 0 {
+		names := make([]string, 0, maxUncoveredNodesInMessage)
+		for _, uncoveredNode := range uncoveredNodes {
+			if len(names) == maxUncoveredNodesInMessage {
+				names = append(names, "...")
+				break
+			}
+			names = append(names, fmt.Sprintf("%s (%s)", uncoveredNode.NodeName, uncoveredNode.Reason))
+		}
+
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = dynatracev1beta1.ReasonNodesNotCovered
+		condition.Message = fmt.Sprintf("OneAgent doesn't run on %d of %d nodes: %s", len(uncoveredNodes), nodeCount, strings.Join(names, ", "))
+	}
+
+	meta.SetStatusCondition(&dynakube.Status.Conditions, condition)
+}
@@ -0,0 +1,213 @@
+package oneagent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testCoverageNamespace = "dynatrace"
+	testCoverageDynakube  = "dynakube"
+)
+
+var testCoverageLabels = map[string]string{"app": "oneagent"}
+
+func TestFindUncoveredNodes(t *testing.T) {
+	now := time.Now()
+	old := metav1.NewTime(now.Add(-time.Hour))
+	podSpec := &corev1.PodSpec{
+		NodeSelector: map[string]string{"monitored": "true"},
+		Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "oneagent"}},
+	}
+	podSpecs := []*corev1.PodSpec{podSpec}
+
+	t.Run("node with running pod is covered", func(t *testing.T) {
+		nodes := []corev1.Node{newCoverageNode("node1", old)}
+		pods := []corev1.Pod{newCoveragePod("node1", corev1.PodRunning, old)}
+
+		assert.Empty(t, findUncoveredNodes(nodes, podSpecs, pods, now))
+	})
+	t.Run("node outside of node selector", func(t *testing.T) {
+		node := newCoverageNode("node1", old)
+		node.Labels = nil
+
+		uncoveredNodes := findUncoveredNodes([]corev1.Node{node}, podSpecs, nil, now)
+
+		require.Len(t, uncoveredNodes, 1)
+		assert.Equal(t, dynatracev1beta1.CoverageGapSelectorMismatch, uncoveredNodes[0].Reason)
+	})
+	t.Run("node with untolerated taint", func(t *testing.T) {
+		node := newCoverageNode("node1", old)
+		node.Spec.Taints = []corev1.Taint{
+			{Key: "dedicated", Value: "oneagent", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "node.kubernetes.io/unschedulable", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "nvidia.com/gpu", Value: "present", Effect: corev1.TaintEffectNoSchedule},
+		}
+
+		uncoveredNodes := findUncoveredNodes([]corev1.Node{node}, podSpecs, nil, now)
+
+		require.Len(t, uncoveredNodes, 1)
+		assert.Equal(t, dynatracev1beta1.CoverageGapUntoleratedTaint, uncoveredNodes[0].Reason)
+		assert.Contains(t, uncoveredNodes[0].Message, "nvidia.com/gpu=present:NoSchedule")
+	})
+	t.Run("pod lacking resources", func(t *testing.T) {
+		pod := newCoveragePod("", corev1.PodPending, metav1.NewTime(now))
+		pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node1"}}}}},
+		}}}
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable", Message: "0/1 nodes are available: 1 Insufficient memory."}}
+
+		uncoveredNodes := findUncoveredNodes([]corev1.Node{newCoverageNode("node1", old)}, podSpecs, []corev1.Pod{pod}, now)
+
+		require.Len(t, uncoveredNodes, 1)
+		assert.Equal(t, dynatracev1beta1.CoverageGapInsufficientResources, uncoveredNodes[0].Reason)
+		assert.Equal(t, "0/1 nodes are available: 1 Insufficient memory.", uncoveredNodes[0].Message)
+	})
+	t.Run("pending pod is reported after the grace period", func(t *testing.T) {
+		nodes := []corev1.Node{newCoverageNode("node1", old), newCoverageNode("node2", old)}
+		pods := []corev1.Pod{
+			newCoveragePod("node1", corev1.PodPending, metav1.NewTime(now)),
+			newCoveragePod("node2", corev1.PodPending, old),
+		}
+
+		uncoveredNodes := findUncoveredNodes(nodes, podSpecs, pods, now)
+
+		require.Len(t, uncoveredNodes, 1)
+		assert.Equal(t, "node2", uncoveredNodes[0].NodeName)
+		assert.Equal(t, dynatracev1beta1.CoverageGapPodPending, uncoveredNodes[0].Reason)
+	})
+	t.Run("node of a node profile is checked against the daemonset of the node profile", func(t *testing.T) {
+		node := newCoverageNode("node1", old)
+		node.Labels = map[string]string{"pool": "gpu"}
+		node.Spec.Taints = []corev1.Taint{{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}}
+		profilePodSpec := &corev1.PodSpec{
+			NodeSelector: map[string]string{"pool": "gpu"},
+			Tolerations:  []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+		}
+
+		uncoveredNodes := findUncoveredNodes([]corev1.Node{node}, []*corev1.PodSpec{podSpec, profilePodSpec}, nil, now)
+
+		require.Len(t, uncoveredNodes, 1)
+		assert.Equal(t, dynatracev1beta1.CoverageGapPodPending, uncoveredNodes[0].Reason)
+	})
+	t.Run("missing pod is reported after the grace period of the node", func(t *testing.T) {
+		nodes := []corev1.Node{newCoverageNode("node1", metav1.NewTime(now)), newCoverageNode("node2", old)}
+
+		uncoveredNodes := findUncoveredNodes(nodes, podSpecs, nil, now)
+
+		require.Len(t, uncoveredNodes, 1)
+		assert.Equal(t, "node2", uncoveredNodes[0].NodeName)
+		assert.Equal(t, dynatracev1beta1.CoverageGapPodPending, uncoveredNodes[0].Reason)
+	})
+}
+
+func TestReconcileCoverage(t *testing.T) {
+	ctx := context.Background()
+	old := metav1.NewTime(time.Now().Add(-time.Hour))
+	dynakube := &dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: testCoverageDynakube, Namespace: testCoverageNamespace}}
+	tainted := newCoverageNode("node2", old)
+	tainted.Spec.Taints = []corev1.Taint{{Key: "CriticalAddonsOnly", Effect: corev1.TaintEffectNoSchedule}}
+	clt := fake.NewClient(
+		newCoverageDaemonSet(),
+		&corev1.Node{ObjectMeta: newCoverageNode("node1", old).ObjectMeta},
+		&tainted,
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "oneagent-node1", Namespace: testCoverageNamespace, Labels: testCoverageLabels},
+			Spec:       corev1.PodSpec{NodeName: "node1"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	)
+	recorder := record.NewFakeRecorder(10)
+	reconciler := NewOneAgentReconciler(clt, clt, clt.Scheme(), recorder, testClusterID)
+
+	t.Run("report uncovered node", func(t *testing.T) {
+		require.NoError(t, reconciler.reconcileCoverage(ctx, dynakube))
+
+		require.Len(t, dynakube.Status.OneAgent.UncoveredNodes, 1)
+		assert.Equal(t, "node2", dynakube.Status.OneAgent.UncoveredNodes[0].NodeName)
+		assert.Equal(t, dynatracev1beta1.CoverageGapUntoleratedTaint, dynakube.Status.OneAgent.UncoveredNodes[0].Reason)
+
+		condition := meta.FindStatusCondition(dynakube.Status.Conditions, dynatracev1beta1.OneAgentCoverageConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, "OneAgent doesn't run on 1 of 2 nodes: node2 (UntoleratedTaint)", condition.Message)
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Warning "+nodeNotCoveredEvent+" OneAgent doesn't run on node node2")
+	})
+	t.Run("no repeated events for the same gap", func(t *testing.T) {
+		require.NoError(t, reconciler.reconcileCoverage(ctx, dynakube))
+
+		assert.Empty(t, recorder.Events)
+	})
+	t.Run("report covered node", func(t *testing.T) {
+		var daemonSet appsv1.DaemonSet
+		require.NoError(t, clt.Get(ctx, client.ObjectKey{Name: dynakube.OneAgentDaemonsetName(), Namespace: testCoverageNamespace}, &daemonSet))
+		daemonSet.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "CriticalAddonsOnly", Operator: corev1.TolerationOpExists}}
+		require.NoError(t, clt.Update(ctx, &daemonSet))
+		require.NoError(t, clt.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "oneagent-node2", Namespace: testCoverageNamespace, Labels: testCoverageLabels},
+			Spec:       corev1.PodSpec{NodeName: "node2"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}))
+
+		require.NoError(t, reconciler.reconcileCoverage(ctx, dynakube))
+
+		assert.Empty(t, dynakube.Status.OneAgent.UncoveredNodes)
+		condition := meta.FindStatusCondition(dynakube.Status.Conditions, dynatracev1beta1.OneAgentCoverageConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, dynatracev1beta1.ReasonAllNodesCovered, condition.Reason)
+
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal "+nodeCoveredEvent+" OneAgent runs on node node2", <-recorder.Events)
+	})
+	t.Run("reset coverage", func(t *testing.T) {
+		ResetCoverage(dynakube)
+
+		assert.Nil(t, dynakube.Status.OneAgent.UncoveredNodes)
+		assert.Nil(t, meta.FindStatusCondition(dynakube.Status.Conditions, dynatracev1beta1.OneAgentCoverageConditionType))
+	})
+}
+
+func newCoverageNode(name string, created metav1.Time) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Labels:            map[string]string{"monitored": "true"},
+			CreationTimestamp: created,
+		},
+	}
+}
+
+func newCoveragePod(nodeName string, phase corev1.PodPhase, created metav1.Time) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "oneagent-" + nodeName, CreationTimestamp: created},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func newCoverageDaemonSet() *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: testCoverageDynakube + "-" + dynatracev1beta1.PodNameOsAgent, Namespace: testCoverageNamespace},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: testCoverageLabels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: testCoverageLabels},
+			},
+		},
+	}
+}
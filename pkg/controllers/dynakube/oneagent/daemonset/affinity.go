@@ -17,14 +17,20 @@ func (dsInfo *builderInfo) affinity() *corev1.Affinity {
 
 func (dsInfo *builderInfo) affinityNodeSelectorTerms() []corev1.NodeSelectorTerm {
 	nodeSelectorTerms := []corev1.NodeSelectorTerm{
-		kubernetesArchOsSelectorTerm(),
+		dsInfo.kubernetesArchOsSelectorTerm(),
 	}
 
-	return nodeSelectorTerms
+	return excludeNodeProfiles(nodeSelectorTerms, dsInfo.excludedProfiles())
 }
 
-func kubernetesArchOsSelectorTerm() corev1.NodeSelectorTerm {
+// kubernetesArchOsSelectorTerm keeps the OneAgent off nodes its (multi-arch) image isn't available for
+func (dsInfo *builderInfo) kubernetesArchOsSelectorTerm() corev1.NodeSelectorTerm {
+	var imageArches []string
+	if dsInfo.dynakube != nil {
+		imageArches = dsInfo.dynakube.Status.OneAgent.Architectures()
+	}
+
 	return corev1.NodeSelectorTerm{
-		MatchExpressions: kubeobjects.AffinityNodeRequirementForSupportedArches(),
+		MatchExpressions: kubeobjects.AffinityNodeRequirementForImageArches(imageArches),
 	}
 }
@@ -3,6 +3,8 @@ package daemonset
 import (
 	"testing"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -39,3 +41,35 @@ func TestAffinity(t *testing.T) {
 		},
 	})
 }
+
+func TestAffinityForMultiArchImage(t *testing.T) {
+	dsInfo := builderInfo{
+		dynakube: &dynatracev1beta1.DynaKube{
+			Status: dynatracev1beta1.DynaKubeStatus{
+				OneAgent: dynatracev1beta1.OneAgentStatus{
+					VersionStatus: status.VersionStatus{
+						Platforms: []status.PlatformImage{
+							{Architecture: "amd64", Digest: "sha256:123"},
+							{Architecture: "arm64", Digest: "sha256:456"},
+						},
+					},
+				},
+			},
+		},
+	}
+	affinity := dsInfo.affinity()
+	assert.Contains(t, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms, corev1.NodeSelectorTerm{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{
+				Key:      "kubernetes.io/arch",
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{"amd64", "arm64"},
+			},
+			{
+				Key:      "kubernetes.io/os",
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{"linux"},
+			},
+		},
+	})
+}
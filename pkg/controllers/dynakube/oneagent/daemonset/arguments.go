@@ -10,6 +10,7 @@ import (
 
 const argumentPrefix = "--"
 const customArgumentPriority = 2
+const nodeProfileArgumentPriority = 3
 const defaultArgumentPriority = 1
 
 func (dsInfo *builderInfo) arguments() []string {
@@ -34,6 +35,9 @@ func (dsInfo *builderInfo) appendHostInjectArgs(argMap *prioritymap.Map) {
 	if dsInfo.hostInjectSpec != nil {
 		prioritymap.Append(argMap, dsInfo.hostInjectSpec.Args, prioritymap.WithPriority(customArgumentPriority))
 	}
+	if dsInfo.profile != nil {
+		prioritymap.Append(argMap, dsInfo.profile.Args, prioritymap.WithPriority(nodeProfileArgumentPriority))
+	}
 }
 
 func appendOperatorVersionArg(argMap *prioritymap.Map) {
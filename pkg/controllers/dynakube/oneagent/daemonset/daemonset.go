@@ -3,6 +3,7 @@ package daemonset
 import (
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/deploymentmetadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/address"
 	"github.com/Dynatrace/dynatrace-operator/pkg/webhook"
@@ -56,10 +57,14 @@ type builderInfo struct {
 	hostInjectSpec *dynatracev1beta1.HostInjectSpec
 	clusterID      string
 	deploymentType string
+
+	// the node profile the DaemonSet is built for, nil for the nodes without a node profile
+	profile *dynatracev1beta1.OneAgentNodeProfile
 }
 
 type Builder interface {
 	BuildDaemonSet() (*appsv1.DaemonSet, error)
+	BuildDaemonSets() ([]*appsv1.DaemonSet, error)
 }
 
 func NewHostMonitoring(instance *dynatracev1beta1.DynaKube, clusterId string) Builder {
@@ -101,7 +106,7 @@ func (dsInfo *HostMonitoring) BuildDaemonSet() (*appsv1.DaemonSet, error) {
 		return nil, err
 	}
 
-	daemonSet.Name = dsInfo.dynakube.OneAgentDaemonsetName()
+	daemonSet.Name = dsInfo.daemonSetName()
 
 	if len(daemonSet.Spec.Template.Spec.Containers) > 0 {
 		appendHostIdArgument(daemonSet, inframonHostIdSource)
@@ -117,7 +122,7 @@ func (dsInfo *ClassicFullStack) BuildDaemonSet() (*appsv1.DaemonSet, error) {
 		return nil, err
 	}
 
-	result.Name = dsInfo.dynakube.OneAgentDaemonsetName()
+	result.Name = dsInfo.daemonSetName()
 
 	if len(result.Spec.Template.Spec.Containers) > 0 {
 		appendHostIdArgument(result, classicHostIdSource)
@@ -126,6 +131,18 @@ func (dsInfo *ClassicFullStack) BuildDaemonSet() (*appsv1.DaemonSet, error) {
 	return result, nil
 }
 
+func (dsInfo *HostMonitoring) BuildDaemonSets() ([]*appsv1.DaemonSet, error) {
+	return buildDaemonSets(dsInfo.builderInfo, func(info builderInfo) Builder {
+		return &HostMonitoring{info}
+	})
+}
+
+func (dsInfo *ClassicFullStack) BuildDaemonSets() ([]*appsv1.DaemonSet, error) {
+	return buildDaemonSets(dsInfo.builderInfo, func(info builderInfo) Builder {
+		return &ClassicFullStack{info}
+	})
+}
+
 func appendHostIdArgument(result *appsv1.DaemonSet, source string) {
 	result.Spec.Template.Spec.Containers[0].Args = append(result.Spec.Template.Spec.Containers[0].Args, source)
 }
@@ -141,14 +158,14 @@ func (dsInfo *builderInfo) BuildDaemonSet() (*appsv1.DaemonSet, error) {
 	labels := kubeobjects.MergeMap(
 		appLabels.BuildLabels(),
 		dsInfo.hostInjectSpec.Labels,
+		dsInfo.nodeProfileLabels(),
 	)
-	maxUnavailable := intstr.FromInt(dynakube.FeatureOneAgentMaxUnavailable())
 	annotations := map[string]string{
 		annotationUnprivileged:            annotationUnprivilegedValue,
 		webhook.AnnotationDynatraceInject: "false",
 	}
 
-	annotations = kubeobjects.MergeMap(annotations, dsInfo.hostInjectSpec.Annotations)
+	annotations = kubeobjects.MergeMap(annotations, token.RotationAnnotations(dynakube), dsInfo.hostInjectSpec.Annotations)
 
 	result := &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -158,9 +175,7 @@ func (dsInfo *builderInfo) BuildDaemonSet() (*appsv1.DaemonSet, error) {
 			Annotations: map[string]string{},
 		},
 		Spec: appsv1.DaemonSetSpec{
-			Selector: &metav1.LabelSelector{
-				MatchLabels: appLabels.BuildMatchLabels(),
-			},
+			Selector: dsInfo.selector(appLabels.BuildMatchLabels()),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      labels,
@@ -168,17 +183,29 @@ func (dsInfo *builderInfo) BuildDaemonSet() (*appsv1.DaemonSet, error) {
 				},
 				Spec: podSpec,
 			},
-			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
-				RollingUpdate: &appsv1.RollingUpdateDaemonSet{
-					MaxUnavailable: &maxUnavailable,
-				},
-			},
+			UpdateStrategy: dsInfo.updateStrategy(),
 		},
 	}
 
 	return result, nil
 }
 
+// updateStrategy leaves the update of the pods to the operator while the canary OneAgents of a staged rollout are updated
+func (dsInfo *builderInfo) updateStrategy() appsv1.DaemonSetUpdateStrategy {
+	if dsInfo.dynakube.NeedsStagedOneAgentRollout() && dsInfo.dynakube.Status.OneAgent.Rollout.InProgress() {
+		return appsv1.DaemonSetUpdateStrategy{
+			Type: appsv1.OnDeleteDaemonSetStrategyType,
+		}
+	}
+
+	maxUnavailable := intstr.FromInt(dsInfo.dynakube.FeatureOneAgentMaxUnavailable())
+	return appsv1.DaemonSetUpdateStrategy{
+		RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+			MaxUnavailable: &maxUnavailable,
+		},
+	}
+}
+
 func (dsInfo *builderInfo) podSpec() corev1.PodSpec {
 	resources := dsInfo.resources()
 	dnsPolicy := dsInfo.dnsPolicy()
@@ -229,14 +256,6 @@ func (dsInfo *builderInfo) immutableOneAgentImage() string {
 	return dsInfo.dynakube.OneAgentImage()
 }
 
-func (dsInfo *builderInfo) tolerations() []corev1.Toleration {
-	if dsInfo.hostInjectSpec != nil {
-		return dsInfo.hostInjectSpec.Tolerations
-	}
-
-	return nil
-}
-
 func (dsInfo *builderInfo) priorityClassName() string {
 	if dsInfo.hostInjectSpec != nil {
 		return dsInfo.hostInjectSpec.PriorityClassName
@@ -249,6 +268,9 @@ func (dsInfo *builderInfo) nodeSelector() map[string]string {
 	if dsInfo.hostInjectSpec == nil {
 		return make(map[string]string, 0)
 	}
+	if dsInfo.profile != nil {
+		return kubeobjects.MergeMap(dsInfo.hostInjectSpec.NodeSelector, dsInfo.profile.NodeSelector)
+	}
 
 	return dsInfo.hostInjectSpec.NodeSelector
 }
@@ -266,6 +288,9 @@ func (dsInfo *builderInfo) resources() corev1.ResourceRequirements {
 }
 
 func (dsInfo *builderInfo) oneAgentResource() corev1.ResourceRequirements {
+	if dsInfo.profile != nil && dsInfo.profile.OneAgentResources != nil {
+		return *dsInfo.profile.OneAgentResources.DeepCopy()
+	}
 	if dsInfo.hostInjectSpec == nil {
 		return corev1.ResourceRequirements{}
 	}
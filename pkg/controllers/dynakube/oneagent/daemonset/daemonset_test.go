@@ -8,6 +8,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/deploymentmetadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/address"
 	"github.com/Dynatrace/dynatrace-operator/pkg/version"
@@ -15,6 +16,7 @@ import (
 	containerv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -53,6 +55,78 @@ func TestUseImmutableImage(t *testing.T) {
 	})
 }
 
+func TestTokenRotationAnnotation(t *testing.T) {
+	t.Run(`no annotation without token rotation`, func(t *testing.T) {
+		instance := dynatracev1beta1.DynaKube{
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testURL,
+				OneAgent: dynatracev1beta1.OneAgentSpec{
+					ClassicFullStack: &dynatracev1beta1.HostInjectSpec{},
+				},
+			},
+		}
+		ds, err := NewClassicFullStack(&instance, testClusterID).BuildDaemonSet()
+		require.NoError(t, err)
+
+		assert.NotContains(t, ds.Spec.Template.Annotations, token.AnnotationTokenRotation)
+	})
+	t.Run(`annotation changes with token rotation`, func(t *testing.T) {
+		instance := dynatracev1beta1.DynaKube{
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testURL,
+				OneAgent: dynatracev1beta1.OneAgentSpec{
+					ClassicFullStack: &dynatracev1beta1.HostInjectSpec{},
+				},
+			},
+		}
+		instance.Status.DynatraceApi.LastTokenRotation = metav1.NewTime(time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC))
+
+		ds, err := NewClassicFullStack(&instance, testClusterID).BuildDaemonSet()
+		require.NoError(t, err)
+
+		assert.Equal(t, "2023-10-01T12:00:00Z", ds.Spec.Template.Annotations[token.AnnotationTokenRotation])
+	})
+}
+
+func TestUpdateStrategy(t *testing.T) {
+	newInstance := func(rollout *dynatracev1beta1.OneAgentRolloutStatus) *dynatracev1beta1.DynaKube {
+		instance := &dynatracev1beta1.DynaKube{
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testURL,
+				OneAgent: dynatracev1beta1.OneAgentSpec{
+					ClassicFullStack: &dynatracev1beta1.HostInjectSpec{},
+					Rollout: &dynatracev1beta1.OneAgentRolloutSpec{
+						Canary: dynatracev1beta1.OneAgentCanarySpec{Percentage: 10},
+					},
+				},
+			},
+		}
+		instance.Status.OneAgent.Rollout = rollout
+		return instance
+	}
+
+	t.Run(`rolling update without staged rollout`, func(t *testing.T) {
+		ds, err := NewClassicFullStack(newInstance(nil), testClusterID).BuildDaemonSet()
+		require.NoError(t, err)
+
+		require.NotNil(t, ds.Spec.UpdateStrategy.RollingUpdate)
+		assert.Equal(t, 1, ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable.IntValue())
+	})
+	t.Run(`on delete while canaries are updated`, func(t *testing.T) {
+		ds, err := NewClassicFullStack(newInstance(&dynatracev1beta1.OneAgentRolloutStatus{Phase: dynatracev1beta1.OneAgentRolloutBaking}), testClusterID).BuildDaemonSet()
+		require.NoError(t, err)
+
+		assert.Equal(t, appsv1.OnDeleteDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+		assert.Nil(t, ds.Spec.UpdateStrategy.RollingUpdate)
+	})
+	t.Run(`rolling update after completed rollout`, func(t *testing.T) {
+		ds, err := NewClassicFullStack(newInstance(&dynatracev1beta1.OneAgentRolloutStatus{Phase: dynatracev1beta1.OneAgentRolloutCompleted}), testClusterID).BuildDaemonSet()
+		require.NoError(t, err)
+
+		assert.NotNil(t, ds.Spec.UpdateStrategy.RollingUpdate)
+	})
+}
+
 func TestLabels(t *testing.T) {
 	feature := strings.ReplaceAll(deploymentmetadata.ClassicFullStackDeploymentType, "_", "")
 	t.Run("use version when set", func(t *testing.T) {
@@ -672,6 +746,30 @@ func TestTolerations(t *testing.T) {
 			Value: testValue,
 		})
 	})
+	t.Run("adds tolerations for well-known taints once", func(t *testing.T) {
+		hostInjectSpec := &dynatracev1beta1.HostInjectSpec{
+			Tolerations: []corev1.Toleration{
+				{
+					Key:      "CriticalAddonsOnly",
+					Operator: corev1.TolerationOpExists,
+				},
+			},
+			AutoTolerations: []dynatracev1beta1.WellKnownTaint{
+				dynatracev1beta1.WellKnownTaintControlPlane,
+				dynatracev1beta1.WellKnownTaintCriticalAddonsOnly,
+			},
+		}
+		dsInfo := builderInfo{hostInjectSpec: hostInjectSpec}
+		tolerations := dsInfo.tolerations()
+
+		assert.Len(t, tolerations, 3)
+		assert.Contains(t, tolerations, corev1.Toleration{
+			Key:      "node-role.kubernetes.io/control-plane",
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+		assert.Len(t, hostInjectSpec.Tolerations, 1)
+	})
 }
 
 func TestImagePullSecrets(t *testing.T) {
@@ -22,6 +22,7 @@ const (
 )
 
 const customEnvPriority = prioritymap.HighPriority
+const nodeProfileEnvPriority = customEnvPriority + 1
 const defaultEnvPriority = prioritymap.DefaultPriority
 
 func (dsInfo *builderInfo) environmentVariables() []corev1.EnvVar {
@@ -30,6 +31,9 @@ func (dsInfo *builderInfo) environmentVariables() []corev1.EnvVar {
 	if dsInfo.hostInjectSpec != nil {
 		prioritymap.Append(envMap, dsInfo.hostInjectSpec.Env, prioritymap.WithPriority(customEnvPriority))
 	}
+	if dsInfo.profile != nil {
+		prioritymap.Append(envMap, dsInfo.profile.Env, prioritymap.WithPriority(nodeProfileEnvPriority))
+	}
 
 	addNodeNameEnv(envMap)
 	dsInfo.addClusterIDEnv(envMap)
@@ -0,0 +1,122 @@
+package daemonset
+
+import (
+	"sort"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeProfileLabel tells the node profile of the pods of a node profile DaemonSet
+const NodeProfileLabel = "dynatrace.com/oneagent-node-profile"
+
+// buildDaemonSets builds the DaemonSet for the nodes without a node profile followed by one DaemonSet per node profile
+func buildDaemonSets(dsInfo builderInfo, newBuilder func(builderInfo) Builder) ([]*appsv1.DaemonSet, error) {
+	profiles := dsInfo.dynakube.Spec.OneAgent.NodeProfiles
+	daemonSets := make([]*appsv1.DaemonSet, 0, len(profiles)+1)
+
+	defaultInfo := dsInfo
+	defaultInfo.profile = nil
+	daemonSet, err := newBuilder(defaultInfo).BuildDaemonSet()
+	if err != nil {
+		return nil, err
+	}
+	daemonSets = append(daemonSets, daemonSet)
+
+	for i := range profiles {
+		profileInfo := dsInfo
+		profileInfo.profile = &profiles[i]
+		daemonSet, err := newBuilder(profileInfo).BuildDaemonSet()
+		if err != nil {
+			return nil, err
+		}
+		daemonSets = append(daemonSets, daemonSet)
+	}
+	return daemonSets, nil
+}
+
+func (dsInfo *builderInfo) daemonSetName() string {
+	if dsInfo.profile != nil {
+		return dsInfo.dynakube.OneAgentNodeProfileDaemonsetName(dsInfo.profile.Name)
+	}
+	return dsInfo.dynakube.OneAgentDaemonsetName()
+}
+
+// excludedProfiles are the node profiles whose nodes the DaemonSet must stay off,
+// a node matching several profiles belongs to the first of them, nodes matching none to the DaemonSet without a profile
+func (dsInfo *builderInfo) excludedProfiles() []dynatracev1beta1.OneAgentNodeProfile {
+	if dsInfo.dynakube == nil {
+		return nil
+	}
+
+	profiles := dsInfo.dynakube.Spec.OneAgent.NodeProfiles
+	if dsInfo.profile == nil {
+		return profiles
+	}
+	for i := range profiles {
+		if profiles[i].Name == dsInfo.profile.Name {
+			return profiles[:i]
+		}
+	}
+	return nil
+}
+
+// excludeNodeProfiles extends the node selector terms, so they don't match the nodes of the given node profiles.
+// A node is outside a profile if one of the labels of its node selector doesn't match, which makes one term per label and term.
+func excludeNodeProfiles(terms []corev1.NodeSelectorTerm, profiles []dynatracev1beta1.OneAgentNodeProfile) []corev1.NodeSelectorTerm {
+	for _, profile := range profiles {
+		keys := make([]string, 0, len(profile.NodeSelector))
+		for key := range profile.NodeSelector {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		excludingTerms := make([]corev1.NodeSelectorTerm, 0, len(terms)*len(keys))
+		for _, term := range terms {
+			for _, key := range keys {
+				matchExpressions := make([]corev1.NodeSelectorRequirement, 0, len(term.MatchExpressions)+1)
+				matchExpressions = append(matchExpressions, term.MatchExpressions...)
+				matchExpressions = append(matchExpressions, corev1.NodeSelectorRequirement{
+					Key:      key,
+					Operator: corev1.NodeSelectorOpNotIn,
+					Values:   []string{profile.NodeSelector[key]},
+				})
+				excludingTerms = append(excludingTerms, corev1.NodeSelectorTerm{
+					MatchExpressions: matchExpressions,
+					MatchFields:      term.MatchFields,
+				})
+			}
+		}
+		terms = excludingTerms
+	}
+	return terms
+}
+
+// selector keeps the DaemonSets from adopting the pods of each other. As long as node profiles are configured, the DaemonSet
+// without a node profile only selects pods without the NodeProfileLabel. A selector can't be updated, so configuring the
+// first or removing the last node profile recreates that DaemonSet, see kubeobjects.CreateOrUpdateDaemonSet.
+// Without node profiles the selector stays the same as before node profiles existed.
+func (dsInfo *builderInfo) selector(matchLabels map[string]string) *metav1.LabelSelector {
+	if dsInfo.profile != nil {
+		return &metav1.LabelSelector{MatchLabels: kubeobjects.MergeMap(matchLabels, dsInfo.nodeProfileLabels())}
+	}
+	if len(dsInfo.excludedProfiles()) == 0 {
+		return &metav1.LabelSelector{MatchLabels: matchLabels}
+	}
+	return &metav1.LabelSelector{
+		MatchLabels: matchLabels,
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: NodeProfileLabel, Operator: metav1.LabelSelectorOpDoesNotExist},
+		},
+	}
+}
+
+func (dsInfo *builderInfo) nodeProfileLabels() map[string]string {
+	if dsInfo.profile == nil {
+		return nil
+	}
+	return map[string]string{NodeProfileLabel: dsInfo.profile.Name}
+}
@@ -0,0 +1,143 @@
+package daemonset
+
+import (
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestBuildDaemonSets(t *testing.T) {
+	gpuResources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+	}
+	dynakube := &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: "dynatrace"},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: testURL,
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ClassicFullStack: &dynatracev1beta1.HostInjectSpec{
+					NodeSelector: map[string]string{"monitored": "true"},
+					Args:         []string{"--set-host-group=default"},
+					Env:          []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}},
+					Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+				},
+				NodeProfiles: []dynatracev1beta1.OneAgentNodeProfile{
+					{
+						Name:              "gpu",
+						NodeSelector:      map[string]string{"pool": "gpu"},
+						OneAgentResources: &gpuResources,
+						Args:              []string{"--set-host-group=gpu", "--set-host-tag=gpu"},
+						Env:               []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+						Tolerations:       []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+					},
+					{
+						Name:         "spot",
+						NodeSelector: map[string]string{"lifecycle": "spot", "zone": "a"},
+					},
+				},
+			},
+		},
+	}
+
+	daemonSets, err := NewClassicFullStack(dynakube, testClusterID).BuildDaemonSets()
+	require.NoError(t, err)
+	require.Len(t, daemonSets, 3)
+
+	t.Run("one daemonset per node profile", func(t *testing.T) {
+		assert.Equal(t, "dynakube-oneagent", daemonSets[0].Name)
+		assert.Equal(t, "dynakube-oneagent-gpu", daemonSets[1].Name)
+		assert.Equal(t, "dynakube-oneagent-spot", daemonSets[2].Name)
+
+		assert.NotContains(t, daemonSets[0].Spec.Selector.MatchLabels, NodeProfileLabel)
+		assert.Equal(t, "gpu", daemonSets[1].Spec.Selector.MatchLabels[NodeProfileLabel])
+		assert.Equal(t, "gpu", daemonSets[1].Spec.Template.Labels[NodeProfileLabel])
+	})
+	t.Run("daemonsets don't select the pods of each other", func(t *testing.T) {
+		for _, daemonSet := range daemonSets {
+			selector, err := metav1.LabelSelectorAsSelector(daemonSet.Spec.Selector)
+			require.NoError(t, err)
+
+			for _, other := range daemonSets {
+				matches := selector.Matches(labels.Set(other.Spec.Template.Labels))
+				assert.Equal(t, daemonSet.Name == other.Name, matches, "%s selects pods of %s", daemonSet.Name, other.Name)
+			}
+		}
+	})
+	t.Run("selector without node profiles is unchanged", func(t *testing.T) {
+		withoutProfiles := dynakube.DeepCopy()
+		withoutProfiles.Spec.OneAgent.NodeProfiles = nil
+
+		daemonSets, err := NewClassicFullStack(withoutProfiles, testClusterID).BuildDaemonSets()
+		require.NoError(t, err)
+		require.Len(t, daemonSets, 1)
+
+		assert.Empty(t, daemonSets[0].Spec.Selector.MatchExpressions)
+		assert.NotContains(t, daemonSets[0].Spec.Selector.MatchLabels, NodeProfileLabel)
+	})
+	t.Run("node profile overrides the OneAgent mode", func(t *testing.T) {
+		podSpec := daemonSets[1].Spec.Template.Spec
+		container := podSpec.Containers[0]
+
+		assert.Equal(t, map[string]string{"monitored": "true", "pool": "gpu"}, podSpec.NodeSelector)
+		assert.Equal(t, gpuResources.Limits, container.Resources.Limits)
+		assert.Contains(t, container.Args, "--set-host-group=gpu")
+		assert.Contains(t, container.Args, "--set-host-tag=gpu")
+		assert.NotContains(t, container.Args, "--set-host-group=default")
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "LOG_LEVEL", Value: "debug"})
+		assert.Len(t, podSpec.Tolerations, 2)
+
+		assert.Contains(t, daemonSets[0].Spec.Template.Spec.Containers[0].Args, "--set-host-group=default")
+		assert.Contains(t, daemonSets[0].Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "LOG_LEVEL", Value: "info"})
+		assert.Nil(t, gpuResources.Requests)
+	})
+	t.Run("every node is covered by a single daemonset", func(t *testing.T) {
+		assert.Equal(t, []string{"dynakube-oneagent"}, matchingDaemonSets(daemonSets, newProfileNode(nil)))
+		assert.Equal(t, []string{"dynakube-oneagent-gpu"}, matchingDaemonSets(daemonSets, newProfileNode(map[string]string{"pool": "gpu"})))
+		assert.Equal(t, []string{"dynakube-oneagent-spot"}, matchingDaemonSets(daemonSets, newProfileNode(map[string]string{"lifecycle": "spot", "zone": "a"})))
+		assert.Equal(t, []string{"dynakube-oneagent"}, matchingDaemonSets(daemonSets, newProfileNode(map[string]string{"lifecycle": "spot", "zone": "b"})))
+		assert.Equal(t, []string{"dynakube-oneagent-gpu"}, matchingDaemonSets(daemonSets, newProfileNode(map[string]string{"pool": "gpu", "lifecycle": "spot", "zone": "a"})))
+	})
+	t.Run("without node profiles", func(t *testing.T) {
+		dynakube := dynakube.DeepCopy()
+		dynakube.Spec.OneAgent.NodeProfiles = nil
+
+		daemonSets, err := NewClassicFullStack(dynakube, testClusterID).BuildDaemonSets()
+		require.NoError(t, err)
+		require.Len(t, daemonSets, 1)
+
+		daemonSet, err := NewClassicFullStack(dynakube, testClusterID).BuildDaemonSet()
+		require.NoError(t, err)
+		assert.Equal(t, daemonSet, daemonSets[0])
+	})
+}
+
+func newProfileNode(nodeLabels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node",
+			Labels: kubeobjects.MergeMap(map[string]string{
+				"kubernetes.io/arch": "amd64",
+				"kubernetes.io/os":   "linux",
+				"monitored":          "true",
+			}, nodeLabels),
+		},
+	}
+}
+
+func matchingDaemonSets(daemonSets []*appsv1.DaemonSet, node *corev1.Node) []string {
+	var names []string
+	for _, daemonSet := range daemonSets {
+		if kubeobjects.NodeMatchesPodSpec(node, &daemonSet.Spec.Template.Spec) {
+			names = append(names, daemonSet.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,59 @@
+package daemonset
+
+import (
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var wellKnownTolerations = map[dynatracev1beta1.WellKnownTaint][]corev1.Toleration{
+	dynatracev1beta1.WellKnownTaintControlPlane: {
+		{Key: "node-role.kubernetes.io/control-plane", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		{Key: "node-role.kubernetes.io/master", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	},
+	dynatracev1beta1.WellKnownTaintGPU: {
+		{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists},
+		{Key: "amd.com/gpu", Operator: corev1.TolerationOpExists},
+	},
+	dynatracev1beta1.WellKnownTaintSpot: {
+		{Key: "kubernetes.azure.com/scalesetpriority", Operator: corev1.TolerationOpEqual, Value: "spot", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "cloud.google.com/gke-spot", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "cloud.google.com/gke-preemptible", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+	},
+	dynatracev1beta1.WellKnownTaintCriticalAddonsOnly: {
+		{Key: "CriticalAddonsOnly", Operator: corev1.TolerationOpExists},
+	},
+}
+
+func (dsInfo *builderInfo) tolerations() []corev1.Toleration {
+	if dsInfo.hostInjectSpec == nil {
+		return nil
+	}
+	if len(dsInfo.hostInjectSpec.AutoTolerations) == 0 && (dsInfo.profile == nil || len(dsInfo.profile.Tolerations) == 0) {
+		return dsInfo.hostInjectSpec.Tolerations
+	}
+
+	tolerations := slices.Clone(dsInfo.hostInjectSpec.Tolerations)
+	for _, taint := range dsInfo.hostInjectSpec.AutoTolerations {
+		tolerations = appendMissingTolerations(tolerations, wellKnownTolerations[taint])
+	}
+	if dsInfo.profile != nil {
+		tolerations = appendMissingTolerations(tolerations, dsInfo.profile.Tolerations)
+	}
+	return tolerations
+}
+
+func appendMissingTolerations(tolerations []corev1.Toleration, additional []corev1.Toleration) []corev1.Toleration {
+	for _, toleration := range additional {
+		if !containsToleration(tolerations, toleration) {
+			tolerations = append(tolerations, toleration)
+		}
+	}
+	return tolerations
+}
+
+func containsToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	return slices.ContainsFunc(tolerations, func(other corev1.Toleration) bool {
+		return other.MatchToleration(&toleration)
+	})
+}
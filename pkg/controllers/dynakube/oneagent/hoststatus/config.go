@@ -0,0 +1,22 @@
+package hoststatus
+
+import (
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/logger"
+)
+
+const (
+	// UpdateInterval is the time between two host status updates, if the last update succeeded.
+	UpdateInterval = 5 * time.Minute
+
+	// BaseBackoff is the delay after the first failed host status update, it doubles with every further failure.
+	BaseBackoff = 30 * time.Second
+
+	// MaxBackoff caps the delay between failed host status updates.
+	MaxBackoff = time.Hour
+)
+
+var (
+	log = logger.Factory.GetLogger("oneagent-host-status")
+)
@@ -0,0 +1,134 @@
+package hoststatus
+
+import (
+	"fmt"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reconciler resolves the Dynatrace host entity of every node with a OneAgent instance and stores it in the DynaKube status,
+// failing lookups are retried with an exponential backoff to spare the Dynatrace API
+type Reconciler struct {
+	dynakube     *dynatracev1beta1.DynaKube
+	dtClient     dtclient.Client
+	timeProvider *timeprovider.Provider
+}
+
+func NewReconciler(dynakube *dynatracev1beta1.DynaKube, dtClient dtclient.Client, timeProvider *timeprovider.Provider) *Reconciler {
+	return &Reconciler{
+		dynakube:     dynakube,
+		dtClient:     dtClient,
+		timeProvider: timeProvider,
+	}
+}
+
+// Reconcile updates the host status if it is due and returns the time until the next update, zero means no update is planned.
+func (reconciler *Reconciler) Reconcile() (time.Duration, error) {
+	status := &reconciler.dynakube.Status.OneAgent
+	if !reconciler.dynakube.NeedsOneAgent() || reconciler.dynakube.FeatureDisableHostsRequests() {
+		resetHostStatus(status)
+		return 0, nil
+	}
+
+	interval := updateIntervalAfter(status.HostStatusFailures)
+	if !reconciler.isDue(interval) {
+		return interval - reconciler.timeProvider.Now().Sub(status.LastHostStatusUpdate.Time), nil
+	}
+
+	hosts, err := reconciler.lookupHosts()
+	status.LastHostStatusUpdate = reconciler.timeProvider.Now()
+	if err != nil {
+		status.HostStatusFailures++
+		return updateIntervalAfter(status.HostStatusFailures), err
+	}
+
+	status.Hosts = hosts
+	status.MonitoredHosts = monitoredHosts(hosts)
+	status.HostStatusFailures = 0
+	return UpdateInterval, nil
+}
+
+// isDue is true if the update interval passed or, unless the last update failed, the nodes with a OneAgent instance changed
+func (reconciler *Reconciler) isDue(interval time.Duration) bool {
+	status := reconciler.dynakube.Status.OneAgent
+	if reconciler.timeProvider.IsOutdated(status.LastHostStatusUpdate, interval) {
+		return true
+	}
+	return status.HostStatusFailures == 0 && !sameNodes(status.Instances, status.Hosts)
+}
+
+func (reconciler *Reconciler) lookupHosts() (map[string]dynatracev1beta1.OneAgentHostStatus, error) {
+	hosts := make(map[string]dynatracev1beta1.OneAgentHostStatus, len(reconciler.dynakube.Status.OneAgent.Instances))
+	for nodeName, instance := range reconciler.dynakube.Status.OneAgent.Instances {
+		if instance.IPAddress == "" {
+			hosts[nodeName] = dynatracev1beta1.OneAgentHostStatus{}
+			continue
+		}
+
+		hostInfo, err := reconciler.dtClient.GetHostInfoForIP(instance.IPAddress)
+		if errors.As(err, &dtclient.HostNotFoundErr{}) {
+			log.Info("no host found for node", "node", nodeName, "ip", instance.IPAddress)
+			hosts[nodeName] = dynatracev1beta1.OneAgentHostStatus{}
+			continue
+		} else if err != nil {
+			return nil, errors.WithMessagef(err, "failed to look up the host of node %s", nodeName)
+		}
+
+		lastSeen := metav1.NewTime(hostInfo.LastSeen)
+		hosts[nodeName] = dynatracev1beta1.OneAgentHostStatus{
+			EntityID:     hostInfo.EntityID,
+			AgentVersion: hostInfo.AgentVersion,
+			LastSeen:     &lastSeen,
+		}
+	}
+	return hosts, nil
+}
+
+func updateIntervalAfter(failures int) time.Duration {
+	if failures <= 0 {
+		return UpdateInterval
+	}
+
+	backoff := BaseBackoff
+	for i := 1; i < failures && backoff < MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > MaxBackoff {
+		return MaxBackoff
+	}
+	return backoff
+}
+
+func sameNodes(instances map[string]dynatracev1beta1.OneAgentInstance, hosts map[string]dynatracev1beta1.OneAgentHostStatus) bool {
+	if len(instances) != len(hosts) {
+		return false
+	}
+	for nodeName := range instances {
+		if _, ok := hosts[nodeName]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func monitoredHosts(hosts map[string]dynatracev1beta1.OneAgentHostStatus) string {
+	resolved := 0
+	for _, host := range hosts {
+		if host.EntityID != "" {
+			resolved++
+		}
+	}
+	return fmt.Sprintf("%d/%d", resolved, len(hosts))
+}
+
+func resetHostStatus(status *dynatracev1beta1.OneAgentStatus) {
+	status.Hosts = nil
+	status.MonitoredHosts = ""
+	status.LastHostStatusUpdate = nil
+	status.HostStatusFailures = 0
+}
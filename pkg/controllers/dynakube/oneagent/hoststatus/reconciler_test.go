@@ -0,0 +1,153 @@
+package hoststatus
+
+import (
+	"testing"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testNode1 = "node1"
+	testNode2 = "node2"
+	testIP1   = "1.2.3.4"
+	testIP2   = "5.6.7.8"
+)
+
+func TestReconcile(t *testing.T) {
+	lastSeen := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("resolve hosts of all nodes", func(t *testing.T) {
+		dynakube := newTestDynakube()
+		dtClient := &dtclient.MockDynatraceClient{}
+		dtClient.On("GetHostInfoForIP", testIP1).Return(dtclient.HostInfo{EntityID: "HOST-1", AgentVersion: "1.275.0.20231001-120000", LastSeen: lastSeen}, nil)
+		dtClient.On("GetHostInfoForIP", testIP2).Return(dtclient.HostInfo{}, dtclient.HostNotFoundErr{IP: testIP2})
+		timeProvider := timeprovider.New().Freeze()
+
+		nextUpdate, err := NewReconciler(dynakube, dtClient, timeProvider).Reconcile()
+
+		require.NoError(t, err)
+		assert.Equal(t, UpdateInterval, nextUpdate)
+		status := dynakube.Status.OneAgent
+		require.Len(t, status.Hosts, 2)
+		assert.Equal(t, "HOST-1", status.Hosts[testNode1].EntityID)
+		assert.Equal(t, "1.275.0.20231001-120000", status.Hosts[testNode1].AgentVersion)
+		assert.True(t, lastSeen.Equal(status.Hosts[testNode1].LastSeen.Time))
+		assert.Equal(t, dynatracev1beta1.OneAgentHostStatus{}, status.Hosts[testNode2])
+		assert.Equal(t, "1/2", status.MonitoredHosts)
+		assert.Equal(t, timeProvider.Now(), status.LastHostStatusUpdate)
+		assert.Zero(t, status.HostStatusFailures)
+	})
+	t.Run("skip update until it is due", func(t *testing.T) {
+		dynakube := newTestDynakube()
+		timeProvider := timeprovider.New().Freeze()
+		dynakube.Status.OneAgent.Hosts = map[string]dynatracev1beta1.OneAgentHostStatus{testNode1: {}, testNode2: {}}
+		dynakube.Status.OneAgent.LastHostStatusUpdate = &metav1.Time{Time: timeProvider.Now().Add(-time.Minute)}
+		dtClient := &dtclient.MockDynatraceClient{}
+
+		nextUpdate, err := NewReconciler(dynakube, dtClient, timeProvider).Reconcile()
+
+		require.NoError(t, err)
+		assert.Equal(t, UpdateInterval-time.Minute, nextUpdate)
+		dtClient.AssertNotCalled(t, "GetHostInfoForIP")
+	})
+	t.Run("update early if nodes changed", func(t *testing.T) {
+		dynakube := newTestDynakube()
+		timeProvider := timeprovider.New().Freeze()
+		dynakube.Status.OneAgent.Hosts = map[string]dynatracev1beta1.OneAgentHostStatus{testNode1: {}}
+		dynakube.Status.OneAgent.LastHostStatusUpdate = &metav1.Time{Time: timeProvider.Now().Add(-time.Minute)}
+		dtClient := &dtclient.MockDynatraceClient{}
+		dtClient.On("GetHostInfoForIP", testIP1).Return(dtclient.HostInfo{EntityID: "HOST-1"}, nil)
+		dtClient.On("GetHostInfoForIP", testIP2).Return(dtclient.HostInfo{EntityID: "HOST-2"}, nil)
+
+		_, err := NewReconciler(dynakube, dtClient, timeProvider).Reconcile()
+
+		require.NoError(t, err)
+		assert.Equal(t, "2/2", dynakube.Status.OneAgent.MonitoredHosts)
+	})
+	t.Run("back off on api errors and keep previous hosts", func(t *testing.T) {
+		dynakube := newTestDynakube()
+		previousHosts := map[string]dynatracev1beta1.OneAgentHostStatus{testNode1: {EntityID: "HOST-1"}, testNode2: {}}
+		dynakube.Status.OneAgent.Hosts = previousHosts
+		dynakube.Status.OneAgent.MonitoredHosts = "1/2"
+		dynakube.Status.OneAgent.HostStatusFailures = 2
+		dtClient := &dtclient.MockDynatraceClient{}
+		dtClient.On("GetHostInfoForIP", testIP1).Return(dtclient.HostInfo{}, errors.New("api unavailable"))
+		dtClient.On("GetHostInfoForIP", testIP2).Return(dtclient.HostInfo{}, errors.New("api unavailable"))
+
+		nextUpdate, err := NewReconciler(dynakube, dtClient, timeprovider.New().Freeze()).Reconcile()
+
+		require.Error(t, err)
+		assert.Equal(t, 4*BaseBackoff, nextUpdate)
+		assert.Equal(t, 3, dynakube.Status.OneAgent.HostStatusFailures)
+		assert.Equal(t, previousHosts, dynakube.Status.OneAgent.Hosts)
+		assert.Equal(t, "1/2", dynakube.Status.OneAgent.MonitoredHosts)
+	})
+	t.Run("no update while backing off, even if nodes changed", func(t *testing.T) {
+		dynakube := newTestDynakube()
+		timeProvider := timeprovider.New().Freeze()
+		dynakube.Status.OneAgent.HostStatusFailures = 1
+		dynakube.Status.OneAgent.LastHostStatusUpdate = &metav1.Time{Time: timeProvider.Now().Add(-10 * time.Second)}
+		dtClient := &dtclient.MockDynatraceClient{}
+
+		nextUpdate, err := NewReconciler(dynakube, dtClient, timeProvider).Reconcile()
+
+		require.NoError(t, err)
+		assert.Equal(t, BaseBackoff-10*time.Second, nextUpdate)
+		dtClient.AssertNotCalled(t, "GetHostInfoForIP")
+	})
+	t.Run("reset status if hosts requests are disabled", func(t *testing.T) {
+		dynakube := newTestDynakube()
+		dynakube.Annotations = map[string]string{dynatracev1beta1.AnnotationFeatureHostsRequests: "false"}
+		dynakube.Status.OneAgent.Hosts = map[string]dynatracev1beta1.OneAgentHostStatus{testNode1: {EntityID: "HOST-1"}}
+		dynakube.Status.OneAgent.MonitoredHosts = "1/1"
+		dynakube.Status.OneAgent.HostStatusFailures = 1
+		dtClient := &dtclient.MockDynatraceClient{}
+
+		nextUpdate, err := NewReconciler(dynakube, dtClient, timeprovider.New()).Reconcile()
+
+		require.NoError(t, err)
+		assert.Zero(t, nextUpdate)
+		assert.Nil(t, dynakube.Status.OneAgent.Hosts)
+		assert.Empty(t, dynakube.Status.OneAgent.MonitoredHosts)
+		assert.Zero(t, dynakube.Status.OneAgent.HostStatusFailures)
+		dtClient.AssertNotCalled(t, "GetHostInfoForIP")
+	})
+}
+
+func TestUpdateIntervalAfter(t *testing.T) {
+	assert.Equal(t, UpdateInterval, updateIntervalAfter(0))
+	assert.Equal(t, BaseBackoff, updateIntervalAfter(1))
+	assert.Equal(t, 2*BaseBackoff, updateIntervalAfter(2))
+	assert.Equal(t, 64*BaseBackoff, updateIntervalAfter(7))
+	assert.Equal(t, MaxBackoff, updateIntervalAfter(8))
+	assert.Equal(t, MaxBackoff, updateIntervalAfter(100))
+}
+
+func newTestDynakube() *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dynakube",
+			Namespace: "dynatrace",
+		},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ClassicFullStack: &dynatracev1beta1.HostInjectSpec{},
+			},
+		},
+		Status: dynatracev1beta1.DynaKubeStatus{
+			OneAgent: dynatracev1beta1.OneAgentStatus{
+				Instances: map[string]dynatracev1beta1.OneAgentInstance{
+					testNode1: {PodName: "oneagent-1", IPAddress: testIP1},
+					testNode2: {PodName: "oneagent-2", IPAddress: testIP2},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package oneagent
+
+import (
+	"context"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/oneagent/daemonset"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DeleteNodeProfileDaemonSets removes the OneAgent DaemonSets of the node profiles of the DynaKube, except the ones named in keep
+func DeleteNodeProfileDaemonSets(ctx context.Context, clt client.Client, dynakube *dynatracev1beta1.DynaKube, keep ...string) error {
+	matchLabels := kubeobjects.NewAppLabels(kubeobjects.OneAgentComponentLabel, dynakube.Name, "", "").BuildMatchLabels()
+
+	var daemonSets appsv1.DaemonSetList
+	err := clt.List(ctx, &daemonSets, client.InNamespace(dynakube.Namespace), client.MatchingLabels(matchLabels), client.HasLabels{daemonset.NodeProfileLabel})
+	if err != nil {
+		return errors.WithMessage(err, "failed to list OneAgent daemonsets of node profiles")
+	}
+
+	for i := range daemonSets.Items {
+		if slices.Contains(keep, daemonSets.Items[i].Name) {
+			continue
+		}
+		log.Info("removing OneAgent daemonset of removed node profile", "name", daemonSets.Items[i].Name)
+		if err := kubeobjects.Delete(ctx, clt, &daemonSets.Items[i]); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// reconcileNodeProfileStatuses stores the state of the DaemonSet of every node profile, DaemonSets not created yet count as empty
+func (r *Reconciler) reconcileNodeProfileStatuses(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) error {
+	var profileStatuses []dynatracev1beta1.OneAgentNodeProfileStatus
+	for _, profile := range dynakube.Spec.OneAgent.NodeProfiles {
+		profileStatus := dynatracev1beta1.OneAgentNodeProfileStatus{
+			Name:      profile.Name,
+			DaemonSet: dynakube.OneAgentNodeProfileDaemonsetName(profile.Name),
+		}
+
+		var daemonSet appsv1.DaemonSet
+		err := r.client.Get(ctx, types.NamespacedName{Name: profileStatus.DaemonSet, Namespace: dynakube.Namespace}, &daemonSet)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.WithMessage(err, "failed to get OneAgent daemonset of node profile")
+		} else if err == nil {
+			profileStatus.DesiredNodes = daemonSet.Status.DesiredNumberScheduled
+			profileStatus.ReadyNodes = daemonSet.Status.NumberReady
+		}
+		profileStatuses = append(profileStatuses, profileStatus)
+	}
+
+	dynakube.Status.OneAgent.NodeProfiles = profileStatuses
+	return nil
+}
@@ -0,0 +1,84 @@
+package oneagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/oneagent/daemonset"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const testProfileNamespace = "dynatrace"
+
+func TestDeleteNodeProfileDaemonSets(t *testing.T) {
+	ctx := context.Background()
+	dynakube := &dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: testProfileNamespace}}
+
+	t.Run("removes daemonsets of removed node profiles only", func(t *testing.T) {
+		clt := fake.NewClient(
+			newProfileDaemonSet(dynakube, ""),
+			newProfileDaemonSet(dynakube, "gpu"),
+			newProfileDaemonSet(dynakube, "spot"),
+		)
+
+		require.NoError(t, DeleteNodeProfileDaemonSets(ctx, clt, dynakube, dynakube.OneAgentNodeProfileDaemonsetName("gpu")))
+
+		var daemonSet appsv1.DaemonSet
+		require.NoError(t, clt.Get(ctx, client.ObjectKey{Name: dynakube.OneAgentDaemonsetName(), Namespace: testProfileNamespace}, &daemonSet))
+		require.NoError(t, clt.Get(ctx, client.ObjectKey{Name: dynakube.OneAgentNodeProfileDaemonsetName("gpu"), Namespace: testProfileNamespace}, &daemonSet))
+		err := clt.Get(ctx, client.ObjectKey{Name: dynakube.OneAgentNodeProfileDaemonsetName("spot"), Namespace: testProfileNamespace}, &daemonSet)
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+}
+
+func TestReconcileNodeProfileStatuses(t *testing.T) {
+	ctx := context.Background()
+	dynakube := &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: testProfileNamespace},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				NodeProfiles: []dynatracev1beta1.OneAgentNodeProfile{
+					{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+					{Name: "spot", NodeSelector: map[string]string{"pool": "spot"}},
+				},
+			},
+		},
+	}
+	gpuDaemonSet := newProfileDaemonSet(dynakube, "gpu")
+	gpuDaemonSet.Status = appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 2}
+	clt := fake.NewClient(gpuDaemonSet)
+	reconciler := NewOneAgentReconciler(clt, clt, clt.Scheme(), &record.FakeRecorder{}, testClusterID)
+
+	require.NoError(t, reconciler.reconcileNodeProfileStatuses(ctx, dynakube))
+
+	assert.Equal(t, []dynatracev1beta1.OneAgentNodeProfileStatus{
+		{Name: "gpu", DaemonSet: "dynakube-oneagent-gpu", DesiredNodes: 3, ReadyNodes: 2},
+		{Name: "spot", DaemonSet: "dynakube-oneagent-spot"},
+	}, dynakube.Status.OneAgent.NodeProfiles)
+}
+
+func newProfileDaemonSet(dynakube *dynatracev1beta1.DynaKube, profileName string) *appsv1.DaemonSet {
+	labels := kubeobjects.NewAppLabels(kubeobjects.OneAgentComponentLabel, dynakube.Name, "", "").BuildMatchLabels()
+	name := dynakube.OneAgentDaemonsetName()
+	if profileName != "" {
+		labels[daemonset.NodeProfileLabel] = profileName
+		name = dynakube.OneAgentNodeProfileDaemonsetName(profileName)
+	}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: dynakube.Namespace,
+			Labels:    labels,
+		},
+	}
+}
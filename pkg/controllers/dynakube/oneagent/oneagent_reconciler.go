@@ -20,6 +20,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -35,11 +36,13 @@ func NewOneAgentReconciler( //nolint:revive // maximum number of return results
 	client client.Client,
 	apiReader client.Reader,
 	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
 	clusterID string) *Reconciler {
 	return &Reconciler{
 		client:    client,
 		apiReader: apiReader,
 		scheme:    scheme,
+		recorder:  recorder,
 		clusterID: clusterID,
 	}
 }
@@ -50,6 +53,7 @@ type Reconciler struct {
 	client    client.Client
 	apiReader client.Reader
 	scheme    *runtime.Scheme
+	recorder  record.EventRecorder
 	clusterID string
 }
 
@@ -99,6 +103,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, dynakube *dynatracev1beta1.D
 		}
 		dynakube.Status.OneAgent.LastInstanceStatusUpdate = &now
 		log.Info("oneagent instance statuses reconciled")
+
+		err = r.reconcileNodeProfileStatuses(ctx, dynakube)
+		if err != nil {
+			return err
+		}
+
+		// the coverage is only informational, a failure mustn't block the OneAgent deployment
+		if err := r.reconcileCoverage(ctx, dynakube); err != nil {
+			log.Error(err, "could not determine the nodes not covered by the OneAgent daemonset")
+		}
 	}
 
 	log.Info("reconciled " + deploymentmetadata.GetOneAgentDeploymentType(*dynakube))
@@ -138,23 +152,48 @@ func extractPublicData(dynakube *dynatracev1beta1.DynaKube) map[string]string {
 }
 
 func (r *Reconciler) reconcileRollout(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) error {
-	// Define a new DaemonSet object
-	dsDesired, err := r.buildDesiredDaemonSet(dynakube)
+	var rollout *canaryRollout
+	deployedDynakube := dynakube
+
+	if dynakube.NeedsStagedOneAgentRollout() {
+		rollout = newCanaryRollout(r.client, dynakube, timeprovider.Now())
+		if err := rollout.advance(ctx); err != nil {
+			log.Info("failed to advance staged OneAgent rollout")
+			return err
+		}
+		deployedDynakube = rollout.deployedDynakube()
+	} else {
+		dynakube.Status.OneAgent.Rollout = nil
+	}
+
+	// Define the new DaemonSet objects, one for the nodes without a node profile and one per node profile
+	dsDesired, err := r.buildDesiredDaemonSets(deployedDynakube)
 	if err != nil {
 		log.Info("failed to get desired daemonset")
 		return err
 	}
 
-	// Set OneAgent instance as the owner and controller
-	if err := controllerutil.SetControllerReference(dynakube, dsDesired, r.scheme); err != nil {
-		return err
+	updated := false
+	for _, ds := range dsDesired {
+		// Set OneAgent instance as the owner and controller
+		if err := controllerutil.SetControllerReference(dynakube, ds, r.scheme); err != nil {
+			return err
+		}
+
+		dsUpdated, err := kubeobjects.CreateOrUpdateDaemonSet(r.client, log, ds)
+		if err != nil {
+			log.Info("failed to roll out new OneAgent DaemonSet", "name", ds.Name)
+			return err
+		}
+		updated = updated || dsUpdated
 	}
 
-	updated, err := kubeobjects.CreateOrUpdateDaemonSet(r.client, log, dsDesired)
+	err = DeleteNodeProfileDaemonSets(ctx, r.client, dynakube, dynakube.OneAgentDaemonsetNames()...)
 	if err != nil {
-		log.Info("failed to roll out new OneAgent DaemonSet")
+		log.Info("failed to remove OneAgent DaemonSets of removed node profiles")
 		return err
 	}
+
 	if updated {
 		log.Info("rolled out new OneAgent DaemonSet")
 		// remove old daemonset with feature in name
@@ -172,6 +211,10 @@ func (r *Reconciler) reconcileRollout(ctx context.Context, dynakube *dynatracev1
 			return err
 		}
 	}
+
+	if rollout != nil {
+		return rollout.updateCanaryPods(ctx)
+	}
 	return nil
 }
 
@@ -188,29 +231,31 @@ func (r *Reconciler) getOneagentPods(ctx context.Context, dynakube *dynatracev1b
 	return podList.Items, listOps, err
 }
 
-func (r *Reconciler) buildDesiredDaemonSet(dynakube *dynatracev1beta1.DynaKube) (*appsv1.DaemonSet, error) {
-	var ds *appsv1.DaemonSet
+func (r *Reconciler) buildDesiredDaemonSets(dynakube *dynatracev1beta1.DynaKube) ([]*appsv1.DaemonSet, error) {
+	var daemonSets []*appsv1.DaemonSet
 	var err error
 
 	switch {
 	case dynakube.ClassicFullStackMode():
-		ds, err = daemonset.NewClassicFullStack(dynakube, r.clusterID).BuildDaemonSet()
+		daemonSets, err = daemonset.NewClassicFullStack(dynakube, r.clusterID).BuildDaemonSets()
 	case dynakube.HostMonitoringMode():
-		ds, err = daemonset.NewHostMonitoring(dynakube, r.clusterID).BuildDaemonSet()
+		daemonSets, err = daemonset.NewHostMonitoring(dynakube, r.clusterID).BuildDaemonSets()
 	case dynakube.CloudNativeFullstackMode():
-		ds, err = daemonset.NewCloudNativeFullStack(dynakube, r.clusterID).BuildDaemonSet()
+		daemonSets, err = daemonset.NewCloudNativeFullStack(dynakube, r.clusterID).BuildDaemonSets()
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	dsHash, err := kubeobjects.GenerateHash(ds)
-	if err != nil {
-		return nil, err
+	for _, ds := range daemonSets {
+		dsHash, err := kubeobjects.GenerateHash(ds)
+		if err != nil {
+			return nil, err
+		}
+		ds.Annotations[kubeobjects.AnnotationHash] = dsHash
 	}
-	ds.Annotations[kubeobjects.AnnotationHash] = dsHash
 
-	return ds, nil
+	return daemonSets, nil
 }
 
 func (r *Reconciler) reconcileInstanceStatuses(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) error {
@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -300,8 +301,9 @@ func TestMigrationForDaemonSetWithoutAnnotation(t *testing.T) {
 		},
 	}
 
-	ds2, err := r.buildDesiredDaemonSet(dynakube)
-	assert.NoError(t, err)
+	daemonSets, err := r.buildDesiredDaemonSets(dynakube)
+	require.NoError(t, err)
+	ds2 := daemonSets[0]
 	assert.NotEmpty(t, ds2.Annotations[kubeobjects.AnnotationHash])
 
 	assert.True(t, kubeobjects.IsHashAnnotationDifferent(ds1, ds2))
@@ -458,11 +460,13 @@ func TestHasSpecChanged(t *testing.T) {
 				},
 			}
 			test.mod(&oldInstance, &newInstance)
-			ds1, err := r.buildDesiredDaemonSet(&oldInstance)
-			assert.NoError(t, err)
+			oldDaemonSets, err := r.buildDesiredDaemonSets(&oldInstance)
+			require.NoError(t, err)
+			ds1 := oldDaemonSets[0]
 
-			ds2, err := r.buildDesiredDaemonSet(&newInstance)
-			assert.NoError(t, err)
+			newDaemonSets, err := r.buildDesiredDaemonSets(&newInstance)
+			require.NoError(t, err)
+			ds2 := newDaemonSets[0]
 
 			assert.NotEmpty(t, ds1.Annotations[kubeobjects.AnnotationHash])
 			assert.NotEmpty(t, ds2.Annotations[kubeobjects.AnnotationHash])
@@ -479,10 +483,10 @@ func TestNewDaemonset_Affinity(t *testing.T) {
 		dynakube := newDynaKube()
 		versionProvider.On("Major").Return("1", nil)
 		versionProvider.On("Minor").Return("20+", nil)
-		ds, err := r.buildDesiredDaemonSet(dynakube)
-
-		assert.NoError(t, err)
-		assert.NotNil(t, ds)
+		daemonSets, err := r.buildDesiredDaemonSets(dynakube)
+		require.NoError(t, err)
+		require.Len(t, daemonSets, 1)
+		ds := daemonSets[0]
 
 		affinity := ds.Spec.Template.Spec.Affinity
 
@@ -693,7 +697,7 @@ func TestReconcile_ActivegateConfigMap(t *testing.T) {
 		sampleKubeSystemNS)
 
 	t.Run(`create OneAgent connection info ConfigMap`, func(t *testing.T) {
-		reconciler := NewOneAgentReconciler(fakeClient, fakeClient, scheme.Scheme, "")
+		reconciler := NewOneAgentReconciler(fakeClient, fakeClient, scheme.Scheme, record.NewFakeRecorder(10), "")
 
 		err := reconciler.Reconcile(context.TODO(), dynakube)
 		require.NoError(t, err)
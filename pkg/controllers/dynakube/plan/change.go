@@ -0,0 +1,174 @@
+package plan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Change is a write the reconciliation would make, Diff is a unified diff of the object as YAML.
+// The values of secrets are replaced by their hash, so the plan shows that they change but not to what.
+type Change struct {
+	Action    Action `json:"action"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Diff      string `json:"diff,omitempty"`
+}
+
+func (change Change) String() string {
+	if change.Namespace == "" {
+		return fmt.Sprintf("%s %s '%s'", change.Action, change.Kind, change.Name)
+	}
+	return fmt.Sprintf("%s %s '%s:%s'", change.Action, change.Kind, change.Namespace, change.Name)
+}
+
+// Changes returns the recorded changes in the order they were first made, writes that didn't change anything are left out.
+func (recorder *Recorder) Changes() ([]Change, error) {
+	changes := make([]Change, 0, len(recorder.order))
+
+	for _, key := range recorder.order {
+		recorded := recorder.objects[key]
+
+		change, err := newChange(key.gvk, recorded.before, recorded.after)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	for _, tracker := range recorder.istioTrackers {
+		istioChanges, err := tracker.changes()
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, istioChanges...)
+	}
+
+	return append(changes, recorder.externalChanges...), nil
+}
+
+func newChange(gvk schema.GroupVersionKind, before, after client.Object) (*Change, error) {
+	var action Action
+	var obj client.Object
+
+	switch {
+	case before == nil && after == nil:
+		return nil, nil
+	case before == nil:
+		action, obj = ActionCreate, after
+	case after == nil:
+		action, obj = ActionDelete, before
+	default:
+		action, obj = ActionUpdate, after
+	}
+
+	beforeYaml, err := toYaml(gvk, before)
+	if err != nil {
+		return nil, err
+	}
+	afterYaml, err := toYaml(gvk, after)
+	if err != nil {
+		return nil, err
+	}
+	if beforeYaml == afterYaml {
+		return nil, nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(beforeYaml),
+		B:        splitLines(afterYaml),
+		FromFile: "current",
+		ToFile:   "planned",
+		Context:  3,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &Change{
+		Action:    action,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Diff:      diff,
+	}, nil
+}
+
+// splitLines returns no lines for missing objects and doesn't add an empty one at the end, unlike difflib.SplitLines
+func splitLines(rawYaml string) []string {
+	if rawYaml == "" {
+		return nil
+	}
+	return difflib.SplitLines(strings.TrimSuffix(rawYaml, "\n"))
+}
+
+var serverManagedFields = []string{"managedFields", "resourceVersion", "uid", "generation", "creationTimestamp"}
+
+// toYaml renders the object without the fields managed by the API server, they would only add noise to the diff
+func toYaml(gvk schema.GroupVersionKind, obj client.Object) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+
+	unstructuredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(redact(obj.DeepCopyObject().(client.Object)))
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	unstructuredObj["apiVersion"], unstructuredObj["kind"] = gvk.ToAPIVersionAndKind()
+	for _, field := range serverManagedFields {
+		unstructured.RemoveNestedField(unstructuredObj, "metadata", field)
+	}
+
+	rawYaml, err := yaml.Marshal(unstructuredObj)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return string(rawYaml), nil
+}
+
+// redact replaces the values of a secret by their hash, they are moved to stringData so the hash isn't base64 encoded
+func redact(obj client.Object) client.Object {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return obj
+	}
+
+	redacted := make(map[string]string, len(secret.Data)+len(secret.StringData))
+	for key, value := range secret.Data {
+		redacted[key] = redactedValue(value)
+	}
+	for key, value := range secret.StringData {
+		redacted[key] = redactedValue([]byte(value))
+	}
+
+	secret.Data = nil
+	secret.StringData = redacted
+	return secret
+}
+
+func redactedValue(value []byte) string {
+	hash := sha256.Sum256(value)
+	return "redacted-sha256-" + hex.EncodeToString(hash[:])[:12]
+}
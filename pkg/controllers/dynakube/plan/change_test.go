@@ -0,0 +1,63 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewChange(t *testing.T) {
+	secretGVK := corev1.SchemeGroupVersion.WithKind("Secret")
+
+	t.Run("secret values are redacted", func(t *testing.T) {
+		before := newSecret("old-token")
+		after := newSecret("new-token")
+
+		change, err := newChange(secretGVK, before, after)
+
+		require.NoError(t, err)
+		require.NotNil(t, change)
+		assert.NotContains(t, change.Diff, "old-token")
+		assert.NotContains(t, change.Diff, "new-token")
+		assert.Contains(t, change.Diff, "-  token: "+redactedValue([]byte("old-token")))
+		assert.Contains(t, change.Diff, "+  token: "+redactedValue([]byte("new-token")))
+		assert.Equal(t, []byte("new-token"), after.Data["token"], "recorded object must not be modified")
+	})
+	t.Run("fields managed by the api server are ignored", func(t *testing.T) {
+		before := newSecret("token")
+		before.ResourceVersion = "42"
+		before.UID = "uid"
+		before.CreationTimestamp = metav1.Now()
+
+		change, err := newChange(secretGVK, before, newSecret("token"))
+
+		require.NoError(t, err)
+		assert.Nil(t, change)
+	})
+	t.Run("created objects are diffed against nothing", func(t *testing.T) {
+		change, err := newChange(secretGVK, nil, newSecret("token"))
+
+		require.NoError(t, err)
+		require.NotNil(t, change)
+		assert.Equal(t, ActionCreate, change.Action)
+		assert.Equal(t, "Secret", change.Kind)
+		assert.Contains(t, change.Diff, "+apiVersion: v1\n")
+		assert.Contains(t, change.Diff, "+kind: Secret\n")
+		assert.Equal(t, "create Secret 'dynatrace:dynakube'", change.String())
+	})
+}
+
+func newSecret(token string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+		Data: map[string][]byte{
+			"token": []byte(token),
+		},
+	}
+}
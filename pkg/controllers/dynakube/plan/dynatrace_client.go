@@ -0,0 +1,95 @@
+package plan
+
+import (
+	"context"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+)
+
+const (
+	kindSetting             = "Setting"
+	kindActiveGateAuthToken = "ActiveGateAuthToken"
+)
+
+type dynatraceClientBuilder struct {
+	builder  dynatraceclient.Builder
+	recorder *Recorder
+}
+
+var _ dynatraceclient.Builder = dynatraceClientBuilder{}
+
+// NewDynatraceClientBuilder builds Dynatrace clients that only read from the Dynatrace API,
+// the calls that would create something in the environment are recorded instead.
+func NewDynatraceClientBuilder(builder dynatraceclient.Builder, recorder *Recorder) dynatraceclient.Builder {
+	return dynatraceClientBuilder{
+		builder:  builder,
+		recorder: recorder,
+	}
+}
+
+func (planBuilder dynatraceClientBuilder) SetContext(ctx context.Context) dynatraceclient.Builder {
+	planBuilder.builder = planBuilder.builder.SetContext(ctx)
+	return planBuilder
+}
+
+func (planBuilder dynatraceClientBuilder) SetDynakube(dynakube dynatracev1beta1.DynaKube) dynatraceclient.Builder {
+	planBuilder.builder = planBuilder.builder.SetDynakube(dynakube)
+	return planBuilder
+}
+
+func (planBuilder dynatraceClientBuilder) SetTokens(tokens token.Tokens) dynatraceclient.Builder {
+	planBuilder.builder = planBuilder.builder.SetTokens(tokens)
+	return planBuilder
+}
+
+func (planBuilder dynatraceClientBuilder) Build() (dtclient.Client, error) {
+	dtc, err := planBuilder.builder.Build()
+	if err != nil {
+		return nil, err
+	}
+	return dynatraceClient{Client: dtc, recorder: planBuilder.recorder}, nil
+}
+
+func (planBuilder dynatraceClientBuilder) BuildWithTokenVerification(dynaKubeStatus *dynatracev1beta1.DynaKubeStatus) (dtclient.Client, error) {
+	dtc, err := planBuilder.builder.BuildWithTokenVerification(dynaKubeStatus)
+	if err != nil {
+		return nil, err
+	}
+	return dynatraceClient{Client: dtc, recorder: planBuilder.recorder}, nil
+}
+
+type dynatraceClient struct {
+	dtclient.Client
+	recorder *Recorder
+}
+
+func (dtc dynatraceClient) CreateOrUpdateKubernetesSetting(name, _, _ string) (string, error) {
+	dtc.recorder.recordExternal(Change{
+		Action: ActionCreate,
+		Kind:   kindSetting,
+		Name:   dtclient.SettingsSchemaId + "/" + name,
+	})
+	return "", nil
+}
+
+func (dtc dynatraceClient) CreateOrUpdateKubernetesAppSetting(scope string) (string, error) {
+	dtc.recorder.recordExternal(Change{
+		Action: ActionCreate,
+		Kind:   kindSetting,
+		Name:   dtclient.AppTransitionSchemaId + "/" + scope,
+	})
+	return "", nil
+}
+
+// GetActiveGateAuthToken creates a new token in the environment, so an empty one is returned instead
+func (dtc dynatraceClient) GetActiveGateAuthToken(dynakubeName string) (*dtclient.ActiveGateAuthTokenInfo, error) {
+	dtc.recorder.recordExternal(Change{
+		Action: ActionCreate,
+		Kind:   kindActiveGateAuthToken,
+		Name:   dynakubeName,
+	})
+	return &dtclient.ActiveGateAuthTokenInfo{}, nil
+}
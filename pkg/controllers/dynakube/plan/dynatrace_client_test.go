@@ -0,0 +1,51 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynatraceClientBuilder(t *testing.T) {
+	mockClient := &dtclient.MockDynatraceClient{}
+	mockClient.On("GetActiveGateConnectionInfo").Return(&dtclient.ActiveGateConnectionInfo{}, nil)
+
+	recorder := NewRecorder(fake.NewClient())
+	builder := NewDynatraceClientBuilder(&dynatraceclient.StubBuilder{DynatraceClient: mockClient}, recorder).
+		SetContext(context.Background()).
+		SetDynakube(dynatracev1beta1.DynaKube{})
+
+	dtc, err := builder.BuildWithTokenVerification(&dynatracev1beta1.DynaKubeStatus{})
+	require.NoError(t, err)
+
+	t.Run("reads are sent to the Dynatrace API", func(t *testing.T) {
+		_, err := dtc.GetActiveGateConnectionInfo()
+
+		require.NoError(t, err)
+		mockClient.AssertCalled(t, "GetActiveGateConnectionInfo")
+	})
+	t.Run("writes are recorded", func(t *testing.T) {
+		_, err := dtc.CreateOrUpdateKubernetesSetting("cluster", "uuid", "scope")
+		require.NoError(t, err)
+		_, err = dtc.CreateOrUpdateKubernetesAppSetting("scope")
+		require.NoError(t, err)
+		authToken, err := dtc.GetActiveGateAuthToken(testName)
+		require.NoError(t, err)
+		assert.Empty(t, authToken.Token)
+
+		changes, err := recorder.Changes()
+		require.NoError(t, err)
+		assert.Equal(t, []Change{
+			{Action: ActionCreate, Kind: kindSetting, Name: dtclient.SettingsSchemaId + "/cluster"},
+			{Action: ActionCreate, Kind: kindSetting, Name: dtclient.AppTransitionSchemaId + "/scope"},
+			{Action: ActionCreate, Kind: kindActiveGateAuthToken, Name: testName},
+		}, changes)
+		mockClient.AssertNotCalled(t, "GetActiveGateAuthToken", testName)
+	})
+}
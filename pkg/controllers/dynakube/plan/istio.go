@@ -0,0 +1,149 @@
+package plan
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/istio"
+	"github.com/pkg/errors"
+	istiov1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	istioclientset "istio.io/client-go/pkg/clientset/versioned"
+	fakeistio "istio.io/client-go/pkg/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewIstioClientBuilder builds istio clients that work on an in-memory copy of the ServiceEntries and VirtualServices of the namespace,
+// the difference to the cluster is part of the changes of the recorder.
+func NewIstioClientBuilder(builder istio.ClientBuilder, recorder *Recorder) istio.ClientBuilder {
+	return func(config *rest.Config, scheme *runtime.Scheme, owner metav1.Object) (*istio.Client, error) {
+		istioClient, err := builder(config, scheme, owner)
+		if err != nil {
+			return nil, err
+		}
+
+		tracker, err := newIstioTracker(context.Background(), istioClient.IstioClientset, owner.GetNamespace())
+		if err != nil {
+			return nil, err
+		}
+		recorder.istioTrackers = append(recorder.istioTrackers, tracker)
+
+		return &istio.Client{
+			IstioClientset: tracker.clientset,
+			Scheme:         scheme,
+			Owner:          owner,
+		}, nil
+	}
+}
+
+type istioTracker struct {
+	clientset *fakeistio.Clientset
+	namespace string
+	before    []client.Object
+}
+
+func newIstioTracker(ctx context.Context, live istioclientset.Interface, namespace string) (*istioTracker, error) {
+	tracker := &istioTracker{namespace: namespace}
+
+	isInstalled, err := (&istio.Client{IstioClientset: live}).CheckIstioInstalled()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if !isInstalled {
+		tracker.clientset = fakeistio.NewSimpleClientset()
+		return tracker, nil
+	}
+
+	tracker.before, err = listIstioObjects(ctx, live, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]runtime.Object, 0, len(tracker.before))
+	for _, obj := range tracker.before {
+		objects = append(objects, obj.DeepCopyObject())
+	}
+
+	tracker.clientset = fakeistio.NewSimpleClientset(objects...)
+	tracker.clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{GroupVersion: istio.IstioGVR}}
+
+	return tracker, nil
+}
+
+func (tracker *istioTracker) changes() ([]Change, error) {
+	after, err := listIstioObjects(context.Background(), tracker.clientset, tracker.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]Change, 0)
+	afterByKey := istioObjectsByKey(after)
+
+	for _, before := range tracker.before {
+		key := istioObjectKey(before)
+		change, err := newChange(key.gvk, before, afterByKey[key])
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+		delete(afterByKey, key)
+	}
+
+	for _, created := range after {
+		if _, ok := afterByKey[istioObjectKey(created)]; !ok {
+			continue
+		}
+		change, err := newChange(istioObjectKey(created).gvk, nil, created)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *change)
+	}
+
+	return changes, nil
+}
+
+func listIstioObjects(ctx context.Context, clientset istioclientset.Interface, namespace string) ([]client.Object, error) {
+	serviceEntries, err := clientset.NetworkingV1alpha3().ServiceEntries(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	virtualServices, err := clientset.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	objects := make([]client.Object, 0, len(serviceEntries.Items)+len(virtualServices.Items))
+	for _, serviceEntry := range serviceEntries.Items {
+		objects = append(objects, serviceEntry)
+	}
+	for _, virtualService := range virtualServices.Items {
+		objects = append(objects, virtualService)
+	}
+	return objects, nil
+}
+
+func istioObjectsByKey(objects []client.Object) map[objectKey]client.Object {
+	byKey := make(map[objectKey]client.Object, len(objects))
+	for _, obj := range objects {
+		byKey[istioObjectKey(obj)] = obj
+	}
+	return byKey
+}
+
+func istioObjectKey(obj client.Object) objectKey {
+	kind := "ServiceEntry"
+	if _, ok := obj.(*istiov1alpha3.VirtualService); ok {
+		kind = "VirtualService"
+	}
+	return objectKey{
+		gvk:       istiov1alpha3.SchemeGroupVersion.WithKind(kind),
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+	}
+}
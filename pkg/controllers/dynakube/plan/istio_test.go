@@ -0,0 +1,70 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/istio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	istiov1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	fakeistio "istio.io/client-go/pkg/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestIstioClientBuilder(t *testing.T) {
+	ctx := context.Background()
+	owner := &dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace}}
+
+	t.Run("changes are recorded", func(t *testing.T) {
+		liveIstio := fakeistio.NewSimpleClientset(
+			&istiov1alpha3.ServiceEntry{ObjectMeta: metav1.ObjectMeta{Name: "deleted", Namespace: testNamespace}},
+			&istiov1alpha3.VirtualService{ObjectMeta: metav1.ObjectMeta{Name: "unchanged", Namespace: testNamespace}},
+		)
+		liveIstio.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{GroupVersion: istio.IstioGVR}}
+		recorder := NewRecorder(fake.NewClient())
+
+		istioClient, err := NewIstioClientBuilder(newStaticIstioClientBuilder(liveIstio), recorder)(&rest.Config{}, scheme.Scheme, owner)
+		require.NoError(t, err)
+
+		isInstalled, err := istioClient.CheckIstioInstalled()
+		require.NoError(t, err)
+		assert.True(t, isInstalled)
+
+		require.NoError(t, istioClient.CreateOrUpdateServiceEntry(ctx, &istiov1alpha3.ServiceEntry{ObjectMeta: metav1.ObjectMeta{Name: "created", Namespace: testNamespace}}))
+		require.NoError(t, istioClient.DeleteServiceEntry(ctx, "deleted"))
+
+		changes, err := recorder.Changes()
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, "delete ServiceEntry 'dynatrace:deleted'", changes[0].String())
+		assert.Equal(t, "create ServiceEntry 'dynatrace:created'", changes[1].String())
+
+		_, err = liveIstio.NetworkingV1alpha3().ServiceEntries(testNamespace).Get(ctx, "deleted", metav1.GetOptions{})
+		assert.NoError(t, err, "cluster must not be modified")
+	})
+	t.Run("istio not installed", func(t *testing.T) {
+		istioClient, err := NewIstioClientBuilder(newStaticIstioClientBuilder(fakeistio.NewSimpleClientset()), NewRecorder(fake.NewClient()))(&rest.Config{}, scheme.Scheme, owner)
+		require.NoError(t, err)
+
+		isInstalled, err := istioClient.CheckIstioInstalled()
+		require.NoError(t, err)
+		assert.False(t, isInstalled)
+	})
+}
+
+func newStaticIstioClientBuilder(clientset *fakeistio.Clientset) istio.ClientBuilder {
+	return func(_ *rest.Config, scheme *runtime.Scheme, owner metav1.Object) (*istio.Client, error) {
+		return &istio.Client{
+			IstioClientset: clientset,
+			Scheme:         scheme,
+			Owner:          owner,
+		}, nil
+	}
+}
@@ -0,0 +1,12 @@
+package plan
+
+const kindMirroredArtifact = "MirroredArtifact"
+
+// RecordMirrorSync records that the artifact would be copied into the mirror registry, the registries themselves are never called
+func (recorder *Recorder) RecordMirrorSync(registry, artifact string) {
+	recorder.recordExternal(Change{
+		Action: ActionUpdate,
+		Kind:   kindMirroredArtifact,
+		Name:   registry + "/" + artifact,
+	})
+}
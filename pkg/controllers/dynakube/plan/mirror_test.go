@@ -0,0 +1,20 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordMirrorSync(t *testing.T) {
+	recorder := NewRecorder(fake.NewClient())
+
+	recorder.RecordMirrorSync("registry.example.com/mirror", "oneagent")
+
+	changes, err := recorder.Changes()
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "update MirroredArtifact 'registry.example.com/mirror/oneagent'", changes[0].String())
+}
@@ -0,0 +1,346 @@
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+var ErrNotSupported = errors.New("not supported while planning")
+
+type objectKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+// recordedObject holds the state of an object before the first and after the last write of the plan,
+// a nil object means that it doesn't exist.
+type recordedObject struct {
+	before client.Object
+	after  client.Object
+}
+
+// Recorder is a client that reads from the cluster but only records the writes instead of sending them to the API server.
+// Reads return the recorded state, so the reconcilers see their own writes like they would on a real cluster.
+type Recorder struct {
+	live client.Client
+
+	objects map[objectKey]*recordedObject
+	order   []objectKey
+
+	// changes made outside of the cluster, e.g. to the Dynatrace API
+	externalChanges []Change
+	istioTrackers   []*istioTracker
+}
+
+var _ client.Client = &Recorder{}
+
+func NewRecorder(live client.Client) *Recorder {
+	return &Recorder{
+		live:    live,
+		objects: make(map[objectKey]*recordedObject),
+	}
+}
+
+// Apply records the given object as if it was applied by the user, it is created if it doesn't exist yet and replaced otherwise.
+func (recorder *Recorder) Apply(ctx context.Context, obj client.Object) error {
+	current, err := recorder.current(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return recorder.Create(ctx, obj)
+	}
+	obj.SetResourceVersion(current.GetResourceVersion())
+	return recorder.Update(ctx, obj)
+}
+
+func (recorder *Recorder) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	gvk, err := recorder.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+
+	recorded, ok := recorder.objects[objectKey{gvk: gvk, namespace: key.Namespace, name: key.Name}]
+	if !ok {
+		return recorder.live.Get(ctx, key, obj, opts...)
+	}
+	if recorded.after == nil {
+		return k8serrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, key.Name)
+	}
+	return copyInto(recorded.after, obj)
+}
+
+// List returns the objects of the cluster with the recorded writes applied,
+// recorded objects that didn't exist before are only filtered by namespace and labels.
+func (recorder *Recorder) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	err := recorder.live.List(ctx, list, opts...)
+	if err != nil {
+		return err
+	}
+
+	listGVK, err := apiutil.GVKForObject(list, recorder.Scheme())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	gvk := listGVK.GroupVersion().WithKind(strings.TrimSuffix(listGVK.Kind, "List"))
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	listOptions := &client.ListOptions{}
+	listOptions.ApplyOptions(opts)
+
+	result := make([]runtime.Object, 0, len(items))
+	listed := make(map[objectKey]bool)
+
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			result = append(result, item)
+			continue
+		}
+
+		key := objectKey{gvk: gvk, namespace: obj.GetNamespace(), name: obj.GetName()}
+		listed[key] = true
+
+		recorded, ok := recorder.objects[key]
+		switch {
+		case !ok:
+			result = append(result, item)
+		case recorded.after != nil:
+			result = append(result, recorded.after.DeepCopyObject())
+		}
+	}
+
+	for _, key := range recorder.order {
+		recorded := recorder.objects[key]
+		if key.gvk != gvk || listed[key] || recorded.after == nil || !matches(recorded.after, listOptions) {
+			continue
+		}
+		result = append(result, recorded.after.DeepCopyObject())
+	}
+
+	return errors.WithStack(meta.SetList(list, result))
+}
+
+func (recorder *Recorder) Create(ctx context.Context, obj client.Object, _ ...client.CreateOption) error {
+	current, err := recorder.current(ctx, obj)
+	if err != nil {
+		return err
+	} else if current != nil {
+		gvk, _ := recorder.GroupVersionKindFor(obj)
+		return k8serrors.NewAlreadyExists(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, obj.GetName())
+	}
+	return recorder.record(ctx, obj, obj)
+}
+
+func (recorder *Recorder) Update(ctx context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	current, err := recorder.current(ctx, obj)
+	if err != nil {
+		return err
+	} else if current == nil {
+		gvk, _ := recorder.GroupVersionKindFor(obj)
+		return k8serrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, obj.GetName())
+	}
+	return recorder.record(ctx, obj, obj)
+}
+
+func (recorder *Recorder) Delete(ctx context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	current, err := recorder.current(ctx, obj)
+	if err != nil {
+		return err
+	} else if current == nil {
+		gvk, _ := recorder.GroupVersionKindFor(obj)
+		return k8serrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, obj.GetName())
+	}
+	return recorder.record(ctx, obj, nil)
+}
+
+// Patch applies the patch to the recorded state of the object and records the result, server-side apply isn't supported
+func (recorder *Recorder) Patch(ctx context.Context, obj client.Object, patch client.Patch, _ ...client.PatchOption) error {
+	current, err := recorder.current(ctx, obj)
+	if err != nil {
+		return err
+	} else if current == nil {
+		gvk, _ := recorder.GroupVersionKindFor(obj)
+		return k8serrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, obj.GetName())
+	}
+
+	patchData, err := patch.Data(obj)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	currentJson, err := json.Marshal(current)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var patchedJson []byte
+	switch patch.Type() {
+	case types.JSONPatchType:
+		jsonPatch, err := jsonpatch.DecodePatch(patchData)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		patchedJson, err = jsonPatch.Apply(currentJson)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	case types.MergePatchType:
+		patchedJson, err = jsonpatch.MergePatch(currentJson, patchData)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	case types.StrategicMergePatchType:
+		patchedJson, err = strategicpatch.StrategicMergePatch(currentJson, patchData, obj)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	default:
+		return errors.WithMessagef(ErrNotSupported, "%s patch of '%s:%s'", patch.Type(), obj.GetNamespace(), obj.GetName())
+	}
+
+	// the object is reset first, fields removed by the patch must not survive in it
+	targetValue := reflect.ValueOf(obj).Elem()
+	targetValue.Set(reflect.Zero(targetValue.Type()))
+	if err := json.Unmarshal(patchedJson, obj); err != nil {
+		return errors.WithStack(err)
+	}
+	return recorder.record(ctx, obj, obj)
+}
+
+func (recorder *Recorder) DeleteAllOf(_ context.Context, _ client.Object, _ ...client.DeleteAllOfOption) error {
+	return errors.WithMessage(ErrNotSupported, "delete all of")
+}
+
+func (recorder *Recorder) Status() client.SubResourceWriter {
+	return subResourceRecorder{recorder: recorder}
+}
+
+func (recorder *Recorder) SubResource(_ string) client.SubResourceClient {
+	return subResourceRecorder{recorder: recorder}
+}
+
+func (recorder *Recorder) Scheme() *runtime.Scheme {
+	return recorder.live.Scheme()
+}
+
+func (recorder *Recorder) RESTMapper() meta.RESTMapper {
+	return recorder.live.RESTMapper()
+}
+
+func (recorder *Recorder) GroupVersionKindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
+	gvk, err := apiutil.GVKForObject(obj, recorder.Scheme())
+	return gvk, errors.WithStack(err)
+}
+
+func (recorder *Recorder) IsObjectNamespaced(obj runtime.Object) (bool, error) {
+	return recorder.live.IsObjectNamespaced(obj)
+}
+
+// current returns the recorded state of the object or the one in the cluster, nil if it doesn't exist
+func (recorder *Recorder) current(ctx context.Context, obj client.Object) (client.Object, error) {
+	current, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil, errors.Errorf("can't copy %T", obj)
+	}
+
+	err := recorder.Get(ctx, client.ObjectKeyFromObject(obj), current)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// record stores the new state of the object, the state before the first write is kept to diff against
+func (recorder *Recorder) record(ctx context.Context, obj client.Object, after client.Object) error {
+	gvk, err := recorder.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+	key := objectKey{gvk: gvk, namespace: obj.GetNamespace(), name: obj.GetName()}
+
+	if after != nil {
+		after = after.DeepCopyObject().(client.Object)
+	}
+
+	if recorded, ok := recorder.objects[key]; ok {
+		recorded.after = after
+		return nil
+	}
+
+	before, err := recorder.current(ctx, obj)
+	if err != nil {
+		return err
+	}
+	recorder.objects[key] = &recordedObject{before: before, after: after}
+	recorder.order = append(recorder.order, key)
+	return nil
+}
+
+func (recorder *Recorder) recordExternal(change Change) {
+	recorder.externalChanges = append(recorder.externalChanges, change)
+}
+
+type subResourceRecorder struct {
+	recorder *Recorder
+}
+
+var _ client.SubResourceClient = subResourceRecorder{}
+
+func (subResource subResourceRecorder) Get(_ context.Context, obj client.Object, _ client.Object, _ ...client.SubResourceGetOption) error {
+	return errors.WithMessagef(ErrNotSupported, "get of a subresource of '%s:%s'", obj.GetNamespace(), obj.GetName())
+}
+
+func (subResource subResourceRecorder) Create(_ context.Context, obj client.Object, _ client.Object, _ ...client.SubResourceCreateOption) error {
+	return errors.WithMessagef(ErrNotSupported, "create of a subresource of '%s:%s'", obj.GetNamespace(), obj.GetName())
+}
+
+// Update records the whole object, as the subresources are part of it
+func (subResource subResourceRecorder) Update(ctx context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+	return subResource.recorder.Update(ctx, obj)
+}
+
+func (subResource subResourceRecorder) Patch(ctx context.Context, obj client.Object, patch client.Patch, _ ...client.SubResourcePatchOption) error {
+	return subResource.recorder.Patch(ctx, obj, patch)
+}
+
+func matches(obj client.Object, listOptions *client.ListOptions) bool {
+	if listOptions.Namespace != "" && listOptions.Namespace != obj.GetNamespace() {
+		return false
+	}
+	if listOptions.LabelSelector != nil && !listOptions.LabelSelector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+	return true
+}
+
+func copyInto(source client.Object, target client.Object) error {
+	sourceValue := reflect.ValueOf(source.DeepCopyObject())
+	targetValue := reflect.ValueOf(target)
+
+	if sourceValue.Type() != targetValue.Type() {
+		return errors.Errorf("can't copy %T into %T", source, target)
+	}
+	targetValue.Elem().Set(sourceValue.Elem())
+	return nil
+}
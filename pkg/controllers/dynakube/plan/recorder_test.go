@@ -0,0 +1,165 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testNamespace = "dynatrace"
+	testName      = "dynakube"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("writes are not sent to the cluster", func(t *testing.T) {
+		live := fake.NewClient(newConfigMap("updated", "old"), newConfigMap("deleted", "old"))
+		recorder := NewRecorder(live)
+
+		require.NoError(t, recorder.Create(ctx, newConfigMap("created", "new")))
+		require.NoError(t, recorder.Update(ctx, newConfigMap("updated", "new")))
+		require.NoError(t, recorder.Delete(ctx, newConfigMap("deleted", "")))
+
+		var configMaps corev1.ConfigMapList
+		require.NoError(t, live.List(ctx, &configMaps))
+		require.Len(t, configMaps.Items, 2)
+		for _, configMap := range configMaps.Items {
+			assert.Equal(t, "old", configMap.Data["value"])
+		}
+	})
+	t.Run("reads return the recorded state", func(t *testing.T) {
+		recorder := NewRecorder(fake.NewClient(newConfigMap("updated", "old"), newConfigMap("deleted", "old"), newConfigMap("unchanged", "old")))
+
+		require.NoError(t, recorder.Create(ctx, newConfigMap("created", "new")))
+		require.NoError(t, recorder.Update(ctx, newConfigMap("updated", "new")))
+		require.NoError(t, recorder.Delete(ctx, newConfigMap("deleted", "")))
+
+		var configMap corev1.ConfigMap
+		require.NoError(t, recorder.Get(ctx, client.ObjectKey{Name: "created", Namespace: testNamespace}, &configMap))
+		assert.Equal(t, "new", configMap.Data["value"])
+
+		err := recorder.Get(ctx, client.ObjectKey{Name: "deleted", Namespace: testNamespace}, &configMap)
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		var configMaps corev1.ConfigMapList
+		require.NoError(t, recorder.List(ctx, &configMaps, client.InNamespace(testNamespace)))
+
+		values := make(map[string]string)
+		for _, item := range configMaps.Items {
+			values[item.Name] = item.Data["value"]
+		}
+		assert.Equal(t, map[string]string{"created": "new", "updated": "new", "unchanged": "old"}, values)
+	})
+	t.Run("created objects are filtered by labels", func(t *testing.T) {
+		recorder := NewRecorder(fake.NewClient())
+		labeled := newConfigMap("labeled", "new")
+		labeled.Labels = map[string]string{"app": "dynatrace"}
+
+		require.NoError(t, recorder.Create(ctx, labeled))
+		require.NoError(t, recorder.Create(ctx, newConfigMap("unlabeled", "new")))
+
+		var configMaps corev1.ConfigMapList
+		require.NoError(t, recorder.List(ctx, &configMaps, client.MatchingLabels{"app": "dynatrace"}))
+		require.Len(t, configMaps.Items, 1)
+		assert.Equal(t, "labeled", configMaps.Items[0].Name)
+	})
+	t.Run("writes fail like on the cluster", func(t *testing.T) {
+		recorder := NewRecorder(fake.NewClient(newConfigMap("existing", "old")))
+
+		assert.True(t, k8serrors.IsAlreadyExists(recorder.Create(ctx, newConfigMap("existing", "new"))))
+		assert.True(t, k8serrors.IsNotFound(recorder.Update(ctx, newConfigMap("missing", "new"))))
+		assert.True(t, k8serrors.IsNotFound(recorder.Delete(ctx, newConfigMap("missing", ""))))
+		assert.True(t, k8serrors.IsNotFound(recorder.Patch(ctx, newConfigMap("missing", "new"), client.MergeFrom(newConfigMap("missing", "")))))
+		assert.ErrorIs(t, recorder.Patch(ctx, newConfigMap("existing", ""), client.Apply), ErrNotSupported)
+	})
+	t.Run("patches are applied to the recorded state", func(t *testing.T) {
+		live := fake.NewClient(newConfigMap("merged", "old"), newConfigMap("strategic", "old"), newConfigMap("json", "old"))
+		recorder := NewRecorder(live)
+
+		merged := newConfigMap("merged", "old")
+		base := merged.DeepCopy()
+		merged.Data["value"] = "new"
+		require.NoError(t, recorder.Patch(ctx, merged, client.MergeFrom(base)))
+
+		strategic := newConfigMap("strategic", "old")
+		base = strategic.DeepCopy()
+		strategic.Annotations = map[string]string{"restarted": "now"}
+		require.NoError(t, recorder.Patch(ctx, strategic, client.StrategicMergeFrom(base)))
+
+		jsonPatched := newConfigMap("json", "")
+		require.NoError(t, recorder.Patch(ctx, jsonPatched, client.RawPatch(types.JSONPatchType, []byte(`[{"op":"replace","path":"/data/value","value":"new"}]`))))
+		assert.Equal(t, "new", jsonPatched.Data["value"], "the object holds the patched state")
+
+		var configMap corev1.ConfigMap
+		require.NoError(t, recorder.Get(ctx, client.ObjectKey{Name: "strategic", Namespace: testNamespace}, &configMap))
+		assert.Equal(t, "old", configMap.Data["value"])
+		assert.Equal(t, "now", configMap.Annotations["restarted"])
+
+		require.NoError(t, live.Get(ctx, client.ObjectKey{Name: "merged", Namespace: testNamespace}, &configMap))
+		assert.Equal(t, "old", configMap.Data["value"], "patches are not sent to the cluster")
+
+		changes, err := recorder.Changes()
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+		assert.Contains(t, changes[0].Diff, "-  value: old\n+  value: new\n")
+		assert.Contains(t, changes[1].Diff, "+    restarted: now\n")
+		assert.Contains(t, changes[2].Diff, "-  value: old\n+  value: new\n")
+	})
+	t.Run("changes show the difference between the cluster and the last write", func(t *testing.T) {
+		recorder := NewRecorder(fake.NewClient(newConfigMap("updated", "old"), newConfigMap("deleted", "old"), newConfigMap("unchanged", "old")))
+
+		require.NoError(t, recorder.Update(ctx, newConfigMap("updated", "intermediate")))
+		require.NoError(t, recorder.Update(ctx, newConfigMap("updated", "new")))
+		require.NoError(t, recorder.Delete(ctx, newConfigMap("deleted", "")))
+		require.NoError(t, recorder.Update(ctx, newConfigMap("unchanged", "old")))
+		require.NoError(t, recorder.Create(ctx, newConfigMap("temporary", "new")))
+		require.NoError(t, recorder.Delete(ctx, newConfigMap("temporary", "")))
+
+		changes, err := recorder.Changes()
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+
+		assert.Equal(t, ActionUpdate, changes[0].Action)
+		assert.Equal(t, "ConfigMap", changes[0].Kind)
+		assert.Equal(t, "updated", changes[0].Name)
+		assert.Contains(t, changes[0].Diff, "-  value: old\n+  value: new\n")
+		assert.NotContains(t, changes[0].Diff, "intermediate")
+
+		assert.Equal(t, ActionDelete, changes[1].Action)
+		assert.Equal(t, "deleted", changes[1].Name)
+	})
+	t.Run("apply creates or replaces", func(t *testing.T) {
+		recorder := NewRecorder(fake.NewClient(newConfigMap("existing", "old")))
+
+		require.NoError(t, recorder.Apply(ctx, newConfigMap("existing", "new")))
+		require.NoError(t, recorder.Apply(ctx, newConfigMap("new", "new")))
+
+		changes, err := recorder.Changes()
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, ActionUpdate, changes[0].Action)
+		assert.Equal(t, ActionCreate, changes[1].Action)
+	})
+}
+
+func newConfigMap(name, value string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Data: map[string]string{
+			"value": value,
+		},
+	}
+}
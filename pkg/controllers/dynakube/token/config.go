@@ -0,0 +1,28 @@
+package token
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	scopeCheckOk            = "ok"
+	scopeCheckMissingScopes = "missing_scopes"
+	scopeCheckError         = "error"
+)
+
+var (
+	log = logger.Factory.GetLogger("dynakube-token")
+
+	scopeChecksMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "operator",
+		Name:      "token_scope_checks_total",
+		Help:      "Number of token scope checks by token and result",
+	}, []string{"token", "result"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(scopeChecksMetric)
+}
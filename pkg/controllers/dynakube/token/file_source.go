@@ -0,0 +1,91 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// FileTokenSourceRootDir is where the token files are mounted into the pods of the operator, all file token sources are relative to it.
+const FileTokenSourceRootDir = "/var/run/dynatrace/tokens"
+
+var ErrInvalidTokenDirectory = errors.New("token directory must be a relative path within " + FileTokenSourceRootDir)
+
+type fileTokenSource struct {
+	fs        afero.Fs
+	directory string
+}
+
+var _ TokenSource = fileTokenSource{}
+
+// NewFileTokenSource reads the tokens from the files in the given directory, the name of a file is the type of the token it contains.
+// Hidden files and directories are ignored, so the bookkeeping of projected volumes (..data) is skipped while the symlinks to the actual files are followed.
+func NewFileTokenSource(fs afero.Fs, directory string) TokenSource {
+	return fileTokenSource{
+		fs:        fs,
+		directory: directory,
+	}
+}
+
+func (source fileTokenSource) ReadTokens(_ context.Context) (Tokens, error) {
+	entries, err := afero.ReadDir(source.fs, source.directory)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make(Tokens)
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		rawToken, err := afero.ReadFile(source.fs, filepath.Join(source.directory, entry.Name()))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		result[entry.Name()] = Token{
+			// files written by templates (e.g. of the Vault agent) usually end with a newline
+			Value: strings.TrimSpace(string(rawToken)),
+		}
+	}
+
+	return result, nil
+}
+
+func (source fileTokenSource) String() string {
+	return fmt.Sprintf("directory '%s'", source.directory)
+}
+
+// FileTokenSourceDirectory returns the directory of the file token source of the DynaKube, if one is configured.
+func FileTokenSourceDirectory(dynakube *dynatracev1beta1.DynaKube) (string, bool) {
+	relativePath, isFileTokenSource := fileTokenSourcePath(dynakube)
+	if !isFileTokenSource {
+		return "", false
+	}
+	return filepath.Join(FileTokenSourceRootDir, relativePath), true
+}
+
+// fileTokenSourcePath returns the cleaned path of the file token source relative to FileTokenSourceRootDir.
+// Cleaning it as an absolute path first drops any leading "..", so the result never points outside the root.
+func fileTokenSourcePath(dynakube *dynatracev1beta1.DynaKube) (string, bool) {
+	if dynakube.Spec.TokenSource == nil || dynakube.Spec.TokenSource.File == nil {
+		return "", false
+	}
+	return strings.TrimPrefix(filepath.Join("/", dynakube.Spec.TokenSource.File.Path), "/"), true
+}
+
+// ValidateFileTokenSourcePath makes sure the path doesn't point outside of FileTokenSourceRootDir,
+// otherwise any file of the operator pods, like the service account token, could be sent to the Dynatrace API.
+func ValidateFileTokenSourcePath(path string) error {
+	if path != "" && !filepath.IsLocal(path) {
+		return ErrInvalidTokenDirectory
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package token
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTokenDirectory = "/var/run/dynatrace/tokens/dynakube"
+
+func TestFileTokenSource(t *testing.T) {
+	t.Run("tokens are read from the files in the directory", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		writeTokenFile(t, fs, dtclient.DynatraceApiToken, testApiToken+"\n")
+		writeTokenFile(t, fs, dtclient.DynatracePaasToken, testPaasToken)
+		writeTokenFile(t, fs, "..data/"+dtclient.DynatraceApiToken, "hidden")
+		writeTokenFile(t, fs, ".hidden", "hidden")
+
+		tokens, err := NewFileTokenSource(fs, testTokenDirectory).ReadTokens(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, Tokens{
+			dtclient.DynatraceApiToken:  {Value: testApiToken},
+			dtclient.DynatracePaasToken: {Value: testPaasToken},
+		}, tokens)
+	})
+	t.Run("rotated tokens are read on the next call", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		source := NewFileTokenSource(fs, testTokenDirectory)
+		writeTokenFile(t, fs, dtclient.DynatraceApiToken, testApiToken)
+
+		tokens, err := source.ReadTokens(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, testApiToken, tokens.ApiToken().Value)
+
+		writeTokenFile(t, fs, dtclient.DynatraceApiToken, "rotated")
+
+		tokens, err = source.ReadTokens(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "rotated", tokens.ApiToken().Value)
+	})
+	t.Run("error if directory does not exist", func(t *testing.T) {
+		_, err := NewFileTokenSource(afero.NewMemMapFs(), testTokenDirectory).ReadTokens(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+	t.Run("describes directory", func(t *testing.T) {
+		assert.Equal(t, "directory '"+testTokenDirectory+"'", NewFileTokenSource(nil, testTokenDirectory).String())
+	})
+}
+
+func TestFileTokenSourceDirectory(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		_, isFileTokenSource := FileTokenSourceDirectory(&dynatracev1beta1.DynaKube{})
+
+		assert.False(t, isFileTokenSource)
+	})
+	t.Run("relative to root directory", func(t *testing.T) {
+		directory, isFileTokenSource := FileTokenSourceDirectory(newFileTokenSourceDynakube("dynakube"))
+
+		assert.True(t, isFileTokenSource)
+		assert.Equal(t, testTokenDirectory, directory)
+	})
+	t.Run("root directory if path is empty", func(t *testing.T) {
+		directory, _ := FileTokenSourceDirectory(newFileTokenSourceDynakube(""))
+
+		assert.Equal(t, FileTokenSourceRootDir, directory)
+	})
+	t.Run("never outside of root directory", func(t *testing.T) {
+		directory, _ := FileTokenSourceDirectory(newFileTokenSourceDynakube("../../secrets/kubernetes.io/serviceaccount"))
+		assert.Equal(t, filepath.Join(FileTokenSourceRootDir, "secrets/kubernetes.io/serviceaccount"), directory)
+
+		directory, _ = FileTokenSourceDirectory(newFileTokenSourceDynakube("/etc"))
+		assert.Equal(t, filepath.Join(FileTokenSourceRootDir, "etc"), directory)
+	})
+}
+
+func TestValidateFileTokenSourcePath(t *testing.T) {
+	assert.NoError(t, ValidateFileTokenSourcePath(""))
+	assert.NoError(t, ValidateFileTokenSourcePath("dynakube"))
+	assert.NoError(t, ValidateFileTokenSourcePath("tenant/dynakube"))
+	assert.ErrorIs(t, ValidateFileTokenSourcePath("/etc"), ErrInvalidTokenDirectory)
+	assert.ErrorIs(t, ValidateFileTokenSourcePath("../dynakube"), ErrInvalidTokenDirectory)
+	assert.ErrorIs(t, ValidateFileTokenSourcePath("dynakube/../.."), ErrInvalidTokenDirectory)
+}
+
+func writeTokenFile(t *testing.T, fs afero.Fs, name, value string) {
+	path := filepath.Join(testTokenDirectory, name)
+	require.NoError(t, fs.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, afero.WriteFile(fs, path, []byte(value), 0644))
+}
+
+func newFileTokenSourceDynakube(path string) *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			TokenSource: &dynatracev1beta1.TokenSourceSpec{
+				File: &dynatracev1beta1.FileTokenSource{
+					Path: path,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,185 @@
+package token
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// atomicWriterDataDir is the symlink Kubernetes swaps to update secret, configmap and projected volumes,
+// the files of the volume are symlinks through it, so the swap changes all of them at once without any event of their own
+const atomicWriterDataDir = "..data"
+
+// FileWatcher triggers a reconcile of the DynaKubes using a file token source whenever their token files change,
+// so rotated tokens are used right away instead of after the next periodic reconcile.
+type FileWatcher struct {
+	apiReader client.Reader
+	fs        afero.Fs
+	namespace string
+	rootDir   string
+	events    chan event.GenericEvent
+}
+
+func NewFileWatcher(apiReader client.Reader, namespace string) *FileWatcher {
+	return &FileWatcher{
+		apiReader: apiReader,
+		fs:        afero.NewOsFs(),
+		namespace: namespace,
+		rootDir:   FileTokenSourceRootDir,
+		events:    make(chan event.GenericEvent),
+	}
+}
+
+// Source emits the DynaKubes whose token files changed, it is meant to be watched by the DynaKube controller.
+func (watcher *FileWatcher) Source() source.Source {
+	return &source.Channel{Source: watcher.events}
+}
+
+// Start watches the token files until the context is done, it implements manager.Runnable.
+// Directories created later are watched as soon as they appear, the parent of the root directory is watched in case the root is missing.
+func (watcher *FileWatcher) Start(ctx context.Context) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = fsWatcher.Close() }()
+
+	_, err = watcher.watchDirectories(fsWatcher, watcher.rootDir)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := fsWatcher.Add(filepath.Dir(watcher.rootDir)); err != nil {
+			log.Info("no token files mounted, not watching them", "directory", watcher.rootDir)
+			<-ctx.Done()
+			return nil
+		}
+		log.Info("no token files mounted yet, waiting for them", "directory", watcher.rootDir)
+	} else if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case fsEvent, ok := <-fsWatcher.Events:
+			if !ok {
+				return nil
+			}
+			if fsEvent.Has(fsnotify.Create) && watcher.isTokenDirectory(fsEvent.Name) {
+				watcher.watchCreatedDirectory(ctx, fsWatcher, fsEvent.Name)
+			}
+			changedDirectory := filepath.Dir(fsEvent.Name)
+			watcher.notify(ctx, changedDirectory, filepath.Base(fsEvent.Name) == atomicWriterDataDir)
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Info("failed to watch token files", "error", err)
+		}
+	}
+}
+
+// watchCreatedDirectory watches a directory created after the start, the files it already holds are treated as changed
+func (watcher *FileWatcher) watchCreatedDirectory(ctx context.Context, fsWatcher *fsnotify.Watcher, directory string) {
+	directories, err := watcher.watchDirectories(fsWatcher, directory)
+	if err != nil {
+		log.Info("failed to watch created token directory", "directory", directory, "error", err)
+		return
+	}
+	for _, directory := range directories {
+		watcher.notify(ctx, directory, false)
+	}
+}
+
+func (watcher *FileWatcher) watchDirectories(fsWatcher *fsnotify.Watcher, root string) ([]string, error) {
+	directories, err := watcher.tokenDirectories(root)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, directory := range directories {
+		if err := fsWatcher.Add(directory); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	log.Info("watching token files", "directories", directories)
+	return directories, nil
+}
+
+// isTokenDirectory tells if the path is a directory in the root directory or the root directory itself, hidden directories don't count
+func (watcher *FileWatcher) isTokenDirectory(path string) bool {
+	if path != watcher.rootDir && !strings.HasPrefix(path, watcher.rootDir+string(filepath.Separator)) {
+		return false
+	}
+	if path != watcher.rootDir && strings.HasPrefix(filepath.Base(path), ".") {
+		return false
+	}
+	isDir, err := afero.IsDir(watcher.fs, path)
+	return err == nil && isDir
+}
+
+// tokenDirectories returns the given directory and all its subdirectories,
+// the hidden ones are skipped as projected volumes replace them on every update.
+func (watcher *FileWatcher) tokenDirectories(root string) ([]string, error) {
+	directories := make([]string, 0)
+
+	err := afero.Walk(watcher.fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		directories = append(directories, path)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return directories, nil
+}
+
+// notify emits the DynaKubes reading their tokens from the changed directory,
+// or from any directory below it if all of them changed, like on the swap of the data of an atomic writer volume
+func (watcher *FileWatcher) notify(ctx context.Context, directory string, withSubdirectories bool) {
+	var dynakubeList dynatracev1beta1.DynaKubeList
+
+	err := watcher.apiReader.List(ctx, &dynakubeList, client.InNamespace(watcher.namespace))
+	if err != nil {
+		log.Info("failed to list DynaKubes after token files changed", "error", err)
+		return
+	}
+
+	for i := range dynakubeList.Items {
+		dynakube := &dynakubeList.Items[i]
+
+		relativePath, isFileTokenSource := fileTokenSourcePath(dynakube)
+		if !isFileTokenSource {
+			continue
+		}
+		tokenDirectory := filepath.Join(watcher.rootDir, relativePath)
+		if tokenDirectory != directory && !(withSubdirectories && strings.HasPrefix(tokenDirectory, directory+string(filepath.Separator))) {
+			continue
+		}
+
+		log.Info("token files changed", "dynakube", dynakube.Name, "directory", directory)
+
+		select {
+		case watcher.events <- event.GenericEvent{Object: dynakube}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
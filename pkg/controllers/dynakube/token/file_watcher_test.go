@@ -0,0 +1,196 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestFileWatcher(t *testing.T) {
+	t.Run("token directories skip hidden directories", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		writeTokenFile(t, fs, dtclient.DynatraceApiToken, testApiToken)
+		writeTokenFile(t, fs, "..2023_01_01/"+dtclient.DynatraceApiToken, testApiToken)
+		watcher := &FileWatcher{fs: fs, rootDir: FileTokenSourceRootDir}
+
+		directories, err := watcher.tokenDirectories(FileTokenSourceRootDir)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{FileTokenSourceRootDir, testTokenDirectory}, directories)
+	})
+	t.Run("notifies dynakubes using the changed directory", func(t *testing.T) {
+		watchedDynakube := newFileTokenSourceDynakube(dynakubeName)
+		watchedDynakube.ObjectMeta = metav1.ObjectMeta{Name: dynakubeName, Namespace: dynatraceNamespace}
+		otherDynakube := newFileTokenSourceDynakube("other")
+		otherDynakube.ObjectMeta = metav1.ObjectMeta{Name: "other", Namespace: dynatraceNamespace}
+		secretDynakube := &dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: dynatraceNamespace}}
+		watcher := &FileWatcher{
+			apiReader: fake.NewClient(watchedDynakube, otherDynakube, secretDynakube),
+			namespace: dynatraceNamespace,
+			rootDir:   FileTokenSourceRootDir,
+			events:    make(chan event.GenericEvent, 3),
+		}
+
+		watcher.notify(context.Background(), testTokenDirectory, false)
+
+		require.Len(t, watcher.events, 1)
+		assert.Equal(t, dynakubeName, (<-watcher.events).Object.GetName())
+	})
+	t.Run("notifies dynakubes of all directories on swap of the volume data", func(t *testing.T) {
+		watchedDynakube := newFileTokenSourceDynakube(dynakubeName)
+		watchedDynakube.ObjectMeta = metav1.ObjectMeta{Name: dynakubeName, Namespace: dynatraceNamespace}
+		rootDynakube := newFileTokenSourceDynakube("")
+		rootDynakube.ObjectMeta = metav1.ObjectMeta{Name: "root", Namespace: dynatraceNamespace}
+		secretDynakube := &dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: dynatraceNamespace}}
+		watcher := &FileWatcher{
+			apiReader: fake.NewClient(watchedDynakube, rootDynakube, secretDynakube),
+			namespace: dynatraceNamespace,
+			rootDir:   FileTokenSourceRootDir,
+			events:    make(chan event.GenericEvent, 3),
+		}
+
+		watcher.notify(context.Background(), FileTokenSourceRootDir, true)
+
+		assert.Len(t, watcher.events, 2)
+	})
+	t.Run("notifies on file changes", func(t *testing.T) {
+		rootDir := t.TempDir()
+		require.NoError(t, afero.WriteFile(afero.NewOsFs(), filepath.Join(rootDir, dtclient.DynatraceApiToken), []byte(testApiToken), 0644))
+
+		dynakube := newFileTokenSourceDynakube("")
+		dynakube.ObjectMeta = metav1.ObjectMeta{Name: dynakubeName, Namespace: dynatraceNamespace}
+		watcher := NewFileWatcher(fake.NewClient(dynakube), dynatraceNamespace)
+		watcher.rootDir = rootDir
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			_ = watcher.Start(ctx)
+		}()
+
+		assert.Eventually(t, func() bool {
+			_ = afero.WriteFile(afero.NewOsFs(), filepath.Join(rootDir, dtclient.DynatraceApiToken), []byte("rotated"), 0644)
+			select {
+			case changed := <-watcher.events:
+				return changed.Object.GetName() == dynakubeName
+			case <-time.After(100 * time.Millisecond):
+				return false
+			}
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+	t.Run("notifies on updates of atomic writer volumes", func(t *testing.T) {
+		// the layout of secret and projected volumes, the directory of the dynakube is a symlink through ..data
+		rootDir := t.TempDir()
+		swapAtomicWriterData(t, rootDir, "..2024_01_01", "initial")
+		require.NoError(t, os.Symlink(filepath.Join(atomicWriterDataDir, dynakubeName), filepath.Join(rootDir, dynakubeName)))
+
+		dynakube := newFileTokenSourceDynakube(dynakubeName)
+		dynakube.ObjectMeta = metav1.ObjectMeta{Name: dynakubeName, Namespace: dynatraceNamespace}
+		watcher := NewFileWatcher(fake.NewClient(dynakube), dynatraceNamespace)
+		watcher.rootDir = rootDir
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			_ = watcher.Start(ctx)
+		}()
+
+		update := 0
+		assert.Eventually(t, func() bool {
+			update++
+			swapAtomicWriterData(t, rootDir, fmt.Sprintf("..2024_01_02_%d", update), "rotated")
+			select {
+			case changed := <-watcher.events:
+				return changed.Object.GetName() == dynakubeName
+			case <-time.After(100 * time.Millisecond):
+				return false
+			}
+		}, 5*time.Second, 10*time.Millisecond)
+
+		tokens, err := NewFileTokenSource(afero.NewOsFs(), filepath.Join(rootDir, dynakubeName)).ReadTokens(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "rotated", tokens.ApiToken().Value)
+	})
+	t.Run("watches directories created later", func(t *testing.T) {
+		rootDir := t.TempDir()
+		dynakube := newFileTokenSourceDynakube("late")
+		dynakube.ObjectMeta = metav1.ObjectMeta{Name: dynakubeName, Namespace: dynatraceNamespace}
+		watcher := NewFileWatcher(fake.NewClient(dynakube), dynatraceNamespace)
+		watcher.rootDir = rootDir
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			_ = watcher.Start(ctx)
+		}()
+
+		assertNotifiedAfterWrite(t, watcher, filepath.Join(rootDir, "late"))
+	})
+	t.Run("watches root directory mounted later", func(t *testing.T) {
+		rootDir := filepath.Join(t.TempDir(), "tokens")
+		dynakube := newFileTokenSourceDynakube("")
+		dynakube.ObjectMeta = metav1.ObjectMeta{Name: dynakubeName, Namespace: dynatraceNamespace}
+		watcher := NewFileWatcher(fake.NewClient(dynakube), dynatraceNamespace)
+		watcher.rootDir = rootDir
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			_ = watcher.Start(ctx)
+		}()
+
+		assertNotifiedAfterWrite(t, watcher, rootDir)
+	})
+	t.Run("no error if nothing is mounted", func(t *testing.T) {
+		watcher := NewFileWatcher(nil, dynatraceNamespace)
+		watcher.fs = afero.NewMemMapFs()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.NoError(t, watcher.Start(ctx))
+	})
+}
+
+// assertNotifiedAfterWrite writes the api token into the directory until the watcher notifies about the change,
+// the directory is created by the first write
+func assertNotifiedAfterWrite(t *testing.T, watcher *FileWatcher, directory string) {
+	assert.Eventually(t, func() bool {
+		_ = afero.NewOsFs().MkdirAll(directory, 0755)
+		_ = afero.WriteFile(afero.NewOsFs(), filepath.Join(directory, dtclient.DynatraceApiToken), []byte("rotated"), 0644)
+		select {
+		case changed := <-watcher.events:
+			return changed.Object.GetName() == dynakubeName
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// swapAtomicWriterData updates the volume like the kubelet does, the files are written into a new timestamped directory
+// and the ..data symlink is replaced by renaming a temporary one to it
+func swapAtomicWriterData(t *testing.T, rootDir, timestampDir, apiToken string) {
+	tokenDirectory := filepath.Join(rootDir, timestampDir, dynakubeName)
+	require.NoError(t, os.MkdirAll(tokenDirectory, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tokenDirectory, dtclient.DynatraceApiToken), []byte(apiToken), 0644))
+
+	temporaryLink := filepath.Join(rootDir, "..data_tmp")
+	require.NoError(t, os.Symlink(timestampDir, temporaryLink))
+	require.NoError(t, os.Rename(temporaryLink, filepath.Join(rootDir, atomicWriterDataDir)))
+}
@@ -7,7 +7,6 @@ import (
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -40,32 +39,18 @@ func (reader Reader) ReadTokens(ctx context.Context) (Tokens, error) {
 }
 
 func (reader Reader) readTokens(ctx context.Context) (Tokens, error) {
-	var tokenSecret corev1.Secret
-	result := make(Tokens)
-
-	err := reader.apiReader.Get(ctx, client.ObjectKey{
-		Name:      reader.dynakube.Tokens(),
-		Namespace: reader.dynakube.Namespace,
-	}, &tokenSecret)
-
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	for tokenType, rawToken := range tokenSecret.Data {
-		result[tokenType] = Token{
-			Value: string(rawToken),
-		}
-	}
+	return reader.source().ReadTokens(ctx)
+}
 
-	return result, nil
+func (reader Reader) source() TokenSource {
+	return NewTokenSource(reader.apiReader, reader.dynakube)
 }
 
 func (reader Reader) verifyApiTokenExists(tokens Tokens) error {
 	apiToken, hasApiToken := tokens[dtclient.DynatraceApiToken]
 
 	if !hasApiToken || len(apiToken.Value) == 0 {
-		return errors.New(fmt.Sprintf("the API token is missing from the token secret '%s:%s'", reader.dynakube.Namespace, reader.dynakube.Tokens()))
+		return errors.New(fmt.Sprintf("the API token is missing from the token %s", reader.source()))
 	}
 
 	return nil
@@ -90,6 +90,13 @@ func testVerifyTokens(t *testing.T) {
 
 		assert.EqualError(t, err, "the API token is missing from the token secret 'dynatrace:dynakube'")
 	})
+	t.Run("error names the token source", func(t *testing.T) {
+		reader := NewReader(nil, newFileTokenSourceDynakube(dynakubeName))
+
+		err := reader.verifyApiTokenExists(map[string]Token{})
+
+		assert.EqualError(t, err, "the API token is missing from the token directory '/var/run/dynatrace/tokens/dynakube'")
+	})
 	t.Run("no error if api token exists", func(t *testing.T) {
 		reader := NewReader(nil, nil)
 
@@ -0,0 +1,23 @@
+package token
+
+import (
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+)
+
+// AnnotationTokenRotation is set on the pod templates of the ActiveGate and OneAgent,
+// so their pods are replaced according to their rolling update strategy once the tokens are rotated.
+const AnnotationTokenRotation = dynatracev1beta1.InternalFlagPrefix + "token-rotation"
+
+// RotationAnnotations returns the pod template annotations for the last token rotation of the DynaKube,
+// nothing is returned as long as no rotation happened, so pods aren't restarted when the operator is updated.
+func RotationAnnotations(dynakube *dynatracev1beta1.DynaKube) map[string]string {
+	lastTokenRotation := dynakube.Status.DynatraceApi.LastTokenRotation
+	if lastTokenRotation.IsZero() {
+		return map[string]string{}
+	}
+	return map[string]string{
+		AnnotationTokenRotation: lastTokenRotation.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,22 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRotationAnnotations(t *testing.T) {
+	t.Run("no annotation without rotation", func(t *testing.T) {
+		assert.Empty(t, RotationAnnotations(&dynatracev1beta1.DynaKube{}))
+	})
+	t.Run("annotation with time of last rotation", func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{}
+		dynakube.Status.DynatraceApi.LastTokenRotation = metav1.NewTime(time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC))
+
+		assert.Equal(t, map[string]string{AnnotationTokenRotation: "2023-10-01T12:00:00Z"}, RotationAnnotations(dynakube))
+	})
+}
@@ -0,0 +1,99 @@
+package token
+
+import (
+	"context"
+	"fmt"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TokenSource provides the tokens used for connecting to Dynatrace.
+// The tokens are read on every call, so rotated tokens are picked up without caching issues.
+type TokenSource interface {
+	ReadTokens(ctx context.Context) (Tokens, error)
+
+	// String describes where the tokens come from, it is used in log and error messages.
+	String() string
+}
+
+// NewTokenSource returns the TokenSource configured in the DynaKube, which is the token secret if nothing else is configured.
+func NewTokenSource(apiReader client.Reader, dynakube *dynatracev1beta1.DynaKube) TokenSource {
+	if tokenDirectory, isFileTokenSource := FileTokenSourceDirectory(dynakube); isFileTokenSource {
+		return NewFileTokenSource(afero.NewOsFs(), tokenDirectory)
+	}
+	return NewSecretTokenSource(apiReader, dynakube.Tokens(), dynakube.Namespace)
+}
+
+type secretTokenSource struct {
+	apiReader client.Reader
+	name      string
+	namespace string
+}
+
+var _ TokenSource = secretTokenSource{}
+
+// NewSecretTokenSource reads the tokens from the data of the given secret.
+func NewSecretTokenSource(apiReader client.Reader, name, namespace string) TokenSource {
+	return secretTokenSource{
+		apiReader: apiReader,
+		name:      name,
+		namespace: namespace,
+	}
+}
+
+func (source secretTokenSource) ReadTokens(ctx context.Context) (Tokens, error) {
+	var tokenSecret corev1.Secret
+	result := make(Tokens)
+
+	err := source.apiReader.Get(ctx, client.ObjectKey{
+		Name:      source.name,
+		Namespace: source.namespace,
+	}, &tokenSecret)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for tokenType, rawToken := range tokenSecret.Data {
+		result[tokenType] = Token{
+			Value: string(rawToken),
+		}
+	}
+
+	return result, nil
+}
+
+func (source secretTokenSource) String() string {
+	return fmt.Sprintf("secret '%s:%s'", source.namespace, source.name)
+}
+
+type staticTokenSource struct {
+	description string
+	tokens      Tokens
+}
+
+var _ TokenSource = staticTokenSource{}
+
+// NewStaticTokenSource provides tokens which were already read from somewhere else, e.g. the config of the init-container.
+func NewStaticTokenSource(description string, tokens Tokens) TokenSource {
+	return staticTokenSource{
+		description: description,
+		tokens:      tokens,
+	}
+}
+
+func (source staticTokenSource) ReadTokens(_ context.Context) (Tokens, error) {
+	result := make(Tokens, len(source.tokens))
+	for tokenType, token := range source.tokens {
+		result[tokenType] = token
+	}
+	return result, nil
+}
+
+func (source staticTokenSource) String() string {
+	return source.description
+}
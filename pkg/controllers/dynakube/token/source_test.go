@@ -0,0 +1,66 @@
+package token
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewTokenSource(t *testing.T) {
+	t.Run("secret by default", func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      dynakubeName,
+				Namespace: dynatraceNamespace,
+			},
+		}
+
+		source := NewTokenSource(nil, dynakube)
+
+		assert.IsType(t, secretTokenSource{}, source)
+		assert.Equal(t, "secret 'dynatrace:dynakube'", source.String())
+	})
+	t.Run("file if configured", func(t *testing.T) {
+		source := NewTokenSource(nil, newFileTokenSourceDynakube(dynakubeName))
+
+		assert.IsType(t, fileTokenSource{}, source)
+		assert.Equal(t, "directory '"+testTokenDirectory+"'", source.String())
+	})
+}
+
+func TestSecretTokenSource(t *testing.T) {
+	clt := fake.NewClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tokens",
+			Namespace: dynatraceNamespace,
+		},
+		Data: map[string][]byte{
+			dtclient.DynatraceApiToken: []byte(testApiToken),
+		},
+	})
+
+	tokens, err := NewSecretTokenSource(clt, "tokens", dynatraceNamespace).ReadTokens(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, Tokens{dtclient.DynatraceApiToken: {Value: testApiToken}}, tokens)
+}
+
+func TestStaticTokenSource(t *testing.T) {
+	staticTokens := Tokens{dtclient.DynatraceApiToken: {Value: testApiToken}}
+	source := NewStaticTokenSource("init secret", staticTokens)
+
+	tokens, err := source.ReadTokens(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, staticTokens, tokens)
+	assert.Equal(t, "init secret", source.String())
+
+	tokens[dtclient.DynatracePaasToken] = Token{Value: testPaasToken}
+	assert.Len(t, staticTokens, 1, "source must not be modified")
+}
@@ -42,8 +42,16 @@ func (token Token) setPaasTokenScopes() Token {
 	return token
 }
 
-func (token Token) setDataIngestScopes() Token {
+func (token Token) setDataIngestScopes(dynakube dynatracev1beta1.DynaKube) Token {
 	token.RequiredScopes = []string{dtclient.TokenScopeMetricsIngest}
+
+	// the OTLP exporters of the injected pods authenticate with the data ingest token
+	if dynakube.Spec.OpenTelemetry != nil {
+		token.RequiredScopes = append(token.RequiredScopes,
+			dtclient.TokenScopeOpenTelemetryTraceIngest,
+			dtclient.TokenScopeLogsIngest)
+	}
+
 	return token
 }
 
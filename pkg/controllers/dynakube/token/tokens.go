@@ -1,7 +1,10 @@
 package token
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"sort"
 	"strings"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
@@ -33,6 +36,23 @@ func (tokens Tokens) getToken(tokenName string) Token {
 	return token
 }
 
+// Hash identifies the values of the tokens without revealing them, it changes whenever a token is rotated.
+func (tokens Tokens) Hash() string {
+	tokenTypes := make([]string, 0, len(tokens))
+	for tokenType := range tokens {
+		tokenTypes = append(tokenTypes, tokenType)
+	}
+	sort.Strings(tokenTypes)
+
+	hash := sha256.New()
+	for _, tokenType := range tokenTypes {
+		// writing to a hash never fails
+		_, _ = hash.Write([]byte(tokenType + "=" + tokens[tokenType].Value + "\n"))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
 func (tokens Tokens) SetScopesForDynakube(dynakube dynatracev1beta1.DynaKube) Tokens {
 	_, hasPaasToken := tokens[dtclient.DynatracePaasToken]
 
@@ -43,7 +63,7 @@ func (tokens Tokens) SetScopesForDynakube(dynakube dynatracev1beta1.DynaKube) To
 		case dtclient.DynatracePaasToken:
 			tokens[dtclient.DynatracePaasToken] = token.setPaasTokenScopes()
 		case dtclient.DynatraceDataIngestToken:
-			tokens[dtclient.DynatraceDataIngestToken] = token.setDataIngestScopes()
+			tokens[dtclient.DynatraceDataIngestToken] = token.setDataIngestScopes(dynakube)
 		}
 	}
 
@@ -61,6 +81,7 @@ func (tokens Tokens) VerifyScopes(dtc dtclient.Client) error {
 		scopes, err := dtc.GetTokenScopes(token.Value)
 
 		if err != nil {
+			scopeChecksMetric.WithLabelValues(tokenType, scopeCheckError).Inc()
 			scopeErrors = append(scopeErrors, err)
 			continue
 		}
@@ -68,9 +89,12 @@ func (tokens Tokens) VerifyScopes(dtc dtclient.Client) error {
 		missingScopes := token.getMissingScopes(scopes)
 
 		if len(missingScopes) > 0 {
+			scopeChecksMetric.WithLabelValues(tokenType, scopeCheckMissingScopes).Inc()
 			scopeErrors = append(scopeErrors,
 				errors.New(fmt.Sprintf("token '%s' is missing the following scopes: [ %s ]", tokenType, strings.Join(missingScopes, ", "))))
+			continue
 		}
+		scopeChecksMetric.WithLabelValues(tokenType, scopeCheckOk).Inc()
 	}
 
 	if len(scopeErrors) > 0 {
@@ -7,6 +7,7 @@ import (
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -17,6 +18,35 @@ func TestTokens(t *testing.T) {
 	t.Run("set data ingest token scopes", testDataIngestTokenScopes)
 	t.Run("verify token scopes", testVerifyTokenScopes)
 	t.Run("verify token values", testVerifyTokenValues)
+	t.Run("hash", testHash)
+}
+
+func testHash(t *testing.T) {
+	tokens := Tokens{
+		dtclient.DynatraceApiToken:  {Value: testApiToken},
+		dtclient.DynatracePaasToken: {Value: testPaasToken},
+	}
+
+	t.Run("does not contain token values", func(t *testing.T) {
+		assert.NotContains(t, tokens.Hash(), testApiToken)
+		assert.Len(t, tokens.Hash(), 64)
+	})
+	t.Run("ignores scopes", func(t *testing.T) {
+		scopedTokens := Tokens{
+			dtclient.DynatraceApiToken:  {Value: testApiToken, RequiredScopes: []string{dtclient.TokenScopeDataExport}},
+			dtclient.DynatracePaasToken: {Value: testPaasToken},
+		}
+
+		assert.Equal(t, tokens.Hash(), scopedTokens.Hash())
+	})
+	t.Run("changes with rotated token", func(t *testing.T) {
+		rotatedTokens := Tokens{
+			dtclient.DynatraceApiToken:  {Value: testApiToken},
+			dtclient.DynatracePaasToken: {Value: "rotated"},
+		}
+
+		assert.NotEqual(t, tokens.Hash(), rotatedTokens.Hash())
+	})
 }
 
 func testSetApiTokenScopes(t *testing.T) {
@@ -93,14 +123,34 @@ func testPaasTokenScopes(t *testing.T) {
 }
 
 func testDataIngestTokenScopes(t *testing.T) {
-	tokens := Tokens{
-		dtclient.DynatraceDataIngestToken: {},
-	}
-	tokens = tokens.SetScopesForDynakube(dynatracev1beta1.DynaKube{})
+	t.Run("metrics ingest", func(t *testing.T) {
+		tokens := Tokens{
+			dtclient.DynatraceDataIngestToken: {},
+		}
+		tokens = tokens.SetScopesForDynakube(dynatracev1beta1.DynaKube{})
 
-	assert.Equal(t,
-		[]string{dtclient.TokenScopeMetricsIngest},
-		tokens.DataIngestToken().RequiredScopes)
+		assert.Equal(t,
+			[]string{dtclient.TokenScopeMetricsIngest},
+			tokens.DataIngestToken().RequiredScopes)
+	})
+	t.Run("OTLP injection", func(t *testing.T) {
+		tokens := Tokens{
+			dtclient.DynatraceDataIngestToken: {},
+		}
+		tokens = tokens.SetScopesForDynakube(dynatracev1beta1.DynaKube{
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				OpenTelemetry: &dynatracev1beta1.OpenTelemetrySpec{},
+			},
+		})
+
+		assert.Equal(t,
+			[]string{
+				dtclient.TokenScopeMetricsIngest,
+				dtclient.TokenScopeOpenTelemetryTraceIngest,
+				dtclient.TokenScopeLogsIngest,
+			},
+			tokens.DataIngestToken().RequiredScopes)
+	})
 }
 
 func testVerifyTokenScopes(t *testing.T) {
@@ -149,6 +199,10 @@ func testVerifyTokenScopes(t *testing.T) {
 	assert.EqualError(t,
 		apiError.VerifyScopes(fakeDynatraceClient),
 		"test api-error")
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(scopeChecksMetric.WithLabelValues("valid-scopes", scopeCheckOk)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(scopeChecksMetric.WithLabelValues("invalid-scopes", scopeCheckMissingScopes)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(scopeChecksMetric.WithLabelValues("api-error", scopeCheckError)))
 }
 
 func testVerifyTokenValues(t *testing.T) {
@@ -0,0 +1,58 @@
+package dynakube
+
+import (
+	"context"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// mapTokenSecretToDynakubes enqueues the DynaKubes reading their tokens from the given secret,
+// so rotated tokens are picked up right away instead of with the next periodic reconcile.
+func (controller *Controller) mapTokenSecretToDynakubes(ctx context.Context, secret client.Object) []reconcile.Request {
+	var dynakubeList dynatracev1beta1.DynaKubeList
+
+	err := controller.client.List(ctx, &dynakubeList, client.InNamespace(secret.GetNamespace()))
+	if err != nil {
+		log.Info("failed to list DynaKubes for token secret", "secret", secret.GetName(), "error", err)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0)
+
+	for i := range dynakubeList.Items {
+		dynakube := &dynakubeList.Items[i]
+		if _, isFileTokenSource := token.FileTokenSourceDirectory(dynakube); isFileTokenSource || dynakube.Tokens() != secret.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(dynakube)})
+	}
+
+	return requests
+}
+
+// reconcileTokenRotation compares the hash of the tokens with the one of the last reconcile.
+// When the tokens were rotated, the scopes of the new tokens are verified and the connection info is queried with them right away,
+// and the ActiveGate and OneAgent pods are replaced via the annotation returned by token.RotationAnnotations.
+// The pull secret, the init secrets and the data-ingest endpoint secrets are updated with the new tokens by the same reconcile.
+func (controller *Controller) reconcileTokenRotation(dynakube *dynatracev1beta1.DynaKube, tokens token.Tokens) {
+	tokenHash := tokens.Hash()
+	lastTokenHash := dynakube.Status.DynatraceApi.TokenHash
+	dynakube.Status.DynatraceApi.TokenHash = tokenHash
+
+	if lastTokenHash == "" || lastTokenHash == tokenHash {
+		return
+	}
+
+	log.Info("tokens were rotated", "dynakube", dynakube.Name, "namespace", dynakube.Namespace)
+
+	dynakube.Status.DynatraceApi.LastTokenScopeRequest = metav1.Time{}
+	dynakube.Status.OneAgent.ConnectionInfoStatus.LastRequest = metav1.Time{}
+	dynakube.Status.ActiveGate.ConnectionInfoStatus.LastRequest = metav1.Time{}
+	dynakube.Status.DynatraceApi.LastTokenRotation = metav1.Now()
+
+	controller.setConditionTokensRotated(dynakube)
+}
@@ -0,0 +1,94 @@
+package dynakube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestMapTokenSecretToDynakubes(t *testing.T) {
+	controller := &Controller{
+		client: fake.NewClient(
+			&dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace}},
+			&dynatracev1beta1.DynaKube{
+				ObjectMeta: metav1.ObjectMeta{Name: "shared-tokens", Namespace: testNamespace},
+				Spec:       dynatracev1beta1.DynaKubeSpec{Tokens: testName},
+			},
+			&dynatracev1beta1.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testNamespace}},
+			&dynatracev1beta1.DynaKube{
+				ObjectMeta: metav1.ObjectMeta{Name: "file-tokens", Namespace: testNamespace},
+				Spec: dynatracev1beta1.DynaKubeSpec{
+					Tokens:      testName,
+					TokenSource: &dynatracev1beta1.TokenSourceSpec{File: &dynatracev1beta1.FileTokenSource{}},
+				},
+			},
+		),
+	}
+
+	requests := controller.mapTokenSecretToDynakubes(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace},
+	})
+
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: testName, Namespace: testNamespace}},
+		{NamespacedName: types.NamespacedName{Name: "shared-tokens", Namespace: testNamespace}},
+	}, requests)
+}
+
+func TestReconcileTokenRotation(t *testing.T) {
+	tokens := token.Tokens{dtclient.DynatraceApiToken: {Value: testAPIToken}}
+	rotatedTokens := token.Tokens{dtclient.DynatraceApiToken: {Value: "rotated"}}
+
+	t.Run(`first hash is recorded without rotation`, func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{}
+		dynakube.Status.DynatraceApi.LastTokenScopeRequest = metav1.Now()
+
+		(&Controller{}).reconcileTokenRotation(dynakube, tokens)
+
+		assert.Equal(t, tokens.Hash(), dynakube.Status.DynatraceApi.TokenHash)
+		assert.True(t, dynakube.Status.DynatraceApi.LastTokenRotation.IsZero())
+		assert.False(t, dynakube.Status.DynatraceApi.LastTokenScopeRequest.IsZero())
+		assert.Empty(t, dynakube.Status.Conditions)
+	})
+	t.Run(`unchanged tokens are no rotation`, func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{}
+		dynakube.Status.DynatraceApi.TokenHash = tokens.Hash()
+
+		(&Controller{}).reconcileTokenRotation(dynakube, tokens)
+
+		assert.True(t, dynakube.Status.DynatraceApi.LastTokenRotation.IsZero())
+		assert.Empty(t, dynakube.Status.Conditions)
+	})
+	t.Run(`rotated tokens reset caches and are recorded`, func(t *testing.T) {
+		dynakube := &dynatracev1beta1.DynaKube{}
+		dynakube.Status.DynatraceApi.TokenHash = tokens.Hash()
+		dynakube.Status.DynatraceApi.LastTokenScopeRequest = metav1.Now()
+		dynakube.Status.OneAgent.ConnectionInfoStatus.LastRequest = metav1.Now()
+		dynakube.Status.ActiveGate.ConnectionInfoStatus.LastRequest = metav1.Now()
+
+		(&Controller{}).reconcileTokenRotation(dynakube, rotatedTokens)
+
+		assert.Equal(t, rotatedTokens.Hash(), dynakube.Status.DynatraceApi.TokenHash)
+		assert.False(t, dynakube.Status.DynatraceApi.LastTokenRotation.IsZero())
+		assert.True(t, dynakube.Status.DynatraceApi.LastTokenScopeRequest.IsZero())
+		assert.True(t, dynakube.Status.OneAgent.ConnectionInfoStatus.LastRequest.IsZero())
+		assert.True(t, dynakube.Status.ActiveGate.ConnectionInfoStatus.LastRequest.IsZero())
+
+		condition := meta.FindStatusCondition(dynakube.Status.Conditions, dynatracev1beta1.TokenRotationConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, dynatracev1beta1.ReasonTokensRotated, condition.Reason)
+		assert.Equal(t, dynakube.Status.DynatraceApi.LastTokenRotation, condition.LastTransitionTime)
+	})
+}
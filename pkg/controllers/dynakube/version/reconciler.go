@@ -3,12 +3,15 @@ package version
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/updatewindow"
+	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -23,6 +26,7 @@ type Reconciler struct {
 	dtClient       dtclient.Client
 	registryClient registry.ImageGetter
 	timeProvider   *timeprovider.Provider
+	updateWindow   *updatewindow.Window
 
 	fs        afero.Afero
 	apiReader client.Reader
@@ -41,6 +45,12 @@ func NewReconciler(dynakube *dynatracev1beta1.DynaKube, apiReader client.Reader,
 
 // Reconcile updates the version status used by the dynakube
 func (reconciler *Reconciler) Reconcile(ctx context.Context) error {
+	updateWindow, err := reconciler.dynakube.UpdateWindow()
+	if err != nil {
+		return errors.WithMessage(err, "invalid update window")
+	}
+	reconciler.updateWindow = updateWindow
+
 	updaters := []versionStatusUpdater{
 		newActiveGateUpdater(reconciler.dynakube, reconciler.apiReader, reconciler.dtClient, reconciler.registryClient),
 		newOneAgentUpdater(reconciler.dynakube, reconciler.apiReader, reconciler.dtClient, reconciler.registryClient),
@@ -55,17 +65,38 @@ func (reconciler *Reconciler) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// NextUpdateWindow returns when the next update window opens if a newer version is held back, otherwise it is zero
+func (reconciler *Reconciler) NextUpdateWindow() time.Time {
+	statuses := []status.VersionStatus{
+		reconciler.dynakube.Status.OneAgent.VersionStatus,
+		reconciler.dynakube.Status.ActiveGate.VersionStatus,
+		reconciler.dynakube.Status.CodeModules.VersionStatus,
+		reconciler.dynakube.Status.Synthetic.VersionStatus,
+	}
+	for _, versionStatus := range statuses {
+		if versionStatus.HasPendingUpdate() {
+			return reconciler.updateWindow.NextOpening(reconciler.timeProvider.Now().Time)
+		}
+	}
+	return time.Time{}
+}
+
 func (reconciler *Reconciler) updateVersionStatuses(ctx context.Context, updaters []versionStatusUpdater) error {
 	for _, updater := range updaters {
 		log.Info("updating version status", "updater", updater.Name())
+		previous := *updater.Target()
+		isAutomaticUpdate := isAutomaticUpdate(updater)
+
 		err := reconciler.run(ctx, updater)
 		if err != nil {
 			return err
 		}
 
+		reconciler.applyUpdateWindow(updater, previous, isAutomaticUpdate)
+
 		_, ok := updater.(*oneAgentUpdater)
 		if ok {
-			healthConfig, err := GetOneAgentHealthConfig(ctx, reconciler.apiReader, reconciler.registryClient, reconciler.dynakube, reconciler.dynakube.OneAgentImage())
+			healthConfig, err := GetOneAgentHealthConfig(ctx, reconciler.apiReader, reconciler.registryClient, reconciler.dynakube, reconciler.dynakube.Status.OneAgent.ImageID)
 			if err != nil {
 				log.Error(err, "could not set OneAgent healthcheck")
 			} else {
@@ -101,6 +132,11 @@ func (reconciler *Reconciler) needsUpdate(updater versionStatusUpdater) bool {
 		return true
 	}
 
+	if updater.Target().HasPendingUpdate() && reconciler.updateWindow.IsOpen(reconciler.timeProvider.Now().Time) {
+		log.Info("update window opened, update for version status is needed", "updater", updater.Name())
+		return true
+	}
+
 	if !reconciler.timeProvider.IsOutdated(updater.Target().LastProbeTimestamp, reconciler.dynakube.FeatureApiRequestThreshold()) {
 		log.Info("status timestamp still valid, skipping version status updater", "updater", updater.Name())
 		return false
@@ -108,6 +144,29 @@ func (reconciler *Reconciler) needsUpdate(updater versionStatusUpdater) bool {
 	return true
 }
 
+// applyUpdateWindow holds back automatic updates while the update window is closed, the new version is kept as pending in the status
+func (reconciler *Reconciler) applyUpdateWindow(updater versionStatusUpdater, previous status.VersionStatus, isAutomaticUpdate bool) {
+	target := updater.Target()
+	isUpdated := target.ImageID != previous.ImageID || target.Version != previous.Version
+	if !isAutomaticUpdate || !isUpdated || reconciler.updateWindow.IsOpen(reconciler.timeProvider.Now().Time) {
+		target.ClearPendingUpdate()
+		return
+	}
+
+	log.Info("update window is closed, holding back new version", "updater", updater.Name(), "version", target.Version, "imageID", target.ImageID)
+	target.HoldBackUpdate(previous)
+}
+
+// isAutomaticUpdate checks if a change of the version status would be an automatic update,
+// changes of the source or the custom image/version and the initial version are always applied
+func isAutomaticUpdate(updater versionStatusUpdater) bool {
+	target := updater.Target()
+	return (target.ImageID != "" || target.Version != "") &&
+		target.Source == determineSource(updater) &&
+		target.Source != status.CustomImageVersionSource &&
+		!hasCustomFieldChanged(updater)
+}
+
 func hasCustomFieldChanged(updater versionStatusUpdater) bool {
 	if updater.Target().Source == status.CustomImageVersionSource {
 		oldImage := updater.Target().ImageID
@@ -14,6 +14,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry/mocks"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/updatewindow"
 	containerv1 "github.com/google/go-containerregistry/pkg/v1"
 	fakecontainer "github.com/google/go-containerregistry/pkg/v1/fake"
 	"github.com/opencontainers/go-digest"
@@ -81,6 +82,21 @@ func TestReconcile(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("no update if update window is invalid", func(t *testing.T) {
+		dynakube := dynakubeTemplate.DeepCopy()
+		dynakube.Spec.UpdateWindow = &dynatracev1beta1.UpdateWindowSpec{
+			Schedule: "0 25 * * *",
+			Duration: metav1.Duration{Duration: time.Hour},
+		}
+		versionReconciler := Reconciler{
+			dynakube:     dynakube,
+			timeProvider: timeprovider.New().Freeze(),
+		}
+		err := versionReconciler.Reconcile(ctx)
+		require.Error(t, err)
+		assert.Empty(t, dynakube.Status.OneAgent.Version)
+	})
+
 	t.Run("all image versions were updated", func(t *testing.T) {
 		testActiveGateImage := getTestActiveGateImageInfo()
 		testOneAgentImage := getTestOneAgentImageInfo()
@@ -286,6 +302,160 @@ func TestNeedsUpdate(t *testing.T) {
 		}
 		assert.True(t, reconciler.needsUpdate(newOneAgentUpdater(updatedDynakube, fake.NewClient(), nil, nil)))
 	})
+
+	t.Run("needs, because update window opened with a pending update", func(t *testing.T) {
+		updatedDynakube := dynakube.DeepCopy()
+		updatedDynakube.Status.OneAgent.LastProbeTimestamp = timeProvider.Now()
+		updatedDynakube.Status.OneAgent.PendingVersion = "2.4.5.6-7"
+		reconciler := Reconciler{
+			dynakube:     updatedDynakube,
+			timeProvider: timeProvider,
+		}
+		assert.True(t, reconciler.needsUpdate(newOneAgentUpdater(updatedDynakube, fake.NewClient(), nil, nil)))
+	})
+}
+
+func TestApplyUpdateWindow(t *testing.T) {
+	const (
+		oldVersion = "1.2.3.4-5"
+		oldImage   = "repo.com/oneagent:1.2.3.4-5"
+		newVersion = "2.4.5.6-7"
+		newImage   = "repo.com/oneagent:2.4.5.6-7"
+	)
+
+	updateWindow, err := updatewindow.New("0 22 * * *", 4*time.Hour, "")
+	require.NoError(t, err)
+
+	insideWindow := metav1.NewTime(time.Date(2024, 3, 4, 23, 0, 0, 0, time.UTC))
+	outsideWindow := metav1.NewTime(time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC))
+
+	previous := status.VersionStatus{
+		Source:  status.TenantRegistryVersionSource,
+		ImageID: oldImage,
+		Version: oldVersion,
+	}
+
+	newReconciler := func(now metav1.Time) (*Reconciler, versionStatusUpdater) {
+		dynakube := &dynatracev1beta1.DynaKube{
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				OneAgent: dynatracev1beta1.OneAgentSpec{
+					ClassicFullStack: &dynatracev1beta1.HostInjectSpec{},
+				},
+			},
+		}
+		dynakube.Status.OneAgent.VersionStatus = previous
+		dynakube.Status.OneAgent.ImageID = newImage
+		dynakube.Status.OneAgent.Version = newVersion
+
+		timeProvider := timeprovider.New()
+		timeProvider.Set(&now)
+		reconciler := &Reconciler{
+			dynakube:     dynakube,
+			timeProvider: timeProvider,
+			updateWindow: updateWindow,
+		}
+		return reconciler, newOneAgentUpdater(dynakube, fake.NewClient(), nil, nil)
+	}
+
+	t.Run("holds back automatic update outside of the update window", func(t *testing.T) {
+		reconciler, updater := newReconciler(outsideWindow)
+
+		reconciler.applyUpdateWindow(updater, previous, true)
+
+		target := updater.Target()
+		assert.Equal(t, oldImage, target.ImageID)
+		assert.Equal(t, oldVersion, target.Version)
+		assert.Equal(t, newImage, target.PendingImageID)
+		assert.Equal(t, newVersion, target.PendingVersion)
+		assert.True(t, target.HasPendingUpdate())
+	})
+	t.Run("applies automatic update inside of the update window", func(t *testing.T) {
+		reconciler, updater := newReconciler(insideWindow)
+		updater.Target().PendingImageID = newImage
+		updater.Target().PendingVersion = newVersion
+
+		reconciler.applyUpdateWindow(updater, previous, true)
+
+		target := updater.Target()
+		assert.Equal(t, newImage, target.ImageID)
+		assert.Equal(t, newVersion, target.Version)
+		assert.False(t, target.HasPendingUpdate())
+	})
+	t.Run("applies other changes outside of the update window", func(t *testing.T) {
+		reconciler, updater := newReconciler(outsideWindow)
+
+		reconciler.applyUpdateWindow(updater, previous, false)
+
+		target := updater.Target()
+		assert.Equal(t, newImage, target.ImageID)
+		assert.False(t, target.HasPendingUpdate())
+	})
+	t.Run("applies all changes without update window", func(t *testing.T) {
+		reconciler, updater := newReconciler(outsideWindow)
+		reconciler.updateWindow = nil
+
+		reconciler.applyUpdateWindow(updater, previous, true)
+
+		assert.Equal(t, newImage, updater.Target().ImageID)
+	})
+	t.Run("forgets pending update if the version did not change", func(t *testing.T) {
+		reconciler, updater := newReconciler(outsideWindow)
+		updater.Target().ImageID = oldImage
+		updater.Target().Version = oldVersion
+		updater.Target().PendingImageID = newImage
+
+		reconciler.applyUpdateWindow(updater, previous, true)
+
+		assert.False(t, updater.Target().HasPendingUpdate())
+	})
+	t.Run("next update window is only known for pending updates", func(t *testing.T) {
+		reconciler, updater := newReconciler(outsideWindow)
+		assert.True(t, reconciler.NextUpdateWindow().IsZero())
+
+		reconciler.applyUpdateWindow(updater, previous, true)
+
+		assert.Equal(t, time.Date(2024, 3, 4, 22, 0, 0, 0, time.UTC), reconciler.NextUpdateWindow())
+	})
+}
+
+func TestIsAutomaticUpdate(t *testing.T) {
+	dynakube := dynatracev1beta1.DynaKube{
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ClassicFullStack: &dynatracev1beta1.HostInjectSpec{},
+			},
+		},
+	}
+
+	t.Run("new version from the same source", func(t *testing.T) {
+		updatedDynakube := dynakube.DeepCopy()
+		updatedDynakube.Status.OneAgent.Source = status.TenantRegistryVersionSource
+		updatedDynakube.Status.OneAgent.Version = "1.2.3.4-5"
+		assert.True(t, isAutomaticUpdate(newOneAgentUpdater(updatedDynakube, fake.NewClient(), nil, nil)))
+	})
+	t.Run("not for the initial version", func(t *testing.T) {
+		updatedDynakube := dynakube.DeepCopy()
+		updatedDynakube.Status.OneAgent.Source = status.TenantRegistryVersionSource
+		assert.False(t, isAutomaticUpdate(newOneAgentUpdater(updatedDynakube, fake.NewClient(), nil, nil)))
+	})
+	t.Run("not if the source changed", func(t *testing.T) {
+		updatedDynakube := dynakube.DeepCopy()
+		updatedDynakube.Status.OneAgent.Source = status.PublicRegistryVersionSource
+		updatedDynakube.Status.OneAgent.Version = "1.2.3.4-5"
+		assert.False(t, isAutomaticUpdate(newOneAgentUpdater(updatedDynakube, fake.NewClient(), nil, nil)))
+	})
+	t.Run("not for custom images", func(t *testing.T) {
+		updatedDynakube := dynakube.DeepCopy()
+		updatedDynakube.Spec.OneAgent.ClassicFullStack.Image = "repo.com:tag"
+		setOneAgentCustomImageStatus(updatedDynakube, "repo.com:tag@sha256:123")
+		assert.False(t, isAutomaticUpdate(newOneAgentUpdater(updatedDynakube, fake.NewClient(), nil, nil)))
+	})
+	t.Run("not if the custom version changed", func(t *testing.T) {
+		updatedDynakube := dynakube.DeepCopy()
+		updatedDynakube.Spec.OneAgent.ClassicFullStack.Version = "2.4.5.6-7"
+		setOneAgentCustomVersionStatus(updatedDynakube, "1.2.3.4-5")
+		assert.False(t, isAutomaticUpdate(newOneAgentUpdater(updatedDynakube, fake.NewClient(), nil, nil)))
+	})
 }
 
 func TestHasCustomFieldChanged(t *testing.T) {
@@ -2,6 +2,7 @@ package version
 
 import (
 	"context"
+	"sort"
 	"strings"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
@@ -50,8 +51,7 @@ func (reconciler *Reconciler) run(ctx context.Context, updater versionStatusUpda
 
 	if !updater.IsAutoUpdateEnabled() {
 		previousSource := updater.Target().Source
-		emptyVersionStatus := status.VersionStatus{}
-		if updater.Target() == nil || *updater.Target() == emptyVersionStatus {
+		if updater.Target() == nil || updater.Target().IsEmpty() {
 			log.Info("initial status update in progress with no auto update", "updater", updater.Name())
 		} else if previousSource == currentSource {
 			log.Info("status updated skipped, due to no auto update", "updater", updater.Name())
@@ -131,6 +131,8 @@ func setImageIDWithDigest(
 		return err
 	}
 	target.Version = imageVersion.Version
+	target.Type = imageType(imageVersion)
+	target.Platforms = platformImages(imageVersion)
 
 	if digestRef, ok := ref.(name.Digest); ok {
 		target.ImageID = digestRef.String()
@@ -172,6 +174,8 @@ func updateVersionStatusForTenantRegistry(
 		}
 		target.ImageID = taggedRef.String()
 		target.Version = imageVersion.Version
+		target.Type = imageType(imageVersion)
+		target.Platforms = platformImages(imageVersion)
 	}
 
 	log.Info("updated image version info for tenant registry image",
@@ -181,6 +185,33 @@ func updateVersionStatusForTenantRegistry(
 	return nil
 }
 
+// imageType only keeps the type of code modules artifacts, the CSI driver unpacks them differently than images
+func imageType(imageVersion registry.ImageVersion) string {
+	if imageVersion.Type == status.ArtifactImageType {
+		return status.ArtifactImageType
+	}
+	return ""
+}
+
+// platformImages lists the per architecture digests of a multi-arch image in a stable order, so the status doesn't change on every probe
+func platformImages(imageVersion registry.ImageVersion) []status.PlatformImage {
+	if len(imageVersion.Platforms) == 0 {
+		return nil
+	}
+
+	platforms := make([]status.PlatformImage, 0, len(imageVersion.Platforms))
+	for architecture, digest := range imageVersion.Platforms {
+		platforms = append(platforms, status.PlatformImage{
+			Architecture: architecture,
+			Digest:       digest.String(),
+		})
+	}
+	sort.Slice(platforms, func(i, j int) bool {
+		return platforms[i].Architecture < platforms[j].Architecture
+	})
+	return platforms
+}
+
 func getTagFromImageID(imageID string) (string, error) {
 	ref, err := name.ParseReference(imageID, name.WithDefaultTag(""))
 	if err != nil {
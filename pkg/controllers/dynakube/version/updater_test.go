@@ -361,6 +361,38 @@ func TestUpdateVersionStatus(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, expectedID, target.ImageID)
 	})
+	t.Run("set type of code modules artifact", func(t *testing.T) {
+		target := status.VersionStatus{}
+		mockImageGetter := mocks.MockImageGetter{}
+		mockImageGetter.On("GetImageVersion", mock.Anything, mock.Anything).Return(registry.ImageVersion{
+			Version: testImage.Tag,
+			Type:    status.ArtifactImageType,
+		}, nil)
+		err := setImageIDWithDigest(ctx, &target, &mockImageGetter, testImage.String())
+		require.NoError(t, err)
+		assert.Equal(t, status.ArtifactImageType, target.Type)
+	})
+	t.Run("set platform digests of multi-arch image", func(t *testing.T) {
+		amdDigest := "sha256:7ece13a07a20c77a31cc36906a10ebc90bd47970905ee61e8ed491b7f4c5d62f"
+		armDigest := "sha256:8ece13a07a20c77a31cc36906a10ebc90bd47970905ee61e8ed491b7f4c5d62f"
+		target := status.VersionStatus{}
+		mockImageGetter := mocks.MockImageGetter{}
+		mockImageGetter.On("GetImageVersion", mock.Anything, mock.Anything).Return(registry.ImageVersion{
+			Version: testImage.Tag,
+			Platforms: map[string]digest.Digest{
+				"arm64": digest.Digest(armDigest),
+				"amd64": digest.Digest(amdDigest),
+			},
+		}, nil)
+		err := setImageIDWithDigest(ctx, &target, &mockImageGetter, testImage.String())
+		require.NoError(t, err)
+		assert.Equal(t, []status.PlatformImage{
+			{Architecture: "amd64", Digest: amdDigest},
+			{Architecture: "arm64", Digest: armDigest},
+		}, target.Platforms)
+		assert.Equal(t, "some.registry.com:1.2.3.4-5@"+armDigest, target.ImageIDForArch("arm64"))
+		assert.Equal(t, target.ImageID, target.ImageIDForArch("s390x"))
+	})
 	t.Run("providing it with digest still requires registry access", func(t *testing.T) {
 		expectedRepo := "some.registry.com/image"
 		expectedDigest := "sha256:7ece13a07a20c77a31cc36906a10ebc90bd47970905ee61e8ed491b7f4c5d62f"
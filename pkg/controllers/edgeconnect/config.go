@@ -4,6 +4,8 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/logger"
 )
 
+const controllerName = "edgeconnect"
+
 var (
 	log = logger.Factory.GetLogger("edgeconnect")
 )
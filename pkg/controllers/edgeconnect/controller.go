@@ -7,6 +7,7 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	edgeconnectv1alpha1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha1/edgeconnect"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/edgeconnect/deployment"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/edgeconnect/version"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
@@ -61,7 +62,7 @@ func (controller *Controller) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&edgeconnectv1alpha1.EdgeConnect{}).
 		Owns(&appsv1.Deployment{}).
-		Complete(controller)
+		Complete(controllers.WithReconcileMetrics(controllerName, controller))
 }
 
 func (controller *Controller) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
@@ -118,7 +119,13 @@ func (controller *Controller) Reconcile(ctx context.Context, request reconcile.R
 
 	log.Info("reconciling EdgeConnect done", "name", request.Name, "namespace", request.Namespace)
 
-	return reconcile.Result{RequeueAfter: defaultUpdateInterval}, err
+	requeueAfter := defaultUpdateInterval
+	if nextUpdateWindow := versionReconciler.NextUpdateWindow(); !nextUpdateWindow.IsZero() && time.Until(nextUpdateWindow) < requeueAfter {
+		// held back images are rolled out as soon as the update window opens
+		requeueAfter = time.Until(nextUpdateWindow)
+	}
+
+	return reconcile.Result{RequeueAfter: requeueAfter}, err
 }
 
 func (controller *Controller) getEdgeConnect(ctx context.Context, name, namespace string) (*edgeconnectv1alpha1.EdgeConnect, error) {
@@ -2,10 +2,12 @@ package version
 
 import (
 	"context"
+	"time"
 
 	edgeconnectv1alpha1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha1/edgeconnect"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -27,8 +29,16 @@ func NewReconciler(apiReader client.Reader, registryClient registry.ImageGetter,
 }
 
 func (reconciler *Reconciler) Reconcile(ctx context.Context) error {
+	updateWindow, err := reconciler.edgeConnect.UpdateWindow()
+	if err != nil {
+		return errors.WithMessage(err, "invalid update window")
+	}
+
+	edgeConnectUpdater := newUpdater(reconciler.apiReader, reconciler.timeProvider, reconciler.registryClient, reconciler.edgeConnect)
+	edgeConnectUpdater.updateWindow = updateWindow
+
 	updaters := []versionStatusUpdater{
-		newUpdater(reconciler.apiReader, reconciler.timeProvider, reconciler.registryClient, reconciler.edgeConnect),
+		edgeConnectUpdater,
 	}
 
 	for _, updater := range updaters {
@@ -43,3 +53,16 @@ func (reconciler *Reconciler) Reconcile(ctx context.Context) error {
 
 	return nil
 }
+
+// NextUpdateWindow returns when the next update window opens if a newer image is held back, otherwise it is zero
+func (reconciler *Reconciler) NextUpdateWindow() time.Time {
+	if !reconciler.edgeConnect.Status.Version.HasPendingUpdate() {
+		return time.Time{}
+	}
+
+	updateWindow, err := reconciler.edgeConnect.UpdateWindow()
+	if err != nil {
+		return time.Time{}
+	}
+	return updateWindow.NextOpening(reconciler.timeProvider.Now().Time)
+}
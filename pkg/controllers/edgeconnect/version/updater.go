@@ -9,6 +9,7 @@ import (
 	edgeconnectv1alpha1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha1/edgeconnect"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/updatewindow"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
@@ -20,6 +21,7 @@ type updater struct {
 	apiReader      client.Reader
 	timeProvider   *timeprovider.Provider
 	registryClient registry.ImageGetter
+	updateWindow   *updatewindow.Window
 }
 
 var _ versionStatusUpdater = updater{}
@@ -47,6 +49,9 @@ func (u updater) RequiresReconcile() bool {
 	if didCustomImageChange || version.ImageID == "" {
 		return true
 	}
+	if version.HasPendingUpdate() && u.updateWindow.IsOpen(u.timeProvider.Now().Time) {
+		return true
+	}
 	return isRequestOutdated && u.IsAutoUpdateEnabled()
 }
 
@@ -59,6 +64,9 @@ func (u updater) Update(ctx context.Context) error {
 	}()
 
 	image := u.edgeConnect.Image()
+	previous := *u.Target()
+	// a new digest for the same image is an automatic update, the initial image and changes of the image are always applied
+	isAutomaticUpdate := previous.ImageID != "" && strings.HasPrefix(previous.ImageID, image)
 
 	imageVersion, err := u.registryClient.GetImageVersion(ctx, image)
 	if err != nil {
@@ -78,6 +86,13 @@ func (u updater) Update(ctx context.Context) error {
 		target.Source = status.PublicRegistryVersionSource
 	}
 
+	if isAutomaticUpdate && target.ImageID != previous.ImageID && !u.updateWindow.IsOpen(u.timeProvider.Now().Time) {
+		log.Info("update window is closed, holding back new image", "updater", u.Name(), "imageID", target.ImageID)
+		target.HoldBackUpdate(previous)
+	} else {
+		target.ClearPendingUpdate()
+	}
+
 	return nil
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry/mocks"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/updatewindow"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -47,6 +48,72 @@ func TestReconcile(t *testing.T) {
 	require.True(t, strings.Contains(edgeConnect.Status.Version.ImageID, fakeDigest))
 }
 
+func TestUpdateWindow(t *testing.T) {
+	const newDigest = "sha256:8173b809ca12ec5dee4506cd86be934c4596dd234ee82c0662eac04a8c2c71dc"
+
+	updateWindow, err := updatewindow.New("0 22 * * *", 4*time.Hour, "")
+	require.NoError(t, err)
+
+	insideWindow := metav1.NewTime(time.Date(2024, 3, 4, 23, 0, 0, 0, time.UTC))
+	outsideWindow := metav1.NewTime(time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC))
+	oldImageID := fmt.Sprintf("docker.io/dynatrace/edgeconnect:latest@%s", fakeDigest)
+	newImageID := fmt.Sprintf("docker.io/dynatrace/edgeconnect:latest@%s", newDigest)
+
+	fakeRegistryClient := &mocks.MockImageGetter{}
+	fakeRegistryClient.On("GetImageVersion", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(registry.ImageVersion{Digest: newDigest}, nil)
+
+	newWindowUpdater := func(now metav1.Time, edgeConnect *edgeconnectv1alpha1.EdgeConnect) *updater {
+		timeProvider := timeprovider.New()
+		timeProvider.Set(&now)
+		windowUpdater := newUpdater(fake.NewClient(), timeProvider, fakeRegistryClient, edgeConnect)
+		windowUpdater.updateWindow = updateWindow
+		return windowUpdater
+	}
+
+	t.Run("holds back new image outside of the update window", func(t *testing.T) {
+		edgeConnect := createBasicEdgeConnect()
+		edgeConnect.Status.Version.ImageID = oldImageID
+
+		err := newWindowUpdater(outsideWindow, edgeConnect).Update(context.TODO())
+		require.NoError(t, err)
+
+		assert.Equal(t, oldImageID, edgeConnect.Status.Version.ImageID)
+		assert.Equal(t, newImageID, edgeConnect.Status.Version.PendingImageID)
+		assert.Equal(t, outsideWindow, *edgeConnect.Status.Version.LastProbeTimestamp)
+	})
+	t.Run("applies new image inside of the update window", func(t *testing.T) {
+		edgeConnect := createBasicEdgeConnect()
+		edgeConnect.Status.Version.ImageID = oldImageID
+		edgeConnect.Status.Version.PendingImageID = newImageID
+
+		err := newWindowUpdater(insideWindow, edgeConnect).Update(context.TODO())
+		require.NoError(t, err)
+
+		assert.Equal(t, newImageID, edgeConnect.Status.Version.ImageID)
+		assert.False(t, edgeConnect.Status.Version.HasPendingUpdate())
+	})
+	t.Run("applies initial image outside of the update window", func(t *testing.T) {
+		edgeConnect := createBasicEdgeConnect()
+
+		err := newWindowUpdater(outsideWindow, edgeConnect).Update(context.TODO())
+		require.NoError(t, err)
+
+		assert.Equal(t, newImageID, edgeConnect.Status.Version.ImageID)
+		assert.False(t, edgeConnect.Status.Version.HasPendingUpdate())
+	})
+	t.Run("reconcile when the update window opened with a pending image", func(t *testing.T) {
+		edgeConnect := createBasicEdgeConnect()
+		edgeConnect.Status.Version.ImageID = oldImageID
+		edgeConnect.Status.Version.PendingImageID = newImageID
+		edgeConnect.Status.Version.LastProbeTimestamp = &insideWindow
+
+		assert.True(t, newWindowUpdater(insideWindow, edgeConnect).RequiresReconcile())
+
+		edgeConnect.Status.Version.LastProbeTimestamp = &outsideWindow
+		assert.False(t, newWindowUpdater(outsideWindow, edgeConnect).RequiresReconcile())
+	})
+}
+
 func TestCombineImagesWithDigest(t *testing.T) {
 	edgeConnect := createBasicEdgeConnect()
 	fakeRegistryClient := &mocks.MockImageGetter{}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	reconcileDurationMetric = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "dynatrace",
+		Subsystem: "operator",
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of the reconciliations by controller and reconciler",
+		// reconciliations downloading agents take minutes
+		Buckets: prometheus.ExponentialBuckets(0.01, 3, 10),
+	}, []string{"controller", "reconciler"})
+
+	reconcileErrorsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "operator",
+		Name:      "reconcile_errors_total",
+		Help:      "Number of failed reconciliations by controller and reconciler",
+	}, []string{"controller", "reconciler"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileDurationMetric)
+	metrics.Registry.MustRegister(reconcileErrorsMetric)
+}
+
+// ObserveReconcile records the duration of a reconciler run that started at the given time, it is counted as failed if err is set.
+// The whole reconciliation of a controller uses the name of the controller as reconciler.
+func ObserveReconcile(controller, reconciler string, start time.Time, err error) {
+	reconcileDurationMetric.WithLabelValues(controller, reconciler).Observe(time.Since(start).Seconds())
+	if err != nil {
+		reconcileErrorsMetric.WithLabelValues(controller, reconciler).Inc()
+	}
+}
+
+type observedReconciler struct {
+	reconciler reconcile.Reconciler
+	controller string
+}
+
+// WithReconcileMetrics observes every reconciliation of the controller, see ObserveReconcile
+func WithReconcileMetrics(controller string, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return observedReconciler{
+		reconciler: reconciler,
+		controller: controller,
+	}
+}
+
+func (observed observedReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	result, err := observed.reconciler.Reconcile(ctx, request)
+	ObserveReconcile(observed.controller, observed.controller, start, err)
+	return result, err
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type reconcilerFunc func(context.Context, reconcile.Request) (reconcile.Result, error)
+
+func (function reconcilerFunc) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	return function(ctx, request)
+}
+
+func TestWithReconcileMetrics(t *testing.T) {
+	t.Run("failed reconciliations are counted", func(t *testing.T) {
+		reconciler := WithReconcileMetrics("failing", reconcilerFunc(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, errors.New("failed")
+		}))
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{})
+		require.Error(t, err)
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(reconcileErrorsMetric.WithLabelValues("failing", "failing")))
+	})
+	t.Run("results are passed through", func(t *testing.T) {
+		reconciler := WithReconcileMetrics("succeeding", reconcilerFunc(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{Requeue: true}, nil
+		}))
+
+		result, err := reconciler.Reconcile(context.Background(), reconcile.Request{})
+		require.NoError(t, err)
+
+		assert.True(t, result.Requeue)
+		assert.Equal(t, float64(0), testutil.ToFloat64(reconcileErrorsMetric.WithLabelValues("succeeding", "succeeding")))
+	})
+}
@@ -2,6 +2,8 @@ package nodes
 
 import (
 	"encoding/json"
+	"fmt"
+	"hash/fnv"
 	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
@@ -21,20 +23,56 @@ type CacheEntry struct {
 }
 
 // Cache manages information about Nodes.
+// The entries are split into shards, each stored in its own ConfigMap, so the ConfigMaps stay small in large clusters
+// and a change of a Node only updates the shard of the Node.
 type Cache struct {
-	Obj          *corev1.ConfigMap
-	Create       bool
-	upd          bool
+	shards []*cacheShard
+	// legacy is the single ConfigMap used by older versions, it is deleted once its entries are stored in the shards
+	legacy       *corev1.ConfigMap
 	timeProvider *timeprovider.Provider
 }
 
+// cacheShard is one ConfigMap of the Cache, it remembers its changes, so they can be applied again on a conflict
+type cacheShard struct {
+	Obj    *corev1.ConfigMap
+	Create bool
+
+	// changes contains the raw entries set since the shard was read, nil for deleted entries
+	changes          map[string]*string
+	timestampChanged bool
+}
+
+func newCache(shards []*cacheShard, timeProvider *timeprovider.Provider) *Cache {
+	return &Cache{shards: shards, timeProvider: timeProvider}
+}
+
+func newCacheShard(obj *corev1.ConfigMap, create bool) *cacheShard {
+	return &cacheShard{Obj: obj, Create: create, changes: map[string]*string{}}
+}
+
+func cacheShardName(index int) string {
+	return fmt.Sprintf("%s-%d", cacheName, index)
+}
+
+func (cache *Cache) shardFor(node string) *cacheShard {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(node))
+	return cache.shards[hash.Sum32()%uint32(len(cache.shards))]
+}
+
+// timestampShard holds the timestamp of the last cleanup of the cache
+func (cache *Cache) timestampShard() *cacheShard {
+	return cache.shards[0]
+}
+
 // Get returns the information about node, or error if not found or failed to unmarshall the data.
 func (cache *Cache) Get(node string) (CacheEntry, error) {
-	if cache.Obj.Data == nil {
+	shard := cache.shardFor(node)
+	if shard.Obj.Data == nil {
 		return CacheEntry{}, ErrNotFound
 	}
 
-	raw, ok := cache.Obj.Data[node]
+	raw, ok := shard.Obj.Data[node]
 	if !ok {
 		return CacheEntry{}, ErrNotFound
 	}
@@ -53,42 +91,54 @@ func (cache *Cache) Set(node string, entry CacheEntry) error {
 	if err != nil {
 		return err
 	}
-	if cache.Obj.Data == nil {
-		cache.Obj.Data = map[string]string{}
+	shard := cache.shardFor(node)
+	if shard.Obj.Data == nil {
+		shard.Obj.Data = map[string]string{}
 	}
-	cache.Obj.Data[node] = string(raw)
-	cache.upd = true
+	value := string(raw)
+	if current, ok := shard.Obj.Data[node]; ok && current == value {
+		return nil
+	}
+	shard.Obj.Data[node] = value
+	shard.changes[node] = &value
 	return nil
 }
 
 // Delete removes the node from the cache.
 func (cache *Cache) Delete(node string) {
-	if cache.Obj.Data != nil {
-		delete(cache.Obj.Data, node)
-		cache.upd = true
+	shard := cache.shardFor(node)
+	if _, ok := shard.Obj.Data[node]; ok {
+		delete(shard.Obj.Data, node)
+		shard.changes[node] = nil
 	}
 }
 
 // Keys returns a list of node names on the cache.
 func (cache *Cache) Keys() []string {
-	if cache.Obj.Data == nil {
-		return []string{}
-	}
-
-	out := make([]string, 0, len(cache.Obj.Data))
-	for k := range cache.Obj.Data {
-		out = append(out, k)
+	out := make([]string, 0)
+	for _, shard := range cache.shards {
+		for k := range shard.Obj.Data {
+			out = append(out, k)
+		}
 	}
 	return out
 }
 
 // Changed returns true if changes have been made to the cache instance.
 func (cache *Cache) Changed() bool {
-	return cache.Create || cache.upd
+	if cache.legacy != nil {
+		return true
+	}
+	for _, shard := range cache.shards {
+		if shard.changed() {
+			return true
+		}
+	}
+	return false
 }
 
 func (cache *Cache) IsCacheOutdated() bool {
-	if lastUpdated, ok := cache.Obj.Annotations[lastUpdatedCacheAnnotation]; ok {
+	if lastUpdated, ok := cache.timestampShard().Obj.Annotations[lastUpdatedCacheAnnotation]; ok {
 		if lastUpdatedTime, err := time.Parse(time.RFC3339, lastUpdated); err == nil {
 			return lastUpdatedTime.Add(cacheLifetime).Before(cache.timeProvider.Now().UTC())
 		} else {
@@ -99,14 +149,60 @@ func (cache *Cache) IsCacheOutdated() bool {
 }
 
 func (cache *Cache) UpdateTimestamp() {
-	if cache.Obj.Annotations == nil {
-		cache.Obj.Annotations = make(map[string]string)
+	shard := cache.timestampShard()
+	if shard.Obj.Annotations == nil {
+		shard.Obj.Annotations = make(map[string]string)
 	}
-	cache.Obj.Annotations[lastUpdatedCacheAnnotation] = cache.timeProvider.Now().Format(time.RFC3339)
-	cache.upd = true
+	shard.Obj.Annotations[lastUpdatedCacheAnnotation] = cache.timeProvider.Now().Format(time.RFC3339)
+	shard.timestampChanged = true
 }
 
 func (cache *Cache) updateLastMarkedForTerminationTimestamp(nodeInfo CacheEntry, nodeName string) error {
 	nodeInfo.LastMarkedForTermination = cache.timeProvider.Now().UTC()
 	return cache.Set(nodeName, nodeInfo)
 }
+
+// migrate moves the entries of the ConfigMap of older versions into the shards, entries already in the shards are kept
+func (cache *Cache) migrate(legacy *corev1.ConfigMap) error {
+	for node, raw := range legacy.Data {
+		if _, err := cache.Get(node); err == nil {
+			continue
+		}
+		var entry CacheEntry
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			log.Info("dropping invalid node cache entry during migration", "node", node, "error", err.Error())
+			continue
+		}
+		if err := cache.Set(node, entry); err != nil {
+			return err
+		}
+	}
+	cache.legacy = legacy
+	return nil
+}
+
+// changed is false for new shards without entries, they are only created once an entry is stored in them
+func (shard *cacheShard) changed() bool {
+	return len(shard.changes) > 0 || shard.timestampChanged
+}
+
+// applyTo applies the changes of the shard to a newer version of its ConfigMap
+func (shard *cacheShard) applyTo(obj *corev1.ConfigMap) {
+	if obj.Data == nil {
+		obj.Data = map[string]string{}
+	}
+	for node, raw := range shard.changes {
+		if raw == nil {
+			delete(obj.Data, node)
+			continue
+		}
+		obj.Data[node] = *raw
+	}
+
+	if shard.timestampChanged {
+		if obj.Annotations == nil {
+			obj.Annotations = make(map[string]string)
+		}
+		obj.Annotations[lastUpdatedCacheAnnotation] = shard.Obj.Annotations[lastUpdatedCacheAnnotation]
+	}
+}
@@ -4,30 +4,30 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestCache(t *testing.T) {
 	t.Run("get non-existing key", func(t *testing.T) {
-		cm := corev1.ConfigMap{}
-		nodesCache := &Cache{Obj: &cm}
+		nodesCache := newTestCache()
 		_, err := nodesCache.Get("node1")
 		assert.Error(t, ErrNotFound, err)
 	})
 
 	t.Run("get non json key", func(t *testing.T) {
-		cm := corev1.ConfigMap{Data: map[string]string{"node1": "non-json-key"}}
-		nodesCache := &Cache{Obj: &cm}
+		nodesCache := newTestCache()
+		nodesCache.shardFor("node1").Obj.Data = map[string]string{"node1": "non-json-key"}
 		_, err := nodesCache.Get("node1")
 		syntaxErr := &json.SyntaxError{}
 		assert.Error(t, syntaxErr, err)
 	})
 
 	t.Run("set cache key if configmap data nil", func(t *testing.T) {
-		cm := corev1.ConfigMap{}
-		nodesCache := &Cache{Obj: &cm}
+		nodesCache := newTestCache()
 		err := nodesCache.Set("node1", CacheEntry{
 			Instance:  "dynakube",
 			IPAddress: "10.128.0.48",
@@ -41,18 +41,80 @@ func TestCache(t *testing.T) {
 			Instance:  "dynakube",
 			IPAddress: "10.128.0.48",
 		}, entry)
+		assert.True(t, nodesCache.Changed())
+	})
+
+	t.Run("setting the same entry is no change", func(t *testing.T) {
+		nodesCache := newTestCache()
+		require.NoError(t, nodesCache.Set("node1", CacheEntry{Instance: "dynakube"}))
+		nodesCache.shardFor("node1").changes = map[string]*string{}
+
+		require.NoError(t, nodesCache.Set("node1", CacheEntry{Instance: "dynakube"}))
+
+		assert.False(t, nodesCache.Changed())
 	})
 
 	t.Run("get all cache keys if configmap data nil", func(t *testing.T) {
-		cm := corev1.ConfigMap{}
-		nodesCache := &Cache{Obj: &cm}
+		nodesCache := newTestCache()
 		keys := nodesCache.Keys()
 		assert.Equal(t, []string{}, keys)
 	})
 
+	t.Run("keys of all shards", func(t *testing.T) {
+		nodesCache := newTestCache()
+		for _, node := range []string{"node1", "node2", "node3", "node4"} {
+			require.NoError(t, nodesCache.Set(node, CacheEntry{}))
+		}
+
+		assert.ElementsMatch(t, []string{"node1", "node2", "node3", "node4"}, nodesCache.Keys())
+	})
+
 	t.Run("check if cache is not outdated", func(t *testing.T) {
-		cm := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{lastUpdatedCacheAnnotation: ""}}}
-		nodesCache := &Cache{Obj: &cm}
+		nodesCache := newTestCache()
+		nodesCache.timestampShard().Obj.Annotations = map[string]string{lastUpdatedCacheAnnotation: ""}
 		assert.Equal(t, false, nodesCache.IsCacheOutdated())
 	})
+
+	t.Run("changes are applied to a newer version of the shard", func(t *testing.T) {
+		nodesCache := newTestCache()
+		require.NoError(t, nodesCache.Set("node1", CacheEntry{Instance: "dynakube"}))
+		nodesCache.UpdateTimestamp()
+		shard := nodesCache.shardFor("node1")
+		newer := corev1.ConfigMap{Data: map[string]string{"node1": "outdated", "other": "kept"}}
+
+		shard.applyTo(&newer)
+
+		assert.Equal(t, shard.Obj.Data["node1"], newer.Data["node1"])
+		assert.Equal(t, "kept", newer.Data["other"])
+		if shard == nodesCache.timestampShard() {
+			assert.NotEmpty(t, newer.Annotations[lastUpdatedCacheAnnotation])
+		}
+	})
+
+	t.Run("migrate keeps the entries of the shards", func(t *testing.T) {
+		nodesCache := newTestCache()
+		require.NoError(t, nodesCache.Set("node1", CacheEntry{Instance: "current"}))
+		legacy := &corev1.ConfigMap{Data: map[string]string{
+			"node1": `{"instance":"legacy"}`,
+			"node2": `{"instance":"legacy"}`,
+			"node3": "invalid",
+		}}
+
+		require.NoError(t, nodesCache.migrate(legacy))
+
+		node1, _ := nodesCache.Get("node1")
+		node2, _ := nodesCache.Get("node2")
+		assert.Equal(t, "current", node1.Instance)
+		assert.Equal(t, "legacy", node2.Instance)
+		assert.ElementsMatch(t, []string{"node1", "node2"}, nodesCache.Keys())
+		assert.Equal(t, legacy, nodesCache.legacy)
+	})
+}
+
+func newTestCache() *Cache {
+	shards := make([]*cacheShard, cacheShardCount)
+	for i := range shards {
+		shards[i] = newCacheShard(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: cacheShardName(i)}}, true)
+	}
+	return newCache(shards, timeprovider.New())
 }
@@ -7,12 +7,16 @@ import (
 )
 
 const (
+	controllerName = "nodes"
+
 	cacheName                  = "dynatrace-node-cache"
+	cacheShardCount            = 16
+	cacheComponentLabel        = "node-cache"
 	cacheLifetime              = 10 * time.Minute
+	lastSeenRefreshInterval    = 5 * time.Minute
 	lastUpdatedCacheAnnotation = "DTOperatorLastUpdated"
 )
 
 var (
-	log                 = logger.Factory.GetLogger("nodes")
-	unschedulableTaints = []string{"ToBeDeletedByClusterAutoscaler"}
+	log = logger.Factory.GetLogger("nodes")
 )
@@ -0,0 +1,138 @@
+package nodes
+
+import (
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	lifecycleScaleDown        = "SCALE_DOWN"
+	lifecycleSpotInterruption = "SPOT_INTERRUPTION"
+	lifecycleDrain            = "DRAIN"
+	lifecycleDeletion         = "DELETION"
+	lifecycleRecovery         = "RECOVERY"
+
+	lifecycleProperty    = "dt.kubernetes.node.lifecycle"
+	nodeNameProperty     = "dt.kubernetes.node.name"
+	zoneProperty         = "dt.kubernetes.node.zone"
+	instanceTypeProperty = "dt.kubernetes.node.instance_type"
+	nodePoolProperty     = "dt.kubernetes.node.pool"
+	capacityTypeProperty = "dt.kubernetes.node.capacity_type"
+)
+
+// lifecycleEvent describes a change of a node, which is sent as event to the host of the node.
+// All terminating changes are sent as MARKED_FOR_TERMINATION, as it is the only event type suppressing the
+// host unavailable problem, they are distinguished by the lifecycle property instead.
+type lifecycleEvent struct {
+	lifecycle   string
+	eventType   string
+	description string
+}
+
+var (
+	scaleDownEvent = lifecycleEvent{
+		lifecycle:   lifecycleScaleDown,
+		eventType:   dtclient.MarkedForTerminationEvent,
+		description: "Kubernetes node is removed by the cluster autoscaler.",
+	}
+	spotInterruptionEvent = lifecycleEvent{
+		lifecycle:   lifecycleSpotInterruption,
+		eventType:   dtclient.MarkedForTerminationEvent,
+		description: "Kubernetes node is reclaimed by the cloud provider, spot or preemptible instance is interrupted.",
+	}
+	drainEvent = lifecycleEvent{
+		lifecycle:   lifecycleDrain,
+		eventType:   dtclient.MarkedForTerminationEvent,
+		description: "Kubernetes node cordoned. Node might be drained or terminated.",
+	}
+	deletionEvent = lifecycleEvent{
+		lifecycle:   lifecycleDeletion,
+		eventType:   dtclient.MarkedForTerminationEvent,
+		description: "Kubernetes node deleted. Node is terminated.",
+	}
+	recoveryEvent = lifecycleEvent{
+		lifecycle:   lifecycleRecovery,
+		eventType:   dtclient.CustomInfoEvent,
+		description: "Kubernetes node uncordoned. Node is schedulable again.",
+	}
+)
+
+var (
+	// scaleDownTaints are set by autoscalers on nodes they are about to remove
+	scaleDownTaints = []string{
+		"ToBeDeletedByClusterAutoscaler",
+		"karpenter.sh/disruption",
+		"karpenter.sh/disrupted",
+	}
+
+	// spotInterruptionTaints are set by the cloud providers, or their termination handlers, on nodes which are reclaimed
+	spotInterruptionTaints = []string{
+		"aws-node-termination-handler/spot-itn",
+		"aws-node-termination-handler/rebalance-recommendation",
+		"aws-node-termination-handler/asg-lifecycle-termination",
+		"cloud.google.com/impending-node-termination",
+	}
+
+	zoneLabels         = []string{corev1.LabelTopologyZone, corev1.LabelFailureDomainBetaZone}
+	instanceTypeLabels = []string{corev1.LabelInstanceTypeStable, corev1.LabelInstanceType}
+	nodePoolLabels     = []string{
+		"cloud.google.com/gke-nodepool",
+		"eks.amazonaws.com/nodegroup",
+		"kubernetes.azure.com/agentpool",
+		"karpenter.sh/nodepool",
+		"karpenter.sh/provisioner-name",
+	}
+	capacityTypeLabels = []string{
+		"karpenter.sh/capacity-type",
+		"eks.amazonaws.com/capacityType",
+		"kubernetes.azure.com/scalesetpriority",
+	}
+)
+
+// lifecycleEventFor determines why the node doesn't accept pods anymore, it returns nil for schedulable nodes
+func lifecycleEventFor(node *corev1.Node) *lifecycleEvent {
+	switch {
+	case hasTaint(node, spotInterruptionTaints):
+		return &spotInterruptionEvent
+	case hasTaint(node, scaleDownTaints):
+		return &scaleDownEvent
+	case node.Spec.Unschedulable:
+		return &drainEvent
+	}
+	return nil
+}
+
+func hasTaint(node *corev1.Node, keys []string) bool {
+	for _, taint := range node.Spec.Taints {
+		for _, key := range keys {
+			if taint.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nodeProperties are added to the lifecycle events, so the reason of a host change can be found without access to the cluster
+func nodeProperties(nodeName string, node *corev1.Node) map[string]string {
+	properties := map[string]string{nodeNameProperty: nodeName}
+	if node == nil {
+		return properties
+	}
+
+	addLabelProperty(properties, zoneProperty, node.Labels, zoneLabels)
+	addLabelProperty(properties, instanceTypeProperty, node.Labels, instanceTypeLabels)
+	addLabelProperty(properties, nodePoolProperty, node.Labels, nodePoolLabels)
+	addLabelProperty(properties, capacityTypeProperty, node.Labels, capacityTypeLabels)
+	return properties
+}
+
+// addLabelProperty uses the first of the labels set on the node, the clouds use different labels for the same information
+func addLabelProperty(properties map[string]string, property string, nodeLabels map[string]string, labels []string) {
+	for _, label := range labels {
+		if value, ok := nodeLabels[label]; ok && value != "" {
+			properties[property] = value
+			return
+		}
+	}
+}
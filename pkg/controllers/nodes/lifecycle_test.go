@@ -0,0 +1,64 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLifecycleEventFor(t *testing.T) {
+	t.Run("schedulable node", func(t *testing.T) {
+		assert.Nil(t, lifecycleEventFor(&corev1.Node{}))
+	})
+	t.Run("cordoned node is drained", func(t *testing.T) {
+		node := &corev1.Node{Spec: corev1.NodeSpec{Unschedulable: true}}
+
+		assert.Equal(t, &drainEvent, lifecycleEventFor(node))
+	})
+	t.Run("cluster autoscaler scale-down", func(t *testing.T) {
+		node := &corev1.Node{Spec: corev1.NodeSpec{
+			Unschedulable: true,
+			Taints:        []corev1.Taint{{Key: "ToBeDeletedByClusterAutoscaler"}},
+		}}
+
+		assert.Equal(t, &scaleDownEvent, lifecycleEventFor(node))
+	})
+	t.Run("spot interruption takes precedence", func(t *testing.T) {
+		node := &corev1.Node{Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{{Key: "ToBeDeletedByClusterAutoscaler"}, {Key: "cloud.google.com/impending-node-termination"}},
+		}}
+
+		assert.Equal(t, &spotInterruptionEvent, lifecycleEventFor(node))
+	})
+	t.Run("unrelated taints are ignored", func(t *testing.T) {
+		node := &corev1.Node{Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{{Key: "node-role.kubernetes.io/control-plane"}},
+		}}
+
+		assert.Nil(t, lifecycleEventFor(node))
+	})
+}
+
+func TestNodeProperties(t *testing.T) {
+	t.Run("labels of the node", func(t *testing.T) {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			corev1.LabelTopologyZone:         "eu-west-1a",
+			corev1.LabelInstanceTypeStable:   "m5.large",
+			"eks.amazonaws.com/nodegroup":    "workers",
+			"eks.amazonaws.com/capacityType": "SPOT",
+		}}}
+
+		assert.Equal(t, map[string]string{
+			nodeNameProperty:     "node1",
+			zoneProperty:         "eu-west-1a",
+			instanceTypeProperty: "m5.large",
+			nodePoolProperty:     "workers",
+			capacityTypeProperty: "SPOT",
+		}, nodeProperties("node1", node))
+	})
+	t.Run("deleted node", func(t *testing.T) {
+		assert.Equal(t, map[string]string{nodeNameProperty: "node1"}, nodeProperties("node1", nil))
+	})
+}
@@ -7,16 +7,19 @@ import (
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubesystem"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -47,7 +50,7 @@ func Add(mgr manager.Manager, _ string) error {
 func (controller *Controller) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Node{}).
-		Complete(controller)
+		Complete(controllers.WithReconcileMetrics(controllerName, controller))
 }
 
 func NewController(mgr manager.Manager) *Controller {
@@ -95,21 +98,30 @@ func (controller *Controller) Reconcile(ctx context.Context, request reconcile.R
 
 		if cached, err := nodeCache.Get(nodeName); err == nil {
 			cacheEntry.LastMarkedForTermination = cached.LastMarkedForTermination
+			// nodes are updated frequently, refreshing LastSeen on every update would rewrite the cache every time
+			if cached.Instance == cacheEntry.Instance && cached.IPAddress == cacheEntry.IPAddress &&
+				cacheEntry.LastSeen.Sub(cached.LastSeen) < lastSeenRefreshInterval {
+				cacheEntry.LastSeen = cached.LastSeen
+			}
 		}
 
 		if err := nodeCache.Set(nodeName, cacheEntry); err != nil {
 			return reconcile.Result{}, err
 		}
 
-		// Handle unschedulable Nodes, if they have a OneAgent instance
-		if controller.isUnschedulable(&node) {
-			cachedNodeData := CachedNodeInfo{
-				cachedNode: cacheEntry,
-				nodeCache:  nodeCache,
-				nodeName:   nodeName,
-			}
+		cachedNodeData := CachedNodeInfo{
+			cachedNode: cacheEntry,
+			nodeCache:  nodeCache,
+			nodeName:   nodeName,
+		}
 
-			if err := controller.markForTermination(dynakube, cachedNodeData); err != nil {
+		// Handle Nodes not accepting pods anymore, or accepting them again, if they have a OneAgent instance
+		if lifecycle := lifecycleEventFor(&node); lifecycle != nil {
+			if err := controller.markForTermination(dynakube, cachedNodeData, *lifecycle, &node); err != nil {
+				return reconcile.Result{}, err
+			}
+		} else if !cacheEntry.LastMarkedForTermination.IsZero() {
+			if err := controller.markRecovered(dynakube, cachedNodeData, &node); err != nil {
 				return reconcile.Result{}, err
 			}
 		}
@@ -153,7 +165,7 @@ func (controller *Controller) reconcileNodeDeletion(ctx context.Context, nodeNam
 			nodeName:   nodeName,
 		}
 
-		if err := controller.markForTermination(dynakube, cachedNodeData); err != nil {
+		if err := controller.markForTermination(dynakube, cachedNodeData, deletionEvent, nil); err != nil {
 			return err
 		}
 	}
@@ -165,55 +177,133 @@ func (controller *Controller) reconcileNodeDeletion(ctx context.Context, nodeNam
 	return nil
 }
 
+// getCache reads all shards of the cache, the ConfigMap of older versions is migrated into them
 func (controller *Controller) getCache(ctx context.Context) (*Cache, error) {
-	var cm corev1.ConfigMap
+	var shardList corev1.ConfigMapList
+	err := controller.apiReader.List(ctx, &shardList,
+		client.InNamespace(controller.podNamespace),
+		client.MatchingLabels{kubeobjects.AppComponentLabel: cacheComponentLabel})
+	if err != nil {
+		return nil, err
+	}
 
-	err := controller.apiReader.Get(ctx, client.ObjectKey{Name: cacheName, Namespace: controller.podNamespace}, &cm)
-	if err == nil {
-		return &Cache{Obj: &cm, timeProvider: controller.timeProvider}, nil
+	existingShards := make(map[string]*corev1.ConfigMap, len(shardList.Items))
+	for i := range shardList.Items {
+		existingShards[shardList.Items[i].Name] = &shardList.Items[i]
 	}
 
-	if k8serrors.IsNotFound(err) {
-		cm := &corev1.ConfigMap{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      cacheName,
-				Namespace: controller.podNamespace,
-			},
-			Data: map[string]string{},
+	// the owner of the shards is only looked up once, and only if a shard is missing
+	var owner *appsv1.Deployment
+	shards := make([]*cacheShard, cacheShardCount)
+	for i := range shards {
+		if cm, ok := existingShards[cacheShardName(i)]; ok {
+			shards[i] = newCacheShard(cm, false)
+			continue
 		}
-		// If running locally, don't set the controller.
-		if !controller.runLocal {
-			deploy, err := kubeobjects.GetDeployment(controller.client, os.Getenv(kubeobjects.EnvPodName), controller.podNamespace)
+		if owner == nil {
+			owner, err = controller.getCacheOwner()
 			if err != nil {
 				return nil, err
 			}
-
-			if err = controllerutil.SetControllerReference(deploy, cm, controller.scheme); err != nil {
-				return nil, err
-			}
 		}
+		cm, err := controller.newCacheShardConfigMap(cacheShardName(i), owner)
+		if err != nil {
+			return nil, err
+		}
+		shards[i] = newCacheShard(cm, true)
+	}
+	nodeCache := newCache(shards, controller.timeProvider)
+
+	var legacy corev1.ConfigMap
+	err = controller.apiReader.Get(ctx, client.ObjectKey{Name: cacheName, Namespace: controller.podNamespace}, &legacy)
+	switch {
+	case err == nil:
+		log.Info("migrating the node cache into shards", "configMap", cacheName)
+		if err := nodeCache.migrate(&legacy); err != nil {
+			return nil, err
+		}
+	case !k8serrors.IsNotFound(err):
+		return nil, err
+	}
+	return nodeCache, nil
+}
 
-		return &Cache{Create: true, Obj: cm, timeProvider: controller.timeProvider}, nil
+// getCacheOwner returns the Deployment of the operator, which owns the shards of the cache.
+// If running locally, there is no Deployment and the shards get no owner.
+func (controller *Controller) getCacheOwner() (*appsv1.Deployment, error) {
+	if controller.runLocal {
+		return nil, nil
 	}
+	return kubeobjects.GetDeployment(controller.client, os.Getenv(kubeobjects.EnvPodName), controller.podNamespace)
+}
 
-	return nil, err
+func (controller *Controller) newCacheShardConfigMap(name string, owner *appsv1.Deployment) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: controller.podNamespace,
+			Labels:    map[string]string{kubeobjects.AppComponentLabel: cacheComponentLabel},
+		},
+		Data: map[string]string{},
+	}
+	if owner != nil {
+		if err := controllerutil.SetControllerReference(owner, cm, controller.scheme); err != nil {
+			return nil, err
+		}
+	}
+	return cm, nil
 }
 
+// updateCache writes every changed shard once, shards without changes aren't created or updated
 func (controller *Controller) updateCache(ctx context.Context, nodeCache *Cache) error {
 	if !nodeCache.Changed() {
 		return nil
 	}
 
-	if nodeCache.Create {
-		return controller.client.Create(context.TODO(), nodeCache.Obj)
+	for _, shard := range nodeCache.shards {
+		if !shard.changed() {
+			continue
+		}
+		if err := controller.updateCacheShard(ctx, shard); err != nil {
+			return err
+		}
 	}
 
-	if err := controller.client.Update(ctx, nodeCache.Obj); err != nil {
-		return err
+	if nodeCache.legacy != nil {
+		if err := controller.client.Delete(ctx, nodeCache.legacy); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		log.Info("migrated the node cache into shards", "configMap", cacheName)
 	}
 	return nil
 }
 
+// updateCacheShard applies the changes of the shard to the latest version of its ConfigMap if it was changed in the meantime,
+// so concurrent updates of different nodes don't overwrite each other
+func (controller *Controller) updateCacheShard(ctx context.Context, shard *cacheShard) error {
+	var err error
+	if shard.Create {
+		err = controller.client.Create(ctx, shard.Obj)
+		if !k8serrors.IsAlreadyExists(err) {
+			return err
+		}
+	} else {
+		err = controller.client.Update(ctx, shard.Obj)
+		if !k8serrors.IsConflict(err) {
+			return err
+		}
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var current corev1.ConfigMap
+		if err := controller.apiReader.Get(ctx, client.ObjectKeyFromObject(shard.Obj), &current); err != nil {
+			return err
+		}
+		shard.applyTo(&current)
+		return controller.client.Update(ctx, &current)
+	})
+}
+
 func (controller *Controller) handleOutdatedCache(ctx context.Context, nodeCache *Cache) error {
 	var nodeLst corev1.NodeList
 	if err := controller.client.List(ctx, &nodeLst); err != nil {
@@ -261,7 +351,7 @@ func (controller *Controller) isNodeDeletable(cachedNode CacheEntry) bool {
 	return false
 }
 
-func (controller *Controller) sendMarkedForTermination(dynakubeInstance *dynatracev1beta1.DynaKube, cachedNode CacheEntry) error {
+func (controller *Controller) sendLifecycleEvent(dynakubeInstance *dynatracev1beta1.DynaKube, cachedNode CacheEntry, lifecycle lifecycleEvent, properties map[string]string, timestamp time.Time) error {
 	tokenReader := token.NewReader(controller.apiReader, dynakubeInstance)
 	tokens, err := tokenReader.ReadTokens(context.TODO())
 
@@ -280,29 +370,33 @@ func (controller *Controller) sendMarkedForTermination(dynakubeInstance *dynatra
 	entityID, err := dynatraceClient.GetEntityIDForIP(cachedNode.IPAddress)
 	if err != nil {
 		if errors.As(err, &dtclient.HostNotFoundErr{}) {
-			log.Info("skipping to send mark for termination event", "dynakube", dynakubeInstance.Name, "nodeIP", cachedNode.IPAddress, "reason", err.Error())
+			log.Info("skipping to send node lifecycle event", "dynakube", dynakubeInstance.Name, "nodeIP", cachedNode.IPAddress, "lifecycle", lifecycle.lifecycle, "reason", err.Error())
 			return nil
 		}
-		log.Info("failed to send mark for termination event",
-			"reason", "failed to determine entity id", "dynakube", dynakubeInstance.Name, "nodeIP", cachedNode.IPAddress, "cause", err)
+		log.Info("failed to send node lifecycle event",
+			"reason", "failed to determine entity id", "dynakube", dynakubeInstance.Name, "nodeIP", cachedNode.IPAddress, "lifecycle", lifecycle.lifecycle, "cause", err)
 
 		return err
 	}
 
-	ts := uint64(cachedNode.LastSeen.Add(-10*time.Minute).UnixNano()) / uint64(time.Millisecond)
+	properties[lifecycleProperty] = lifecycle.lifecycle
+	ts := uint64(timestamp.UnixNano()) / uint64(time.Millisecond)
 	return dynatraceClient.SendEvent(&dtclient.EventData{
-		EventType:     dtclient.MarkedForTerminationEvent,
+		EventType:     lifecycle.eventType,
 		Source:        "Dynatrace Operator",
-		Description:   "Kubernetes node cordoned. Node might be drained or terminated.",
+		Description:   lifecycle.description,
 		StartInMillis: ts,
 		EndInMillis:   ts,
 		AttachRules: dtclient.EventDataAttachRules{
 			EntityIDs: []string{entityID},
 		},
+		CustomProperties: properties,
 	})
 }
 
-func (controller *Controller) markForTermination(dynakube *dynatracev1beta1.DynaKube, cachedNodeData CachedNodeInfo) error {
+// markForTermination sends the lifecycle event of a node which doesn't accept pods anymore, at most once an hour.
+// The node is nil if it was deleted already.
+func (controller *Controller) markForTermination(dynakube *dynatracev1beta1.DynaKube, cachedNodeData CachedNodeInfo, lifecycle lifecycleEvent, node *corev1.Node) error {
 	if !controller.isMarkableForTermination(&cachedNodeData.cachedNode) {
 		return nil
 	}
@@ -312,24 +406,23 @@ func (controller *Controller) markForTermination(dynakube *dynatracev1beta1.Dyna
 	}
 
 	log.Info("sending mark for termination event to dynatrace server", "dynakube", dynakube.Name, "ip", cachedNodeData.cachedNode.IPAddress,
-		"node", cachedNodeData.nodeName)
-
-	return controller.sendMarkedForTermination(dynakube, cachedNodeData.cachedNode)
-}
+		"node", cachedNodeData.nodeName, "lifecycle", lifecycle.lifecycle)
 
-func (controller *Controller) isUnschedulable(node *corev1.Node) bool {
-	return node.Spec.Unschedulable || controller.hasUnschedulableTaint(node)
+	timestamp := cachedNodeData.cachedNode.LastSeen.Add(-10 * time.Minute)
+	return controller.sendLifecycleEvent(dynakube, cachedNodeData.cachedNode, lifecycle, nodeProperties(cachedNodeData.nodeName, node), timestamp)
 }
 
-func (controller *Controller) hasUnschedulableTaint(node *corev1.Node) bool {
-	for _, taint := range node.Spec.Taints {
-		for _, unschedulableTaint := range unschedulableTaints {
-			if taint.Key == unschedulableTaint {
-				return true
-			}
-		}
+// markRecovered sends the recovery event of a node which accepts pods again, after it was marked for termination
+func (controller *Controller) markRecovered(dynakube *dynatracev1beta1.DynaKube, cachedNodeData CachedNodeInfo, node *corev1.Node) error {
+	cachedNode := cachedNodeData.cachedNode
+	cachedNode.LastMarkedForTermination = time.Time{}
+	if err := cachedNodeData.nodeCache.Set(cachedNodeData.nodeName, cachedNode); err != nil {
+		return err
 	}
-	return false
+
+	log.Info("sending recovery event to dynatrace server", "dynakube", dynakube.Name, "ip", cachedNode.IPAddress, "node", cachedNodeData.nodeName)
+
+	return controller.sendLifecycleEvent(dynakube, cachedNode, recoveryEvent, nodeProperties(cachedNodeData.nodeName, node), controller.timeProvider.Now().UTC())
 }
 
 // isMarkableForTermination checks if the timestamp from last mark is at least one hour old
@@ -2,6 +2,8 @@ package nodes
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -11,14 +13,18 @@ import (
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -73,9 +79,8 @@ func TestReconcile(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 
-		var cm corev1.ConfigMap
-		require.NoError(t, fakeClient.Get(ctx, testCacheKey, &cm))
-		nodesCache := &Cache{Obj: &cm}
+		nodesCache, err := ctrl.getCache(ctx)
+		require.NoError(t, err)
 
 		_, err = nodesCache.Get("node1")
 		assert.Error(t, err)
@@ -101,9 +106,8 @@ func TestReconcile(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 
-		var cm corev1.ConfigMap
-		require.NoError(t, fakeClient.Get(ctx, testCacheKey, &cm))
-		nodesCache := &Cache{Obj: &cm}
+		nodesCache, err := ctrl.getCache(ctx)
+		require.NoError(t, err)
 
 		_, err = nodesCache.Get("node1")
 		assert.Error(t, err)
@@ -148,6 +152,47 @@ func TestReconcile(t *testing.T) {
 		assert.True(t, node.LastMarkedForTermination.Add(time.Minute).After(now))
 	})
 
+	t.Run("Node is interrupted and recovers", func(t *testing.T) {
+		fakeClient := createDefaultFakeClient()
+		dtClient := &dtclient.MockDynatraceClient{}
+		dtClient.On("GetEntityIDForIP", "1.2.3.4").Return("HOST-42", nil)
+		dtClient.On("SendEvent", mock.MatchedBy(func(e *dtclient.EventData) bool {
+			return e.EventType == dtclient.MarkedForTerminationEvent && e.CustomProperties[lifecycleProperty] == lifecycleSpotInterruption &&
+				e.CustomProperties[zoneProperty] == "zone-a"
+		})).Return(nil).Once()
+		dtClient.On("SendEvent", mock.MatchedBy(func(e *dtclient.EventData) bool {
+			return e.EventType == dtclient.CustomInfoEvent && e.CustomProperties[lifecycleProperty] == lifecycleRecovery
+		})).Return(nil).Once()
+		defer mock.AssertExpectationsForObjects(t, dtClient)
+		ctrl := createDefaultReconciler(fakeClient, dtClient)
+		reconcileAllNodes(t, ctrl, fakeClient)
+
+		node1 := &corev1.Node{}
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "node1"}, node1))
+		node1.Labels = map[string]string{corev1.LabelTopologyZone: "zone-a"}
+		node1.Spec.Taints = []corev1.Taint{{Key: "aws-node-termination-handler/spot-itn"}}
+		require.NoError(t, fakeClient.Update(ctx, node1))
+
+		_, err := ctrl.Reconcile(ctx, createReconcileRequest("node1"))
+		require.NoError(t, err)
+
+		node1.Spec.Taints = nil
+		require.NoError(t, fakeClient.Update(ctx, node1))
+
+		_, err = ctrl.Reconcile(ctx, createReconcileRequest("node1"))
+		require.NoError(t, err)
+
+		c, err := ctrl.getCache(ctx)
+		require.NoError(t, err)
+		node, err := c.Get("node1")
+		require.NoError(t, err)
+		assert.True(t, node.LastMarkedForTermination.IsZero())
+
+		// a recovered node is only reported once
+		_, err = ctrl.Reconcile(ctx, createReconcileRequest("node1"))
+		require.NoError(t, err)
+	})
+
 	t.Run("Server error when removing node", func(t *testing.T) {
 		fakeClient := createDefaultFakeClient()
 
@@ -194,13 +239,12 @@ func TestReconcile(t *testing.T) {
 		reconcileAllNodes(t, ctrl, fakeClient)
 
 		// Emulate error by explicitly removing node1 from cache
-		var cm corev1.ConfigMap
-		require.NoError(t, fakeClient.Get(ctx, testCacheKey, &cm))
-		nodesCache := &Cache{Obj: &cm}
+		nodesCache, err := ctrl.getCache(ctx)
+		require.NoError(t, err)
 
 		// delete node from kube api
 		node1 := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
-		err := fakeClient.Delete(ctx, node1)
+		err = fakeClient.Delete(ctx, node1)
 		assert.NoError(t, err)
 
 		// run another request reconcile
@@ -208,6 +252,126 @@ func TestReconcile(t *testing.T) {
 	})
 }
 
+func TestCacheShards(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("only shards with entries are created", func(t *testing.T) {
+		fakeClient := createDefaultFakeClient()
+		ctrl := createDefaultReconciler(fakeClient, createDTMockClient("1.2.3.4", "HOST-42"))
+
+		reconcileAllNodes(t, ctrl, fakeClient)
+
+		nodesCache, err := ctrl.getCache(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"node1", "node2"}, nodesCache.Keys())
+
+		// the timestamp of the last cleanup is stored in the first shard
+		expectedShards := map[string]bool{cacheShardName(0): true}
+		for i, shard := range nodesCache.shards {
+			if shard == nodesCache.shardFor("node1") || shard == nodesCache.shardFor("node2") {
+				expectedShards[cacheShardName(i)] = true
+			}
+		}
+		var shards corev1.ConfigMapList
+		require.NoError(t, fakeClient.List(ctx, &shards, client.InNamespace(testNamespace)))
+		assert.Len(t, shards.Items, len(expectedShards))
+		for _, shard := range shards.Items {
+			assert.True(t, expectedShards[shard.Name], shard.Name)
+		}
+	})
+	t.Run("legacy cache is migrated", func(t *testing.T) {
+		fakeClient := createDefaultFakeClient()
+		lastMarked := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		legacyEntry, err := json.Marshal(CacheEntry{Instance: "oneagent1", IPAddress: "1.2.3.4", LastSeen: time.Now().UTC(), LastMarkedForTermination: lastMarked})
+		require.NoError(t, err)
+		require.NoError(t, fakeClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: cacheName, Namespace: testNamespace},
+			Data:       map[string]string{"node1": string(legacyEntry)},
+		}))
+		ctrl := createDefaultReconciler(fakeClient, createDTMockClient("1.2.3.4", "HOST-42"))
+
+		_, err = ctrl.Reconcile(ctx, createReconcileRequest("node2"))
+		require.NoError(t, err)
+
+		var legacy corev1.ConfigMap
+		assert.True(t, k8serrors.IsNotFound(fakeClient.Get(ctx, testCacheKey, &legacy)))
+
+		nodesCache, err := ctrl.getCache(ctx)
+		require.NoError(t, err)
+		entry, err := nodesCache.Get("node1")
+		require.NoError(t, err)
+		assert.True(t, lastMarked.Equal(entry.LastMarkedForTermination))
+		_, err = nodesCache.Get("node2")
+		assert.NoError(t, err)
+	})
+	t.Run("owner of missing shards is looked up once", func(t *testing.T) {
+		t.Setenv(kubeobjects.EnvPodName, "operator-pod")
+		isController := true
+		clt := fake.NewClient(
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name: "operator-pod", Namespace: testNamespace,
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "operator-rs", Controller: &isController}},
+			}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+				Name: "operator-rs", Namespace: testNamespace,
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "operator", Controller: &isController}},
+			}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "operator", Namespace: testNamespace}})
+		podGets := 0
+		countingClient := interceptor.NewClient(clt.(client.WithWatch), interceptor.Funcs{
+			Get: func(ctx context.Context, clt client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*corev1.Pod); ok {
+					podGets++
+				}
+				return clt.Get(ctx, key, obj, opts...)
+			},
+		})
+		ctrl := createDefaultReconciler(countingClient, createDTMockClient("1.2.3.4", "HOST-42"))
+		ctrl.runLocal = false
+
+		nodesCache, err := ctrl.getCache(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, podGets)
+		for _, shard := range nodesCache.shards {
+			require.True(t, shard.Create)
+			require.Len(t, shard.Obj.OwnerReferences, 1)
+			assert.Equal(t, "operator", shard.Obj.OwnerReferences[0].Name)
+		}
+	})
+	t.Run("concurrent changes of a shard are kept", func(t *testing.T) {
+		fakeClient := createDefaultFakeClient()
+		ctrl := createDefaultReconciler(fakeClient, createDTMockClient("1.2.3.4", "HOST-42"))
+		reconcileAllNodes(t, ctrl, fakeClient)
+
+		staleCache, err := ctrl.getCache(ctx)
+		require.NoError(t, err)
+		concurrentCache, err := ctrl.getCache(ctx)
+		require.NoError(t, err)
+
+		concurrentCache.shards[0].Obj.Labels["concurrent"] = "true"
+		require.NoError(t, fakeClient.Update(ctx, concurrentCache.shards[0].Obj))
+
+		require.NoError(t, staleCache.Set(nodeInShard(staleCache, 0), CacheEntry{Instance: "stale"}))
+		require.NoError(t, ctrl.updateCache(ctx, staleCache))
+
+		var shard corev1.ConfigMap
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: cacheShardName(0), Namespace: testNamespace}, &shard))
+		assert.Equal(t, "true", shard.Labels["concurrent"])
+		assert.Contains(t, shard.Data[nodeInShard(staleCache, 0)], "stale")
+	})
+}
+
+// nodeInShard finds a node name stored in the shard with the given index
+func nodeInShard(nodeCache *Cache, index int) string {
+	for i := 0; ; i++ {
+		name := fmt.Sprintf("node-%d", i)
+		if nodeCache.shardFor(name) == nodeCache.shards[index] {
+			return name
+		}
+	}
+}
+
 func createReconcileRequest(nodeName string) reconcile.Request {
 	return reconcile.Request{
 		NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: nodeName},
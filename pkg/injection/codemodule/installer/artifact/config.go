@@ -0,0 +1,9 @@
+package artifact
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/logger"
+)
+
+var (
+	log = logger.Factory.GetLogger("oneagent-artifact")
+)
@@ -0,0 +1,122 @@
+package artifact
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/common"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/symlink"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/zip"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/dockerkeychain"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Properties struct {
+	ImageUri     string
+	ApiReader    client.Reader
+	Dynakube     *dynatracev1beta1.DynaKube
+	PathResolver metadata.PathResolver
+	// Technologies to unpack, all technologies are unpacked if it is empty
+	Technologies []string
+}
+
+func NewArtifactInstaller(fs afero.Fs, props *Properties) (installer.Installer, error) {
+	transport, err := registry.PrepareTransportForDynaKube(context.TODO(), props.ApiReader, http.DefaultTransport.(*http.Transport).Clone(), props.Dynakube)
+	if err != nil {
+		return nil, err
+	}
+
+	keychain, err := dockerkeychain.NewDockerKeychain(context.TODO(), props.ApiReader, props.Dynakube.PullSecretWithoutData())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Installer{
+		fs:        fs,
+		extractor: zip.NewOneAgentExtractor(fs, props.PathResolver),
+		props:     props,
+		transport: transport,
+		keychain:  keychain,
+	}, nil
+}
+
+// Installer installs the code modules from an OCI artifact holding them as single tar.gz or zip layer,
+// only the requested technologies are unpacked
+type Installer struct {
+	fs        afero.Fs
+	extractor zip.Extractor
+	props     *Properties
+	transport http.RoundTripper
+	keychain  authn.Keychain
+}
+
+func (installer *Installer) InstallAgent(targetDir string) (bool, error) {
+	log.Info("installing agent from artifact")
+
+	if installer.isAlreadyPresent(targetDir) {
+		log.Info("agent already installed", "target dir", targetDir)
+		return false, nil
+	}
+
+	err := installer.fs.MkdirAll(installer.props.PathResolver.AgentSharedBinaryDirBase(), common.MkDirFileMode)
+	if err != nil {
+		log.Info("failed to create the base shared agent directory", "err", err)
+		return false, errors.WithStack(err)
+	}
+
+	log.Info("installing agent", "target dir", targetDir, "technologies", installer.props.Technologies)
+	if err := installer.installAgentFromArtifact(targetDir); err != nil {
+		_ = installer.fs.RemoveAll(targetDir)
+		log.Info("failed to install agent from artifact", "err", err)
+		return false, err
+	}
+
+	if err := symlink.CreateSymlinkForCurrentVersionIfNotExists(installer.fs, targetDir); err != nil {
+		_ = installer.fs.RemoveAll(targetDir)
+		log.Info("failed to create symlink for agent installation", "err", err)
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+func (installer *Installer) installAgentFromArtifact(targetDir string) error {
+	layer, err := installer.pullLayer(installer.props.ImageUri)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := afero.TempFile(installer.fs, installer.props.PathResolver.AgentSharedBinaryDirBase(), "artifact")
+	if err != nil {
+		log.Info("failed to create temp file for artifact", "err", err)
+		return errors.WithStack(err)
+	}
+	defer func() {
+		_ = tmpFile.Close()
+		if err := installer.fs.Remove(tmpFile.Name()); err != nil {
+			log.Error(err, "failed to delete downloaded artifact", "path", tmpFile.Name())
+		}
+	}()
+
+	start := time.Now()
+	size, err := downloadLayer(layer, tmpFile)
+	common.ObserveDownload(common.ArtifactInstaller, start, size, err)
+	if err != nil {
+		return err
+	}
+
+	return installer.unpackLayer(layer, tmpFile, targetDir)
+}
+
+func (installer Installer) isAlreadyPresent(targetDir string) bool {
+	_, err := installer.fs.Stat(targetDir)
+	return !os.IsNotExist(err)
+}
@@ -0,0 +1,215 @@
+package artifact
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	installerzip "github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/zip"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	testregistry "github.com/google/go-containerregistry/pkg/registry"
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTargetDir = "/codemodules"
+
+	testManifest = `{"technologies": {
+		"java": {"linux-x86-64": [{"path": "agent/lib64/liboneagentjava.so"}, {"path": "agent/lib64/liboneagentloader.so"}]},
+		"nodejs": {"linux-x86-64": [{"path": "agent/lib64/liboneagentnodejs.so"}, {"path": "agent/lib64/liboneagentloader.so"}]}
+	}}`
+)
+
+var testFiles = []string{
+	"agent/lib64/liboneagentjava.so",
+	"agent/lib64/liboneagentnodejs.so",
+	"agent/lib64/liboneagentloader.so",
+	"agent/conf/ruxitagentproc.conf",
+}
+
+func TestInstallAgent(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+	host := registryHost(t, server)
+
+	t.Run("unpacks only requested technologies", func(t *testing.T) {
+		imageUri := pushArtifact(t, host+"/codemodules:java", testArtifact(t, registry.CodeModulesConfigMediaType, createTestZip(t, true)))
+		fs := afero.NewMemMapFs()
+
+		isInstalled, err := newTestInstaller(fs, imageUri, []string{"java"}).InstallAgent(testTargetDir)
+		require.NoError(t, err)
+		assert.True(t, isInstalled)
+
+		assertFilesExist(t, fs, "agent/lib64/liboneagentjava.so", "agent/lib64/liboneagentloader.so", "agent/conf/ruxitagentproc.conf", technologyManifestFile)
+		assertFilesMissing(t, fs, "agent/lib64/liboneagentnodejs.so")
+	})
+	t.Run("unpacks all technologies if none are requested", func(t *testing.T) {
+		imageUri := pushArtifact(t, host+"/codemodules:all", testArtifact(t, registry.CodeModulesConfigMediaType, createTestZip(t, true)))
+		fs := afero.NewMemMapFs()
+
+		isInstalled, err := newTestInstaller(fs, imageUri, nil).InstallAgent(testTargetDir)
+		require.NoError(t, err)
+		assert.True(t, isInstalled)
+
+		assertFilesExist(t, fs, testFiles...)
+	})
+	t.Run("unpacks all technologies if artifact has no manifest", func(t *testing.T) {
+		imageUri := pushArtifact(t, host+"/codemodules:nomanifest", testArtifact(t, registry.CodeModulesConfigMediaType, createTestZip(t, false)))
+		fs := afero.NewMemMapFs()
+
+		_, err := newTestInstaller(fs, imageUri, []string{"java"}).InstallAgent(testTargetDir)
+		require.NoError(t, err)
+
+		assertFilesExist(t, fs, testFiles...)
+	})
+	t.Run("skips already installed agent", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, fs.MkdirAll(testTargetDir, 0755))
+
+		isInstalled, err := newTestInstaller(fs, host+"/codemodules@sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", nil).InstallAgent(testTargetDir)
+		require.NoError(t, err)
+		assert.False(t, isInstalled)
+	})
+	t.Run("fails for artifact not pinned to digest", func(t *testing.T) {
+		pushArtifact(t, host+"/codemodules:tag", testArtifact(t, registry.CodeModulesConfigMediaType, createTestZip(t, true)))
+		fs := afero.NewMemMapFs()
+
+		_, err := newTestInstaller(fs, host+"/codemodules:tag", nil).InstallAgent(testTargetDir)
+		require.Error(t, err)
+		assertFilesMissing(t, fs, "")
+	})
+	t.Run("fails for images", func(t *testing.T) {
+		image, err := random.Image(64, 1)
+		require.NoError(t, err)
+		imageUri := pushArtifact(t, host+"/codemodules:image", image)
+
+		_, err = newTestInstaller(afero.NewMemMapFs(), imageUri, nil).InstallAgent(testTargetDir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is no code modules artifact")
+	})
+	t.Run("fails for artifact with several layers", func(t *testing.T) {
+		artifact, err := mutate.Append(testArtifact(t, registry.CodeModulesConfigMediaType, createTestZip(t, true)),
+			mutate.Addendum{Layer: static.NewLayer([]byte("other"), registry.CodeModulesZipMediaType)})
+		require.NoError(t, err)
+		imageUri := pushArtifact(t, host+"/codemodules:layers", artifact)
+
+		_, err = newTestInstaller(afero.NewMemMapFs(), imageUri, nil).InstallAgent(testTargetDir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "single layer")
+	})
+}
+
+func TestTechnologyManifestFilter(t *testing.T) {
+	manifest, err := parseTechnologyManifest(bytes.NewBufferString(testManifest))
+	require.NoError(t, err)
+
+	t.Run("keeps shared files of requested technology", func(t *testing.T) {
+		filter := manifest.filter([]string{"nodejs"})
+
+		assert.True(t, filter("agent/lib64/liboneagentnodejs.so"))
+		assert.True(t, filter("agent/lib64/liboneagentloader.so"))
+		assert.True(t, filter("agent/conf/ruxitagentproc.conf"))
+		assert.False(t, filter("agent/lib64/liboneagentjava.so"))
+	})
+	t.Run("skips shared files if no technology is requested", func(t *testing.T) {
+		filter := manifest.filter([]string{"php"})
+
+		assert.False(t, filter("agent/lib64/liboneagentloader.so"))
+		assert.True(t, filter("agent/conf/ruxitagentproc.conf"))
+	})
+}
+
+func newTestInstaller(fs afero.Fs, imageUri string, technologies []string) *Installer {
+	pathResolver := metadata.PathResolver{RootDir: consts.AgentBinDirMount}
+	return &Installer{
+		fs:        fs,
+		extractor: installerzip.NewOneAgentExtractor(fs, pathResolver),
+		props: &Properties{
+			ImageUri:     imageUri,
+			PathResolver: pathResolver,
+			Technologies: technologies,
+		},
+		transport: http.DefaultTransport,
+		keychain:  authn.DefaultKeychain,
+	}
+}
+
+func createTestZip(t *testing.T, withManifest bool) []byte {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+
+	files := testFiles
+	if withManifest {
+		files = append([]string{technologyManifestFile}, files...)
+	}
+	for _, file := range files {
+		fileWriter, err := writer.Create(file)
+		require.NoError(t, err)
+
+		content := file
+		if file == technologyManifestFile {
+			content = testManifest
+		}
+		_, err = fileWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func testArtifact(t *testing.T, configMediaType types.MediaType, content []byte) containerv1.Image {
+	artifact := mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), configMediaType)
+	artifact, err := mutate.Append(artifact, mutate.Addendum{Layer: static.NewLayer(content, registry.CodeModulesZipMediaType)})
+	require.NoError(t, err)
+	return artifact
+}
+
+// pushArtifact pushes the artifact and returns it pinned to its digest
+func pushArtifact(t *testing.T, image string, artifact containerv1.Image) string {
+	ref, err := name.NewTag(image)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, artifact))
+
+	artifactDigest, err := artifact.Digest()
+	require.NoError(t, err)
+	return ref.Context().Name() + registry.DigestDelimiter + artifactDigest.String()
+}
+
+func registryHost(t *testing.T, server *httptest.Server) string {
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return serverUrl.Host
+}
+
+func assertFilesExist(t *testing.T, fs afero.Fs, files ...string) {
+	for _, file := range files {
+		exists, err := afero.Exists(fs, filepath.Join(testTargetDir, file))
+		require.NoError(t, err)
+		assert.True(t, exists, file)
+	}
+}
+
+func assertFilesMissing(t *testing.T, fs afero.Fs, files ...string) {
+	for _, file := range files {
+		exists, err := afero.Exists(fs, filepath.Join(testTargetDir, file))
+		require.NoError(t, err)
+		assert.False(t, exists, file)
+	}
+}
@@ -0,0 +1,84 @@
+package artifact
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/google/go-containerregistry/pkg/name"
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// pullLayer gets the layer of the code modules artifact, the artifact has to be referenced by the digest from the status of the DynaKube
+func (installer Installer) pullLayer(imageUri string) (containerv1.Layer, error) {
+	ref, err := name.NewDigest(imageUri)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "code modules artifact has to be pinned to a digest, got %s", imageUri)
+	}
+
+	descriptor, err := remote.Get(ref,
+		remote.WithContext(context.TODO()),
+		remote.WithAuthFromKeychain(installer.keychain),
+		remote.WithTransport(installer.transport))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "getting artifact %q", imageUri)
+	}
+	if descriptor.Digest.String() != ref.DigestStr() {
+		return nil, errors.Errorf("digest of artifact %s doesn't match, got %s", imageUri, descriptor.Digest)
+	}
+
+	artifact, err := descriptor.Image()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	manifest, err := artifact.Manifest()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if manifest.Config.MediaType != registry.CodeModulesConfigMediaType {
+		return nil, errors.Errorf("%s is no code modules artifact, config media type is %s", imageUri, manifest.Config.MediaType)
+	}
+	if len(manifest.Layers) != 1 {
+		return nil, errors.Errorf("expected code modules artifact %s to have a single layer, got %d", imageUri, len(manifest.Layers))
+	}
+
+	layerMediaType := manifest.Layers[0].MediaType
+	if layerMediaType != registry.CodeModulesTarGzipMediaType && layerMediaType != registry.CodeModulesZipMediaType {
+		return nil, errors.Errorf("media type %s of code modules artifact %s is not supported", layerMediaType, imageUri)
+	}
+
+	return artifact.LayerByDigest(manifest.Layers[0].Digest)
+}
+
+// downloadLayer writes the layer to the file and verifies it against the digest in the manifest
+func downloadLayer(layer containerv1.Layer, file afero.File) (int64, error) {
+	expectedDigest, err := layer.Digest()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	content, err := layer.Compressed()
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to pull artifact layer")
+	}
+	defer func() { _ = content.Close() }()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), content)
+	if err != nil {
+		return size, errors.WithMessage(err, "failed to download artifact layer")
+	}
+
+	if actualDigest := hex.EncodeToString(hash.Sum(nil)); actualDigest != expectedDigest.Hex {
+		return size, errors.Errorf("digest of artifact layer doesn't match, expected %s, got sha256:%s", expectedDigest, actualDigest)
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	return size, errors.WithStack(err)
+}
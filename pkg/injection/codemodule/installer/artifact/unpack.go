@@ -0,0 +1,161 @@
+package artifact
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/zip"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/klauspost/compress/gzip"
+	kpzip "github.com/klauspost/compress/zip"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// technologyManifestFile lists the files of each technology of the code modules
+	technologyManifestFile = "manifest.json"
+
+	allTechnologies = "all"
+)
+
+// technologyManifest is the manifest.json of the code modules, e.g.
+// {"technologies": {"java": {"linux-x86-64": [{"path": "agent/lib64/liboneagentjava.so"}]}}}
+type technologyManifest struct {
+	Technologies map[string]map[string][]technologyFile `json:"technologies"`
+}
+
+type technologyFile struct {
+	Path string `json:"path"`
+}
+
+func (installer Installer) unpackLayer(layer containerv1.Layer, file afero.File, targetDir string) error {
+	mediaType, err := layer.MediaType()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	filter, err := installer.technologyFilter(file, mediaType)
+	if err != nil {
+		return err
+	}
+
+	switch mediaType {
+	case registry.CodeModulesZipMediaType:
+		return installer.extractor.ExtractZipFiltered(file, targetDir, filter)
+	case registry.CodeModulesTarGzipMediaType:
+		return installer.extractor.ExtractGzipFiltered(file.Name(), targetDir, filter)
+	default:
+		return errors.Errorf("media type %s is not implemented", mediaType)
+	}
+}
+
+// technologyFilter skips the files only used by technologies which aren't requested,
+// all files are unpacked if all technologies are requested or the code modules have no manifest.json
+func (installer Installer) technologyFilter(file afero.File, mediaType types.MediaType) (zip.Filter, error) {
+	technologies := installer.props.Technologies
+	if len(technologies) == 0 || slices.Contains(technologies, allTechnologies) {
+		return nil, nil
+	}
+
+	var manifest *technologyManifest
+	var err error
+	switch mediaType {
+	case registry.CodeModulesZipMediaType:
+		manifest, err = readTechnologyManifestFromZip(file)
+	case registry.CodeModulesTarGzipMediaType:
+		manifest, err = readTechnologyManifestFromGzip(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		log.Info("code modules have no manifest.json, unpacking all technologies")
+		return nil, nil
+	}
+	return manifest.filter(technologies), nil
+}
+
+func (manifest technologyManifest) filter(technologies []string) zip.Filter {
+	requiredFiles := make(map[string]bool)
+	skippedFiles := make(map[string]bool)
+	for technology, platforms := range manifest.Technologies {
+		isRequested := slices.Contains(technologies, technology)
+		for _, files := range platforms {
+			for _, file := range files {
+				if isRequested {
+					requiredFiles[zip.CleanFileName(file.Path)] = true
+				} else {
+					skippedFiles[zip.CleanFileName(file.Path)] = true
+				}
+			}
+		}
+	}
+
+	// files shared by several technologies are kept, if one of them is requested
+	return func(fileName string) bool {
+		return requiredFiles[fileName] || !skippedFiles[fileName]
+	}
+}
+
+func readTechnologyManifestFromZip(file afero.File) (*technologyManifest, error) {
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	reader, err := kpzip.NewReader(file, fileInfo.Size())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for _, zipFile := range reader.File {
+		if zip.CleanFileName(zipFile.Name) != technologyManifestFile {
+			continue
+		}
+
+		content, err := zipFile.Open()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		defer func() { _ = content.Close() }()
+		return parseTechnologyManifest(content)
+	}
+	return nil, nil
+}
+
+func readTechnologyManifestFromGzip(file afero.File) (*technologyManifest, error) {
+	defer func() { _, _ = file.Seek(0, io.SeekStart) }()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer func() { _ = gzipReader.Close() }()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		if header.Typeflag == tar.TypeReg && zip.CleanFileName(header.Name) == technologyManifestFile {
+			return parseTechnologyManifest(tarReader)
+		}
+	}
+}
+
+func parseTechnologyManifest(content io.Reader) (*technologyManifest, error) {
+	var manifest technologyManifest
+	if err := json.NewDecoder(content).Decode(&manifest); err != nil {
+		return nil, errors.WithMessage(err, "failed to parse manifest.json of code modules")
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	UrlInstaller      = "url"
+	ImageInstaller    = "image"
+	ArtifactInstaller = "artifact"
+
+	downloadSucceeded = "success"
+	downloadFailed    = "error"
+)
+
+var (
+	downloadedBytesMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "csi_driver",
+		Name:      "downloaded_bytes_total",
+		Help:      "Number of bytes downloaded for the code modules by installer",
+	}, []string{"installer"})
+
+	downloadDurationMetric = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "dynatrace",
+		Subsystem: "csi_driver",
+		Name:      "download_duration_seconds",
+		Help:      "Duration of the code module downloads by installer and result",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+	}, []string{"installer", "result"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(downloadedBytesMetric)
+	metrics.Registry.MustRegister(downloadDurationMetric)
+}
+
+// ObserveDownload records a download that started at the given time, the size is only counted for successful downloads
+func ObserveDownload(installer string, start time.Time, size int64, err error) {
+	result := downloadSucceeded
+	if err != nil {
+		result = downloadFailed
+	} else {
+		downloadedBytesMetric.WithLabelValues(installer).Add(float64(size))
+	}
+	downloadDurationMetric.WithLabelValues(installer, result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,29 @@
+package common
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveDownload(t *testing.T) {
+	t.Run("counts bytes of successful downloads", func(t *testing.T) {
+		before := testutil.ToFloat64(downloadedBytesMetric.WithLabelValues(UrlInstaller))
+
+		ObserveDownload(UrlInstaller, time.Now(), 1024, nil)
+
+		assert.Equal(t, before+1024, testutil.ToFloat64(downloadedBytesMetric.WithLabelValues(UrlInstaller)))
+	})
+	t.Run("does not count bytes of failed downloads", func(t *testing.T) {
+		before := testutil.ToFloat64(downloadedBytesMetric.WithLabelValues(ImageInstaller))
+
+		ObserveDownload(ImageInstaller, time.Now(), 1024, errors.New("failed"))
+
+		assert.Equal(t, before, testutil.ToFloat64(downloadedBytesMetric.WithLabelValues(ImageInstaller)))
+		assert.Equal(t, 1, testutil.CollectAndCount(downloadDurationMetric.WithLabelValues(ImageInstaller, downloadFailed).(prometheus.Histogram)))
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/common"
 	"github.com/google/go-containerregistry/pkg/crane"
@@ -65,7 +66,10 @@ func (installer Installer) pullOCIimage(image containerv1.Image, imageName strin
 		return errors.WithStack(err)
 	}
 
-	if err := crane.SaveOCI(image, path.Join(imageCacheDir, ref.Identifier())); err != nil {
+	start := time.Now()
+	err = crane.SaveOCI(image, path.Join(imageCacheDir, ref.Identifier()))
+	common.ObserveDownload(common.ImageInstaller, start, layersSize(image), err)
+	if err != nil {
 		log.Info("saving v1.Image img as an OCI Image Layout at path", imageCacheDir, err)
 		return errors.WithMessagef(err, "saving v1.Image img as an OCI Image Layout at path %s", imageCacheDir)
 	}
@@ -84,6 +88,22 @@ func (installer Installer) pullOCIimage(image containerv1.Image, imageName strin
 	return nil
 }
 
+func layersSize(image containerv1.Image) int64 {
+	layers, err := image.Layers()
+	if err != nil {
+		return 0
+	}
+
+	var size int64
+	for _, layer := range layers {
+		layerSize, err := layer.Size()
+		if err == nil {
+			size += layerSize
+		}
+	}
+	return size
+}
+
 func (installer Installer) unpackOciImage(layers []containerv1.Layer, imageCacheDir string, targetDir string) error {
 	for _, layer := range layers {
 		mediaType, _ := layer.MediaType()
@@ -1,6 +1,11 @@
 package url
 
 import (
+	"context"
+	"io"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 )
@@ -70,3 +75,24 @@ func (installer Installer) downloadOneAgentViaInstallerUrl(tmpFile afero.File) e
 	log.Info("downloading OneAgent package using provided url, all other properties are ignored", "url", installer.props.Url)
 	return installer.dtc.GetAgentViaInstallerUrl(installer.props.Url, tmpFile)
 }
+
+func (installer Installer) downloadOneAgentFromMirror(tmpFile afero.File) error {
+	// drop whatever the failed download left behind
+	if err := tmpFile.Truncate(0); err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return errors.WithStack(err)
+	}
+
+	var nameOptions []name.Option
+	if installer.props.MirrorInsecure {
+		nameOptions = append(nameOptions, name.Insecure)
+	}
+	ref, err := name.ParseReference(installer.props.MirrorImage, nameOptions...)
+	if err != nil {
+		return errors.WithMessagef(err, "invalid mirrored installer %s", installer.props.MirrorImage)
+	}
+	log.Info("downloading OneAgent package from mirror", "image", installer.props.MirrorImage)
+	return registry.PullArtifact(context.Background(), ref, tmpFile)
+}
@@ -3,6 +3,7 @@ package url
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
@@ -26,6 +27,10 @@ type Properties struct {
 	SkipMetadata  bool
 
 	PathResolver metadata.PathResolver
+
+	// MirrorImage is the OCI artifact of the installer in the mirror, it is used if the download fails
+	MirrorImage    string
+	MirrorInsecure bool
 }
 
 func (props *Properties) fillEmptyWithDefaults() {
@@ -101,12 +106,27 @@ func (installer Installer) installAgent(targetDir string) error {
 			log.Error(err, "failed to delete downloaded file", "path", tmpFile.Name())
 		}
 	}()
-	if err := installer.downloadOneAgentFromUrl(tmpFile); err != nil {
+	start := time.Now()
+	err = installer.downloadOneAgentFromUrl(tmpFile)
+	if err != nil && installer.props.MirrorImage != "" {
+		log.Info("failed to download agent, falling back to mirror", "err", err.Error(), "mirror", installer.props.MirrorImage)
+		err = installer.downloadOneAgentFromMirror(tmpFile)
+	}
+	common.ObserveDownload(common.UrlInstaller, start, downloadedSize(tmpFile), err)
+	if err != nil {
 		return err
 	}
 	return installer.unpackOneAgentZip(targetDir, tmpFile)
 }
 
+func downloadedSize(file afero.File) int64 {
+	info, err := file.Stat()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
 func (installer Installer) isInitContainerMode() bool {
 	if installer.props != nil {
 		return installer.props.PathResolver.RootDir == consts.AgentBinDirMount
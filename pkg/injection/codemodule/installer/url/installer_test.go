@@ -1,8 +1,11 @@
 package url
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 
@@ -11,6 +14,9 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/zip"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/google/go-containerregistry/pkg/name"
+	testregistry "github.com/google/go-containerregistry/pkg/registry"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -191,6 +197,70 @@ func TestInstallAgentFromUrl(t *testing.T) {
 		require.NoError(t, err)
 		// afero can't rename directories properly: https://github.com/spf13/afero/issues/141
 	})
+	t.Run(`falling back to mirror if download fails`, func(t *testing.T) {
+		server := httptest.NewServer(testregistry.New())
+		defer server.Close()
+		serverUrl, err := url.Parse(server.URL)
+		require.NoError(t, err)
+
+		fs := afero.NewMemMapFs()
+		zipFile := zip.SetupTestArchive(t, fs, zip.TestRawZip)
+		_ = zipFile.Close()
+		mirrorImage := serverUrl.Host + "/mirror/oneagent-installer:" + testVersion + "-amd64"
+		ref, err := name.ParseReference(mirrorImage)
+		require.NoError(t, err)
+		_, err = registry.PushArtifact(context.Background(), ref, func() (io.ReadCloser, error) {
+			return fs.Open(zipFile.Name())
+		}, registry.InstallerMediaType)
+		require.NoError(t, err)
+
+		dtc := &dtclient.MockDynatraceClient{}
+		dtc.
+			On("GetAgent", dtclient.OsUnix, dtclient.InstallerTypePaaS, arch.FlavorMultidistro,
+				mock.AnythingOfType("string"), testVersion, mock.AnythingOfType("[]string"), mock.AnythingOfType("*mem.File")).
+			Run(func(args mock.Arguments) {
+				writer, _ := args.Get(6).(io.Writer)
+				_, _ = writer.Write([]byte("partial download"))
+			}).
+			Return(fmt.Errorf(testErrorMessage))
+		dtc.
+			On("GetAgentVersions", dtclient.OsUnix, dtclient.InstallerTypePaaS, arch.FlavorMultidistro, mock.AnythingOfType("string")).
+			Return([]string{testVersion}, nil)
+		installer := &Installer{
+			fs:        fs,
+			dtc:       dtc,
+			extractor: zip.NewOneAgentExtractor(fs, metadata.PathResolver{}),
+			props: &Properties{
+				Os:             dtclient.OsUnix,
+				Type:           dtclient.InstallerTypePaaS,
+				Flavor:         arch.FlavorMultidistro,
+				TargetVersion:  testVersion,
+				MirrorImage:    mirrorImage,
+				MirrorInsecure: true,
+			},
+		}
+
+		err = installer.installAgent(testDir)
+		require.NoError(t, err)
+	})
+	t.Run(`error if download from mirror fails too`, func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		dtc := &dtclient.MockDynatraceClient{}
+		dtc.
+			On("GetAgentViaInstallerUrl", testUrl, mock.AnythingOfType("*mem.File")).
+			Return(fmt.Errorf(testErrorMessage))
+		installer := &Installer{
+			fs:  fs,
+			dtc: dtc,
+			props: &Properties{
+				Url:         testUrl,
+				MirrorImage: "invalid image:",
+			},
+		}
+
+		err := installer.installAgent(testDir)
+		require.Error(t, err)
+	})
 }
 
 func TestIsAlreadyDownloaded(t *testing.T) {
@@ -2,6 +2,7 @@ package zip
 
 import (
 	"os"
+	"path"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
 	"github.com/spf13/afero"
@@ -10,6 +11,21 @@ import (
 type Extractor interface {
 	ExtractZip(sourceFile afero.File, targetDir string) error
 	ExtractGzip(sourceFilePath, targetDir string) error
+	ExtractZipFiltered(sourceFile afero.File, targetDir string, filter Filter) error
+	ExtractGzipFiltered(sourceFilePath, targetDir string, filter Filter) error
+}
+
+// Filter decides if a file of an archive is extracted, it gets the path of the file relative to the root of the archive (e.g. agent/lib64/liboneagentjava.so)
+type Filter func(fileName string) bool
+
+func (filter Filter) includes(fileName string) bool {
+	return filter == nil || filter(CleanFileName(fileName))
+}
+
+// CleanFileName unifies the file names of zip and tar archives,
+// tar.Header.Name == "./path/to/file" and zip.File.Name == "path/to/file" both become "path/to/file"
+func CleanFileName(fileName string) string {
+	return path.Clean(fileName)
 }
 
 func NewOneAgentExtractor(fs afero.Fs, pathResolver metadata.PathResolver) Extractor {
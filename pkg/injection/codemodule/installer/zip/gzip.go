@@ -15,6 +15,10 @@ import (
 )
 
 func (extractor OneAgentExtractor) ExtractGzip(sourceFilePath, targetDir string) error {
+	return extractor.ExtractGzipFiltered(sourceFilePath, targetDir, nil)
+}
+
+func (extractor OneAgentExtractor) ExtractGzipFiltered(sourceFilePath, targetDir string, filter Filter) error {
 	extractor.cleanTempZipDir()
 	fs := extractor.fs
 	targetDir = filepath.Clean(targetDir)
@@ -34,14 +38,14 @@ func (extractor OneAgentExtractor) ExtractGzip(sourceFilePath, targetDir string)
 
 	tmpUnzipDir := extractor.pathResolver.AgentTempUnzipRootDir()
 	tarReader := tar.NewReader(gzipReader)
-	err = extractFilesFromGzip(fs, tmpUnzipDir, tarReader)
+	err = extractFilesFromGzip(fs, tmpUnzipDir, tarReader, filter)
 	if err != nil {
 		return err
 	}
 	return extractor.moveToTargetDir(targetDir)
 }
 
-func extractFilesFromGzip(fs afero.Fs, targetDir string, reader *tar.Reader) error {
+func extractFilesFromGzip(fs afero.Fs, targetDir string, reader *tar.Reader, filter Filter) error {
 	for {
 		header, err := reader.Next()
 		if errors.Is(err, io.EOF) {
@@ -49,6 +53,9 @@ func extractFilesFromGzip(fs afero.Fs, targetDir string, reader *tar.Reader) err
 		} else if err != nil {
 			return errors.WithStack(err)
 		}
+		if !filter.includes(header.Name) {
+			continue
+		}
 
 		target := filepath.Join(targetDir, header.Name)
 
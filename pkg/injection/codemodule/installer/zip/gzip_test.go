@@ -29,8 +29,22 @@ func TestExtractGzip(t *testing.T) {
 		require.NoError(t, err)
 		tarReader := tar.NewReader(reader)
 
-		err = extractFilesFromGzip(fs, TestZipDirName, tarReader)
+		err = extractFilesFromGzip(fs, TestZipDirName, tarReader, nil)
 		require.NoError(t, err)
 		testUnpackedArchive(t, fs)
 	})
+	t.Run(`unzip test gzip file with filter`, func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		gzipFile := SetupTestArchive(t, fs, TestRawGzip)
+
+		defer func() { _ = gzipFile.Close() }()
+
+		reader, err := gzip.NewReader(gzipFile)
+		require.NoError(t, err)
+		tarReader := tar.NewReader(reader)
+
+		err = extractFilesFromGzip(fs, TestZipDirName, tarReader, skipNestedTestFiles)
+		require.NoError(t, err)
+		testFilteredArchive(t, fs)
+	})
 }
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
@@ -55,6 +56,29 @@ func createTestExtractor(fs afero.Fs) Extractor {
 	return NewOneAgentExtractor(fs, metadata.PathResolver{})
 }
 
+// skipNestedTestFiles only keeps the files at the root of test/
+func skipNestedTestFiles(fileName string) bool {
+	return !strings.HasPrefix(fileName, TestZipDirName+"/"+TestZipDirName)
+}
+
+func testFilteredArchive(t *testing.T, fs afero.Fs) {
+	exists, err := afero.Exists(fs, filepath.Join(TestZipDirName, TestZipFilename))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = afero.Exists(fs, filepath.Join(TestZipDirName, common.AgentConfDirPath, common.RuxitConfFileName))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = afero.Exists(fs, filepath.Join(TestZipDirName, TestZipDirName, TestZipFilename))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, err = afero.Exists(fs, filepath.Join(TestZipDirName, TestZipDirName, TestZipDirName, TestZipFilename))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
 func testUnpackedArchive(t *testing.T, fs afero.Fs) {
 	exists, err := afero.Exists(fs, filepath.Join(TestZipDirName, TestZipFilename))
 	require.NoError(t, err)
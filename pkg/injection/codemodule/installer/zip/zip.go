@@ -15,6 +15,10 @@ import (
 )
 
 func (extractor OneAgentExtractor) ExtractZip(sourceFile afero.File, targetDir string) error {
+	return extractor.ExtractZipFiltered(sourceFile, targetDir, nil)
+}
+
+func (extractor OneAgentExtractor) ExtractZipFiltered(sourceFile afero.File, targetDir string, filter Filter) error {
 	extractor.cleanTempZipDir()
 	fs := extractor.fs
 	if sourceFile == nil {
@@ -38,7 +42,7 @@ func (extractor OneAgentExtractor) ExtractZip(sourceFile afero.File, targetDir s
 		extractDest = targetDir
 	}
 
-	err = extractFilesFromZip(fs, extractDest, reader)
+	err = extractFilesFromZip(fs, extractDest, reader, filter)
 	if err != nil {
 		log.Info("failed to extract files from zip", "err", err)
 		return err
@@ -53,11 +57,14 @@ func (extractor OneAgentExtractor) ExtractZip(sourceFile afero.File, targetDir s
 	return nil
 }
 
-func extractFilesFromZip(fs afero.Fs, targetDir string, reader *zip.Reader) error {
+func extractFilesFromZip(fs afero.Fs, targetDir string, reader *zip.Reader, filter Filter) error {
 	if err := fs.MkdirAll(targetDir, common.MkDirFileMode); err != nil {
 		return errors.WithStack(err)
 	}
 	for _, file := range reader.File {
+		if !filter.includes(file.Name) {
+			continue
+		}
 		path := filepath.Join(targetDir, file.Name)
 
 		// Check for ZipSlip: https://snyk.io/research/zip-slip-vulnerability
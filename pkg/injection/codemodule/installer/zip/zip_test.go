@@ -29,8 +29,23 @@ func TestExtractZip(t *testing.T) {
 		reader, err := zip.NewReader(zipFile, fileInfo.Size())
 		require.NoError(t, err)
 
-		err = extractFilesFromZip(fs, TestZipDirName, reader)
+		err = extractFilesFromZip(fs, TestZipDirName, reader, nil)
 		require.NoError(t, err)
 		testUnpackedArchive(t, fs)
 	})
+	t.Run(`unzip test zip file with filter`, func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		zipFile := SetupTestArchive(t, fs, TestRawZip)
+
+		defer func() { _ = zipFile.Close() }()
+
+		fileInfo, err := zipFile.Stat()
+		require.NoError(t, err)
+		reader, err := zip.NewReader(zipFile, fileInfo.Size())
+		require.NoError(t, err)
+
+		err = extractFilesFromZip(fs, TestZipDirName, reader, skipNestedTestFiles)
+		require.NoError(t, err)
+		testFilteredArchive(t, fs)
+	})
 }
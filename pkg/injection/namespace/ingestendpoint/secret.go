@@ -11,6 +11,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/capability"
 	agconsts "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
 	"github.com/pkg/errors"
@@ -24,6 +25,11 @@ const (
 	MetricsUrlSecretField   = "DT_METRICS_INGEST_URL"
 	MetricsTokenSecretField = "DT_METRICS_INGEST_API_TOKEN"
 	configFile              = "endpoint.properties"
+
+	// OtlpEndpointSecretField and OtlpHeadersSecretField are stored as separate keys of the secret,
+	// so they can be referenced by the env vars of the OpenTelemetry SDKs
+	OtlpEndpointSecretField = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	OtlpHeadersSecretField  = "OTEL_EXPORTER_OTLP_HEADERS"
 )
 
 // EndpointSecretGenerator manages the mint endpoint secret generation for the user namespaces.
@@ -149,20 +155,24 @@ func (g *EndpointSecretGenerator) prepare(ctx context.Context, dk *dynatracev1be
 	data := map[string][]byte{
 		configFile: bytes.NewBufferString(endpointPropertiesBuilder.String()).Bytes(),
 	}
+	if dk.Spec.OpenTelemetry != nil {
+		data[OtlpEndpointSecretField] = []byte(fields[OtlpEndpointSecretField])
+		data[OtlpHeadersSecretField] = []byte(fields[OtlpHeadersSecretField])
+	}
 	return data, nil
 }
 
 func (g *EndpointSecretGenerator) PrepareFields(ctx context.Context, dk *dynatracev1beta1.DynaKube) (map[string]string, error) {
 	fields := make(map[string]string)
 
-	var tokens corev1.Secret
-	if err := g.client.Get(ctx, client.ObjectKey{Name: dk.Tokens(), Namespace: g.namespace}, &tokens); err != nil {
-		return nil, errors.WithMessage(err, "failed to query tokens")
+	tokens, err := g.getTokens(ctx, dk)
+	if err != nil {
+		return nil, err
 	}
 
 	if !dk.FeatureDisableMetadataEnrichment() {
-		if token, ok := tokens.Data[dtclient.DynatraceDataIngestToken]; ok {
-			fields[MetricsTokenSecretField] = string(token)
+		if dataIngestToken, ok := tokens[dtclient.DynatraceDataIngestToken]; ok {
+			fields[MetricsTokenSecretField] = dataIngestToken.Value
 		}
 
 		if dataIngestUrl, err := dataIngestUrlFor(dk); err != nil {
@@ -172,9 +182,34 @@ func (g *EndpointSecretGenerator) PrepareFields(ctx context.Context, dk *dynatra
 		}
 	}
 
+	if dk.Spec.OpenTelemetry != nil {
+		if dataIngestToken, ok := tokens[dtclient.DynatraceDataIngestToken]; ok {
+			fields[OtlpHeadersSecretField] = fmt.Sprintf("Authorization=Api-Token %s", dataIngestToken.Value)
+		}
+
+		if otlpUrl, err := otlpUrlFor(dk); err != nil {
+			return nil, err
+		} else {
+			fields[OtlpEndpointSecretField] = otlpUrl
+		}
+	}
+
 	return fields, nil
 }
 
+// getTokens leaves out the data ingest token of file token sources, it would be readable in every monitored namespace otherwise
+func (g *EndpointSecretGenerator) getTokens(ctx context.Context, dk *dynatracev1beta1.DynaKube) (token.Tokens, error) {
+	if _, isFileTokenSource := token.FileTokenSourceDirectory(dk); isFileTokenSource {
+		return token.Tokens{}, nil
+	}
+
+	tokens, err := token.NewTokenSource(g.client, dk).ReadTokens(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query tokens")
+	}
+	return tokens, nil
+}
+
 func dataIngestUrlFor(dk *dynatracev1beta1.DynaKube) (string, error) {
 	switch {
 	case dk.IsActiveGateMode(dynatracev1beta1.MetricsIngestCapability.DisplayName):
@@ -199,3 +234,19 @@ func metricsIngestUrlForClusterActiveGate(dk *dynatracev1beta1.DynaKube) (string
 	serviceName := capability.BuildServiceName(dk.Name, agconsts.MultiActiveGateName)
 	return fmt.Sprintf("http://%s.%s/e/%s/api/v2/metrics/ingest", serviceName, dk.Namespace, tenant), nil
 }
+
+func otlpUrlFor(dk *dynatracev1beta1.DynaKube) (string, error) {
+	switch {
+	case dk.Spec.OpenTelemetry.UseActiveGate:
+		tenant, err := dk.TenantUUIDFromApiUrl()
+		if err != nil {
+			return "", err
+		}
+		serviceName := capability.BuildServiceName(dk.Name, agconsts.MultiActiveGateName)
+		return fmt.Sprintf("http://%s.%s/e/%s/api/v2/otlp", serviceName, dk.Namespace, tenant), nil
+	case len(dk.Spec.APIURL) > 0:
+		return fmt.Sprintf("%s/v2/otlp", dk.Spec.APIURL), nil
+	default:
+		return "", fmt.Errorf("failed to create OTLP endpoint, DynaKube.spec.apiUrl is empty")
+	}
+}
@@ -229,6 +229,62 @@ func TestGenerateDataIngestSecret_ForDynakube(t *testing.T) {
 	})
 }
 
+func TestGenerateDataIngestSecret_OpenTelemetry(t *testing.T) {
+	t.Run("OTLP fields are only added with openTelemetry", func(t *testing.T) {
+		instance := buildTestDynakube()
+		fakeClient := buildTestClientBeforeGenerate(instance)
+
+		testGenerateEndpointsSecret(t, instance, fakeClient)
+
+		var secret corev1.Secret
+		require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace1, Name: consts.EnrichmentEndpointSecretName}, &secret))
+		assert.NotContains(t, secret.Data, OtlpEndpointSecretField)
+		assert.NotContains(t, secret.Data, OtlpHeadersSecretField)
+	})
+	t.Run("OTLP fields point to the tenant", func(t *testing.T) {
+		instance := buildTestDynakube()
+		instance.Spec.OpenTelemetry = &dynatracev1beta1.OpenTelemetrySpec{}
+		fakeClient := buildTestClientBeforeGenerate(instance)
+
+		testGenerateEndpointsSecret(t, instance, fakeClient)
+
+		var secret corev1.Secret
+		require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace1, Name: consts.EnrichmentEndpointSecretName}, &secret))
+		assert.Equal(t, "https://tenant.test/api/v2/otlp", string(secret.Data[OtlpEndpointSecretField]))
+		assert.Equal(t, "Authorization=Api-Token test-data-ingest-token", string(secret.Data[OtlpHeadersSecretField]))
+		assert.Equal(t, testDataIngestSecretWithMetrics, string(secret.Data["endpoint.properties"]))
+	})
+	t.Run("OTLP fields point to the ActiveGate", func(t *testing.T) {
+		instance := buildTestDynakube()
+		instance.Spec.OpenTelemetry = &dynatracev1beta1.OpenTelemetrySpec{UseActiveGate: true}
+		fakeClient := buildTestClientBeforeGenerate(instance)
+
+		testGenerateEndpointsSecret(t, instance, fakeClient)
+
+		var secret corev1.Secret
+		require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace1, Name: consts.EnrichmentEndpointSecretName}, &secret))
+		assert.Equal(t, "http://dynakube-activegate.dynatrace/e/tenant/api/v2/otlp", string(secret.Data[OtlpEndpointSecretField]))
+	})
+}
+
+func TestGenerateDataIngestSecret_FileTokenSource(t *testing.T) {
+	t.Run("data ingest token of file token source isn't copied into the namespaces", func(t *testing.T) {
+		instance := buildTestDynakube()
+		instance.Spec.TokenSource = &dynatracev1beta1.TokenSourceSpec{File: &dynatracev1beta1.FileTokenSource{}}
+		instance.Spec.OpenTelemetry = &dynatracev1beta1.OpenTelemetrySpec{}
+		fakeClient := buildTestClientBeforeGenerate(instance)
+
+		testGenerateEndpointsSecret(t, instance, fakeClient)
+
+		var secret corev1.Secret
+		require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace1, Name: consts.EnrichmentEndpointSecretName}, &secret))
+		assert.Equal(t, "https://tenant.test/api/v2/otlp", string(secret.Data[OtlpEndpointSecretField]))
+		for field, value := range secret.Data {
+			assert.NotContains(t, string(value), testDataIngestToken, field)
+		}
+	})
+}
+
 func testGenerateEndpointsSecret(t *testing.T, instance *dynatracev1beta1.DynaKube, fakeClient client.Client) {
 	endpointSecretGenerator := NewEndpointSecretGenerator(fakeClient, fakeClient, testNamespaceDynatrace)
 
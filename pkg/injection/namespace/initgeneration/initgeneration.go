@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
-	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/startup"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
@@ -130,13 +130,12 @@ func (g *InitGenerator) generate(ctx context.Context, dk *dynatracev1beta1.DynaK
 }
 
 func (g *InitGenerator) createSecretConfigForDynaKube(ctx context.Context, dynakube *dynatracev1beta1.DynaKube, kubeSystemUID types.UID, hostMonitoringNodes map[string]string) (*startup.SecretConfig, error) {
-	var tokens corev1.Secret
-	if err := g.client.Get(ctx, client.ObjectKey{Name: dynakube.Tokens(), Namespace: g.namespace}, &tokens); err != nil {
-		return nil, errors.WithMessage(err, "failed to query tokens")
+	tokens, err := g.getTokens(ctx, dynakube)
+	if err != nil {
+		return nil, err
 	}
 
 	var proxy string
-	var err error
 	if dynakube.NeedsOneAgentProxy() {
 		proxy, err = dynakube.Proxy(ctx, g.apiReader)
 		if err != nil {
@@ -173,15 +172,30 @@ func (g *InitGenerator) createSecretConfigForDynaKube(ctx context.Context, dynak
 	}, nil
 }
 
-func getPaasToken(tokens corev1.Secret) string {
-	if len(tokens.Data[dtclient.DynatracePaasToken]) != 0 {
-		return string(tokens.Data[dtclient.DynatracePaasToken])
+// getTokens returns no tokens for DynaKubes with a file token source, the files are only mounted into the pods of the operator
+// and must not be copied into the init secrets of the monitored namespaces.
+// The init container only needs the tokens to download the code modules, which the validation doesn't allow for file token sources.
+func (g *InitGenerator) getTokens(ctx context.Context, dynakube *dynatracev1beta1.DynaKube) (token.Tokens, error) {
+	if _, isFileTokenSource := token.FileTokenSourceDirectory(dynakube); isFileTokenSource {
+		return token.Tokens{}, nil
+	}
+
+	tokens, err := token.NewTokenSource(g.client, dynakube).ReadTokens(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query tokens")
+	}
+	return tokens, nil
+}
+
+func getPaasToken(tokens token.Tokens) string {
+	if paasToken := tokens.PaasToken().Value; len(paasToken) != 0 {
+		return paasToken
 	}
-	return string(tokens.Data[dtclient.DynatraceApiToken])
+	return tokens.ApiToken().Value
 }
 
-func getAPIToken(tokens corev1.Secret) string {
-	return string(tokens.Data[dtclient.DynatraceApiToken])
+func getAPIToken(tokens token.Tokens) string {
+	return tokens.ApiToken().Value
 }
 
 // getHostMonitoringNodes creates a mapping between all the nodes and the tenantUID for the host-monitoring dynakube on that node.
@@ -76,6 +76,30 @@ func TestGenerateForNamespace(t *testing.T) {
 		checkSecretConfigExists(t, initSecret)
 		checkProxy(t, initSecret, "")
 	})
+	t.Run("Add secret without tokens for dynakube with file token source", func(t *testing.T) {
+		dynakube := createDynakube()
+		dynakube.Spec.TokenSource = &dynatracev1beta1.TokenSourceSpec{File: &dynatracev1beta1.FileTokenSource{}}
+
+		// the token secret is ignored for file token sources, it must not end up in the init secret either
+		apiToken := "api-test"
+		apiTokenSecret := createApiTokenSecret(dynakube, apiToken, apiToken)
+
+		testNamespace := createTestInjectedNamespace(dynakube, "test")
+		clt := fake.NewClient(dynakube, testNamespace, apiTokenSecret, getKubeNamespace())
+		ig := NewInitGenerator(clt, clt, dynakube.Namespace)
+
+		err := ig.GenerateForNamespace(context.TODO(), *dynakube, testNamespace.Name)
+		require.NoError(t, err)
+
+		initSecret := retrieveInitSecret(t, clt, testNamespace.Name)
+		var secretConfig startup.SecretConfig
+		require.NoError(t, json.Unmarshal(initSecret.Data[consts.AgentInitSecretConfigField], &secretConfig))
+		assert.Empty(t, secretConfig.ApiToken)
+		assert.Empty(t, secretConfig.PaasToken)
+		for _, value := range initSecret.Data {
+			assert.NotContains(t, string(value), apiToken)
+		}
+	})
 }
 
 func TestGenerateForDynakube(t *testing.T) {
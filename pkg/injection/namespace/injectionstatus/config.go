@@ -0,0 +1,17 @@
+package injectionstatus
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/logger"
+)
+
+const (
+	// SkippedReasonDisabled is used for pods that disabled the injection via annotation.
+	SkippedReasonDisabled = "InjectionDisabled"
+
+	// SkippedReasonNotInjected is used for pods the webhook didn't mutate, like pods created before their namespace was monitored.
+	SkippedReasonNotInjected = "NotInjected"
+)
+
+var (
+	log = logger.Factory.GetLogger("injection-status")
+)
@@ -0,0 +1,157 @@
+package injectionstatus
+
+import (
+	"context"
+	"encoding/json"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reconciler aggregates the injection outcome of the pods in the namespaces monitored by a DynaKube,
+// the totals are stored in the DynaKube status and a summary per namespace in a namespace annotation
+type Reconciler struct {
+	client    client.Client
+	apiReader client.Reader
+	dynakube  *dynatracev1beta1.DynaKube
+}
+
+func NewReconciler(clt client.Client, apiReader client.Reader, dynakube *dynatracev1beta1.DynaKube) *Reconciler {
+	return &Reconciler{
+		client:    clt,
+		apiReader: apiReader,
+		dynakube:  dynakube,
+	}
+}
+
+func (reconciler *Reconciler) Reconcile(ctx context.Context) error {
+	if !reconciler.dynakube.NeedAppInjection() {
+		reconciler.dynakube.Status.Injection = dynatracev1beta1.InjectionStatus{}
+		return nil
+	}
+
+	namespaces, err := mapper.GetNamespacesForDynakube(ctx, reconciler.apiReader, reconciler.dynakube.Name)
+	if err != nil {
+		return errors.WithMessage(err, "failed to list monitored namespaces")
+	}
+
+	injectionStatus := dynatracev1beta1.InjectionStatus{
+		Namespaces: int32(len(namespaces)),
+	}
+	for i := range namespaces {
+		namespace := &namespaces[i]
+		counts, err := reconciler.countPods(ctx, namespace.Name)
+		if err != nil {
+			return err
+		}
+		addCounts(&injectionStatus.Pods, counts)
+		if counts.Failed > 0 {
+			injectionStatus.FailedNamespaces = append(injectionStatus.FailedNamespaces, namespace.Name)
+		}
+
+		if err := reconciler.updateSummary(ctx, namespace, counts); err != nil {
+			return err
+		}
+	}
+	reconciler.dynakube.Status.Injection = injectionStatus
+	return nil
+}
+
+func (reconciler *Reconciler) countPods(ctx context.Context, namespace string) (dynatracev1beta1.InjectionCounts, error) {
+	var counts dynatracev1beta1.InjectionCounts
+
+	var pods corev1.PodList
+	if err := reconciler.client.List(ctx, &pods, client.InNamespace(namespace)); err != nil {
+		return counts, errors.WithMessagef(err, "failed to list pods of namespace %s", namespace)
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodPending && pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		countPod(&counts, pod)
+	}
+	return counts, nil
+}
+
+func countPod(counts *dynatracev1beta1.InjectionCounts, pod corev1.Pod) {
+	switch {
+	case installContainerFailed(pod):
+		counts.Failed++
+	case !kubeobjects.GetFieldBool(pod.Annotations, dtwebhook.AnnotationDynatraceInject, true):
+		countSkipped(counts, SkippedReasonDisabled)
+	case pod.Annotations[dtwebhook.AnnotationOneAgentReason] != "":
+		countSkipped(counts, pod.Annotations[dtwebhook.AnnotationOneAgentReason])
+	case kubeobjects.GetFieldBool(pod.Annotations, dtwebhook.AnnotationDynatraceInjected, false):
+		counts.Injected++
+	default:
+		countSkipped(counts, SkippedReasonNotInjected)
+	}
+}
+
+// installContainerFailed returns true if the last run of the install container exited with an error,
+// or masked one because of the "silent" failure policy
+func installContainerFailed(pod corev1.Pod) bool {
+	for _, containerStatus := range pod.Status.InitContainerStatuses {
+		if containerStatus.Name != dtwebhook.InstallContainerName {
+			continue
+		}
+		if containerStatus.State.Terminated != nil {
+			return terminatedWithFailure(*containerStatus.State.Terminated)
+		}
+		lastTermination := containerStatus.LastTerminationState.Terminated
+		return lastTermination != nil && terminatedWithFailure(*lastTermination)
+	}
+	return false
+}
+
+func terminatedWithFailure(termination corev1.ContainerStateTerminated) bool {
+	return termination.ExitCode != 0 || termination.Message == consts.InjectionSilentFailureMessage
+}
+
+func countSkipped(counts *dynatracev1beta1.InjectionCounts, reason string) {
+	if counts.Skipped == nil {
+		counts.Skipped = make(map[string]int32)
+	}
+	counts.Skipped[reason]++
+}
+
+func addCounts(total *dynatracev1beta1.InjectionCounts, counts dynatracev1beta1.InjectionCounts) {
+	total.Injected += counts.Injected
+	total.Failed += counts.Failed
+	for reason, skipped := range counts.Skipped {
+		if total.Skipped == nil {
+			total.Skipped = make(map[string]int32)
+		}
+		total.Skipped[reason] += skipped
+	}
+}
+
+func (reconciler *Reconciler) updateSummary(ctx context.Context, namespace *corev1.Namespace, counts dynatracev1beta1.InjectionCounts) error {
+	summary, err := json.Marshal(counts)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if namespace.Annotations[dtwebhook.AnnotationInjectionSummary] == string(summary) {
+		return nil
+	}
+
+	if namespace.Annotations == nil {
+		namespace.Annotations = make(map[string]string)
+	}
+	namespace.Annotations[dtwebhook.AnnotationInjectionSummary] = string(summary)
+	namespace.Annotations[mapper.UpdatedViaDynakubeAnnotation] = "true"
+
+	err = reconciler.client.Update(ctx, namespace)
+	if k8serrors.IsConflict(err) {
+		log.Info("could not update injection summary due to conflict", "namespace", namespace.Name)
+		return nil
+	}
+	return errors.WithMessagef(err, "failed to update injection summary of namespace %s", namespace.Name)
+}
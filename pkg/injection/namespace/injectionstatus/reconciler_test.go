@@ -0,0 +1,172 @@
+package injectionstatus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testDynakubeName = "dynakube"
+	testNamespace    = "monitored"
+	testNamespace2   = "monitored-2"
+)
+
+func TestReconcile(t *testing.T) {
+	t.Run("aggregate injection outcome of monitored namespaces", func(t *testing.T) {
+		dynakube := createTestDynakube()
+		clt := fake.NewClient(
+			createTestNamespace(testNamespace, testDynakubeName),
+			createTestNamespace(testNamespace2, testDynakubeName),
+			createTestNamespace("other", "other-dynakube"),
+			createTestPod("injected", testNamespace, map[string]string{dtwebhook.AnnotationDynatraceInjected: "true"}, nil),
+			createTestPod("failed", testNamespace, map[string]string{dtwebhook.AnnotationDynatraceInjected: "true"}, &corev1.ContainerStateTerminated{ExitCode: 1}),
+			createTestPod("disabled", testNamespace2, map[string]string{dtwebhook.AnnotationDynatraceInject: "false"}, nil),
+			createTestPod("no-connection", testNamespace2, map[string]string{
+				dtwebhook.AnnotationDynatraceInjected: "true",
+				dtwebhook.AnnotationOneAgentInjected:  "false",
+				dtwebhook.AnnotationOneAgentReason:    dtwebhook.EmptyConnectionInfoReason,
+			}, nil),
+			createTestPod("not-injected", testNamespace2, nil, nil),
+			createTestPod("other", "other", map[string]string{dtwebhook.AnnotationDynatraceInjected: "true"}, nil),
+		)
+
+		err := NewReconciler(clt, clt, dynakube).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, dynatracev1beta1.InjectionStatus{
+			Namespaces: 2,
+			Pods: dynatracev1beta1.InjectionCounts{
+				Injected: 1,
+				Failed:   1,
+				Skipped: map[string]int32{
+					SkippedReasonDisabled:               1,
+					SkippedReasonNotInjected:            1,
+					dtwebhook.EmptyConnectionInfoReason: 1,
+				},
+			},
+			FailedNamespaces: []string{testNamespace},
+		}, dynakube.Status.Injection)
+
+		assertSummary(t, clt, testNamespace, `{"injected":1,"failed":1}`)
+		assertSummary(t, clt, testNamespace2, `{"injected":0,"failed":0,"skipped":{"EmptyConnectionInfo":1,"InjectionDisabled":1,"NotInjected":1}}`)
+		assertSummary(t, clt, "other", "")
+	})
+	t.Run("finished pods and successful install container are not failed", func(t *testing.T) {
+		dynakube := createTestDynakube()
+		finishedPod := createTestPod("finished", testNamespace, map[string]string{dtwebhook.AnnotationDynatraceInjected: "true"}, &corev1.ContainerStateTerminated{ExitCode: 1})
+		finishedPod.Status.Phase = corev1.PodSucceeded
+		clt := fake.NewClient(
+			createTestNamespace(testNamespace, testDynakubeName),
+			finishedPod,
+			createTestPod("successful", testNamespace, map[string]string{dtwebhook.AnnotationDynatraceInjected: "true"}, &corev1.ContainerStateTerminated{ExitCode: 0}),
+		)
+
+		err := NewReconciler(clt, clt, dynakube).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, dynatracev1beta1.InjectionCounts{Injected: 1}, dynakube.Status.Injection.Pods)
+		assert.Empty(t, dynakube.Status.Injection.FailedNamespaces)
+	})
+	t.Run("previous failure of restarted install container is failed", func(t *testing.T) {
+		pod := createTestPod("restarted", testNamespace, map[string]string{dtwebhook.AnnotationDynatraceInjected: "true"}, nil)
+		pod.Status.InitContainerStatuses[0].LastTerminationState.Terminated = &corev1.ContainerStateTerminated{ExitCode: 1}
+		counts := dynatracev1beta1.InjectionCounts{}
+
+		countPod(&counts, *pod)
+
+		assert.Equal(t, int32(1), counts.Failed)
+	})
+	t.Run("install container with silently masked error is failed", func(t *testing.T) {
+		pod := createTestPod("silent", testNamespace, map[string]string{dtwebhook.AnnotationDynatraceInjected: "true"},
+			&corev1.ContainerStateTerminated{ExitCode: 0, Message: consts.InjectionSilentFailureMessage})
+		counts := dynatracev1beta1.InjectionCounts{}
+
+		countPod(&counts, *pod)
+
+		assert.Equal(t, int32(1), counts.Failed)
+		assert.Zero(t, counts.Injected)
+	})
+	t.Run("unchanged summary doesn't update namespace", func(t *testing.T) {
+		dynakube := createTestDynakube()
+		namespace := createTestNamespace(testNamespace, testDynakubeName)
+		namespace.Annotations = map[string]string{dtwebhook.AnnotationInjectionSummary: `{"injected":0,"failed":0}`}
+		clt := fake.NewClient(namespace)
+
+		err := NewReconciler(clt, clt, dynakube).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		var updatedNamespace corev1.Namespace
+		require.NoError(t, clt.Get(context.Background(), client.ObjectKey{Name: testNamespace}, &updatedNamespace))
+		assert.NotContains(t, updatedNamespace.Annotations, mapper.UpdatedViaDynakubeAnnotation)
+	})
+	t.Run("reset status without app injection", func(t *testing.T) {
+		dynakube := createTestDynakube()
+		dynakube.Spec.OneAgent.ApplicationMonitoring = nil
+		dynakube.Status.Injection.Namespaces = 1
+		clt := fake.NewClient()
+
+		err := NewReconciler(clt, clt, dynakube).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, dynatracev1beta1.InjectionStatus{}, dynakube.Status.Injection)
+	})
+}
+
+func assertSummary(t *testing.T, clt client.Client, namespaceName, expectedSummary string) {
+	var namespace corev1.Namespace
+	require.NoError(t, clt.Get(context.Background(), client.ObjectKey{Name: namespaceName}, &namespace))
+	assert.Equal(t, expectedSummary, namespace.Annotations[dtwebhook.AnnotationInjectionSummary])
+}
+
+func createTestDynakube() *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testDynakubeName,
+			Namespace: "dynatrace",
+		},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ApplicationMonitoring: &dynatracev1beta1.ApplicationMonitoringSpec{},
+			},
+		},
+	}
+}
+
+func createTestNamespace(name, dynakubeName string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{dtwebhook.InjectionInstanceLabel: dynakubeName},
+		},
+	}
+}
+
+func createTestPod(name, namespace string, annotations map[string]string, installContainerState *corev1.ContainerStateTerminated) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  dtwebhook.InstallContainerName,
+					State: corev1.ContainerState{Terminated: installContainerState},
+				},
+			},
+		},
+	}
+}
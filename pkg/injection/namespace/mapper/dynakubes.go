@@ -55,8 +55,8 @@ func (dm DynakubeMapper) UnmapFromDynaKube() error {
 		return errors.WithMessagef(err, "failed to list namespaces for dynakube %s", dm.dk.Name)
 	}
 	for _, ns := range nsList {
-		delete(ns.Labels, dtwebhook.InjectionInstanceLabel)
 		ns := ns
+		removeNamespaceInjectLabel(&ns)
 		setUpdatedViaDynakubeAnnotation(&ns)
 		if err := dm.client.Update(dm.ctx, &ns); err != nil {
 			return errors.WithMessagef(err, "failed to remove label %s from namespace %s", dtwebhook.InjectionInstanceLabel, ns.Name)
@@ -47,6 +47,12 @@ func addNamespaceInjectLabel(dkName string, ns *corev1.Namespace) {
 	ns.Labels[dtwebhook.InjectionInstanceLabel] = dkName
 }
 
+// removeNamespaceInjectLabel also removes the injection summary, as it is only maintained for monitored namespaces
+func removeNamespaceInjectLabel(ns *corev1.Namespace) {
+	delete(ns.Labels, dtwebhook.InjectionInstanceLabel)
+	delete(ns.Annotations, dtwebhook.AnnotationInjectionSummary)
+}
+
 func setUpdatedViaDynakubeAnnotation(ns *corev1.Namespace) {
 	if ns.Annotations == nil {
 		ns.Annotations = make(map[string]string)
@@ -113,7 +119,7 @@ func updateLabels(matches bool, dynakube *dynatracev1beta1.DynaKube, namespace *
 		}
 	} else if instanceLabelFound && associatedDynakubeName == dynakube.Name {
 		updated = true
-		delete(namespace.Labels, dtwebhook.InjectionInstanceLabel)
+		removeNamespaceInjectLabel(namespace)
 	}
 	return updated
 }
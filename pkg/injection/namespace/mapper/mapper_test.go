@@ -86,6 +86,21 @@ func TestUpdateNamespace(t *testing.T) {
 		require.True(t, updated)
 		assert.Equal(t, 0, len(namespace.Labels))
 	})
+	t.Run("Remove injection summary of no longer matching ns", func(t *testing.T) {
+		labels := map[string]string{"test": "selector"}
+		movedDk := createTestDynakubeWithAppInject("moved-dk", labels, nil)
+		nsLabels := map[string]string{
+			dtwebhook.InjectionInstanceLabel: movedDk.Name,
+		}
+		namespace := createNamespace("test-namespace", nsLabels)
+		namespace.Annotations = map[string]string{dtwebhook.AnnotationInjectionSummary: `{"injected":1,"failed":0}`}
+
+		updated, err := updateNamespace(namespace, &dynatracev1beta1.DynaKubeList{Items: []dynatracev1beta1.DynaKube{*movedDk}})
+
+		require.NoError(t, err)
+		require.True(t, updated)
+		assert.NotContains(t, namespace.Annotations, dtwebhook.AnnotationInjectionSummary)
+	})
 	t.Run("Throw error in case of conflicting Dynakubes", func(t *testing.T) {
 		labels := map[string]string{"test": "selector"}
 		dk := createTestDynakubeWithMultipleFeatures("dk-test", labels)
@@ -0,0 +1,22 @@
+package rollout
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/logger"
+)
+
+const (
+	// AnnotationRolloutVersion is set by the operator on the pod template of restarted workloads,
+	// it contains the code modules version the workload was restarted for, so it's restarted only once per version.
+	AnnotationRolloutVersion = "oneagent.dynatrace.com/rollout-version"
+
+	// AnnotationSkippedPodsRestart is set by the operator on the pod template of workloads restarted because the webhook admitted their pods
+	// without injection, as the mutation exceeded its time budget. It contains the creation time of the newest of these pods,
+	// so the workload is only restarted again if pods created later were skipped as well.
+	AnnotationSkippedPodsRestart = "oneagent.dynatrace.com/skipped-pods-restart"
+
+	defaultMaxConcurrentRollouts = 1
+)
+
+var (
+	log = logger.Factory.GetLogger("injection-rollout")
+)
@@ -0,0 +1,213 @@
+package rollout
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reconciler restarts the workloads of pods injected with an outdated code modules version, in the namespaces listed by rolloutOnUpgrade.
+// Workloads of pods the webhook admitted without injection, because their mutation exceeded its time budget, are restarted
+// in all monitored namespaces, as these pods would otherwise stay without monitoring until they're recreated.
+// Only a limited number of workloads is restarted at the same time, workloads blocked by a PodDisruptionBudget are postponed.
+type Reconciler struct {
+	client    client.Client
+	apiReader client.Reader
+	dynakube  *dynatracev1beta1.DynaKube
+}
+
+func NewReconciler(clt client.Client, apiReader client.Reader, dynakube *dynatracev1beta1.DynaKube) *Reconciler {
+	return &Reconciler{
+		client:    clt,
+		apiReader: apiReader,
+		dynakube:  dynakube,
+	}
+}
+
+func (reconciler *Reconciler) Reconcile(ctx context.Context) error {
+	if !reconciler.dynakube.NeedAppInjection() {
+		return nil
+	}
+	rolloutSpec := reconciler.dynakube.Spec.RolloutOnUpgrade
+	version := reconciler.dynakube.CodeModulesVersion()
+
+	namespaces, err := mapper.GetNamespacesForDynakube(ctx, reconciler.apiReader, reconciler.dynakube.Name)
+	if err != nil {
+		return errors.WithMessage(err, "failed to list monitored namespaces")
+	}
+
+	var pending []*workload
+	inProgress := 0
+	for _, namespace := range namespaces {
+		// outdated pods are only looked for in the namespaces listed by rolloutOnUpgrade
+		outdatedVersion := ""
+		if rolloutSpec != nil && slices.Contains(rolloutSpec.Namespaces, namespace.Name) {
+			outdatedVersion = version
+		}
+
+		workloads, err := reconciler.findWorkloadsToRestart(ctx, namespace.Name, outdatedVersion)
+		if err != nil {
+			return err
+		}
+		for _, restartedWorkload := range workloads {
+			switch {
+			case restartedWorkload.needsRestart(version):
+				pending = append(pending, restartedWorkload)
+			case !restartedWorkload.rolloutFinished:
+				inProgress++
+			default:
+				log.Info("workload was already restarted, but still runs outdated pods", "workload", restartedWorkload.key())
+			}
+		}
+	}
+
+	maxConcurrentRollouts := defaultMaxConcurrentRollouts
+	if rolloutSpec != nil && rolloutSpec.MaxConcurrentRollouts > 0 {
+		maxConcurrentRollouts = int(rolloutSpec.MaxConcurrentRollouts)
+	}
+	for _, pendingWorkload := range pending {
+		if inProgress >= maxConcurrentRollouts {
+			log.Info("max concurrent rollouts reached, remaining workloads are restarted later", "pending", len(pending))
+			return nil
+		}
+
+		blocked, err := reconciler.isBlockedByDisruptionBudget(ctx, pendingWorkload)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			log.Info("workload is blocked by a PodDisruptionBudget, restart is postponed", "workload", pendingWorkload.key())
+			continue
+		}
+
+		if err := reconciler.restart(ctx, pendingWorkload, version); err != nil {
+			return err
+		}
+		inProgress++
+	}
+	return nil
+}
+
+// findWorkloadsToRestart returns the workloads of the namespace with pods injected with another code modules version
+// or pods skipped by the webhook, sorted by their kind and name. Outdated pods are ignored if the version is empty.
+func (reconciler *Reconciler) findWorkloadsToRestart(ctx context.Context, namespaceName, version string) ([]*workload, error) {
+	// the cached client is used, the pods of all namespaces are part of the cache of the operator
+	var pods corev1.PodList
+	if err := reconciler.client.List(ctx, &pods, client.InNamespace(namespaceName)); err != nil {
+		return nil, errors.WithMessagef(err, "failed to list pods of namespace %s", namespaceName)
+	}
+
+	// the workloads aren't cached, only the few controlling outdated or skipped pods are read
+	finder := newWorkloadFinder(reconciler.apiReader)
+	workloads := map[string]*workload{}
+	for _, pod := range pods.Items {
+		outdated := isOutdated(pod, version)
+		skipped := isSkipped(pod)
+		if !outdated && !skipped {
+			continue
+		}
+
+		podWorkload, err := finder.find(ctx, pod)
+		if err != nil {
+			return nil, err
+		}
+		if podWorkload == nil {
+			continue
+		}
+		if known, ok := workloads[podWorkload.key()]; ok {
+			podWorkload = known
+		} else {
+			workloads[podWorkload.key()] = podWorkload
+		}
+
+		podWorkload.hasOutdatedPods = podWorkload.hasOutdatedPods || outdated
+		if skipped && podWorkload.lastSkippedPod.Before(&pod.CreationTimestamp) {
+			podWorkload.lastSkippedPod = pod.CreationTimestamp
+		}
+	}
+
+	keys := make([]string, 0, len(workloads))
+	for key := range workloads {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sortedWorkloads := make([]*workload, 0, len(keys))
+	for _, key := range keys {
+		sortedWorkloads = append(sortedWorkloads, workloads[key])
+	}
+	return sortedWorkloads, nil
+}
+
+// isOutdated is true for pods injected with another version, pods injected before the version was recorded are outdated as well
+func isOutdated(pod corev1.Pod, version string) bool {
+	if pod.DeletionTimestamp != nil || version == "" {
+		return false
+	}
+	if !kubeobjects.GetFieldBool(pod.Annotations, dtwebhook.AnnotationOneAgentInjected, false) {
+		return false
+	}
+	return pod.Annotations[dtwebhook.AnnotationOneAgentVersion] != version
+}
+
+// isSkipped is true for pods which weren't injected, because the webhook exceeded its time budget
+func isSkipped(pod corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || kubeobjects.GetFieldBool(pod.Annotations, dtwebhook.AnnotationOneAgentInjected, false) {
+		return false
+	}
+	return pod.Annotations[dtwebhook.AnnotationOneAgentReason] == dtwebhook.TimeBudgetExceededReason
+}
+
+// isBlockedByDisruptionBudget checks if a PodDisruptionBudget selecting the pods of the workload doesn't allow any disruption right now
+func (reconciler *Reconciler) isBlockedByDisruptionBudget(ctx context.Context, blockedWorkload *workload) (bool, error) {
+	var disruptionBudgets policyv1.PodDisruptionBudgetList
+	if err := reconciler.apiReader.List(ctx, &disruptionBudgets, client.InNamespace(blockedWorkload.object.GetNamespace())); err != nil {
+		return false, errors.WithMessagef(err, "failed to list pod disruption budgets of namespace %s", blockedWorkload.object.GetNamespace())
+	}
+
+	podLabels := labels.Set(blockedWorkload.template.Labels)
+	for _, disruptionBudget := range disruptionBudgets.Items {
+		selector, err := metav1.LabelSelectorAsSelector(disruptionBudget.Spec.Selector)
+		if err != nil {
+			log.Info("ignoring pod disruption budget with invalid selector", "podDisruptionBudget", disruptionBudget.Name, "error", err.Error())
+			continue
+		}
+		if selector.Matches(podLabels) && disruptionBudget.Status.DisruptionsAllowed < 1 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// restart triggers a rollout of the workload, the same way as kubectl rollout restart, by annotating its pod template
+func (reconciler *Reconciler) restart(ctx context.Context, restartedWorkload *workload, version string) error {
+	patch := client.MergeFrom(restartedWorkload.object.DeepCopyObject().(client.Object))
+	if restartedWorkload.template.Annotations == nil {
+		restartedWorkload.template.Annotations = make(map[string]string)
+	}
+	if restartedWorkload.hasOutdatedPods {
+		restartedWorkload.template.Annotations[AnnotationRolloutVersion] = version
+	}
+	if !restartedWorkload.lastSkippedPod.IsZero() {
+		restartedWorkload.template.Annotations[AnnotationSkippedPodsRestart] = restartedWorkload.lastSkippedPod.UTC().Format(time.RFC3339)
+	}
+
+	if err := reconciler.client.Patch(ctx, restartedWorkload.object, patch); err != nil {
+		return errors.WithMessagef(err, "failed to restart %s", restartedWorkload.key())
+	}
+	log.Info("restarted workload to inject its pods", "workload", restartedWorkload.key(),
+		"version", version, "skippedPods", !restartedWorkload.lastSkippedPod.IsZero())
+	return nil
+}
@@ -0,0 +1,352 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const (
+	testDynakubeName = "dynakube"
+	testNamespace    = "shop"
+	testVersion      = "1.2.3"
+	testOldVersion   = "1.0.0"
+)
+
+var testSkipTime = time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+func TestReconcile(t *testing.T) {
+	t.Run("restart workloads of outdated pods", func(t *testing.T) {
+		dynakube := createTestDynakube(2, testNamespace, "not-allowed")
+		clt := fake.NewClient(
+			createTestNamespace(testNamespace, testDynakubeName),
+			createTestNamespace("not-allowed", "other-dynakube"),
+		)
+		createTestDeployment(t, clt, testNamespace, "outdated", testOldVersion)
+		createTestDeployment(t, clt, testNamespace, "not-recorded", "")
+		createTestDeployment(t, clt, testNamespace, "up-to-date", testVersion)
+		createTestDeployment(t, clt, "not-allowed", "other", testOldVersion)
+		createTestStatefulSet(t, clt, testNamespace, "stateful", testOldVersion)
+
+		err := NewReconciler(clt, clt, dynakube).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "not-recorded", testVersion)
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "outdated", testVersion)
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "up-to-date", "")
+		assertDeploymentRolloutVersion(t, clt, "not-allowed", "other", "")
+
+		var statefulSet appsv1.StatefulSet
+		require.NoError(t, clt.Get(context.Background(), client.ObjectKey{Name: "stateful", Namespace: testNamespace}, &statefulSet))
+		assert.Empty(t, statefulSet.Spec.Template.Annotations[AnnotationRolloutVersion], "max concurrent rollouts already reached")
+	})
+	t.Run("restart workloads of pods skipped by the webhook", func(t *testing.T) {
+		dynakube := createTestDynakube(1, testNamespace)
+		clt := fake.NewClient(createTestNamespace(testNamespace, testDynakubeName))
+		createTestDeployment(t, clt, testNamespace, "skipped", testVersion)
+		skipTestPod(t, clt, "skipped-pod", testSkipTime)
+
+		err := NewReconciler(clt, clt, dynakube).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "skipped", "")
+		assertDeploymentSkippedPodsRestart(t, clt, "skipped", testSkipTime)
+	})
+	t.Run("skipped pods are restarted regardless of the version", func(t *testing.T) {
+		dynakube := createTestDynakube(1, testNamespace)
+		dynakube.Status.CodeModules.Version = ""
+		clt := fake.NewClient(createTestNamespace(testNamespace, testDynakubeName))
+		createTestDeployment(t, clt, testNamespace, "restarted", testVersion)
+		setDeploymentTemplateAnnotation(t, clt, "restarted", AnnotationRolloutVersion, testVersion)
+		skipTestPod(t, clt, "restarted-pod", testSkipTime)
+
+		err := NewReconciler(clt, clt, dynakube).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		assertDeploymentSkippedPodsRestart(t, clt, "restarted", testSkipTime)
+	})
+	t.Run("skipped pods are restarted without rolloutOnUpgrade", func(t *testing.T) {
+		dynakube := createTestDynakube(1, testNamespace)
+		dynakube.Spec.RolloutOnUpgrade = nil
+		clt := fake.NewClient(createTestNamespace(testNamespace, testDynakubeName))
+		createTestDeployment(t, clt, testNamespace, "skipped", testOldVersion)
+		skipTestPod(t, clt, "skipped-pod", testSkipTime)
+
+		err := NewReconciler(clt, clt, dynakube).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "skipped", "")
+		assertDeploymentSkippedPodsRestart(t, clt, "skipped", testSkipTime)
+	})
+	t.Run("skipped pods are restarted in monitored namespaces not listed by rolloutOnUpgrade", func(t *testing.T) {
+		dynakube := createTestDynakube(1, "other-namespace")
+		clt := fake.NewClient(createTestNamespace(testNamespace, testDynakubeName))
+		createTestDeployment(t, clt, testNamespace, "outdated", testOldVersion)
+		createTestDeployment(t, clt, testNamespace, "skipped", testVersion)
+		skipTestPod(t, clt, "skipped-pod", testSkipTime)
+
+		err := NewReconciler(clt, clt, dynakube).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "outdated", "")
+		assertDeploymentSkippedPodsRestart(t, clt, "skipped", testSkipTime)
+	})
+	t.Run("workload is restarted again only for pods skipped after the last restart", func(t *testing.T) {
+		dynakube := createTestDynakube(1, testNamespace)
+		clt := fake.NewClient(createTestNamespace(testNamespace, testDynakubeName))
+		createTestDeployment(t, clt, testNamespace, "skipped", testVersion)
+		setDeploymentTemplateAnnotation(t, clt, "skipped", AnnotationSkippedPodsRestart, testSkipTime.Format(time.RFC3339))
+		skipTestPod(t, clt, "skipped-pod", testSkipTime)
+		setDeploymentRolloutFinished(t, clt, "skipped")
+
+		require.NoError(t, NewReconciler(clt, clt, dynakube).Reconcile(context.Background()))
+		assertDeploymentSkippedPodsRestart(t, clt, "skipped", testSkipTime)
+
+		laterSkipTime := testSkipTime.Add(time.Minute)
+		skipTestPod(t, clt, "skipped-pod", laterSkipTime)
+
+		require.NoError(t, NewReconciler(clt, clt, dynakube).Reconcile(context.Background()))
+		assertDeploymentSkippedPodsRestart(t, clt, "skipped", laterSkipTime)
+	})
+	t.Run("next workload is restarted once the rollout finished", func(t *testing.T) {
+		dynakube := createTestDynakube(1, testNamespace)
+		clt := fake.NewClient(createTestNamespace(testNamespace, testDynakubeName))
+		createTestDeployment(t, clt, testNamespace, "first", testOldVersion)
+		createTestDeployment(t, clt, testNamespace, "second", testOldVersion)
+
+		require.NoError(t, NewReconciler(clt, clt, dynakube).Reconcile(context.Background()))
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "first", testVersion)
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "second", "")
+
+		require.NoError(t, NewReconciler(clt, clt, dynakube).Reconcile(context.Background()))
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "second", "")
+
+		setDeploymentRolloutFinished(t, clt, "first")
+
+		require.NoError(t, NewReconciler(clt, clt, dynakube).Reconcile(context.Background()))
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "second", testVersion)
+	})
+	t.Run("workload blocked by pod disruption budget is postponed", func(t *testing.T) {
+		dynakube := createTestDynakube(1, testNamespace)
+		clt := fake.NewClient(
+			createTestNamespace(testNamespace, testDynakubeName),
+			&policyv1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{Name: "blocking", Namespace: testNamespace},
+				Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "blocked"}}},
+				Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+			},
+		)
+		createTestDeployment(t, clt, testNamespace, "blocked", testOldVersion)
+		createTestDeployment(t, clt, testNamespace, "free", testOldVersion)
+
+		err := NewReconciler(clt, clt, dynakube).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "blocked", "")
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "free", testVersion)
+	})
+	t.Run("namespace not monitored by the dynakube is skipped", func(t *testing.T) {
+		dynakube := createTestDynakube(1, testNamespace)
+		clt := fake.NewClient(createTestNamespace(testNamespace, "other-dynakube"))
+		createTestDeployment(t, clt, testNamespace, "outdated", testOldVersion)
+
+		err := NewReconciler(clt, clt, dynakube).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "outdated", "")
+	})
+	t.Run("nothing is restarted without rolloutOnUpgrade", func(t *testing.T) {
+		dynakube := createTestDynakube(1, testNamespace)
+		dynakube.Spec.RolloutOnUpgrade = nil
+		clt := fake.NewClient(createTestNamespace(testNamespace, testDynakubeName))
+		createTestDeployment(t, clt, testNamespace, "outdated", testOldVersion)
+
+		err := NewReconciler(clt, clt, dynakube).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		assertDeploymentRolloutVersion(t, clt, testNamespace, "outdated", "")
+	})
+}
+
+func TestWorkloadFinder(t *testing.T) {
+	t.Run("controllers are looked up once", func(t *testing.T) {
+		ctx := context.Background()
+		clt := fake.NewClient()
+		createTestDeployment(t, clt, testNamespace, "scaled", testOldVersion)
+		replicaSetReference := createControllerReference(replicaSetKind, "scaled-rs")
+		require.NoError(t, clt.Create(ctx, createTestPod(testNamespace, "scaled-2", replicaSetReference, testOldVersion)))
+
+		gets := 0
+		countingClient := interceptor.NewClient(clt.(client.WithWatch), interceptor.Funcs{
+			Get: func(ctx context.Context, clt client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				gets++
+				return clt.Get(ctx, key, obj, opts...)
+			},
+		})
+
+		var pods corev1.PodList
+		require.NoError(t, clt.List(ctx, &pods))
+		require.Len(t, pods.Items, 2)
+
+		finder := newWorkloadFinder(countingClient)
+		for _, pod := range pods.Items {
+			podWorkload, err := finder.find(ctx, pod)
+			require.NoError(t, err)
+			require.NotNil(t, podWorkload)
+			assert.Equal(t, deploymentKind+"/"+testNamespace+"/scaled", podWorkload.key())
+		}
+		assert.Equal(t, 2, gets, "replica set and deployment")
+	})
+}
+
+func TestNewStatefulSetWorkload(t *testing.T) {
+	t.Run("on delete strategy can't be restarted", func(t *testing.T) {
+		statefulSet := &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+		}}
+
+		assert.Nil(t, newStatefulSetWorkload(statefulSet))
+	})
+}
+
+func assertDeploymentRolloutVersion(t *testing.T, clt client.Client, namespace, name, expectedVersion string) {
+	var deployment appsv1.Deployment
+	require.NoError(t, clt.Get(context.Background(), client.ObjectKey{Name: name, Namespace: namespace}, &deployment))
+	assert.Equal(t, expectedVersion, deployment.Spec.Template.Annotations[AnnotationRolloutVersion], name)
+}
+
+func assertDeploymentSkippedPodsRestart(t *testing.T, clt client.Client, name string, expectedRestart time.Time) {
+	var deployment appsv1.Deployment
+	require.NoError(t, clt.Get(context.Background(), client.ObjectKey{Name: name, Namespace: testNamespace}, &deployment))
+	assert.Equal(t, expectedRestart.Format(time.RFC3339), deployment.Spec.Template.Annotations[AnnotationSkippedPodsRestart], name)
+}
+
+func setDeploymentTemplateAnnotation(t *testing.T, clt client.Client, name, key, value string) {
+	var deployment appsv1.Deployment
+	require.NoError(t, clt.Get(context.Background(), client.ObjectKey{Name: name, Namespace: testNamespace}, &deployment))
+	deployment.Spec.Template.Annotations = map[string]string{key: value}
+	require.NoError(t, clt.Update(context.Background(), &deployment))
+}
+
+func setDeploymentRolloutFinished(t *testing.T, clt client.Client, name string) {
+	var deployment appsv1.Deployment
+	require.NoError(t, clt.Get(context.Background(), client.ObjectKey{Name: name, Namespace: testNamespace}, &deployment))
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: deployment.Generation, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	require.NoError(t, clt.Status().Update(context.Background(), &deployment))
+}
+
+// skipTestPod marks the pod as admitted without injection, as if the webhook exceeded its time budget when it was created
+func skipTestPod(t *testing.T, clt client.Client, name string, created time.Time) {
+	var pod corev1.Pod
+	require.NoError(t, clt.Get(context.Background(), client.ObjectKey{Name: name, Namespace: testNamespace}, &pod))
+	pod.CreationTimestamp = metav1.NewTime(created)
+	pod.Annotations = map[string]string{
+		dtwebhook.AnnotationOneAgentInjected: "false",
+		dtwebhook.AnnotationOneAgentReason:   dtwebhook.TimeBudgetExceededReason,
+	}
+	require.NoError(t, clt.Update(context.Background(), &pod))
+}
+
+func createTestDynakube(maxConcurrentRollouts int32, namespaces ...string) *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testDynakubeName,
+			Namespace: "dynatrace",
+		},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ApplicationMonitoring: &dynatracev1beta1.ApplicationMonitoringSpec{},
+			},
+			RolloutOnUpgrade: &dynatracev1beta1.RolloutOnUpgradeSpec{
+				Namespaces:            namespaces,
+				MaxConcurrentRollouts: maxConcurrentRollouts,
+			},
+		},
+		Status: dynatracev1beta1.DynaKubeStatus{
+			CodeModules: dynatracev1beta1.CodeModulesStatus{
+				VersionStatus: status.VersionStatus{Version: testVersion},
+			},
+		},
+	}
+}
+
+func createTestNamespace(name, dynakubeName string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{dtwebhook.InjectionInstanceLabel: dynakubeName},
+		},
+	}
+}
+
+// createTestDeployment creates a deployment with a replica set and an injected pod
+func createTestDeployment(t *testing.T, clt client.Client, namespace, name, injectedVersion string) {
+	ctx := context.Background()
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(name)},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}}},
+		},
+	}
+	require.NoError(t, clt.Create(ctx, deployment))
+
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name + "-rs",
+			Namespace:       namespace,
+			UID:             types.UID(name + "-rs"),
+			OwnerReferences: []metav1.OwnerReference{createControllerReference(deploymentKind, name)},
+		},
+	}
+	require.NoError(t, clt.Create(ctx, replicaSet))
+	require.NoError(t, clt.Create(ctx, createTestPod(namespace, name, createControllerReference(replicaSetKind, replicaSet.Name), injectedVersion)))
+}
+
+func createTestStatefulSet(t *testing.T, clt client.Client, namespace, name, injectedVersion string) {
+	ctx := context.Background()
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(name)},
+	}
+	require.NoError(t, clt.Create(ctx, statefulSet))
+	require.NoError(t, clt.Create(ctx, createTestPod(namespace, name, createControllerReference(statefulSetKind, name), injectedVersion)))
+}
+
+func createTestPod(namespace, name string, owner metav1.OwnerReference, injectedVersion string) *corev1.Pod {
+	annotations := map[string]string{dtwebhook.AnnotationOneAgentInjected: "true"}
+	if injectedVersion != "" {
+		annotations[dtwebhook.AnnotationOneAgentVersion] = injectedVersion
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name + "-pod",
+			Namespace:       namespace,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+	}
+}
+
+func createControllerReference(kind, name string) metav1.OwnerReference {
+	isController := true
+	return metav1.OwnerReference{
+		APIVersion: appsv1.SchemeGroupVersion.String(),
+		Kind:       kind,
+		Name:       name,
+		UID:        types.UID(name),
+		Controller: &isController,
+	}
+}
@@ -0,0 +1,190 @@
+package rollout
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	replicaSetKind  = "ReplicaSet"
+	deploymentKind  = "Deployment"
+	statefulSetKind = "StatefulSet"
+	daemonSetKind   = "DaemonSet"
+)
+
+// workload is a Deployment, StatefulSet or DaemonSet which can be restarted by changing its pod template
+type workload struct {
+	object          client.Object
+	kind            string
+	template        *corev1.PodTemplateSpec
+	rolloutFinished bool
+
+	// hasOutdatedPods is set if pods of the workload are injected with another code modules version
+	hasOutdatedPods bool
+	// lastSkippedPod is the creation time of the newest pod admitted without injection, as its mutation exceeded the time budget
+	lastSkippedPod metav1.Time
+}
+
+func (w workload) key() string {
+	return w.kind + "/" + w.object.GetNamespace() + "/" + w.object.GetName()
+}
+
+func (w workload) rolloutVersion() string {
+	return w.template.Annotations[AnnotationRolloutVersion]
+}
+
+// skippedPodsRestart returns the creation time of the newest skipped pod the workload was last restarted for
+func (w workload) skippedPodsRestart() time.Time {
+	restart, err := time.Parse(time.RFC3339, w.template.Annotations[AnnotationSkippedPodsRestart])
+	if err != nil {
+		return time.Time{}
+	}
+	return restart
+}
+
+// needsRestart is true if the workload wasn't restarted for the current version yet, or if pods were skipped since its last restart
+func (w workload) needsRestart(version string) bool {
+	if w.hasOutdatedPods && w.rolloutVersion() != version {
+		return true
+	}
+	return !w.lastSkippedPod.IsZero() && w.lastSkippedPod.Time.After(w.skippedPodsRestart())
+}
+
+func newDeploymentWorkload(deployment *appsv1.Deployment) *workload {
+	desiredReplicas := replicasOrDefault(deployment.Spec.Replicas)
+	deploymentStatus := deployment.Status
+	return &workload{
+		object:   deployment,
+		kind:     deploymentKind,
+		template: &deployment.Spec.Template,
+		rolloutFinished: deploymentStatus.ObservedGeneration >= deployment.Generation &&
+			deploymentStatus.UpdatedReplicas == desiredReplicas &&
+			deploymentStatus.Replicas == deploymentStatus.UpdatedReplicas &&
+			deploymentStatus.AvailableReplicas == deploymentStatus.UpdatedReplicas,
+	}
+}
+
+func newStatefulSetWorkload(statefulSet *appsv1.StatefulSet) *workload {
+	if statefulSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		// changing the template doesn't restart any pods
+		return nil
+	}
+	statefulSetStatus := statefulSet.Status
+	return &workload{
+		object:   statefulSet,
+		kind:     statefulSetKind,
+		template: &statefulSet.Spec.Template,
+		rolloutFinished: statefulSetStatus.ObservedGeneration >= statefulSet.Generation &&
+			statefulSetStatus.UpdatedReplicas == replicasOrDefault(statefulSet.Spec.Replicas) &&
+			statefulSetStatus.CurrentRevision == statefulSetStatus.UpdateRevision,
+	}
+}
+
+func newDaemonSetWorkload(daemonSet *appsv1.DaemonSet) *workload {
+	if daemonSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+		// changing the template doesn't restart any pods
+		return nil
+	}
+	daemonSetStatus := daemonSet.Status
+	return &workload{
+		object:   daemonSet,
+		kind:     daemonSetKind,
+		template: &daemonSet.Spec.Template,
+		rolloutFinished: daemonSetStatus.ObservedGeneration >= daemonSet.Generation &&
+			daemonSetStatus.UpdatedNumberScheduled == daemonSetStatus.DesiredNumberScheduled &&
+			daemonSetStatus.NumberAvailable == daemonSetStatus.DesiredNumberScheduled,
+	}
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// workloadFinder looks up the workloads controlling pods, every controller is only looked up once,
+// as the pods of a workload share it
+type workloadFinder struct {
+	reader    client.Reader
+	workloads map[types.UID]*workload
+}
+
+func newWorkloadFinder(reader client.Reader) *workloadFinder {
+	return &workloadFinder{
+		reader:    reader,
+		workloads: make(map[types.UID]*workload),
+	}
+}
+
+// find returns the workload controlling the pod, or nil if the pod isn't controlled by a workload which can be restarted
+func (finder *workloadFinder) find(ctx context.Context, pod corev1.Pod) (*workload, error) {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil || owner.APIVersion != appsv1.SchemeGroupVersion.String() {
+		return nil, nil
+	}
+
+	if ownerWorkload, ok := finder.workloads[owner.UID]; ok {
+		return ownerWorkload, nil
+	}
+	ownerWorkload, err := finder.findByOwner(ctx, owner, pod.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	finder.workloads[owner.UID] = ownerWorkload
+	return ownerWorkload, nil
+}
+
+func (finder *workloadFinder) findByOwner(ctx context.Context, owner *metav1.OwnerReference, namespace string) (*workload, error) {
+	key := client.ObjectKey{Name: owner.Name, Namespace: namespace}
+	switch owner.Kind {
+	case replicaSetKind:
+		// only the owner of the replica set is needed, so its metadata is enough
+		replicaSet := &metav1.PartialObjectMetadata{}
+		replicaSet.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind(replicaSetKind))
+		if found, err := finder.getOwner(ctx, key, replicaSet); err != nil || !found {
+			return nil, err
+		}
+		replicaSetOwner := metav1.GetControllerOf(replicaSet)
+		if replicaSetOwner == nil || replicaSetOwner.Kind != deploymentKind {
+			return nil, nil
+		}
+		var deployment appsv1.Deployment
+		if found, err := finder.getOwner(ctx, client.ObjectKey{Name: replicaSetOwner.Name, Namespace: namespace}, &deployment); err != nil || !found {
+			return nil, err
+		}
+		return newDeploymentWorkload(&deployment), nil
+	case statefulSetKind:
+		var statefulSet appsv1.StatefulSet
+		if found, err := finder.getOwner(ctx, key, &statefulSet); err != nil || !found {
+			return nil, err
+		}
+		return newStatefulSetWorkload(&statefulSet), nil
+	case daemonSetKind:
+		var daemonSet appsv1.DaemonSet
+		if found, err := finder.getOwner(ctx, key, &daemonSet); err != nil || !found {
+			return nil, err
+		}
+		return newDaemonSetWorkload(&daemonSet), nil
+	}
+	return nil, nil
+}
+
+// getOwner returns false if the owner was already deleted
+func (finder *workloadFinder) getOwner(ctx context.Context, key client.ObjectKey, object client.Object) (bool, error) {
+	err := finder.reader.Get(ctx, key, object)
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
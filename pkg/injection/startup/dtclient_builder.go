@@ -1,28 +1,37 @@
 package startup
 
 import (
+	"context"
+
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 )
 
 type dtclientBuilder struct {
-	config  *SecretConfig
-	options []dtclient.Option
+	config      *SecretConfig
+	tokenSource token.TokenSource
+	options     []dtclient.Option
 }
 
 func newDTClientBuilder(config *SecretConfig) *dtclientBuilder {
 	return &dtclientBuilder{
-		config:  config,
-		options: []dtclient.Option{},
+		config:      config,
+		tokenSource: config.tokenSource(),
+		options:     []dtclient.Option{},
 	}
 }
 
 func (builder *dtclientBuilder) createClient() (dtclient.Client, error) {
-	log.Info("creating dtclient")
+	log.Info("creating dtclient", "tokens", builder.tokenSource.String())
 	builder.setOptions()
+	tokens, err := builder.tokenSource.ReadTokens(context.Background())
+	if err != nil {
+		return nil, err
+	}
 	client, err := dtclient.NewClient(
 		builder.config.ApiUrl,
-		builder.config.ApiToken,
-		builder.config.PaasToken,
+		tokens.ApiToken().Value,
+		tokens.PaasToken().Value,
 		builder.options...,
 	)
 	if err != nil {
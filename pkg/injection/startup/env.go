@@ -1,6 +1,7 @@
 package startup
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
@@ -28,6 +29,9 @@ type environment struct {
 	FailurePolicy string             `json:"failurePolicy"`
 	InstallerUrl  string             `json:"installerUrl"`
 
+	InstallerMirror         string `json:"installerMirror"`
+	InstallerMirrorInsecure bool   `json:"installerMirrorInsecure"`
+
 	InstallerFlavor string          `json:"installerFlavor"`
 	InstallVersion  string          `json:"installVersion"`
 	InstallerTech   []string        `json:"installerTech"`
@@ -44,6 +48,8 @@ type environment struct {
 	WorkloadKind string `json:"workloadKind"`
 	WorkloadName string `json:"workloadName"`
 
+	EnrichmentAttributes map[string]string `json:"enrichmentAttributes"`
+
 	OneAgentInjected   bool `json:"oneAgentInjected"`
 	DataIngestInjected bool `json:"dataIngestInjected"`
 	IsReadOnlyCSI      bool `json:"isReadOnlyCSI"`
@@ -118,6 +124,8 @@ func (env *environment) setOptionalFields() {
 	env.addInstallerUrl()
 	env.addInstallerFlavor()
 	env.addInstallVersion()
+	env.addInstallerMirror()
+	env.addEnrichmentAttributes()
 }
 
 func (env *environment) setMutationTypeFields() {
@@ -299,6 +307,24 @@ func (env *environment) addInstallVersion() {
 	env.InstallVersion = version
 }
 
+func (env *environment) addInstallerMirror() {
+	mirror, _ := checkEnvVar(consts.AgentInstallerMirrorEnv)
+	env.InstallerMirror = mirror
+	insecure, _ := checkEnvVar(consts.AgentInstallerMirrorInsecureEnv)
+	env.InstallerMirrorInsecure = insecure == trueStatement
+}
+
+func (env *environment) addEnrichmentAttributes() {
+	attributes, _ := checkEnvVar(consts.EnrichmentAttributesEnv)
+	if attributes == "" {
+		return
+	}
+	if err := json.Unmarshal([]byte(attributes), &env.EnrichmentAttributes); err != nil {
+		log.Info("ignoring invalid enrichment attributes", "attributes", attributes, "err", err.Error())
+		env.EnrichmentAttributes = nil
+	}
+}
+
 func (env *environment) addOneAgentInjected() {
 	oneAgentInjected, _ := checkEnvVar(consts.AgentInjectedEnv)
 	env.OneAgentInjected = oneAgentInjected == trueStatement
@@ -118,6 +118,41 @@ func TestNewEnv(t *testing.T) {
 		assert.True(t, env.OneAgentInjected)
 		assert.False(t, env.DataIngestInjected)
 		assert.True(t, env.IsReadOnlyCSI)
+
+		assert.Empty(t, env.InstallerMirror)
+		assert.False(t, env.InstallerMirrorInsecure)
+	})
+	t.Run(`create new env with installer mirror`, func(t *testing.T) {
+		resetEnv := prepOneAgentTestEnv(t)
+		t.Setenv(consts.AgentInstallerMirrorEnv, "registry.mirror:5000/oneagent-installer:1.2.3")
+		t.Setenv(consts.AgentInstallerMirrorInsecureEnv, trueStatement)
+
+		env, err := newEnv()
+		resetEnv()
+
+		require.NoError(t, err)
+		assert.Equal(t, "registry.mirror:5000/oneagent-installer:1.2.3", env.InstallerMirror)
+		assert.True(t, env.InstallerMirrorInsecure)
+	})
+	t.Run(`create new env with enrichment attributes`, func(t *testing.T) {
+		resetEnv := prepDataIngestTestEnv(t, false)
+		t.Setenv(consts.EnrichmentAttributesEnv, `{"k8s.pod.label.team": "checkout"}`)
+
+		env, err := newEnv()
+		resetEnv()
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"k8s.pod.label.team": "checkout"}, env.EnrichmentAttributes)
+	})
+	t.Run(`invalid enrichment attributes are ignored`, func(t *testing.T) {
+		resetEnv := prepDataIngestTestEnv(t, false)
+		t.Setenv(consts.EnrichmentAttributesEnv, `team=checkout`)
+
+		env, err := newEnv()
+		resetEnv()
+
+		require.NoError(t, err)
+		assert.Nil(t, env.EnrichmentAttributes)
 	})
 }
 
@@ -1,16 +1,22 @@
 package startup
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"golang.org/x/exp/slices"
 )
 
 const (
 	onlyReadAllFileMode = 0444
+	ownerWritableMode   = 0644
 )
 
 var (
@@ -33,28 +39,20 @@ isCloudNativeFullStack true
 	k8ClusterIDFormatString = `k8s_cluster_id %s
 `
 
-	jsonEnrichmentContentFormatString = `{
-  "k8s.pod.uid": "%s",
-  "k8s.pod.name": "%s",
-  "k8s.namespace.name": "%s",
-  "dt.kubernetes.workload.kind": "%s",
-  "dt.kubernetes.workload.name": "%s",
-  "dt.kubernetes.cluster.id": "%s"
-}
-`
-
-	propsEnrichmentContentFormatString = `k8s.pod.uid=%s
-k8s.pod.name=%s
-k8s.namespace.name=%s
-dt.kubernetes.workload.kind=%s
-dt.kubernetes.workload.name=%s
-dt.kubernetes.cluster.id=%s
+	enrichmentAttributeConfFormatString = `%s %s
 `
 
 	curlOptionsFormatString = `initialConnectRetryMs %d
 `
+
+	propertiesValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`)
 )
 
+type enrichmentAttribute struct {
+	key   string
+	value string
+}
+
 func (runner *Runner) getBaseConfContent(container containerInfo) string {
 	return fmt.Sprintf(baseConfContentFormatString,
 		container.Name,
@@ -80,36 +78,85 @@ func (runner *Runner) getK8SClusterID() string {
 	)
 }
 
+// getEnrichmentAttributesConf adds the attributes of the metadataEnrichment rules to the [container] section
+func (runner *Runner) getEnrichmentAttributesConf() string {
+	var content strings.Builder
+	for _, attribute := range runner.customEnrichmentAttributes() {
+		content.WriteString(fmt.Sprintf(enrichmentAttributeConfFormatString, attribute.key, singleLine(attribute.value)))
+	}
+	return content.String()
+}
+
 func (runner *Runner) getCurlOptionsContent() string {
 	return fmt.Sprintf(curlOptionsFormatString, runner.config.InitialConnectRetry)
 }
 
+// enrichmentAttributes lists the attributes about the pod, followed by the attributes of the metadataEnrichment rules
+func (runner *Runner) enrichmentAttributes() []enrichmentAttribute {
+	return append(runner.podEnrichmentAttributes(), runner.customEnrichmentAttributes()...)
+}
+
+func (runner *Runner) podEnrichmentAttributes() []enrichmentAttribute {
+	return []enrichmentAttribute{
+		{key: "k8s.pod.uid", value: runner.env.K8PodUID},
+		{key: "k8s.pod.name", value: runner.env.K8PodName},
+		{key: "k8s.namespace.name", value: runner.env.K8Namespace},
+		{key: "dt.kubernetes.workload.kind", value: runner.env.WorkloadKind},
+		{key: "dt.kubernetes.workload.name", value: runner.env.WorkloadName},
+		{key: "dt.kubernetes.cluster.id", value: runner.env.K8ClusterID},
+	}
+}
+
+// customEnrichmentAttributes lists the attributes of the metadataEnrichment rules sorted by key,
+// they can't override the attributes about the pod
+func (runner *Runner) customEnrichmentAttributes() []enrichmentAttribute {
+	podAttributes := runner.podEnrichmentAttributes()
+	keys := make([]string, 0, len(runner.env.EnrichmentAttributes))
+	for key := range runner.env.EnrichmentAttributes {
+		isPodAttribute := slices.ContainsFunc(podAttributes, func(attribute enrichmentAttribute) bool {
+			return attribute.key == key
+		})
+		if !isPodAttribute {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	attributes := make([]enrichmentAttribute, 0, len(keys))
+	for _, key := range keys {
+		attributes = append(attributes, enrichmentAttribute{key: key, value: runner.env.EnrichmentAttributes[key]})
+	}
+	return attributes
+}
+
 func (runner *Runner) createJsonEnrichmentFile() error {
-	jsonContent := fmt.Sprintf(jsonEnrichmentContentFormatString,
-		runner.env.K8PodUID,
-		runner.env.K8PodName,
-		runner.env.K8Namespace,
-		runner.env.WorkloadKind,
-		runner.env.WorkloadName,
-		runner.env.K8ClusterID,
-	)
+	attributes := runner.enrichmentAttributes()
+	lines := make([]string, 0, len(attributes))
+	for _, attribute := range attributes {
+		key, err := json.Marshal(attribute.key)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		value, err := json.Marshal(attribute.value)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", key, value))
+	}
+	jsonContent := "{\n" + strings.Join(lines, ",\n") + "\n}\n"
 	jsonPath := filepath.Join(consts.EnrichmentMountPath, fmt.Sprintf(consts.EnrichmentFilenameTemplate, "json"))
 
 	return runner.createConfFile(jsonPath, jsonContent)
 }
 
 func (runner *Runner) createPropsEnrichmentFile() error {
-	propsContent := fmt.Sprintf(propsEnrichmentContentFormatString,
-		runner.env.K8PodUID,
-		runner.env.K8PodName,
-		runner.env.K8Namespace,
-		runner.env.WorkloadKind,
-		runner.env.WorkloadName,
-		runner.env.K8ClusterID,
-	)
+	var propsContent strings.Builder
+	for _, attribute := range runner.enrichmentAttributes() {
+		propsContent.WriteString(fmt.Sprintf("%s=%s\n", attribute.key, propertiesValueEscaper.Replace(attribute.value)))
+	}
 	propsPath := filepath.Join(consts.EnrichmentMountPath, fmt.Sprintf(consts.EnrichmentFilenameTemplate, "properties"))
 
-	return runner.createConfFile(propsPath, propsContent)
+	return runner.createConfFile(propsPath, propsContent.String())
 }
 
 func (runner *Runner) createCurlOptionsFile() error {
@@ -119,12 +166,25 @@ func (runner *Runner) createCurlOptionsFile() error {
 	return runner.createConfFile(path, content)
 }
 
+func singleLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// createConfFile rewrites existing files in place, the user containers mount them via subPath,
+// which keeps showing the original inode, so a replaced file would never reach them
 func (runner *Runner) createConfFile(path string, content string) error {
 	err := runner.fs.MkdirAll(filepath.Dir(path), onlyReadAllFileMode)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
+	if exists, _ := afero.Exists(runner.fs, path); exists {
+		// the file is read-only, only the refresh sidecar updates it
+		if err := runner.fs.Chmod(path, ownerWritableMode); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
 	file, err := runner.fs.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, onlyReadAllFileMode)
 	if err != nil {
 		return errors.WithStack(err)
@@ -132,6 +192,14 @@ func (runner *Runner) createConfFile(path string, content string) error {
 
 	_, err = file.Write([]byte(content))
 	if err != nil {
+		_ = file.Close()
+		return errors.WithStack(err)
+	}
+	if err := file.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := runner.fs.Chmod(path, onlyReadAllFileMode); err != nil {
 		return errors.WithStack(err)
 	}
 
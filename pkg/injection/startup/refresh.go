@@ -0,0 +1,148 @@
+package startup
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/processmoduleconfig"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	RefreshInterval = 5 * time.Minute
+
+	// refreshJitterFactor spreads the refreshes of the pods, so they don't poll the Dynatrace API at the same time
+	refreshJitterFactor = 0.5
+)
+
+// processModuleConfigRevisionPath is where the install container leaves the revision of the process module config for the sidecar
+var processModuleConfigRevisionPath = filepath.Join(consts.AgentBinDirMount, "agent", "conf", "ruxitagentproc.revision")
+
+// RunRefresh is used by the native sidecar, it keeps the configuration files created by Run up to date until the context is done.
+// The files are only recreated if the secret config or the revision of the process module config changed.
+// Errors are only logged, so the sidecar keeps running and the files of the last successful refresh stay in place.
+func (runner *Runner) RunRefresh(ctx context.Context, interval time.Duration) error {
+	log.Info("standalone agent config refresh started", "interval", interval)
+	if runner.env.OneAgentInjected && runner.env.Mode == consts.AgentInstallerMode {
+		runner.loadProcessModuleConfigRevision()
+	}
+
+	for {
+		// the install container has just created the files, so the first refresh waits for the interval as well
+		select {
+		case <-ctx.Done():
+			log.Info("standalone agent config refresh stopped")
+			return nil
+		case <-time.After(wait.Jitter(interval, refreshJitterFactor)):
+		}
+
+		if err := runner.refresh(); err != nil {
+			log.Error(err, "failed to refresh the agent configuration")
+		}
+	}
+}
+
+func (runner *Runner) refresh() error {
+	if !runner.env.OneAgentInjected {
+		// the enrichment files only depend on the pod, they never change
+		return nil
+	}
+
+	secretConfigChanged, err := runner.reloadSecretConfig()
+	if err != nil {
+		return err
+	}
+
+	processModuleConfigChanged, err := runner.refreshProcessModuleConfig(secretConfigChanged)
+	if err != nil {
+		return err
+	}
+
+	if !secretConfigChanged && !processModuleConfigChanged {
+		log.Info("agent configuration is up to date")
+		return nil
+	}
+
+	if err := runner.setHostTenant(); err != nil {
+		return err
+	}
+	return runner.configureInstallation()
+}
+
+// reloadSecretConfig reads the secret config again, as the kubelet updates the mounted secret when it changes
+func (runner *Runner) reloadSecretConfig() (bool, error) {
+	secretConfig, err := newSecretConfigViaFs(runner.fs)
+	if err != nil {
+		return false, err
+	}
+	if reflect.DeepEqual(secretConfig, runner.config) {
+		return false, nil
+	}
+
+	log.Info("secret config changed")
+	if runner.env.Mode == consts.AgentInstallerMode {
+		client, err := newDTClientBuilder(secretConfig).createClient()
+		if err != nil {
+			return false, err
+		}
+		runner.dtclient = client
+	}
+	runner.config = secretConfig
+	return true, nil
+}
+
+// refreshProcessModuleConfig updates the ruxitagentproc.conf if the process module config of the tenant has a new revision,
+// only the installer mode owns the agent binaries, the CSI driver keeps them up to date on its own
+func (runner *Runner) refreshProcessModuleConfig(forceUpdate bool) (bool, error) {
+	if runner.env.Mode != consts.AgentInstallerMode {
+		return false, nil
+	}
+
+	revision := runner.processModuleConfigRevision
+	if forceUpdate {
+		// the proxy is part of the secret config, so the whole config has to be recreated
+		revision = 0
+	}
+	processModuleConfig, err := runner.getProcessModuleConfig(revision)
+	if err != nil {
+		return false, err
+	}
+	if processModuleConfig.IsEmpty() {
+		return false, nil
+	}
+
+	log.Info("process module config changed", "revision", processModuleConfig.Revision)
+	if err := processmoduleconfig.UpdateProcessModuleConfigInPlace(runner.fs, consts.AgentBinDirMount, processModuleConfig); err != nil {
+		return false, err
+	}
+	return true, runner.storeProcessModuleConfigRevision(processModuleConfig.Revision)
+}
+
+func (runner *Runner) storeProcessModuleConfigRevision(revision uint) error {
+	runner.processModuleConfigRevision = revision
+	err := afero.WriteFile(runner.fs, processModuleConfigRevisionPath, []byte(strconv.FormatUint(uint64(revision), 10)), ownerWritableMode)
+	return errors.WithStack(err)
+}
+
+// loadProcessModuleConfigRevision continues from the revision the install container fetched,
+// without it the first refresh fetches the whole process module config again
+func (runner *Runner) loadProcessModuleConfigRevision() {
+	content, err := afero.ReadFile(runner.fs, processModuleConfigRevisionPath)
+	if err != nil {
+		log.Info("no revision of the process module config found", "error", err.Error())
+		return
+	}
+	revision, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 0)
+	if err != nil {
+		log.Info("invalid revision of the process module config found", "error", err.Error())
+		return
+	}
+	runner.processModuleConfigRevision = uint(revision)
+}
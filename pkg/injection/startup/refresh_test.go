@@ -0,0 +1,146 @@
+package startup
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefresh(t *testing.T) {
+	t.Run("nothing changed, no files are written", func(t *testing.T) {
+		runner := createRefreshTestRunner(t, consts.AgentInstallerMode)
+		runner.processModuleConfigRevision = 5
+		runner.dtclient.(*dtclient.MockDynatraceClient).
+			On("GetProcessModuleConfig", uint(5)).
+			Return(&dtclient.ProcessModuleConfig{}, nil)
+
+		err := runner.refresh()
+
+		require.NoError(t, err)
+		assertIfAgentFilesNotExists(t, *runner)
+	})
+	t.Run("new revision of the process module config recreates the configuration", func(t *testing.T) {
+		runner := createRefreshTestRunner(t, consts.AgentInstallerMode)
+		runner.processModuleConfigRevision = 5
+		processModuleConfig := getTestProcessModuleConfig()
+		processModuleConfig.Revision = 6
+		runner.dtclient.(*dtclient.MockDynatraceClient).
+			On("GetProcessModuleConfig", uint(5)).
+			Return(processModuleConfig, nil)
+
+		err := runner.refresh()
+
+		require.NoError(t, err)
+		assert.Equal(t, uint(6), runner.processModuleConfigRevision)
+		storedRevision, err := afero.ReadFile(runner.fs, processModuleConfigRevisionPath)
+		require.NoError(t, err)
+		assert.Equal(t, "6", string(storedRevision))
+		assertIfAgentFilesExists(t, *runner)
+
+		content, err := afero.ReadFile(runner.fs, filepath.Join(consts.AgentBinDirMount, "agent/conf/ruxitagentproc.conf"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "proxy "+testProxy)
+	})
+	t.Run("changed secret config recreates the configuration", func(t *testing.T) {
+		runner := createRefreshTestRunner(t, consts.AgentCsiMode)
+		secretConfig := *runner.config
+		secretConfig.TlsCert = "new-cert"
+		writeTestSecretConfig(t, runner.fs, &secretConfig)
+
+		err := runner.refresh()
+
+		require.NoError(t, err)
+		assert.Equal(t, secretConfig, *runner.config)
+		assertIfAgentFilesExists(t, *runner)
+
+		content, err := afero.ReadFile(runner.fs, filepath.Join(consts.AgentShareDirMount, "custom.pem"))
+		require.NoError(t, err)
+		assert.Equal(t, "new-cert", string(content))
+	})
+	t.Run("containers read the refreshed files", func(t *testing.T) {
+		// a subPath mount binds the inode of the file, a hard link sees the same content as the container
+		shareDir := t.TempDir()
+		runner := Runner{fs: afero.NewOsFs()}
+		confFilePath := filepath.Join(shareDir, "container_app.conf")
+		containerView := filepath.Join(t.TempDir(), "container.conf")
+		require.NoError(t, runner.createConfFile(confFilePath, "old-config"))
+		require.NoError(t, os.Link(confFilePath, containerView))
+
+		require.NoError(t, runner.createConfFile(confFilePath, "new-config"))
+
+		content, err := os.ReadFile(containerView)
+		require.NoError(t, err)
+		assert.Equal(t, "new-config", string(content))
+
+		info, err := os.Stat(confFilePath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(onlyReadAllFileMode), info.Mode().Perm())
+	})
+}
+
+func TestRunRefresh(t *testing.T) {
+	t.Run("stops when the context is done", func(t *testing.T) {
+		runner := createRefreshTestRunner(t, consts.AgentCsiMode)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := runner.RunRefresh(ctx, time.Hour)
+
+		require.NoError(t, err)
+	})
+	t.Run("continues from the revision of the install container", func(t *testing.T) {
+		runner := createRefreshTestRunner(t, consts.AgentInstallerMode)
+		require.NoError(t, afero.WriteFile(runner.fs, processModuleConfigRevisionPath, []byte("5"), 0644))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := runner.RunRefresh(ctx, time.Hour)
+
+		require.NoError(t, err)
+		assert.Equal(t, uint(5), runner.processModuleConfigRevision)
+	})
+	t.Run("missing revision of the install container fetches the whole config", func(t *testing.T) {
+		runner := createRefreshTestRunner(t, consts.AgentInstallerMode)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := runner.RunRefresh(ctx, time.Hour)
+
+		require.NoError(t, err)
+		assert.Zero(t, runner.processModuleConfigRevision)
+	})
+}
+
+func createRefreshTestRunner(t *testing.T, mode consts.InstallMode) *Runner {
+	runner := createMockedRunner(t)
+	runner.env.OneAgentInjected = true
+	runner.env.DataIngestInjected = false
+	runner.env.IsReadOnlyCSI = false
+	runner.env.Mode = mode
+
+	secretConfig := getTestSecretConfig()
+	secretConfig.HasHost = false
+	writeTestSecretConfig(t, runner.fs, secretConfig)
+	runner.config = secretConfig
+
+	if mode == consts.AgentInstallerMode {
+		_, err := runner.fs.Create(filepath.Join(consts.AgentBinDirMount, "agent/conf/ruxitagentproc.conf"))
+		require.NoError(t, err)
+	}
+	return runner
+}
+
+func writeTestSecretConfig(t *testing.T, fs afero.Fs, secretConfig *SecretConfig) {
+	rawJson, err := json.Marshal(secretConfig)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(consts.AgentConfigDirMount, consts.AgentInitSecretConfigField), rawJson, 0770))
+}
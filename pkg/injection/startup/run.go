@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"runtime"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/arch"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
@@ -14,6 +15,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/processmoduleconfig"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type Runner struct {
@@ -23,6 +25,8 @@ type Runner struct {
 	dtclient   dtclient.Client
 	installer  installer.Installer
 	hostTenant string
+
+	processModuleConfigRevision uint
 }
 
 func NewRunner(fs afero.Fs) (*Runner, error) {
@@ -40,7 +44,10 @@ func NewRunner(fs afero.Fs) (*Runner, error) {
 		if err != nil {
 			return nil, err
 		}
-
+	}
+	// the Dynatrace API is only needed for downloading the code modules, the secret config of DynaKubes
+	// reading their tokens from files doesn't contain any tokens, the validation only allows them together with the CSI driver
+	if env.OneAgentInjected && env.Mode == consts.AgentInstallerMode {
 		client, err = newDTClientBuilder(secretConfig).createClient()
 		if err != nil {
 			return nil, err
@@ -62,6 +69,9 @@ func NewRunner(fs afero.Fs) (*Runner, error) {
 				Url:           env.InstallerUrl,
 				SkipMetadata:  false,
 				PathResolver:  metadata.PathResolver{RootDir: consts.AgentBinDirMount},
+
+				MirrorImage:    mirroredInstaller(env.InstallerMirror),
+				MirrorInsecure: env.InstallerMirrorInsecure,
 			},
 		)
 	}
@@ -75,6 +85,14 @@ func NewRunner(fs afero.Fs) (*Runner, error) {
 	}, nil
 }
 
+// mirroredInstaller returns the installer for the architecture of the node the init container runs on, the mirror tags them as <version>-<architecture>
+func mirroredInstaller(mirror string) string {
+	if mirror == "" {
+		return ""
+	}
+	return mirror + "-" + runtime.GOARCH
+}
+
 func (runner *Runner) Run() (resultedError error) {
 	log.Info("standalone agent init started")
 	defer runner.consumeErrorIfNecessary(&resultedError)
@@ -103,6 +121,16 @@ func (runner *Runner) consumeErrorIfNecessary(resultedError *error) {
 	if runner.env.FailurePolicy == silentPhrase && *resultedError != nil {
 		log.Error(*resultedError, "This error has been masked to not fail the container.")
 		*resultedError = nil
+		runner.writeSilentFailureMessage()
+	}
+}
+
+// writeSilentFailureMessage sets the termination message of the container, so the operator counts the pod as failed
+// even though the container exited successfully
+func (runner *Runner) writeSilentFailureMessage() {
+	err := afero.WriteFile(runner.fs, corev1.TerminationMessagePathDefault, []byte(consts.InjectionSilentFailureMessage), 0644)
+	if err != nil {
+		log.Info("failed to write the termination message", "error", err.Error())
 	}
 }
 
@@ -131,7 +159,7 @@ func (runner *Runner) installOneAgent() error {
 	if err != nil {
 		return err
 	}
-	processModuleConfig, err := runner.getProcessModuleConfig()
+	processModuleConfig, err := runner.getProcessModuleConfig(0)
 	if err != nil {
 		return err
 	}
@@ -139,14 +167,18 @@ func (runner *Runner) installOneAgent() error {
 	if err != nil {
 		return err
 	}
-	return nil
+	return runner.storeProcessModuleConfigRevision(processModuleConfig.Revision)
 }
 
-func (runner *Runner) getProcessModuleConfig() (*dtclient.ProcessModuleConfig, error) {
-	processModuleConfig, err := runner.dtclient.GetProcessModuleConfig(0)
+// getProcessModuleConfig returns an empty config if the revision of the tenant's config is still the same as prevRevision
+func (runner *Runner) getProcessModuleConfig(prevRevision uint) (*dtclient.ProcessModuleConfig, error) {
+	processModuleConfig, err := runner.dtclient.GetProcessModuleConfig(prevRevision)
 	if err != nil {
 		return nil, err
 	}
+	if processModuleConfig.IsEmpty() {
+		return processModuleConfig, nil
+	}
 
 	if runner.config.Proxy != "" {
 		processModuleConfig = processModuleConfig.AddProxy(runner.config.Proxy)
@@ -217,6 +249,7 @@ func (runner *Runner) createContainerConfigurationFiles() error {
 
 		log.Info("adding k8s cluster id")
 		content += runner.getK8SClusterID()
+		content += runner.getEnrichmentAttributesConf()
 
 		if runner.hostTenant != consts.AgentNoHostTenant {
 			if runner.config.TenantUUID == runner.hostTenant {
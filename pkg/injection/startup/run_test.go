@@ -1,7 +1,9 @@
 package startup
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func getTestProcessModuleConfig() *dtclient.ProcessModuleConfig {
@@ -30,6 +33,7 @@ func TestNewRunner(t *testing.T) {
 	fs := prepTestFs(t)
 	t.Run("create runner with oneagent and data-ingest injection", func(t *testing.T) {
 		resetEnv := prepCombinedTestEnv(t)
+		require.NoError(t, os.Setenv(consts.AgentInstallModeEnv, string(consts.AgentInstallerMode)))
 		runner, err := NewRunner(fs)
 		resetEnv()
 
@@ -44,6 +48,7 @@ func TestNewRunner(t *testing.T) {
 	})
 	t.Run("create runner with only oneagent", func(t *testing.T) {
 		resetEnv := prepOneAgentTestEnv(t)
+		require.NoError(t, os.Setenv(consts.AgentInstallModeEnv, string(consts.AgentInstallerMode)))
 		runner, err := NewRunner(fs)
 		resetEnv()
 
@@ -55,6 +60,21 @@ func TestNewRunner(t *testing.T) {
 		assert.NotNil(t, runner.installer)
 		assert.Empty(t, runner.hostTenant)
 	})
+	t.Run("create runner without tokens for the csi driver", func(t *testing.T) {
+		resetEnv := prepOneAgentTestEnv(t)
+		tokenlessFs := prepTestFs(t)
+		secretConfig := getTestSecretConfig()
+		secretConfig.ApiToken = ""
+		secretConfig.PaasToken = ""
+		writeTestSecretConfig(t, tokenlessFs, secretConfig)
+		runner, err := NewRunner(tokenlessFs)
+		resetEnv()
+
+		require.NoError(t, err)
+		assert.NotNil(t, runner.config)
+		assert.Nil(t, runner.dtclient)
+		assert.Nil(t, runner.installer)
+	})
 	t.Run("create runner with only data-ingest injection", func(t *testing.T) {
 		resetEnv := prepDataIngestTestEnv(t, false)
 		runner, err := NewRunner(fs)
@@ -82,6 +102,10 @@ func TestConsumeErrorIfNecessary(t *testing.T) {
 		runner.env.FailurePolicy = silentPhrase
 		err := runner.Run()
 		assert.Nil(t, err)
+
+		terminationMessage, err := afero.ReadFile(runner.fs, corev1.TerminationMessagePathDefault)
+		require.NoError(t, err)
+		assert.Equal(t, consts.InjectionSilentFailureMessage, string(terminationMessage))
 	})
 	t.Run("error thrown, but don't consume error", func(t *testing.T) {
 		runner.env.K8NodeName = "" // create artificial error
@@ -143,6 +167,9 @@ func TestInstallOneAgent(t *testing.T) {
 		err := runner.installOneAgent()
 
 		require.NoError(t, err)
+		storedRevision, err := afero.ReadFile(runner.fs, processModuleConfigRevisionPath)
+		require.NoError(t, err)
+		assert.Equal(t, "0", string(storedRevision))
 	})
 	t.Run("sad install -> install fail", func(t *testing.T) {
 		runner := createMockedRunner(t)
@@ -282,7 +309,7 @@ func TestGetProcessModuleConfig(t *testing.T) {
 			On("GetProcessModuleConfig", uint(0)).
 			Return(&dtclient.ProcessModuleConfig{}, fmt.Errorf("BOOM"))
 
-		config, err := runner.getProcessModuleConfig()
+		config, err := runner.getProcessModuleConfig(0)
 		require.Error(t, err)
 		require.Nil(t, config)
 	})
@@ -295,7 +322,7 @@ func TestGetProcessModuleConfig(t *testing.T) {
 			On("GetProcessModuleConfig", uint(0)).
 			Return(getTestProcessModuleConfig(), nil)
 
-		config, err := runner.getProcessModuleConfig()
+		config, err := runner.getProcessModuleConfig(0)
 		require.NoError(t, err)
 		require.NotNil(t, config)
 
@@ -317,6 +344,10 @@ k8s_containername TEST_CONTAINER_%d_NAME
 k8s_basepodname TEST_K8S_BASEPODNAME
 k8s_namespace TEST_K8S_NAMESPACE
 k8s_cluster_id TEST_K8S_CLUSTER_ID
+`
+
+	const expectedContainerConfContentWithAttributes = expectedContainerConfContentAppMon + `k8s.pod.annotation.owner jane doe
+k8s.pod.label.team checkout
 `
 
 	const expectedContainerConfContentCloudNative = expectedContainerConfContentAppMon + `k8s_node_name TEST_K8S_NODE_NAME
@@ -385,6 +416,28 @@ isCloudNativeFullStack true
 			assert.Equal(t, fmt.Sprintf(expectedContainerConfContentCloudNative, i+1, i+1, i+1), string(content))
 		}
 	})
+	t.Run("create config files with attributes of metadataEnrichment rules", func(t *testing.T) {
+		runner.config.HasHost = false
+		runner.hostTenant = consts.AgentNoHostTenant
+		runner.fs = afero.NewMemMapFs()
+		runner.env.EnrichmentAttributes = map[string]string{
+			"k8s.pod.label.team":       "checkout",
+			"k8s.pod.annotation.owner": "jane\ndoe",
+		}
+		defer func() { runner.env.EnrichmentAttributes = nil }()
+
+		err := runner.createContainerConfigurationFiles()
+
+		require.NoError(t, err)
+		for i, container := range runner.env.Containers {
+			content, err := afero.ReadFile(runner.fs, filepath.Join(
+				consts.AgentShareDirMount,
+				fmt.Sprintf(consts.AgentContainerConfFilenameTemplate, container.Name)))
+			require.NoError(t, err)
+
+			assert.Equal(t, fmt.Sprintf(expectedContainerConfContentWithAttributes, i+1, i+1, i+1), string(content))
+		}
+	})
 }
 
 func TestSetLDPreload(t *testing.T) {
@@ -417,6 +470,35 @@ func TestEnrichMetadata(t *testing.T) {
 		assertIfEnrichmentFilesExists(t, *runner)
 		// TODO: Check content ?
 	})
+	t.Run("create enrichment files with attributes of metadataEnrichment rules", func(t *testing.T) {
+		runner.fs = afero.NewMemMapFs()
+		runner.env.EnrichmentAttributes = map[string]string{
+			"k8s.pod.label.team":         "checkout",
+			"k8s.pod.annotation.owner":   "jane\ndoe",
+			"k8s.namespace.name":         "overridden",
+			"k8s.namespace.label.region": `eu "west"`,
+		}
+		defer func() { runner.env.EnrichmentAttributes = nil }()
+
+		err := runner.enrichMetadata()
+		require.NoError(t, err)
+
+		jsonContent, err := afero.ReadFile(runner.fs, filepath.Join(consts.EnrichmentMountPath, fmt.Sprintf(consts.EnrichmentFilenameTemplate, "json")))
+		require.NoError(t, err)
+		var attributes map[string]string
+		require.NoError(t, json.Unmarshal(jsonContent, &attributes))
+		assert.Equal(t, "checkout", attributes["k8s.pod.label.team"])
+		assert.Equal(t, "jane\ndoe", attributes["k8s.pod.annotation.owner"])
+		assert.Equal(t, `eu "west"`, attributes["k8s.namespace.label.region"])
+		assert.Equal(t, runner.env.K8Namespace, attributes["k8s.namespace.name"])
+
+		propsContent, err := afero.ReadFile(runner.fs, filepath.Join(consts.EnrichmentMountPath, fmt.Sprintf(consts.EnrichmentFilenameTemplate, "properties")))
+		require.NoError(t, err)
+		assert.Contains(t, string(propsContent), "k8s.namespace.name="+runner.env.K8Namespace+"\n")
+		assert.Contains(t, string(propsContent), "k8s.pod.annotation.owner=jane\\ndoe\n")
+		assert.Contains(t, string(propsContent), "k8s.pod.label.team=checkout\n")
+		assert.NotContains(t, string(propsContent), "overridden")
+	})
 }
 
 func TestPropagateTLSCert(t *testing.T) {
@@ -5,7 +5,9 @@ import (
 	"io"
 	"path/filepath"
 
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 )
@@ -50,6 +52,18 @@ func (secret SecretConfig) logContent() {
 	log.Info("contents of secret config", "content", secret)
 }
 
+// tokenSource provides the tokens of the secret config, so the init-container reads them the same way as the operator.
+func (secret SecretConfig) tokenSource() token.TokenSource {
+	tokens := token.Tokens{}
+	if secret.ApiToken != "" {
+		tokens[dtclient.DynatraceApiToken] = token.Token{Value: secret.ApiToken}
+	}
+	if secret.PaasToken != "" {
+		tokens[dtclient.DynatracePaasToken] = token.Token{Value: secret.PaasToken}
+	}
+	return token.NewStaticTokenSource("init secret config", tokens)
+}
+
 func newSecretConfigViaFs(fs afero.Fs) (*SecretConfig, error) {
 	file, err := fs.Open(filepath.Join(consts.AgentConfigDirMount, consts.AgentInitSecretConfigField))
 	if err != nil {
@@ -1,6 +1,7 @@
 package startup
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -79,6 +80,22 @@ func TestNewSecretConfigViaFs(t *testing.T) {
 	assert.Equal(t, testInitialConnectRetry, config.InitialConnectRetry)
 }
 
+func TestSecretConfigTokenSource(t *testing.T) {
+	t.Run(`provides tokens of config`, func(t *testing.T) {
+		tokens, err := getTestSecretConfig().tokenSource().ReadTokens(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, testApiToken, tokens.ApiToken().Value)
+		assert.Equal(t, testPaasToken, tokens.PaasToken().Value)
+	})
+	t.Run(`empty tokens are left out`, func(t *testing.T) {
+		tokens, err := SecretConfig{ApiToken: testApiToken}.tokenSource().ReadTokens(context.Background())
+
+		require.NoError(t, err)
+		assert.Len(t, tokens, 1)
+	})
+}
+
 func prepTestFs(t *testing.T) afero.Fs {
 	fs := afero.NewMemMapFs()
 	require.NoError(t, fs.MkdirAll(consts.AgentConfigDirMount, 0770))
@@ -0,0 +1,134 @@
+package registry
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// InstallerMediaType is the media type of the layer of an OCI artifact holding a OneAgent installer zip
+	InstallerMediaType types.MediaType = "application/vnd.dynatrace.oneagent.installer.v1+zip"
+
+	// CodeModulesConfigMediaType is the config media type of OCI artifacts holding the code modules as a single tar.gz or zip layer
+	CodeModulesConfigMediaType types.MediaType = "application/vnd.dynatrace.codemodules.config.v1+json"
+	// CodeModulesTarGzipMediaType is the media type of a code modules layer packaged as tar.gz
+	CodeModulesTarGzipMediaType types.MediaType = "application/vnd.dynatrace.codemodules.layer.v1.tar+gzip"
+	// CodeModulesZipMediaType is the media type of a code modules layer packaged as zip
+	CodeModulesZipMediaType types.MediaType = "application/vnd.dynatrace.codemodules.layer.v1+zip"
+
+	// versionAnnotation holds the version of the code modules in the manifest of the artifact
+	versionAnnotation = "org.opencontainers.image.version"
+
+	artifactConfigMediaType types.MediaType = "application/vnd.dynatrace.artifact.config.v1+json"
+)
+
+// Opener opens the content of an artifact, it is called once per read of the content
+type Opener func() (io.ReadCloser, error)
+
+// blobLayer is a layer which content is stored as is, without being compressed
+type blobLayer struct {
+	open      Opener
+	hash      containerv1.Hash
+	size      int64
+	mediaType types.MediaType
+}
+
+var _ containerv1.Layer = &blobLayer{}
+
+func newBlobLayer(open Opener, mediaType types.MediaType) (*blobLayer, error) {
+	content, err := open()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer func() { _ = content.Close() }()
+
+	hash, size, err := containerv1.SHA256(content)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &blobLayer{
+		open:      open,
+		hash:      hash,
+		size:      size,
+		mediaType: mediaType,
+	}, nil
+}
+
+func (layer *blobLayer) Digest() (containerv1.Hash, error) {
+	return layer.hash, nil
+}
+
+func (layer *blobLayer) DiffID() (containerv1.Hash, error) {
+	return layer.hash, nil
+}
+
+func (layer *blobLayer) Compressed() (io.ReadCloser, error) {
+	return layer.open()
+}
+
+func (layer *blobLayer) Uncompressed() (io.ReadCloser, error) {
+	return layer.open()
+}
+
+func (layer *blobLayer) Size() (int64, error) {
+	return layer.size, nil
+}
+
+func (layer *blobLayer) MediaType() (types.MediaType, error) {
+	return layer.mediaType, nil
+}
+
+// PushArtifact pushes the content as single layer OCI artifact and returns the digest of its manifest
+func PushArtifact(ctx context.Context, ref name.Reference, open Opener, mediaType types.MediaType, options ...remote.Option) (containerv1.Hash, error) {
+	layer, err := newBlobLayer(open, mediaType)
+	if err != nil {
+		return containerv1.Hash{}, errors.WithMessage(err, "failed to read artifact")
+	}
+
+	artifact := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	artifact = mutate.ConfigMediaType(artifact, artifactConfigMediaType)
+	artifact, err = mutate.Append(artifact, mutate.Addendum{Layer: layer, MediaType: mediaType})
+	if err != nil {
+		return containerv1.Hash{}, errors.WithStack(err)
+	}
+
+	err = remote.Write(ref, artifact, append(options, remote.WithContext(ctx))...)
+	if err != nil {
+		return containerv1.Hash{}, errors.WithMessagef(err, "failed to push artifact %s", ref)
+	}
+	return artifact.Digest()
+}
+
+// PullArtifact writes the content of a single layer OCI artifact to the writer
+func PullArtifact(ctx context.Context, ref name.Reference, writer io.Writer, options ...remote.Option) error {
+	artifact, err := remote.Image(ref, append(options, remote.WithContext(ctx))...)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to get artifact %s", ref)
+	}
+
+	layers, err := artifact.Layers()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(layers) != 1 {
+		return errors.Errorf("expected artifact %s to have a single layer, got %d", ref, len(layers))
+	}
+
+	content, err := layers[0].Compressed()
+	if err != nil {
+		return errors.WithMessagef(err, "failed to pull artifact %s", ref)
+	}
+	defer func() { _ = content.Close() }()
+
+	_, err = io.Copy(writer, content)
+	return errors.WithStack(err)
+}
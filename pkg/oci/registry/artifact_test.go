@@ -0,0 +1,65 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	testregistry "github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifact(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	content := "installer.zip content"
+	open := func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(content)), nil
+	}
+
+	t.Run(`push and pull artifact`, func(t *testing.T) {
+		ref, err := name.ParseReference(serverUrl.Host + "/dynatrace/oneagent-installer:1.2.3-amd64")
+		require.NoError(t, err)
+
+		artifactDigest, err := PushArtifact(ctx, ref, open, InstallerMediaType)
+		require.NoError(t, err)
+
+		descriptor, err := remote.Head(ref)
+		require.NoError(t, err)
+		assert.Equal(t, artifactDigest, descriptor.Digest)
+
+		var pulled bytes.Buffer
+		err = PullArtifact(ctx, ref, &pulled)
+		require.NoError(t, err)
+		assert.Equal(t, content, pulled.String())
+	})
+	t.Run(`pulling image with multiple layers fails`, func(t *testing.T) {
+		ref, err := name.ParseReference(serverUrl.Host + "/dynatrace/image:1.2.3")
+		require.NoError(t, err)
+		image, err := random.Image(16, 2)
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref, image))
+
+		err = PullArtifact(ctx, ref, io.Discard)
+		require.Error(t, err)
+	})
+	t.Run(`pulling missing artifact fails`, func(t *testing.T) {
+		ref, err := name.ParseReference(serverUrl.Host + "/dynatrace/oneagent-installer:missing")
+		require.NoError(t, err)
+
+		err = PullArtifact(ctx, ref, io.Discard)
+		require.Error(t, err)
+	})
+}
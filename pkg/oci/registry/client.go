@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/dockerkeychain"
 	"github.com/google/go-containerregistry/pkg/authn"
@@ -31,6 +32,8 @@ type ImageVersion struct {
 	Version string
 	Digest  digest.Digest
 	Type    string
+	// Platforms holds the digests of the images per architecture, it is empty if the image isn't a multi-arch image
+	Platforms map[string]digest.Digest
 }
 
 type Client struct {
@@ -47,6 +50,8 @@ const (
 	// VersionLabel is the name of the label used on ActiveGate-provided images.
 	VersionLabel    = "com.dynatrace.build-version"
 	DigestDelimiter = "@"
+
+	linuxOS = "linux"
 )
 
 func WithContext(ctx context.Context) func(*Client) {
@@ -124,9 +129,11 @@ func (c *Client) GetImageVersion(ctx context.Context, imageName string) (ImageVe
 	digestFn := img.Digest
 
 	// try to get image manifest to cover multi arch images
+	var platforms map[string]digest.Digest
 	imageIndex, err := descriptor.ImageIndex()
 	if err == nil {
 		digestFn = imageIndex.Digest
+		platforms = platformDigests(imageIndex)
 	}
 
 	dig, err := digestFn()
@@ -134,18 +141,53 @@ func (c *Client) GetImageVersion(ctx context.Context, imageName string) (ImageVe
 		return ImageVersion{}, errors.WithMessagef(err, "could not get image digest")
 	}
 
+	manifest, err := img.Manifest()
+	if err != nil {
+		return ImageVersion{}, errors.WithMessagef(err, "img.Manifest")
+	}
+	if manifest.Config.MediaType == CodeModulesConfigMediaType {
+		// artifacts don't have an image config, their version is annotated on the manifest
+		return ImageVersion{
+			Digest:    digest.Digest(dig.String()),
+			Version:   manifest.Annotations[versionAnnotation],
+			Type:      status.ArtifactImageType,
+			Platforms: platforms,
+		}, nil
+	}
+
 	cf, err := img.ConfigFile()
 	if err != nil {
 		return ImageVersion{}, errors.WithMessagef(err, "img.ConfigFile")
 	}
 
 	return ImageVersion{
-		Digest:  digest.Digest(dig.String()),
-		Version: cf.Config.Labels[VersionLabel], // empty if unset
-		Type:    cf.Config.Labels[TypeLabel],    // empty if unset
+		Digest:    digest.Digest(dig.String()),
+		Version:   cf.Config.Labels[VersionLabel], // empty if unset
+		Type:      cf.Config.Labels[TypeLabel],    // empty if unset
+		Platforms: platforms,
 	}, nil
 }
 
+// platformDigests collects the digests of the linux images in a manifest list per architecture
+func platformDigests(imageIndex containerv1.ImageIndex) map[string]digest.Digest {
+	indexManifest, err := imageIndex.IndexManifest()
+	if err != nil {
+		return nil
+	}
+
+	platforms := make(map[string]digest.Digest)
+	for _, manifest := range indexManifest.Manifests {
+		// attestation manifests are listed with the unknown platform
+		if manifest.Platform == nil || manifest.Platform.OS != linuxOS {
+			continue
+		}
+		if _, ok := platforms[manifest.Platform.Architecture]; !ok {
+			platforms[manifest.Platform.Architecture] = digest.Digest(manifest.Digest.String())
+		}
+	}
+	return platforms
+}
+
 func (c *Client) PullImageInfo(ctx context.Context, imageName string) (*containerv1.Image, error) {
 	ref, err := name.ParseReference(imageName)
 	if err != nil {
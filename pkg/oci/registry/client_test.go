@@ -0,0 +1,51 @@
+package registry
+
+import (
+	"testing"
+
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlatformDigests(t *testing.T) {
+	t.Run(`collects digests of linux images per architecture`, func(t *testing.T) {
+		amdImage := randomImage(t)
+		armImage := randomImage(t)
+		windowsImage := randomImage(t)
+		attestation := randomImage(t)
+
+		imageIndex := mutate.AppendManifests(empty.Index,
+			mutate.IndexAddendum{Add: amdImage, Descriptor: containerv1.Descriptor{Platform: &containerv1.Platform{OS: "linux", Architecture: "amd64"}}},
+			mutate.IndexAddendum{Add: armImage, Descriptor: containerv1.Descriptor{Platform: &containerv1.Platform{OS: "linux", Architecture: "arm64"}}},
+			mutate.IndexAddendum{Add: windowsImage, Descriptor: containerv1.Descriptor{Platform: &containerv1.Platform{OS: "windows", Architecture: "amd64"}}},
+			mutate.IndexAddendum{Add: attestation, Descriptor: containerv1.Descriptor{Platform: &containerv1.Platform{OS: "unknown", Architecture: "unknown"}}},
+		)
+
+		platforms := platformDigests(imageIndex)
+
+		require.Len(t, platforms, 2)
+		assert.Equal(t, digestOf(t, amdImage), platforms["amd64"].String())
+		assert.Equal(t, digestOf(t, armImage), platforms["arm64"].String())
+	})
+	t.Run(`empty for manifest list without platforms`, func(t *testing.T) {
+		imageIndex := mutate.AppendManifests(empty.Index, mutate.IndexAddendum{Add: randomImage(t)})
+
+		assert.Empty(t, platformDigests(imageIndex))
+	})
+}
+
+func randomImage(t *testing.T) containerv1.Image {
+	image, err := random.Image(64, 1)
+	require.NoError(t, err)
+	return image
+}
+
+func digestOf(t *testing.T, image containerv1.Image) string {
+	imageDigest, err := image.Digest()
+	require.NoError(t, err)
+	return imageDigest.String()
+}
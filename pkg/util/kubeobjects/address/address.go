@@ -8,7 +8,7 @@ import (
 )
 
 type scalarType interface {
-	bool | constraints.Integer | constraints.Float | time.Time | metav1.Time
+	bool | ~string | constraints.Integer | constraints.Float | time.Time | metav1.Time
 }
 
 func Of[T scalarType](i T) *T {
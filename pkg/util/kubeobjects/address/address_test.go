@@ -30,4 +30,11 @@ func TestOf(t *testing.T) {
 		mutableInt := int64(4)
 		assert.Equal(t, int64(4), *Of(mutableInt))
 	})
+
+	t.Run("string", func(t *testing.T) {
+		assert.Equal(t, "test", *Of("test"))
+
+		mutableString := "test"
+		assert.Equal(t, "test", *Of(mutableString))
+	})
 }
@@ -1,7 +1,10 @@
 package kubeobjects
 
 import (
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 )
 
 const (
@@ -18,6 +21,22 @@ func AffinityNodeRequirementForSupportedArches() []corev1.NodeSelectorRequiremen
 	return affinityNodeRequirementsForArches(amd64, arm64, ppc64le)
 }
 
+// AffinityNodeRequirementForImageArches restricts the supported arches to the arches an image is available for,
+// all supported arches are used if the image isn't a multi-arch image or isn't available for any of them
+func AffinityNodeRequirementForImageArches(imageArches []string) []corev1.NodeSelectorRequirement {
+	arches := make([]string, 0, len(imageArches))
+	for _, supportedArch := range []string{amd64, arm64, ppc64le} {
+		if slices.Contains(imageArches, supportedArch) {
+			arches = append(arches, supportedArch)
+		}
+	}
+
+	if len(arches) == 0 {
+		return AffinityNodeRequirementForSupportedArches()
+	}
+	return affinityNodeRequirementsForArches(arches...)
+}
+
 func affinityNodeRequirementsForArches(arches ...string) []corev1.NodeSelectorRequirement {
 	return []corev1.NodeSelectorRequirement{
 		{
@@ -32,3 +51,70 @@ func affinityNodeRequirementsForArches(arches ...string) []corev1.NodeSelectorRe
 		},
 	}
 }
+
+// NodeMatchesPodSpec tells if the node selector and the required node affinity of a pod spec allow scheduling on the node
+func NodeMatchesPodSpec(node *corev1.Node, podSpec *corev1.PodSpec) bool {
+	if !labels.SelectorFromSet(podSpec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+
+	affinity := podSpec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	return NodeMatchesSelectorTerms(node, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+}
+
+// NodeMatchesSelectorTerms tells if the node matches any of the terms, a term without requirements matches no node
+func NodeMatchesSelectorTerms(node *corev1.Node, terms []corev1.NodeSelectorTerm) bool {
+	for _, term := range terms {
+		if nodeMatchesSelectorTerm(node, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeMatchesSelectorTerm(node *corev1.Node, term corev1.NodeSelectorTerm) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+
+	for _, requirement := range term.MatchExpressions {
+		if !nodeSelectorRequirementMatches(requirement, labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	for _, requirement := range term.MatchFields {
+		if requirement.Key != "metadata.name" || !nodeSelectorRequirementMatches(requirement, labels.Set{requirement.Key: node.Name}) {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeSelectorRequirementMatches(requirement corev1.NodeSelectorRequirement, set labels.Set) bool {
+	var operator selection.Operator
+	switch requirement.Operator {
+	case corev1.NodeSelectorOpIn:
+		operator = selection.In
+	case corev1.NodeSelectorOpNotIn:
+		operator = selection.NotIn
+	case corev1.NodeSelectorOpExists:
+		operator = selection.Exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		operator = selection.DoesNotExist
+	case corev1.NodeSelectorOpGt:
+		operator = selection.GreaterThan
+	case corev1.NodeSelectorOpLt:
+		operator = selection.LessThan
+	default:
+		return false
+	}
+
+	labelRequirement, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+	if err != nil {
+		return false
+	}
+	return labelRequirement.Matches(set)
+}
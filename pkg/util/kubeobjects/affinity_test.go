@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestAffinityNodeRequirement(t *testing.T) {
@@ -12,6 +13,68 @@ func TestAffinityNodeRequirement(t *testing.T) {
 	assert.Contains(t, AffinityNodeRequirementForSupportedArches(), linuxRequirement())
 }
 
+func TestAffinityNodeRequirementForImageArches(t *testing.T) {
+	t.Run(`restricted to arches of image`, func(t *testing.T) {
+		assert.Equal(t, affinityNodeRequirementsForArches(amd64, arm64), AffinityNodeRequirementForImageArches([]string{arm64, "s390x", amd64}))
+	})
+	t.Run(`all supported arches for single arch image`, func(t *testing.T) {
+		assert.Equal(t, AffinityNodeRequirementForSupportedArches(), AffinityNodeRequirementForImageArches(nil))
+	})
+	t.Run(`all supported arches if image has no supported arch`, func(t *testing.T) {
+		assert.Equal(t, AffinityNodeRequirementForSupportedArches(), AffinityNodeRequirementForImageArches([]string{"s390x"}))
+	})
+}
+
+func TestNodeMatchesPodSpec(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node1",
+			Labels: map[string]string{
+				kubernetesArch: amd64,
+				kubernetesOS:   linux,
+				"pool":         "gpu",
+			},
+		},
+	}
+	archOsAffinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: AffinityNodeRequirementForSupportedArches()}},
+			},
+		},
+	}
+
+	t.Run(`matches without selector and affinity`, func(t *testing.T) {
+		assert.True(t, NodeMatchesPodSpec(node, &corev1.PodSpec{}))
+	})
+	t.Run(`matches node selector and affinity`, func(t *testing.T) {
+		assert.True(t, NodeMatchesPodSpec(node, &corev1.PodSpec{NodeSelector: map[string]string{"pool": "gpu"}, Affinity: archOsAffinity}))
+	})
+	t.Run(`node selector mismatch`, func(t *testing.T) {
+		assert.False(t, NodeMatchesPodSpec(node, &corev1.PodSpec{NodeSelector: map[string]string{"pool": "system"}}))
+	})
+	t.Run(`affinity mismatch`, func(t *testing.T) {
+		windowsNode := node.DeepCopy()
+		windowsNode.Labels[kubernetesOS] = "windows"
+		assert.False(t, NodeMatchesPodSpec(windowsNode, &corev1.PodSpec{Affinity: archOsAffinity}))
+	})
+	t.Run(`terms are ORed, empty terms match no node`, func(t *testing.T) {
+		terms := []corev1.NodeSelectorTerm{
+			{},
+			{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node1"}}}},
+		}
+		assert.True(t, NodeMatchesSelectorTerms(node, terms))
+		assert.False(t, NodeMatchesSelectorTerms(node, terms[:1]))
+	})
+	t.Run(`numeric comparison`, func(t *testing.T) {
+		gpuCountNode := node.DeepCopy()
+		gpuCountNode.Labels["gpus"] = "4"
+		terms := []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "gpus", Operator: corev1.NodeSelectorOpGt, Values: []string{"2"}}}}}
+		assert.True(t, NodeMatchesSelectorTerms(gpuCountNode, terms))
+		assert.False(t, NodeMatchesSelectorTerms(node, terms))
+	})
+}
+
 func linuxRequirement() corev1.NodeSelectorRequirement {
 	return corev1.NodeSelectorRequirement{
 		Key:      kubernetesOS,
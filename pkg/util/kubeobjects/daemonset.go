@@ -2,11 +2,13 @@ package kubeobjects
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -23,7 +25,7 @@ func CreateOrUpdateDaemonSet(kubernetesClient client.Client, logger logr.Logger,
 		return false, nil
 	}
 
-	if LabelsNotEqual(currentDaemonSet.Spec.Selector.MatchLabels, desiredDaemonSet.Spec.Selector.MatchLabels) {
+	if selectorChanged(currentDaemonSet.Spec.Selector, desiredDaemonSet.Spec.Selector) {
 		return recreateDaemonSet(kubernetesClient, logger, currentDaemonSet, desiredDaemonSet)
 	}
 
@@ -34,6 +36,18 @@ func CreateOrUpdateDaemonSet(kubernetesClient client.Client, logger logr.Logger,
 	return true, err
 }
 
+// selectorChanged returns true if the immutable selector of the DaemonSet has to be changed
+func selectorChanged(current, desired *metav1.LabelSelector) bool {
+	if LabelsNotEqual(current.MatchLabels, desired.MatchLabels) {
+		return true
+	}
+	// a missing and an empty list of expressions are the same, recreating the DaemonSet for it would restart every OneAgent
+	if len(current.MatchExpressions) == 0 && len(desired.MatchExpressions) == 0 {
+		return false
+	}
+	return !reflect.DeepEqual(current.MatchExpressions, desired.MatchExpressions)
+}
+
 func recreateDaemonSet(kubernetesClient client.Client, logger logr.Logger, currentDs, desiredDaemonSet *appsv1.DaemonSet) (bool, error) {
 	logger.Info("immutable section changed on daemonset, deleting and recreating", "name", desiredDaemonSet.Name)
 	err := kubernetesClient.Delete(context.TODO(), currentDs)
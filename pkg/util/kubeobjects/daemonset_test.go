@@ -70,6 +70,33 @@ func TestCreateOrUpdateDaemonSet(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, newMatchLabels, actualDaemonSet.Spec.Selector.MatchLabels)
 	})
+	t.Run("recreate when exists and selector expressions changed", func(t *testing.T) {
+		matchLabels := map[string]string{"match": "same"}
+		oldDaemonSet := createTestDaemonSetWithMatchLabels(daemonsetName, namespaceName, map[string]string{AnnotationHash: "old"}, matchLabels)
+
+		newDaemonSet := createTestDaemonSetWithMatchLabels(daemonsetName, namespaceName, map[string]string{AnnotationHash: "new"}, matchLabels)
+		expressions := []metav1.LabelSelectorRequirement{{Key: "excluded", Operator: metav1.LabelSelectorOpDoesNotExist}}
+		newDaemonSet.Spec.Selector.MatchExpressions = expressions
+		require.True(t, selectorChanged(oldDaemonSet.Spec.Selector, newDaemonSet.Spec.Selector))
+		fakeClient := fake.NewClient(&oldDaemonSet)
+
+		updated, err := CreateOrUpdateDaemonSet(fakeClient, daemonSetLog, &newDaemonSet)
+
+		require.NoError(t, err)
+		assert.True(t, updated)
+		var actualDaemonSet appsv1.DaemonSet
+		err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: daemonsetName, Namespace: namespaceName}, &actualDaemonSet)
+		require.NoError(t, err)
+		assert.Equal(t, expressions, actualDaemonSet.Spec.Selector.MatchExpressions)
+	})
+	t.Run("missing and empty selector expressions are the same", func(t *testing.T) {
+		matchLabels := map[string]string{"match": "same"}
+		oldDaemonSet := createTestDaemonSetWithMatchLabels(daemonsetName, namespaceName, nil, matchLabels)
+		newDaemonSet := createTestDaemonSetWithMatchLabels(daemonsetName, namespaceName, nil, matchLabels)
+		newDaemonSet.Spec.Selector.MatchExpressions = []metav1.LabelSelectorRequirement{}
+
+		assert.False(t, selectorChanged(oldDaemonSet.Spec.Selector, newDaemonSet.Spec.Selector))
+	})
 }
 
 func createTestDaemonSetWithMatchLabels(name, namespace string, annotations, matchLabels map[string]string) appsv1.DaemonSet {
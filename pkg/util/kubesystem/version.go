@@ -0,0 +1,32 @@
+package kubesystem
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+// nativeSidecarMinVersion is the first kubernetes version with restartable init containers (SidecarContainers) enabled by default
+var nativeSidecarMinVersion = version.MajorMinor(1, 29)
+
+// SupportsNativeSidecars checks if the kubernetes version of the cluster supports restartable init containers
+func SupportsNativeSidecars(cfg *rest.Config) (bool, error) {
+	client, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	serverVersion, err := client.ServerVersion()
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return isNativeSidecarVersion(serverVersion.GitVersion)
+}
+
+func isNativeSidecarVersion(gitVersion string) (bool, error) {
+	serverVersion, err := version.ParseGeneric(gitVersion)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return serverVersion.AtLeast(nativeSidecarMinVersion), nil
+}
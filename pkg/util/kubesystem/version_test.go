@@ -0,0 +1,27 @@
+package kubesystem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNativeSidecarVersion(t *testing.T) {
+	t.Run("supported from 1.29 on", func(t *testing.T) {
+		for _, gitVersion := range []string{"v1.29.0", "v1.30.2-eks-1552ad0", "v1.29.1+k3s1"} {
+			supported, err := isNativeSidecarVersion(gitVersion)
+			require.NoError(t, err)
+			assert.True(t, supported, gitVersion)
+		}
+	})
+	t.Run("not supported before 1.29", func(t *testing.T) {
+		supported, err := isNativeSidecarVersion("v1.28.5-gke.1217000")
+		require.NoError(t, err)
+		assert.False(t, supported)
+	})
+	t.Run("invalid version is an error", func(t *testing.T) {
+		_, err := isNativeSidecarVersion("latest")
+		require.Error(t, err)
+	})
+}
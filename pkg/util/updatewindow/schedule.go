@@ -0,0 +1,181 @@
+package updatewindow
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	monthNames = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+	weekdayNames = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}
+)
+
+type field struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+
+	// day of week ranges like "sat-sun" end on the sunday at the end of the week
+	zeroEndsRangeAtMax bool
+}
+
+var (
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day of month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12, names: monthNames}
+	// 7 is accepted as Sunday as well, it is folded into 0 after parsing
+	dayOfWeekField = field{name: "day of week", min: 0, max: 7, names: weekdayNames, zeroEndsRangeAtMax: true}
+)
+
+// schedule is a parsed standard 5 field cron expression (minute hour day-of-month month day-of-week)
+type schedule struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	daysOfMonth map[int]bool
+	months      map[int]bool
+	daysOfWeek  map[int]bool
+
+	// as in cron, if both day fields are restricted a day matches if either of them matches
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+func parseSchedule(expression string) (*schedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("expected 5 fields (minute hour day-of-month month day-of-week) in schedule '%s', got %d", expression, len(fields))
+	}
+
+	parsed := &schedule{
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}
+
+	var err error
+	if parsed.minutes, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if parsed.hours, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if parsed.daysOfMonth, err = dayOfMonthField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if parsed.months, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if parsed.daysOfWeek, err = dayOfWeekField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	if parsed.daysOfWeek[7] {
+		parsed.daysOfWeek[0] = true
+		delete(parsed.daysOfWeek, 7)
+	}
+	return parsed, nil
+}
+
+// matches checks if the schedule fires at the minute of t, in the location of t
+func (s *schedule) matches(t time.Time) bool {
+	return s.minutes[t.Minute()] && s.hours[t.Hour()] && s.matchesDay(t)
+}
+
+func (s *schedule) matchesDay(t time.Time) bool {
+	if !s.months[int(t.Month())] {
+		return false
+	}
+
+	dayOfMonth := s.daysOfMonth[t.Day()]
+	dayOfWeek := s.daysOfWeek[int(t.Weekday())]
+	switch {
+	case s.anyDayOfMonth:
+		return dayOfWeek
+	case s.anyDayOfWeek:
+		return dayOfMonth
+	default:
+		return dayOfMonth || dayOfWeek
+	}
+}
+
+// parse parses a comma separated list of values, ranges and steps like "1,5-10,*/15"
+func (f field) parse(expression string) (map[int]bool, error) {
+	values := make(map[int]bool)
+	for _, part := range strings.Split(expression, ",") {
+		if err := f.parsePart(part, values); err != nil {
+			return nil, errors.WithMessagef(err, "invalid %s '%s'", f.name, expression)
+		}
+	}
+	return values, nil
+}
+
+func (f field) parsePart(part string, values map[int]bool) error {
+	rangeExpression, stepExpression, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepExpression)
+		if err != nil || step < 1 {
+			return errors.Errorf("invalid step '%s'", stepExpression)
+		}
+	}
+
+	start, end := f.min, f.max
+	switch {
+	case rangeExpression == "*":
+	case strings.Contains(rangeExpression, "-"):
+		startExpression, endExpression, _ := strings.Cut(rangeExpression, "-")
+		var err error
+		if start, err = f.value(startExpression); err != nil {
+			return err
+		}
+		if end, err = f.value(endExpression); err != nil {
+			return err
+		}
+		if f.zeroEndsRangeAtMax && end == 0 {
+			end = f.max
+		}
+		if start > end {
+			return errors.Errorf("range start %d is after range end %d", start, end)
+		}
+	default:
+		var err error
+		if start, err = f.value(rangeExpression); err != nil {
+			return err
+		}
+		if hasStep {
+			end = f.max
+		} else {
+			end = start
+		}
+	}
+
+	for value := start; value <= end; value += step {
+		values[value] = true
+	}
+	return nil
+}
+
+func (f field) value(expression string) (int, error) {
+	if value, ok := f.names[strings.ToLower(expression)]; ok {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(expression)
+	if err != nil {
+		return 0, errors.Errorf("invalid value '%s'", expression)
+	}
+	if value < f.min || value > f.max {
+		return 0, errors.Errorf("value %d is out of range %d-%d", value, f.min, f.max)
+	}
+	return value, nil
+}
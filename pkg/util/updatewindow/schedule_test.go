@@ -0,0 +1,90 @@
+package updatewindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	t.Run(`parses values, ranges, lists and steps`, func(t *testing.T) {
+		parsed, err := parseSchedule("*/15 22-23,0-4 * * *")
+		require.NoError(t, err)
+
+		assert.Equal(t, map[int]bool{0: true, 15: true, 30: true, 45: true}, parsed.minutes)
+		assert.Equal(t, map[int]bool{22: true, 23: true, 0: true, 1: true, 2: true, 3: true, 4: true}, parsed.hours)
+		assert.Len(t, parsed.daysOfMonth, 31)
+		assert.Len(t, parsed.months, 12)
+		assert.Len(t, parsed.daysOfWeek, 7)
+	})
+	t.Run(`parses month and weekday names`, func(t *testing.T) {
+		parsed, err := parseSchedule("0 2 * JAN,jul sat-sun")
+		require.NoError(t, err)
+
+		assert.Equal(t, map[int]bool{1: true, 7: true}, parsed.months)
+		assert.Equal(t, map[int]bool{6: true, 0: true}, parsed.daysOfWeek)
+	})
+	t.Run(`7 is sunday`, func(t *testing.T) {
+		parsed, err := parseSchedule("0 2 * * 7")
+		require.NoError(t, err)
+
+		assert.Equal(t, map[int]bool{0: true}, parsed.daysOfWeek)
+	})
+	t.Run(`a step without range runs until the end of the field`, func(t *testing.T) {
+		parsed, err := parseSchedule("50/5 * * * *")
+		require.NoError(t, err)
+
+		assert.Equal(t, map[int]bool{50: true, 55: true}, parsed.minutes)
+	})
+	t.Run(`rejects invalid schedules`, func(t *testing.T) {
+		for _, expression := range []string{
+			"",
+			"* * * *",
+			"* * * * * *",
+			"60 * * * *",
+			"* 24 * * *",
+			"* * 0 * *",
+			"* * * 13 *",
+			"* * * * 8",
+			"5-1 * * * *",
+			"*/0 * * * *",
+			"a * * * *",
+			"* * * foo *",
+		} {
+			_, err := parseSchedule(expression)
+			assert.Error(t, err, expression)
+		}
+	})
+}
+
+func TestScheduleMatches(t *testing.T) {
+	t.Run(`matches the minute the schedule fires`, func(t *testing.T) {
+		parsed, err := parseSchedule("30 2 * * *")
+		require.NoError(t, err)
+
+		assert.True(t, parsed.matches(time.Date(2024, 3, 5, 2, 30, 59, 0, time.UTC)))
+		assert.False(t, parsed.matches(time.Date(2024, 3, 5, 2, 31, 0, 0, time.UTC)))
+		assert.False(t, parsed.matches(time.Date(2024, 3, 5, 3, 30, 0, 0, time.UTC)))
+	})
+	t.Run(`matches either restricted day field`, func(t *testing.T) {
+		parsed, err := parseSchedule("0 0 1 * mon")
+		require.NoError(t, err)
+
+		// friday the first
+		assert.True(t, parsed.matches(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+		// monday the fourth
+		assert.True(t, parsed.matches(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)))
+		// tuesday the fifth
+		assert.False(t, parsed.matches(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)))
+	})
+	t.Run(`matches both day fields if one is unrestricted`, func(t *testing.T) {
+		parsed, err := parseSchedule("0 0 * mar sat")
+		require.NoError(t, err)
+
+		assert.True(t, parsed.matches(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)))
+		assert.False(t, parsed.matches(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+		assert.False(t, parsed.matches(time.Date(2024, 4, 6, 0, 0, 0, 0, time.UTC)))
+	})
+}
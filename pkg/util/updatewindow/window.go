@@ -0,0 +1,87 @@
+package updatewindow
+
+import (
+	"time"
+	// the operator images don't ship a time zone database
+	_ "time/tzdata"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxDuration limits how long a window can stay open, a longer window can be expressed by a more frequent schedule
+	MaxDuration = 7 * 24 * time.Hour
+
+	// how far ahead the next opening of a window is searched, a schedule like "0 0 29 2 *" only fires every 4 years
+	searchLimit = 5 * 366 * 24 * time.Hour
+)
+
+// Window is a recurring time window, it opens whenever its cron schedule fires and stays open for its duration
+type Window struct {
+	schedule *schedule
+	duration time.Duration
+	location *time.Location
+}
+
+// New parses the cron schedule, which is evaluated in the given IANA time zone (UTC if empty)
+func New(cronSchedule string, duration time.Duration, timeZone string) (*Window, error) {
+	if duration <= 0 || duration > MaxDuration {
+		return nil, errors.Errorf("duration %s is not between 0 and %s", duration, MaxDuration)
+	}
+
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "unknown time zone '%s'", timeZone)
+	}
+
+	parsedSchedule, err := parseSchedule(cronSchedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Window{
+		schedule: parsedSchedule,
+		duration: duration,
+		location: location,
+	}, nil
+}
+
+// IsOpen checks if the schedule fired less than the duration of the window before now, a nil window is always open
+func (window *Window) IsOpen(now time.Time) bool {
+	if window == nil {
+		return true
+	}
+
+	now = now.In(window.location)
+	for start := now.Truncate(time.Minute); now.Sub(start) < window.duration; start = start.Add(-time.Minute) {
+		if window.schedule.matches(start) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpening returns the next time after now at which the window opens, it is zero if the schedule never fires or the window is nil
+func (window *Window) NextOpening(now time.Time) time.Time {
+	if window == nil {
+		return time.Time{}
+	}
+
+	now = now.In(window.location)
+	limit := now.Add(searchLimit)
+
+	next := now.Truncate(time.Minute).Add(time.Minute)
+	for next.Before(limit) {
+		switch {
+		case !window.schedule.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, window.location)
+		case !window.schedule.hours[next.Hour()]:
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, window.location)
+		case !window.schedule.minutes[next.Minute()]:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,104 @@
+package updatewindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Run(`creates window`, func(t *testing.T) {
+		window, err := New("0 22 * * mon-fri", 4*time.Hour, "Europe/Vienna")
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/Vienna", window.location.String())
+	})
+	t.Run(`defaults to UTC`, func(t *testing.T) {
+		window, err := New("0 22 * * *", time.Hour, "")
+		require.NoError(t, err)
+		assert.Equal(t, time.UTC, window.location)
+	})
+	t.Run(`rejects unknown time zone`, func(t *testing.T) {
+		_, err := New("0 22 * * *", time.Hour, "Mars/Olympus_Mons")
+		require.Error(t, err)
+	})
+	t.Run(`rejects invalid duration`, func(t *testing.T) {
+		_, err := New("0 22 * * *", 0, "")
+		require.Error(t, err)
+
+		_, err = New("0 22 * * *", MaxDuration+time.Minute, "")
+		require.Error(t, err)
+	})
+	t.Run(`rejects invalid schedule`, func(t *testing.T) {
+		_, err := New("0 25 * * *", time.Hour, "")
+		require.Error(t, err)
+	})
+}
+
+func TestIsOpen(t *testing.T) {
+	vienna, err := time.LoadLocation("Europe/Vienna")
+	require.NoError(t, err)
+
+	window, err := New("0 22 * * mon-fri", 4*time.Hour, "Europe/Vienna")
+	require.NoError(t, err)
+
+	t.Run(`open within the duration after the schedule fired`, func(t *testing.T) {
+		// monday
+		assert.True(t, window.IsOpen(time.Date(2024, 3, 4, 22, 0, 0, 0, vienna)))
+		assert.True(t, window.IsOpen(time.Date(2024, 3, 5, 1, 59, 59, 0, vienna)))
+	})
+	t.Run(`closed outside of the window`, func(t *testing.T) {
+		assert.False(t, window.IsOpen(time.Date(2024, 3, 4, 21, 59, 0, 0, vienna)))
+		assert.False(t, window.IsOpen(time.Date(2024, 3, 5, 2, 0, 0, 0, vienna)))
+		assert.False(t, window.IsOpen(time.Date(2024, 3, 5, 12, 0, 0, 0, vienna)))
+	})
+	t.Run(`closed on days the schedule doesn't fire`, func(t *testing.T) {
+		// saturday
+		assert.False(t, window.IsOpen(time.Date(2024, 3, 9, 23, 0, 0, 0, vienna)))
+	})
+	t.Run(`nil window is always open`, func(t *testing.T) {
+		var noWindow *Window
+		assert.True(t, noWindow.IsOpen(time.Date(2024, 3, 5, 12, 0, 0, 0, vienna)))
+		assert.True(t, noWindow.NextOpening(time.Date(2024, 3, 5, 12, 0, 0, 0, vienna)).IsZero())
+	})
+	t.Run(`evaluates the schedule in the time zone of the window`, func(t *testing.T) {
+		// 22:00 in Vienna is 21:00 UTC in winter
+		assert.True(t, window.IsOpen(time.Date(2024, 3, 4, 21, 30, 0, 0, time.UTC)))
+		assert.False(t, window.IsOpen(time.Date(2024, 3, 4, 22, 30, 0, 0, vienna).Add(4*time.Hour)))
+	})
+}
+
+func TestNextOpening(t *testing.T) {
+	vienna, err := time.LoadLocation("Europe/Vienna")
+	require.NoError(t, err)
+
+	t.Run(`next opening on the same day`, func(t *testing.T) {
+		window, err := New("0 22 * * mon-fri", 4*time.Hour, "Europe/Vienna")
+		require.NoError(t, err)
+
+		next := window.NextOpening(time.Date(2024, 3, 4, 12, 0, 0, 0, vienna))
+		assert.True(t, time.Date(2024, 3, 4, 22, 0, 0, 0, vienna).Equal(next))
+	})
+	t.Run(`skips days the schedule doesn't fire`, func(t *testing.T) {
+		window, err := New("0 22 * * mon-fri", 4*time.Hour, "Europe/Vienna")
+		require.NoError(t, err)
+
+		// friday after the window opened
+		next := window.NextOpening(time.Date(2024, 3, 8, 22, 30, 0, 0, vienna))
+		assert.True(t, time.Date(2024, 3, 11, 22, 0, 0, 0, vienna).Equal(next))
+	})
+	t.Run(`handles rarely firing schedules`, func(t *testing.T) {
+		window, err := New("0 0 29 feb *", time.Hour, "")
+		require.NoError(t, err)
+
+		next := window.NextOpening(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+		assert.True(t, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC).Equal(next))
+	})
+	t.Run(`zero if the schedule never fires`, func(t *testing.T) {
+		window, err := New("0 0 31 feb *", time.Hour, "")
+		require.NoError(t, err)
+
+		assert.True(t, window.NextOpening(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)).IsZero())
+	})
+}
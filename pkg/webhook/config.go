@@ -1,5 +1,7 @@
 package webhook
 
+import "fmt"
+
 const (
 	// InjectionInstanceLabel can be set in a Namespace and indicates the corresponding DynaKube object assigned to it.
 	InjectionInstanceLabel = "dynakube.internal.dynatrace.com/instance"
@@ -7,6 +9,10 @@ const (
 	// AnnotationDynatraceInjected is set to "true" by the webhook to Pods to indicate that it has been injected.
 	AnnotationDynatraceInjected = "dynakube.dynatrace.com/injected"
 
+	// AnnotationInjectionSummary is set by the operator on Namespaces assigned to a DynaKube, it contains the
+	// injection outcome of their Pods as json.
+	AnnotationInjectionSummary = "dynakube.dynatrace.com/injection-summary"
+
 	// AnnotationDynatraceInject is set to "false" on the Pod to indicate that does not want any injection.
 	AnnotationDynatraceInject = "dynatrace.com/inject"
 
@@ -15,14 +21,23 @@ const (
 	AnnotationOneAgentInject   = OneAgentPrefix + ".dynatrace.com/inject"
 	AnnotationOneAgentInjected = OneAgentPrefix + ".dynatrace.com/injected"
 	AnnotationOneAgentReason   = OneAgentPrefix + ".dynatrace.com/reason"
+	// AnnotationOneAgentVersion is set by the webhook to the code modules version the Pod was injected with.
+	AnnotationOneAgentVersion = OneAgentPrefix + ".dynatrace.com/version"
 
 	EmptyConnectionInfoReason = "EmptyConnectionInfo"
+	// TimeBudgetExceededReason is used for Pods the webhook admitted unmodified, because their mutation took too long.
+	TimeBudgetExceededReason = "TimeBudgetExceeded"
 
 	DataIngestPrefix = "data-ingest"
 	// AnnotationDataIngestInject can be set at pod level to enable/disable data-ingest injection.
 	AnnotationDataIngestInject   = DataIngestPrefix + ".dynatrace.com/inject"
 	AnnotationDataIngestInjected = DataIngestPrefix + ".dynatrace.com/injected"
 
+	OTelPrefix = "otel"
+	// AnnotationOTelInject can be set at pod level to enable/disable the OpenTelemetry injection.
+	AnnotationOTelInject   = OTelPrefix + ".dynatrace.com/inject"
+	AnnotationOTelInjected = OTelPrefix + ".dynatrace.com/injected"
+
 	// AnnotationFlavor can be set on a Pod to configure which code modules flavor to download. It's set to "default"
 	// if not set.
 	AnnotationFlavor = "oneagent.dynatrace.com/flavor"
@@ -43,6 +58,21 @@ const (
 	// "fail", the init container will exit with error code 1. Defaults to "silent".
 	AnnotationFailurePolicy = "oneagent.dynatrace.com/failure-policy"
 
+	// AnnotationInitResources can be set on a Namespace to replace the init container resources of the DynaKube for
+	// its Pods, as json of the container resources, e.g. {"requests":{"cpu":"30m"},"limits":{"cpu":"100m"}}.
+	AnnotationInitResources = "oneagent.dynatrace.com/init-resources"
+
+	// AnnotationReadOnlyCSIVolume can be set to "true" or "false" on a Namespace to override whether its Pods mount
+	// the CSI volume read-only. Only has an effect if the DynaKube uses the CSI driver.
+	AnnotationReadOnlyCSIVolume = "oneagent.dynatrace.com/readonly-csi-volume"
+
+	// AnnotationInjectionPolicy is set by the webhook to the name of the InjectionPolicy that was applied to the Pod.
+	AnnotationInjectionPolicy = "oneagent.dynatrace.com/injection-policy"
+
+	// AnnotationContainerInjectionPrefix followed by a container name can be set to "false" on a Pod to skip the
+	// OneAgent injection for that container, see ContainerInjectionAnnotation.
+	AnnotationContainerInjectionPrefix = "container.inject.dynatrace.com"
+
 	// DefaultInstallPath is the default directory to install the app-only OneAgent package.
 	DefaultInstallPath = "/opt/dynatrace/oneagent-paas"
 
@@ -56,4 +86,12 @@ const (
 
 	// InstallContainerName is the name used for the install container
 	InstallContainerName = "install-oneagent"
+
+	// RefreshContainerName is the name used for the native sidecar, that keeps the configuration of the install container up to date
+	RefreshContainerName = "refresh-oneagent-config"
 )
+
+// ContainerInjectionAnnotation returns the annotation that controls the OneAgent injection of the given container.
+func ContainerInjectionAnnotation(containerName string) string {
+	return fmt.Sprintf("%s/%s", AnnotationContainerInjectionPrefix, containerName)
+}
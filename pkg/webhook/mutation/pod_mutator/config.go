@@ -2,6 +2,8 @@ package pod_mutator
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 const (
@@ -15,8 +17,30 @@ const (
 	rootUserGroup int64 = 0
 
 	otelName = "DynatraceMutationWebhook"
+
+	injectionReasonError            = "error"
+	injectionReasonNotMonitored     = "not_monitored"
+	injectionReasonDisabled         = "disabled"
+	injectionReasonOcDebugPod       = "oc_debug_pod"
+	injectionReasonAlreadyInjected  = "already_injected"
+	injectionReasonReinvoked        = "reinvoked"
+	injectionReasonInjected         = "injected"
+	injectionReasonNoMutatorEnabled = "no_mutator_enabled"
+
+	injectionReasonTimeBudgetExceeded = "time_budget_exceeded"
 )
 
 var (
 	log = logger.Factory.GetLogger("pod-mutation")
+
+	injectionsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "webhook",
+		Name:      "pod_injections_total",
+		Help:      "Number of pods handled by the webhook by outcome",
+	}, []string{"reason"})
 )
+
+func init() {
+	metrics.Registry.MustRegister(injectionsMetric)
+}
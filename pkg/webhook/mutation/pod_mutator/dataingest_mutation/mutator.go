@@ -15,14 +15,16 @@ type DataIngestPodMutator struct {
 	client           client.Client
 	metaClient       client.Client
 	apiReader        client.Reader
+	workloadKinds    WorkloadKinds
 }
 
-func NewDataIngestPodMutator(webhookNamespace string, client client.Client, apiReader client.Reader, metaClient client.Client) *DataIngestPodMutator {
+func NewDataIngestPodMutator(webhookNamespace string, client client.Client, apiReader client.Reader, metaClient client.Client, workloadKinds WorkloadKinds) *DataIngestPodMutator {
 	return &DataIngestPodMutator{
 		client:           client,
 		apiReader:        apiReader,
 		metaClient:       metaClient,
 		webhookNamespace: webhookNamespace,
+		workloadKinds:    workloadKinds,
 	}
 }
 
@@ -74,7 +76,9 @@ func (mutator *DataIngestPodMutator) ensureDataIngestSecret(request *dtwebhook.M
 			Namespace: request.Namespace.Name,
 		},
 		&endpointSecret)
-	if k8serrors.IsNotFound(err) {
+	if k8serrors.IsNotFound(err) && request.DryRun {
+		log.Info("the data-ingest endpoint secret would be created before pod injection, skipped for dry-run")
+	} else if k8serrors.IsNotFound(err) {
 		err := endpointGenerator.GenerateForNamespace(request.Context, request.DynaKube.Name, request.Namespace.Name)
 		if err != nil && !k8serrors.IsAlreadyExists(err) {
 			log.Info("failed to create the data-ingest endpoint secret before pod injection")
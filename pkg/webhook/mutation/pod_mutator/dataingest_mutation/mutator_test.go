@@ -207,6 +207,7 @@ func createTestPodMutator(objects []client.Object) *DataIngestPodMutator {
 		apiReader:        fake.NewClient(objects...),
 		metaClient:       fake.NewClient(objects...),
 		webhookNamespace: testNamespaceName,
+		workloadKinds:    DefaultWorkloadKinds(),
 	}
 }
 
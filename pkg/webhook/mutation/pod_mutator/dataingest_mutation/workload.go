@@ -31,14 +31,23 @@ func newUnknownWorkloadInfo() workloadInfo {
 }
 
 func (mutator *DataIngestPodMutator) retrieveWorkload(request *dtwebhook.MutationRequest) (*workloadInfo, error) {
-	workload, err := findRootOwnerOfPod(request.Context, mutator.metaClient, request.Pod, request.Namespace.Name)
+	workload, err := findRootOwnerOfPod(request.Context, mutator.metaClient, mutator.workloadKinds, request.Pod, request.Namespace.Name)
 	if err != nil {
 		return nil, err
 	}
 	return workload, nil
 }
 
-func findRootOwnerOfPod(ctx context.Context, clt client.Client, pod *corev1.Pod, namespace string) (*workloadInfo, error) {
+// FindWorkload returns the kind and name of the workload the pod belongs to, the same way it is used for the data-ingest enrichment
+func FindWorkload(ctx context.Context, clt client.Client, kinds WorkloadKinds, pod *corev1.Pod, namespace string) (kind string, name string, err error) {
+	workload, err := findRootOwnerOfPod(ctx, clt, kinds, pod, namespace)
+	if err != nil {
+		return "", "", err
+	}
+	return workload.kind, workload.name, nil
+}
+
+func findRootOwnerOfPod(ctx context.Context, clt client.Client, kinds WorkloadKinds, pod *corev1.Pod, namespace string) (*workloadInfo, error) {
 	podPartialMetadata := &metav1.PartialObjectMetadata{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: pod.APIVersion,
@@ -49,16 +58,17 @@ func findRootOwnerOfPod(ctx context.Context, clt client.Client, pod *corev1.Pod,
 			// pod.ObjectMeta.Namespace is empty yet
 			Namespace:       namespace,
 			OwnerReferences: pod.ObjectMeta.OwnerReferences,
+			Labels:          pod.ObjectMeta.Labels,
 		},
 	}
-	workloadInfo, err := findRootOwner(ctx, clt, podPartialMetadata)
+	workloadInfo, err := findRootOwner(ctx, clt, kinds, podPartialMetadata)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 	return &workloadInfo, nil
 }
 
-func findRootOwner(ctx context.Context, clt client.Client, partialObjectMetadata *metav1.PartialObjectMetadata) (workloadInfo, error) {
+func findRootOwner(ctx context.Context, clt client.Client, kinds WorkloadKinds, partialObjectMetadata *metav1.PartialObjectMetadata) (workloadInfo, error) {
 	if len(partialObjectMetadata.ObjectMeta.OwnerReferences) == 0 {
 		if partialObjectMetadata.ObjectMeta.Name == "" {
 			// pod is not created directly and does not have an owner reference set
@@ -70,7 +80,8 @@ func findRootOwner(ctx context.Context, clt client.Client, partialObjectMetadata
 	objectMetadata := partialObjectMetadata.ObjectMeta
 	for _, owner := range objectMetadata.OwnerReferences {
 		if owner.Controller != nil && *owner.Controller {
-			if !isWellKnownWorkload(owner) {
+			kind, ok := kinds.find(owner)
+			if !ok {
 				// pod is created by workload of kind that is not well known
 				return newUnknownWorkloadInfo(), nil
 			}
@@ -83,6 +94,15 @@ func findRootOwner(ctx context.Context, clt client.Client, partialObjectMetadata
 			}
 			err := clt.Get(ctx, client.ObjectKey{Name: owner.Name, Namespace: objectMetadata.Namespace}, ownerObjectMetadata)
 			if err != nil {
+				if rootWorkload, ok := kind.rootWorkload(objectMetadata.Labels); ok {
+					log.Info("failed to query the object, falling back to the root workload label",
+						"kind", owner.Kind,
+						"name", owner.Name,
+						"label", kind.RootNameLabel,
+						"error", err.Error(),
+					)
+					return rootWorkload, nil
+				}
 				log.Error(err, "failed to query the object",
 					"apiVersion", owner.APIVersion,
 					"kind", owner.Kind,
@@ -92,29 +112,11 @@ func findRootOwner(ctx context.Context, clt client.Client, partialObjectMetadata
 				return newWorkloadInfo(partialObjectMetadata), err
 			}
 
-			return findRootOwner(ctx, clt, ownerObjectMetadata)
+			if rootWorkload, ok := kind.rootWorkload(ownerObjectMetadata.Labels); ok {
+				return rootWorkload, nil
+			}
+			return findRootOwner(ctx, clt, kinds, ownerObjectMetadata)
 		}
 	}
 	return newWorkloadInfo(partialObjectMetadata), nil
 }
-
-func isWellKnownWorkload(ownerRef metav1.OwnerReference) bool {
-	knownWorkloads := []metav1.TypeMeta{
-		{Kind: "ReplicaSet", APIVersion: "apps/v1"},
-		{Kind: "Deployment", APIVersion: "apps/v1"},
-		{Kind: "ReplicationController", APIVersion: "v1"},
-		{Kind: "StatefulSet", APIVersion: "apps/v1"},
-		{Kind: "DaemonSet", APIVersion: "apps/v1"},
-		{Kind: "Job", APIVersion: "batch/v1"},
-		{Kind: "CronJob", APIVersion: "batch/v1"},
-		{Kind: "DeploymentConfig", APIVersion: "apps.openshift.io/v1"},
-	}
-
-	for _, knownController := range knownWorkloads {
-		if ownerRef.Kind == knownController.Kind &&
-			ownerRef.APIVersion == knownController.APIVersion {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,100 @@
+package dataingest_mutation
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// WorkloadKindsEnv holds the json list of workload kinds the webhook traverses in addition to the default ones
+const WorkloadKindsEnv = "DT_WORKLOAD_KINDS"
+
+// WorkloadKind is a kind of controller which is traversed to find the workload of a pod.
+type WorkloadKind struct {
+	Group string `json:"group"`
+	// Version of the kind, all versions of the group match if it is empty
+	Version string `json:"version,omitempty"`
+	Kind    string `json:"kind"`
+
+	// RootKind and RootNameLabel report the workload as RootKind named after the RootNameLabel of the controller,
+	// instead of traversing its owners, e.g. a Knative Revision is reported as the Service in its serving.knative.dev/service label.
+	// The label of the owned object is used if the controller can't be queried.
+	RootKind      string `json:"rootKind,omitempty"`
+	RootNameLabel string `json:"rootNameLabel,omitempty"`
+}
+
+func (kind WorkloadKind) matches(ownerRef metav1.OwnerReference) bool {
+	groupVersion, err := schema.ParseGroupVersion(ownerRef.APIVersion)
+	if err != nil {
+		return false
+	}
+	return ownerRef.Kind == kind.Kind &&
+		groupVersion.Group == kind.Group &&
+		(kind.Version == "" || groupVersion.Version == kind.Version)
+}
+
+func (kind WorkloadKind) rootWorkload(labels map[string]string) (workloadInfo, bool) {
+	if kind.RootNameLabel == "" || labels[kind.RootNameLabel] == "" {
+		return workloadInfo{}, false
+	}
+	return workloadInfo{
+		name: labels[kind.RootNameLabel],
+		kind: kind.RootKind,
+	}, true
+}
+
+type WorkloadKinds []WorkloadKind
+
+func (kinds WorkloadKinds) find(ownerRef metav1.OwnerReference) (WorkloadKind, bool) {
+	for _, kind := range kinds {
+		if kind.matches(ownerRef) {
+			return kind, true
+		}
+	}
+	return WorkloadKind{}, false
+}
+
+// DefaultWorkloadKinds are the workload kinds of Kubernetes, OpenShift, Argo Rollouts, Knative and OpenKruise,
+// the webhook has the permissions to query them.
+func DefaultWorkloadKinds() WorkloadKinds {
+	return WorkloadKinds{
+		{Kind: "ReplicaSet", Group: "apps", Version: "v1"},
+		{Kind: "Deployment", Group: "apps", Version: "v1"},
+		{Kind: "ReplicationController", Group: "", Version: "v1"},
+		{Kind: "StatefulSet", Group: "apps", Version: "v1"},
+		{Kind: "DaemonSet", Group: "apps", Version: "v1"},
+		{Kind: "Job", Group: "batch", Version: "v1"},
+		{Kind: "CronJob", Group: "batch", Version: "v1"},
+		{Kind: "DeploymentConfig", Group: "apps.openshift.io", Version: "v1"},
+		{Kind: "Rollout", Group: "argoproj.io"},
+		{Kind: "Revision", Group: "serving.knative.dev", RootKind: "Service", RootNameLabel: "serving.knative.dev/service"},
+		{Kind: "CloneSet", Group: "apps.kruise.io"},
+		{Kind: "StatefulSet", Group: "apps.kruise.io"},
+		{Kind: "DaemonSet", Group: "apps.kruise.io"},
+	}
+}
+
+// WorkloadKindsFromEnv adds the workload kinds configured for the webhook to the default ones
+func WorkloadKindsFromEnv() (WorkloadKinds, error) {
+	kinds := DefaultWorkloadKinds()
+
+	customKinds := os.Getenv(WorkloadKindsEnv)
+	if customKinds == "" {
+		return kinds, nil
+	}
+
+	var additionalKinds WorkloadKinds
+	if err := json.Unmarshal([]byte(customKinds), &additionalKinds); err != nil {
+		return nil, errors.WithMessagef(err, "invalid %s", WorkloadKindsEnv)
+	}
+	for _, kind := range additionalKinds {
+		if kind.Kind == "" {
+			return nil, errors.Errorf("invalid %s, kind of workload kind is missing", WorkloadKindsEnv)
+		}
+	}
+	// configured kinds take precedence over the default ones
+	return append(additionalKinds, kinds...), nil
+}
@@ -11,6 +11,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func TestFindRootOwnerOfPod(t *testing.T) {
@@ -63,7 +64,7 @@ func TestFindRootOwnerOfPod(t *testing.T) {
 
 		client := fake.NewClient(&pod, &deployment, &daemonSet, &namespace)
 
-		workloadInfo, err := findRootOwnerOfPod(ctx, client, &pod, namespaceName)
+		workloadInfo, err := findRootOwnerOfPod(ctx, client, DefaultWorkloadKinds(), &pod, namespaceName)
 		require.NoError(t, err)
 		assert.Equal(t, resourceName, workloadInfo.name)
 		assert.Equal(t, "DaemonSet", workloadInfo.kind)
@@ -80,7 +81,7 @@ func TestFindRootOwnerOfPod(t *testing.T) {
 			},
 		}
 		client := fake.NewClient(&pod)
-		workloadInfo, err := findRootOwnerOfPod(ctx, client, &pod, namespaceName)
+		workloadInfo, err := findRootOwnerOfPod(ctx, client, DefaultWorkloadKinds(), &pod, namespaceName)
 		require.NoError(t, err)
 		assert.Equal(t, resourceName, workloadInfo.name)
 		assert.Equal(t, "Pod", workloadInfo.kind)
@@ -104,7 +105,7 @@ func TestFindRootOwnerOfPod(t *testing.T) {
 			},
 		}
 		client := fake.NewClient(&pod)
-		workloadInfo, err := findRootOwnerOfPod(ctx, client, &pod, namespaceName)
+		workloadInfo, err := findRootOwnerOfPod(ctx, client, DefaultWorkloadKinds(), &pod, namespaceName)
 		require.NoError(t, err)
 		assert.Equal(t, "UNKNOWN", workloadInfo.name)
 		assert.Equal(t, "UNKNOWN", workloadInfo.kind)
@@ -117,13 +118,116 @@ func TestFindRootOwnerOfPod(t *testing.T) {
 			},
 		}
 		client := fake.NewClient(&pod)
-		workloadInfo, err := findRootOwnerOfPod(ctx, client, &pod, namespaceName)
+		workloadInfo, err := findRootOwnerOfPod(ctx, client, DefaultWorkloadKinds(), &pod, namespaceName)
 		require.NoError(t, err)
 		assert.Equal(t, "UNKNOWN", workloadInfo.name)
 		assert.Equal(t, "UNKNOWN", workloadInfo.kind)
 	})
 }
 
+func TestFindRootOwnerOfPodWithCustomKinds(t *testing.T) {
+	ctx := context.Background()
+	namespaceName := "test"
+
+	t.Run("should traverse argo rollouts", func(t *testing.T) {
+		pod := podOwnedBy("argoproj.io/v1alpha1", "Rollout", "shop", nil)
+		rollout := newUnstructuredOwner("argoproj.io/v1alpha1", "Rollout", "shop", namespaceName, nil)
+		client := fake.NewClient(rollout)
+
+		workloadInfo, err := findRootOwnerOfPod(ctx, client, DefaultWorkloadKinds(), &pod, namespaceName)
+		require.NoError(t, err)
+		assert.Equal(t, "shop", workloadInfo.name)
+		assert.Equal(t, "Rollout", workloadInfo.kind)
+	})
+	t.Run("should report knative revision as service of its label", func(t *testing.T) {
+		pod := podOwnedBy("serving.knative.dev/v1", "Revision", "shop-00001", nil)
+		revision := newUnstructuredOwner("serving.knative.dev/v1", "Revision", "shop-00001", namespaceName, map[string]string{
+			"serving.knative.dev/service": "shop",
+		})
+		client := fake.NewClient(revision)
+
+		workloadInfo, err := findRootOwnerOfPod(ctx, client, DefaultWorkloadKinds(), &pod, namespaceName)
+		require.NoError(t, err)
+		assert.Equal(t, "shop", workloadInfo.name)
+		assert.Equal(t, "Service", workloadInfo.kind)
+	})
+	t.Run("should fall back to root label of owned object if owner can't be queried", func(t *testing.T) {
+		pod := podOwnedBy("serving.knative.dev/v1", "Revision", "shop-00001", map[string]string{
+			"serving.knative.dev/service": "shop",
+		})
+		client := fake.NewClient()
+
+		workloadInfo, err := findRootOwnerOfPod(ctx, client, DefaultWorkloadKinds(), &pod, namespaceName)
+		require.NoError(t, err)
+		assert.Equal(t, "shop", workloadInfo.name)
+		assert.Equal(t, "Service", workloadInfo.kind)
+	})
+	t.Run("should traverse configured kinds", func(t *testing.T) {
+		pod := podOwnedBy("example.com/v2", "Canary", "shop", nil)
+		canary := newUnstructuredOwner("example.com/v2", "Canary", "shop", namespaceName, nil)
+		client := fake.NewClient(canary)
+		kinds := append(WorkloadKinds{{Group: "example.com", Kind: "Canary"}}, DefaultWorkloadKinds()...)
+
+		workloadInfo, err := findRootOwnerOfPod(ctx, client, kinds, &pod, namespaceName)
+		require.NoError(t, err)
+		assert.Equal(t, "shop", workloadInfo.name)
+		assert.Equal(t, "Canary", workloadInfo.kind)
+
+		workloadInfo, err = findRootOwnerOfPod(ctx, client, DefaultWorkloadKinds(), &pod, namespaceName)
+		require.NoError(t, err)
+		assert.Equal(t, "UNKNOWN", workloadInfo.kind)
+	})
+}
+
+func TestWorkloadKindsFromEnv(t *testing.T) {
+	t.Run("default kinds without env", func(t *testing.T) {
+		kinds, err := WorkloadKindsFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, DefaultWorkloadKinds(), kinds)
+	})
+	t.Run("configured kinds take precedence", func(t *testing.T) {
+		t.Setenv(WorkloadKindsEnv, `[{"group": "example.com", "version": "v2", "kind": "Canary", "resource": "canaries"}]`)
+
+		kinds, err := WorkloadKindsFromEnv()
+		require.NoError(t, err)
+		require.Len(t, kinds, len(DefaultWorkloadKinds())+1)
+		assert.Equal(t, WorkloadKind{Group: "example.com", Version: "v2", Kind: "Canary"}, kinds[0])
+	})
+	t.Run("invalid env", func(t *testing.T) {
+		t.Setenv(WorkloadKindsEnv, `[{"group": "example.com"}]`)
+
+		_, err := WorkloadKindsFromEnv()
+		require.Error(t, err)
+	})
+}
+
+func podOwnedBy(apiVersion, kind, name string, labels map[string]string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: apiVersion,
+					Kind:       kind,
+					Name:       name,
+					Controller: address.Of(true),
+				},
+			},
+			Name:   name + "-pod",
+			Labels: labels,
+		},
+	}
+}
+
+func newUnstructuredOwner(apiVersion, kind, name, namespace string, labels map[string]string) *unstructured.Unstructured {
+	owner := &unstructured.Unstructured{}
+	owner.SetAPIVersion(apiVersion)
+	owner.SetKind(kind)
+	owner.SetName(name)
+	owner.SetNamespace(namespace)
+	owner.SetLabels(labels)
+	return owner
+}
+
 func createTestWorkloadInfo() *workloadInfo {
 	return &workloadInfo{
 		kind: "test",
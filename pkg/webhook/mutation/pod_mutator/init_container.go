@@ -1,6 +1,7 @@
 package pod_mutator
 
 import (
+	"encoding/json"
 	"strings"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
@@ -8,6 +9,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/address"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -31,6 +33,22 @@ func createInstallInitContainerBase(webhookImage, clusterID string, pod *corev1.
 	}
 }
 
+// addEnrichmentAttributesEnv passes the labels and annotations selected by the metadataEnrichment rules as json to the init container,
+// the labels of the namespace aren't available in the pod otherwise
+func addEnrichmentAttributesEnv(initContainer *corev1.Container, request *dtwebhook.BaseRequest) error {
+	attributes := request.DynaKube.MetadataEnrichmentAttributes(request.Namespace, *request.Pod)
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	attributesJson, err := json.Marshal(attributes)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	initContainer.Env = append(initContainer.Env, corev1.EnvVar{Name: consts.EnrichmentAttributesEnv, Value: string(attributesJson)})
+	return nil
+}
+
 func initContainerResources(dynakube dynatracev1beta1.DynaKube) corev1.ResourceRequirements {
 	customInitResources := dynakube.InitResources()
 	if customInitResources != nil {
@@ -130,6 +148,36 @@ func addInitContainerToPod(pod *corev1.Pod, initContainer *corev1.Container) {
 	pod.Spec.InitContainers = append(pod.Spec.InitContainers, *initContainer)
 }
 
+// createRefreshSidecar creates a restartable init container (native sidecar) from the install container,
+// it runs after the install container finished and keeps the configuration files up to date for the lifetime of the pod
+func createRefreshSidecar(installContainer *corev1.Container) *corev1.Container {
+	restartPolicy := corev1.ContainerRestartPolicyAlways
+	refreshSidecar := installContainer.DeepCopy()
+	refreshSidecar.Name = dtwebhook.RefreshContainerName
+	refreshSidecar.Args = []string{"init", "--" + consts.RefreshFlag}
+	refreshSidecar.RestartPolicy = &restartPolicy
+	return refreshSidecar
+}
+
+// updateRefreshSidecar syncs the envs and volume mounts of the refresh sidecar with the install container, after it was updated by a reinvocation
+func updateRefreshSidecar(pod *corev1.Pod) {
+	var installContainer, refreshSidecar *corev1.Container
+	for i := range pod.Spec.InitContainers {
+		switch pod.Spec.InitContainers[i].Name {
+		case dtwebhook.InstallContainerName:
+			installContainer = &pod.Spec.InitContainers[i]
+		case dtwebhook.RefreshContainerName:
+			refreshSidecar = &pod.Spec.InitContainers[i]
+		}
+	}
+	if installContainer == nil || refreshSidecar == nil {
+		return
+	}
+	updatedSidecar := installContainer.DeepCopy()
+	refreshSidecar.Env = updatedSidecar.Env
+	refreshSidecar.VolumeMounts = updatedSidecar.VolumeMounts
+}
+
 func addSeccompProfile(ctx *corev1.SecurityContext, dk dynatracev1beta1.DynaKube) {
 	if dk.FeatureInitContainerSeccomp() {
 		ctx.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
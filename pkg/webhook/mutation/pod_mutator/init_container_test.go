@@ -4,12 +4,14 @@ import (
 	"testing"
 
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/address"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestCreateInstallInitContainerBase(t *testing.T) {
@@ -210,6 +212,37 @@ func TestCreateInstallInitContainerBase(t *testing.T) {
 	})
 }
 
+func TestAddEnrichmentAttributesEnv(t *testing.T) {
+	t.Run("no env without metadataEnrichment rules", func(t *testing.T) {
+		request := &dtwebhook.BaseRequest{Pod: getTestPod(), DynaKube: *getTestDynakube()}
+		initContainer := &corev1.Container{}
+
+		require.NoError(t, addEnrichmentAttributesEnv(initContainer, request))
+
+		assert.Nil(t, kubeobjects.FindEnvVar(initContainer.Env, consts.EnrichmentAttributesEnv))
+	})
+	t.Run("selected labels and annotations as json", func(t *testing.T) {
+		dynakube := getTestDynakube()
+		dynakube.Spec.MetadataEnrichment = &dynatracev1beta1.MetadataEnrichmentSpec{
+			Rules: []dynatracev1beta1.EnrichmentRule{
+				{Type: dynatracev1beta1.EnrichmentNamespaceLabelRule, Keys: []string{"cost-center"}},
+				{Type: dynatracev1beta1.EnrichmentPodLabelRule, Keys: []string{"team"}},
+			},
+		}
+		pod := getTestPod()
+		pod.Labels = map[string]string{"team": "checkout", "app": "shop"}
+		namespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cost-center": "1234"}}}
+		request := &dtwebhook.BaseRequest{Pod: pod, DynaKube: *dynakube, Namespace: namespace}
+		initContainer := &corev1.Container{}
+
+		require.NoError(t, addEnrichmentAttributesEnv(initContainer, request))
+
+		env := kubeobjects.FindEnvVar(initContainer.Env, consts.EnrichmentAttributesEnv)
+		require.NotNil(t, env)
+		assert.JSONEq(t, `{"k8s.namespace.label.cost-center": "1234", "k8s.pod.label.team": "checkout"}`, env.Value)
+	})
+}
+
 func TestInitContainerResources(t *testing.T) {
 	t.Run("should return default if nothing is set", func(t *testing.T) {
 		dynakube := getTestDynakubeNoInitLimits()
@@ -237,3 +270,29 @@ func TestInitContainerResources(t *testing.T) {
 		require.Empty(t, initResources)
 	})
 }
+
+func TestUpdateRefreshSidecar(t *testing.T) {
+	t.Run("sync envs and volume mounts with the install container", func(t *testing.T) {
+		installContainer := corev1.Container{
+			Name:         dtwebhook.InstallContainerName,
+			Env:          []corev1.EnvVar{{Name: "CONTAINER_1_NAME", Value: "app"}},
+			VolumeMounts: []corev1.VolumeMount{{Name: "config", MountPath: "/mnt/config"}},
+		}
+		refreshSidecar := createRefreshSidecar(&installContainer)
+		installContainer.Env = append(installContainer.Env, corev1.EnvVar{Name: "CONTAINER_2_NAME", Value: "sidecar"})
+		pod := &corev1.Pod{Spec: corev1.PodSpec{InitContainers: []corev1.Container{installContainer, *refreshSidecar}}}
+
+		updateRefreshSidecar(pod)
+
+		assert.Equal(t, installContainer.Env, pod.Spec.InitContainers[1].Env)
+		assert.Equal(t, installContainer.VolumeMounts, pod.Spec.InitContainers[1].VolumeMounts)
+		assert.Equal(t, dtwebhook.RefreshContainerName, pod.Spec.InitContainers[1].Name)
+	})
+	t.Run("pod without refresh sidecar is unchanged", func(t *testing.T) {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: dtwebhook.InstallContainerName}}}}
+
+		updateRefreshSidecar(pod)
+
+		assert.Len(t, pod.Spec.InitContainers, 1)
+	})
+}
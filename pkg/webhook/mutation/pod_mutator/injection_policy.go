@@ -0,0 +1,102 @@
+package pod_mutator
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha1/injectionpolicy"
+	dtotel "github.com/Dynatrace/dynatrace-operator/pkg/util/otel"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// applyInjectionPolicy applies the first InjectionPolicy of the pod's namespace that matches the pod.
+// The policy only fills in the injection annotations that are not set on the pod explicitly.
+func (webhook *podMutatorWebhook) applyInjectionPolicy(ctx context.Context, mutationRequest *dtwebhook.MutationRequest) error {
+	ctx, span := dtotel.StartSpan(ctx, webhook.spanTracer, "applyInjectionPolicy")
+	defer span.End()
+
+	policy, err := webhook.findInjectionPolicy(ctx, mutationRequest.Namespace.Name, *mutationRequest.Pod)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		recordDecision(ctx, previewStepInjectionPolicy, "no InjectionPolicy of the namespace matches the pod")
+		return nil
+	}
+	log.Info("applying injection policy", "podName", mutationRequest.PodName(), "policy", policy.Name)
+	recordDecision(ctx, previewStepInjectionPolicy, "InjectionPolicy %s matches the pod", policy.Name)
+	setInjectionPolicyAnnotations(mutationRequest.Pod, *policy)
+	return nil
+}
+
+func (webhook *podMutatorWebhook) findInjectionPolicy(ctx context.Context, namespace string, pod corev1.Pod) (*injectionpolicy.InjectionPolicy, error) {
+	if webhook.policyReader == nil {
+		// the CRD is not installed, so there is no policy to apply
+		return nil, nil
+	}
+
+	var policies injectionpolicy.InjectionPolicyList
+	if err := webhook.policyReader.List(ctx, &policies, client.InNamespace(namespace)); meta.IsNoMatchError(err) {
+		// the CRD is not installed, so there is no policy to apply
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.WithMessage(err, "failed to list injection policies")
+	}
+
+	injectionpolicy.SortByPriority(policies.Items)
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		matches, err := policy.MatchesPod(pod)
+		if err != nil {
+			log.Info("skipping invalid injection policy", "policy", policy.Name, "error", err.Error())
+			continue
+		}
+		if matches {
+			return policy, nil
+		}
+	}
+	return nil, nil
+}
+
+func setInjectionPolicyAnnotations(pod *corev1.Pod, policy injectionpolicy.InjectionPolicy) {
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	setAnnotationIfMissing(pod, dtwebhook.AnnotationTechnologies, policy.Spec.Technologies)
+	setAnnotationIfMissing(pod, dtwebhook.AnnotationFlavor, policy.Spec.Flavor)
+	setAnnotationIfMissing(pod, dtwebhook.AnnotationInstallPath, policy.Spec.InstallPath)
+	setAnnotationIfMissing(pod, dtwebhook.AnnotationFailurePolicy, policy.Spec.FailurePolicy)
+
+	for _, container := range pod.Spec.Containers {
+		if !policy.MatchesContainer(container) {
+			setAnnotationIfMissing(pod, dtwebhook.ContainerInjectionAnnotation(container.Name), "false")
+		}
+	}
+	pod.Annotations[dtwebhook.AnnotationInjectionPolicy] = policy.Name
+}
+
+func setAnnotationIfMissing(pod *corev1.Pod, key, value string) {
+	if value == "" {
+		return
+	}
+	if _, ok := pod.Annotations[key]; !ok {
+		pod.Annotations[key] = value
+	}
+}
+
+// newInjectionPolicyReader registers the informer of the InjectionPolicies in the cache of the manager, so they don't have
+// to be listed from the API server on every admission. Returns nil if the CRD is not installed.
+func newInjectionPolicyReader(mgr manager.Manager) (client.Reader, error) {
+	_, err := mgr.GetCache().GetInformer(context.Background(), &injectionpolicy.InjectionPolicy{})
+	if meta.IsNoMatchError(err) {
+		log.Info("InjectionPolicy CRD is not installed, injection policies are not applied")
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.WithMessage(err, "failed to create the injection policy informer")
+	}
+	return mgr.GetClient(), nil
+}
@@ -0,0 +1,103 @@
+package pod_mutator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha1/injectionpolicy"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestApplyInjectionPolicy(t *testing.T) {
+	t.Run("no policy, pod unchanged", func(t *testing.T) {
+		podWebhook := createTestWebhook(nil, nil)
+		mutationRequest := createTestMutationRequest(getTestDynakube())
+
+		err := podWebhook.applyInjectionPolicy(context.Background(), mutationRequest)
+		require.NoError(t, err)
+		assert.Empty(t, mutationRequest.Pod.Annotations)
+	})
+	t.Run("CRD not installed, pod unchanged", func(t *testing.T) {
+		podWebhook := createTestWebhook(nil, []client.Object{createTestInjectionPolicy("policy", 0)})
+		podWebhook.policyReader = nil
+		mutationRequest := createTestMutationRequest(getTestDynakube())
+
+		err := podWebhook.applyInjectionPolicy(context.Background(), mutationRequest)
+		require.NoError(t, err)
+		assert.Empty(t, mutationRequest.Pod.Annotations)
+	})
+	t.Run("matching policy with highest priority is applied", func(t *testing.T) {
+		lowPriority := createTestInjectionPolicy("low", 0)
+		lowPriority.Spec.Technologies = "php"
+		highPriority := createTestInjectionPolicy("high", 10)
+		highPriority.Spec.Technologies = "java"
+		highPriority.Spec.Flavor = "musl"
+		highPriority.Spec.InstallPath = "/opt/custom"
+		highPriority.Spec.FailurePolicy = "fail"
+		otherNamespace := createTestInjectionPolicy("other-namespace", 20)
+		otherNamespace.Namespace = "other"
+		podWebhook := createTestWebhook(nil, []client.Object{lowPriority, highPriority, otherNamespace})
+		mutationRequest := createTestMutationRequest(getTestDynakube())
+
+		err := podWebhook.applyInjectionPolicy(context.Background(), mutationRequest)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			dtwebhook.AnnotationInjectionPolicy: "high",
+			dtwebhook.AnnotationTechnologies:    "java",
+			dtwebhook.AnnotationFlavor:          "musl",
+			dtwebhook.AnnotationInstallPath:     "/opt/custom",
+			dtwebhook.AnnotationFailurePolicy:   "fail",
+		}, mutationRequest.Pod.Annotations)
+	})
+	t.Run("policy not matching the pod labels is skipped", func(t *testing.T) {
+		policy := createTestInjectionPolicy("selective", 10)
+		policy.Spec.PodSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}}
+		podWebhook := createTestWebhook(nil, []client.Object{policy, createTestInjectionPolicy("fallback", 0)})
+		mutationRequest := createTestMutationRequest(getTestDynakube())
+
+		err := podWebhook.applyInjectionPolicy(context.Background(), mutationRequest)
+		require.NoError(t, err)
+		assert.Equal(t, "fallback", mutationRequest.Pod.Annotations[dtwebhook.AnnotationInjectionPolicy])
+	})
+	t.Run("pod annotations take precedence over policy", func(t *testing.T) {
+		policy := createTestInjectionPolicy("policy", 0)
+		policy.Spec.Technologies = "java"
+		podWebhook := createTestWebhook(nil, []client.Object{policy})
+		mutationRequest := createTestMutationRequest(getTestDynakube())
+		mutationRequest.Pod.Annotations = map[string]string{dtwebhook.AnnotationTechnologies: "nodejs"}
+
+		err := podWebhook.applyInjectionPolicy(context.Background(), mutationRequest)
+		require.NoError(t, err)
+		assert.Equal(t, "nodejs", mutationRequest.Pod.Annotations[dtwebhook.AnnotationTechnologies])
+		assert.Equal(t, "policy", mutationRequest.Pod.Annotations[dtwebhook.AnnotationInjectionPolicy])
+	})
+	t.Run("containers not selected by policy are excluded", func(t *testing.T) {
+		policy := createTestInjectionPolicy("policy", 0)
+		policy.Spec.Containers = &injectionpolicy.ContainerSelector{ImagePatterns: []string{"alpine"}}
+		podWebhook := createTestWebhook(nil, []client.Object{policy})
+		mutationRequest := createTestMutationRequest(getTestDynakube())
+		mutationRequest.Pod.Spec.Containers = append(mutationRequest.Pod.Spec.Containers, corev1.Container{Name: "sidecar", Image: "envoy"})
+
+		err := podWebhook.applyInjectionPolicy(context.Background(), mutationRequest)
+		require.NoError(t, err)
+		assert.Equal(t, "false", mutationRequest.Pod.Annotations[dtwebhook.ContainerInjectionAnnotation("sidecar")])
+		assert.NotContains(t, mutationRequest.Pod.Annotations, dtwebhook.ContainerInjectionAnnotation("container"))
+	})
+}
+
+func createTestInjectionPolicy(name string, priority int32) *injectionpolicy.InjectionPolicy {
+	return &injectionpolicy.InjectionPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespaceName,
+		},
+		Spec: injectionpolicy.InjectionPolicySpec{
+			Priority: priority,
+		},
+	}
+}
@@ -0,0 +1,99 @@
+package pod_mutator
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	dtotel "github.com/Dynatrace/dynatrace-operator/pkg/util/otel"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// namespaceOverrideAnnotations are the pod annotations which can be set on the namespace as well, to be used for all of its pods
+var namespaceOverrideAnnotations = []string{
+	dtwebhook.AnnotationTechnologies,
+	dtwebhook.AnnotationFlavor,
+	dtwebhook.AnnotationInstallPath,
+	dtwebhook.AnnotationFailurePolicy,
+}
+
+// applyNamespaceOverrides lets the owners of a namespace change the injection settings of the DynaKube for their pods.
+// Annotations set on the pod explicitly, or by an InjectionPolicy, take precedence over the ones of the namespace.
+func (webhook *podMutatorWebhook) applyNamespaceOverrides(ctx context.Context, mutationRequest *dtwebhook.MutationRequest) {
+	ctx, span := dtotel.StartSpan(ctx, webhook.spanTracer, "applyNamespaceOverrides")
+	defer span.End()
+
+	overrides := mutationRequest.Namespace.Annotations
+	if len(overrides) == 0 {
+		return
+	}
+
+	pod := mutationRequest.Pod
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	for _, key := range namespaceOverrideAnnotations {
+		if overrides[key] == "" {
+			continue
+		}
+		if _, ok := pod.Annotations[key]; ok {
+			recordDecision(ctx, previewStepNamespaceOverrides, "%s of the namespace is overruled by the pod", key)
+			continue
+		}
+		recordDecision(ctx, previewStepNamespaceOverrides, "%s is set to '%s' by the namespace", key, overrides[key])
+		pod.Annotations[key] = overrides[key]
+	}
+
+	// the dynakube of the request is only used for this pod, so the remaining overrides are applied to a copy of it
+	dynakube := mutationRequest.DynaKube.DeepCopy()
+	overrideInitResources(ctx, dynakube, overrides[dtwebhook.AnnotationInitResources])
+	overrideReadOnlyCSIVolume(ctx, dynakube, overrides[dtwebhook.AnnotationReadOnlyCSIVolume])
+	mutationRequest.DynaKube = *dynakube
+}
+
+func overrideInitResources(ctx context.Context, dynakube *dynatracev1beta1.DynaKube, rawInitResources string) {
+	if rawInitResources == "" {
+		return
+	}
+
+	var initResources corev1.ResourceRequirements
+	if err := json.Unmarshal([]byte(rawInitResources), &initResources); err != nil {
+		log.Info("ignoring invalid init resources of namespace", "annotation", dtwebhook.AnnotationInitResources, "error", err.Error())
+		recordDecision(ctx, previewStepNamespaceOverrides, "invalid %s of the namespace is ignored: %s", dtwebhook.AnnotationInitResources, err.Error())
+		return
+	}
+	recordDecision(ctx, previewStepNamespaceOverrides, "init container resources are replaced by the namespace")
+
+	switch {
+	case dynakube.ApplicationMonitoringMode():
+		dynakube.Spec.OneAgent.ApplicationMonitoring.InitResources = &initResources
+	case dynakube.CloudNativeFullstackMode():
+		dynakube.Spec.OneAgent.CloudNativeFullStack.InitResources = &initResources
+	}
+}
+
+func overrideReadOnlyCSIVolume(ctx context.Context, dynakube *dynatracev1beta1.DynaKube, rawReadOnly string) {
+	if rawReadOnly == "" {
+		return
+	}
+
+	readOnly, err := strconv.ParseBool(rawReadOnly)
+	if err != nil {
+		log.Info("ignoring invalid read-only CSI volume setting of namespace", "annotation", dtwebhook.AnnotationReadOnlyCSIVolume, "error", err.Error())
+		recordDecision(ctx, previewStepNamespaceOverrides, "invalid %s of the namespace is ignored: %s", dtwebhook.AnnotationReadOnlyCSIVolume, err.Error())
+		return
+	}
+	if !dynakube.NeedsCSIDriver() {
+		log.Info("ignoring read-only CSI volume setting of namespace, the dynakube doesn't use the CSI driver")
+		recordDecision(ctx, previewStepNamespaceOverrides, "%s of the namespace is ignored, the DynaKube doesn't use the CSI driver", dtwebhook.AnnotationReadOnlyCSIVolume)
+		return
+	}
+	recordDecision(ctx, previewStepNamespaceOverrides, "read-only CSI volume is set to %t by the namespace", readOnly)
+
+	if dynakube.Annotations == nil {
+		dynakube.Annotations = make(map[string]string)
+	}
+	dynakube.Annotations[dynatracev1beta1.AnnotationFeatureReadOnlyCsiVolume] = strconv.FormatBool(readOnly)
+}
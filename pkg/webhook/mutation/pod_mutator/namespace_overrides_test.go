@@ -0,0 +1,94 @@
+package pod_mutator
+
+import (
+	"context"
+	"testing"
+
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestApplyNamespaceOverrides(t *testing.T) {
+	t.Run("no overrides, nothing changes", func(t *testing.T) {
+		podWebhook := createTestWebhook(nil, nil)
+		mutationRequest := createTestMutationRequest(getTestDynakube())
+
+		podWebhook.applyNamespaceOverrides(context.Background(), mutationRequest)
+
+		assert.Empty(t, mutationRequest.Pod.Annotations)
+		assert.Equal(t, *getTestDynakube(), mutationRequest.DynaKube)
+	})
+	t.Run("namespace annotations are set on the pod", func(t *testing.T) {
+		podWebhook := createTestWebhook(nil, nil)
+		mutationRequest := createTestMutationRequest(getTestDynakube())
+		mutationRequest.Namespace.Annotations = map[string]string{
+			dtwebhook.AnnotationTechnologies:  "java",
+			dtwebhook.AnnotationFlavor:        "musl",
+			dtwebhook.AnnotationFailurePolicy: "fail",
+			"unrelated":                       "value",
+		}
+
+		podWebhook.applyNamespaceOverrides(context.Background(), mutationRequest)
+
+		assert.Equal(t, map[string]string{
+			dtwebhook.AnnotationTechnologies:  "java",
+			dtwebhook.AnnotationFlavor:        "musl",
+			dtwebhook.AnnotationFailurePolicy: "fail",
+		}, mutationRequest.Pod.Annotations)
+	})
+	t.Run("pod annotations take precedence over namespace", func(t *testing.T) {
+		podWebhook := createTestWebhook(nil, nil)
+		mutationRequest := createTestMutationRequest(getTestDynakube())
+		mutationRequest.Pod.Annotations = map[string]string{dtwebhook.AnnotationTechnologies: "php"}
+		mutationRequest.Namespace.Annotations = map[string]string{dtwebhook.AnnotationTechnologies: "java"}
+
+		podWebhook.applyNamespaceOverrides(context.Background(), mutationRequest)
+
+		assert.Equal(t, "php", mutationRequest.Pod.Annotations[dtwebhook.AnnotationTechnologies])
+	})
+	t.Run("init resources of the dynakube are replaced", func(t *testing.T) {
+		podWebhook := createTestWebhook(nil, nil)
+		dynakube := getTestDynakube()
+		mutationRequest := createTestMutationRequest(dynakube)
+		mutationRequest.Namespace.Annotations = map[string]string{
+			dtwebhook.AnnotationInitResources: `{"limits":{"cpu":"200m","memory":"200Mi"}}`,
+		}
+
+		podWebhook.applyNamespaceOverrides(context.Background(), mutationRequest)
+
+		initResources := mutationRequest.DynaKube.InitResources()
+		require.NotNil(t, initResources)
+		assert.Equal(t, resource.MustParse("200m"), initResources.Limits[corev1.ResourceCPU])
+		assert.Equal(t, testResourceRequirements, *dynakube.InitResources(), "the dynakube itself is not changed")
+	})
+	t.Run("invalid init resources are ignored", func(t *testing.T) {
+		podWebhook := createTestWebhook(nil, nil)
+		mutationRequest := createTestMutationRequest(getTestDynakube())
+		mutationRequest.Namespace.Annotations = map[string]string{dtwebhook.AnnotationInitResources: "lots"}
+
+		podWebhook.applyNamespaceOverrides(context.Background(), mutationRequest)
+
+		assert.Equal(t, testResourceRequirements, *mutationRequest.DynaKube.InitResources())
+	})
+	t.Run("read-only CSI volume is overridden", func(t *testing.T) {
+		podWebhook := createTestWebhook(nil, nil)
+		mutationRequest := createTestMutationRequest(getTestDynakube())
+		mutationRequest.Namespace.Annotations = map[string]string{dtwebhook.AnnotationReadOnlyCSIVolume: "true"}
+
+		podWebhook.applyNamespaceOverrides(context.Background(), mutationRequest)
+
+		assert.True(t, mutationRequest.DynaKube.FeatureReadOnlyCsiVolume())
+	})
+	t.Run("read-only CSI volume is ignored without CSI driver", func(t *testing.T) {
+		podWebhook := createTestWebhook(nil, nil)
+		mutationRequest := createTestMutationRequest(getTestDynakubeDefaultAppMon())
+		mutationRequest.Namespace.Annotations = map[string]string{dtwebhook.AnnotationReadOnlyCSIVolume: "true"}
+
+		podWebhook.applyNamespaceOverrides(context.Background(), mutationRequest)
+
+		assert.False(t, mutationRequest.DynaKube.FeatureReadOnlyCsiVolume())
+	})
+}
@@ -17,11 +17,14 @@ type installerInfo struct {
 	version      string
 }
 
-func setInjectedAnnotation(pod *corev1.Pod) {
+func setInjectedAnnotation(pod *corev1.Pod, version string) {
 	if pod.Annotations == nil {
 		pod.Annotations = make(map[string]string)
 	}
 	pod.Annotations[dtwebhook.AnnotationOneAgentInjected] = "true"
+	if version != "" {
+		pod.Annotations[dtwebhook.AnnotationOneAgentVersion] = version
+	}
 }
 
 func setNotInjectedAnnotations(pod *corev1.Pod, reason string) {
@@ -1,6 +1,13 @@
 package oneagent_mutation
 
-import "reflect"
+import (
+	"reflect"
+	"testing"
+
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
 
 const (
 	testFlavor       = "testFlavor"
@@ -23,3 +30,23 @@ func getTestInstallerInfo() installerInfo {
 func getInstallerInfoFieldCount() int {
 	return reflect.TypeOf(installerInfo{}).NumField()
 }
+
+func TestSetInjectedAnnotation(t *testing.T) {
+	t.Run("version of the code modules is added", func(t *testing.T) {
+		pod := &corev1.Pod{}
+
+		setInjectedAnnotation(pod, testVersion)
+
+		assert.Equal(t, map[string]string{
+			dtwebhook.AnnotationOneAgentInjected: "true",
+			dtwebhook.AnnotationOneAgentVersion:  testVersion,
+		}, pod.Annotations)
+	})
+	t.Run("unknown version is not added", func(t *testing.T) {
+		pod := &corev1.Pod{}
+
+		setInjectedAnnotation(pod, "")
+
+		assert.NotContains(t, pod.Annotations, dtwebhook.AnnotationOneAgentVersion)
+	})
+}
@@ -20,9 +20,15 @@ func (mutator *OneAgentPodMutator) setContainerCount(initContainer *corev1.Conta
 }
 
 func (mutator *OneAgentPodMutator) mutateUserContainers(request *dtwebhook.MutationRequest) {
+	containerIndex := 0
 	for i := range request.Pod.Spec.Containers {
 		container := &request.Pod.Spec.Containers[i]
-		addContainerInfoInitEnv(request.InstallContainer, i+1, container.Name, container.Image)
+		if isContainerExcluded(request.Pod, container) {
+			log.Info("skipping excluded container", "name", container.Name)
+			continue
+		}
+		containerIndex++
+		addContainerInfoInitEnv(request.InstallContainer, containerIndex, container.Name, container.Image)
 		mutator.addOneAgentToContainer(request.ToReinvocationRequest(), container)
 	}
 }
@@ -34,16 +40,20 @@ func (mutator *OneAgentPodMutator) reinvokeUserContainers(request *dtwebhook.Rei
 	pod := request.Pod
 	oneAgentInstallContainer := findOneAgentInstallContainer(pod.Spec.InitContainers)
 	newContainers := []*corev1.Container{}
+	oldContainersLen := 0
 
 	for i := range pod.Spec.Containers {
 		currentContainer := &pod.Spec.Containers[i]
 		if containerIsInjected(currentContainer) {
+			oldContainersLen++
+			continue
+		}
+		if isContainerExcluded(pod, currentContainer) {
 			continue
 		}
 		newContainers = append(newContainers, currentContainer)
 	}
 
-	oldContainersLen := len(pod.Spec.Containers) - len(newContainers)
 	for i := range newContainers {
 		currentContainer := newContainers[i]
 		addContainerInfoInitEnv(oneAgentInstallContainer, oldContainersLen+i+1, currentContainer.Name, currentContainer.Image)
@@ -54,10 +64,25 @@ func (mutator *OneAgentPodMutator) reinvokeUserContainers(request *dtwebhook.Rei
 		return false
 	}
 
-	mutator.setContainerCount(oneAgentInstallContainer, len(request.Pod.Spec.Containers))
+	mutator.setContainerCount(oneAgentInstallContainer, oldContainersLen+len(newContainers))
 	return true
 }
 
+// isContainerExcluded returns true if the OneAgent injection is disabled for the container, either explicitly or by an InjectionPolicy
+func isContainerExcluded(pod *corev1.Pod, container *corev1.Container) bool {
+	return !kubeobjects.GetFieldBool(pod.Annotations, dtwebhook.ContainerInjectionAnnotation(container.Name), true)
+}
+
+func countInjectableContainers(pod *corev1.Pod) int {
+	count := 0
+	for i := range pod.Spec.Containers {
+		if !isContainerExcluded(pod, &pod.Spec.Containers[i]) {
+			count++
+		}
+	}
+	return count
+}
+
 func (mutator *OneAgentPodMutator) addOneAgentToContainer(request *dtwebhook.ReinvocationRequest, container *corev1.Container) {
 	log.Info("adding OneAgent to container", "name", container.Name)
 	installPath := kubeobjects.GetField(request.Pod.Annotations, dtwebhook.AnnotationInstallPath, dtwebhook.DefaultInstallPath)
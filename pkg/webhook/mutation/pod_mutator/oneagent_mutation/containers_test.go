@@ -160,6 +160,41 @@ func TestReinvokeUserContainers(t *testing.T) {
 	}
 }
 
+func TestExcludedContainers(t *testing.T) {
+	t.Run("excluded container is not injected", func(t *testing.T) {
+		mutator := createTestPodMutator([]client.Object{getTestInitSecret()})
+		request := createTestMutationRequest(getTestDynakube(), nil, getTestNamespace(nil))
+		excludedContainer := request.Pod.Spec.Containers[0]
+		request.Pod.Annotations = map[string]string{dtwebhook.ContainerInjectionAnnotation(excludedContainer.Name): "false"}
+
+		mutator.mutateUserContainers(request)
+
+		require.Len(t, request.InstallContainer.Env, 2)
+		assert.Equal(t, request.Pod.Spec.Containers[1].Name, kubeobjects.FindEnvVar(request.InstallContainer.Env, getContainerNameEnv(1)).Value)
+		assert.Equal(t, excludedContainer, request.Pod.Spec.Containers[0])
+		assert.Equal(t, 1, countInjectableContainers(request.Pod))
+	})
+	t.Run("excluded container is not injected on reinvocation", func(t *testing.T) {
+		mutator := createTestPodMutator([]client.Object{getTestInitSecret()})
+		request := createTestMutationRequest(getTestDynakube(), nil, getTestNamespace(nil)).ToReinvocationRequest()
+		request.Pod.Spec.InitContainers = append(request.Pod.Spec.InitContainers, corev1.Container{
+			Name: dtwebhook.InstallContainerName,
+		})
+		installContainer := &request.Pod.Spec.InitContainers[1]
+		require.True(t, mutator.reinvokeUserContainers(request))
+
+		request.Pod.Spec.Containers = append(request.Pod.Spec.Containers, corev1.Container{
+			Name:  "excluded",
+			Image: "test",
+		})
+		request.Pod.Annotations = map[string]string{dtwebhook.ContainerInjectionAnnotation("excluded"): "false"}
+
+		assert.False(t, mutator.reinvokeUserContainers(request))
+		assert.Nil(t, kubeobjects.FindEnvVar(installContainer.Env, getContainerNameEnv(3)))
+		assert.Empty(t, request.Pod.Spec.Containers[2].Env)
+	})
+}
+
 func assertContainersNamesAndImages(t *testing.T, request *dtwebhook.ReinvocationRequest, installContainer *corev1.Container, containersNumber int) {
 	for containerIdx := 0; containerIdx < containersNumber; containerIdx++ {
 		internalContainerIndex := 1 + containerIdx // starting from 1
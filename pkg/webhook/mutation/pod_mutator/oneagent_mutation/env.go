@@ -77,6 +77,19 @@ func addInstallerInitEnvs(initContainer *corev1.Container, installer installerIn
 		corev1.EnvVar{Name: consts.AgentReadonlyCSI, Value: strconv.FormatBool(dynakube.FeatureReadOnlyCsiVolume())},
 		corev1.EnvVar{Name: consts.AgentInjectedEnv, Value: "true"},
 	)
+	addInstallerMirrorEnvs(initContainer, installer, dynakube)
+}
+
+// addInstallerMirrorEnvs lets the init container fall back to the mirror, if the installer can't be downloaded from the tenant
+func addInstallerMirrorEnvs(initContainer *corev1.Container, installer installerInfo, dynakube dynatracev1beta1.DynaKube) {
+	mirroredInstaller := dynakube.MirroredInstaller()
+	if mirroredInstaller == "" || installer.installerURL != "" {
+		return
+	}
+	initContainer.Env = append(initContainer.Env,
+		corev1.EnvVar{Name: consts.AgentInstallerMirrorEnv, Value: mirroredInstaller},
+		corev1.EnvVar{Name: consts.AgentInstallerMirrorInsecureEnv, Value: strconv.FormatBool(dynakube.Spec.Mirror.Insecure)},
+	)
 }
 
 func addContainerInfoInitEnv(initContainer *corev1.Container, containerIndex int, name string, image string) {
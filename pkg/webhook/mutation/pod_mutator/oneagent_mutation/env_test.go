@@ -112,6 +112,38 @@ func TestAddInstallerInitEnvs(t *testing.T) {
 		require.NotNil(t, env)
 		env.Value = "true"
 	})
+	t.Run("Add installer mirror env", func(t *testing.T) {
+		container := &corev1.Container{}
+		installerInfo := getTestInstallerInfo()
+		installerInfo.installerURL = ""
+		dynakube := getTestCSIDynakube()
+		dynakube.Spec.Mirror = &dynatracev1beta1.MirrorSpec{Registry: "registry.mirror:5000", Insecure: true}
+		dynakube.Status.CodeModules.Version = "1.2.3"
+		dynakube.Status.Mirror.Installer = dynatracev1beta1.MirroredArtifact{
+			Source: dynakube.CodeModulesVersion(),
+			Image:  "registry.mirror:5000/oneagent-installer:" + dynakube.CodeModulesVersion(),
+		}
+
+		addInstallerInitEnvs(container, installerInfo, *dynakube)
+		require.Len(t, container.Env, expectedBaseInitContainerEnvCount+2)
+		assert.Equal(t, dynakube.Status.Mirror.Installer.Image, kubeobjects.FindEnvVar(container.Env, consts.AgentInstallerMirrorEnv).Value)
+		assert.Equal(t, "true", kubeobjects.FindEnvVar(container.Env, consts.AgentInstallerMirrorInsecureEnv).Value)
+	})
+	t.Run("Don't add installer mirror env for outdated mirror", func(t *testing.T) {
+		container := &corev1.Container{}
+		installerInfo := getTestInstallerInfo()
+		installerInfo.installerURL = ""
+		dynakube := getTestCSIDynakube()
+		dynakube.Spec.Mirror = &dynatracev1beta1.MirrorSpec{Registry: "registry.mirror:5000"}
+		dynakube.Status.CodeModules.Version = "1.2.3"
+		dynakube.Status.Mirror.Installer = dynatracev1beta1.MirroredArtifact{
+			Source: "outdated",
+			Image:  "registry.mirror:5000/oneagent-installer:outdated",
+		}
+
+		addInstallerInitEnvs(container, installerInfo, *dynakube)
+		require.Len(t, container.Env, expectedBaseInitContainerEnvCount)
+	})
 }
 
 func TestAddContainerInfoInitEnv(t *testing.T) {
@@ -53,10 +53,10 @@ func (mutator *OneAgentPodMutator) Mutate(request *dtwebhook.MutationRequest) er
 	installerInfo := getInstallerInfo(request.Pod, request.DynaKube)
 	mutator.addVolumes(request.Pod, request.DynaKube)
 	mutator.configureInitContainer(request, installerInfo)
-	mutator.setContainerCount(request.InstallContainer, len(request.Pod.Spec.Containers))
+	mutator.setContainerCount(request.InstallContainer, countInjectableContainers(request.Pod))
 	mutator.mutateUserContainers(request)
 	addInjectionConfigVolumeMount(request.InstallContainer)
-	setInjectedAnnotation(request.Pod)
+	setInjectedAnnotation(request.Pod, installerInfo.version)
 	return nil
 }
 
@@ -71,7 +71,9 @@ func (mutator *OneAgentPodMutator) Reinvoke(request *dtwebhook.ReinvocationReque
 func (mutator *OneAgentPodMutator) ensureInitSecret(request *dtwebhook.MutationRequest) error {
 	var initSecret corev1.Secret
 	secretObjectKey := client.ObjectKey{Name: consts.AgentInitSecretName, Namespace: request.Namespace.Name}
-	if err := mutator.apiReader.Get(request.Context, secretObjectKey, &initSecret); k8serrors.IsNotFound(err) {
+	if err := mutator.apiReader.Get(request.Context, secretObjectKey, &initSecret); k8serrors.IsNotFound(err) && request.DryRun {
+		log.Info("the init secret would be created before oneagent pod injection, skipped for dry-run")
+	} else if k8serrors.IsNotFound(err) {
 		initGenerator := initgeneration.NewInitGenerator(mutator.client, mutator.apiReader, mutator.webhookNamespace)
 		err := initGenerator.GenerateForNamespace(request.Context, request.DynaKube, request.Namespace.Name)
 		if err != nil && !k8serrors.IsAlreadyExists(err) {
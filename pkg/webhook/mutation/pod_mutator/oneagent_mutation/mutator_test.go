@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -96,6 +97,18 @@ func TestEnsureInitSecret(t *testing.T) {
 		err := mutator.ensureInitSecret(request)
 		require.NoError(t, err)
 	})
+	t.Run("shouldn't create init secret for dry-run", func(t *testing.T) {
+		mutator := createTestPodMutator(nil)
+		request := createTestMutationRequest(getTestDynakube(), nil, getTestNamespace(nil))
+		request.DryRun = true
+
+		err := mutator.ensureInitSecret(request)
+		require.NoError(t, err)
+
+		var initSecret corev1.Secret
+		err = mutator.client.Get(context.Background(), client.ObjectKey{Name: consts.AgentInitSecretName, Namespace: request.Namespace.Name}, &initSecret)
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
 }
 
 type mutateTestCase struct {
@@ -0,0 +1,22 @@
+package otel_mutation
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/logger"
+)
+
+const (
+	otlpEndpointEnv       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	otlpHeadersEnv        = "OTEL_EXPORTER_OTLP_HEADERS"
+	otlpProtocolEnv       = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	resourceAttributesEnv = "OTEL_RESOURCE_ATTRIBUTES"
+
+	// podNameEnv and podUIDEnv are referenced by the resource attributes, the values are only known once the pod is created
+	podNameEnv = "DT_OTEL_POD_NAME"
+	podUIDEnv  = "DT_OTEL_POD_UID"
+
+	otlpProtocol = "http/protobuf"
+)
+
+var (
+	log = logger.Factory.GetLogger("otel-pod-mutation")
+)
@@ -0,0 +1,92 @@
+package otel_mutation
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	dtingestendpoint "github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/ingestendpoint"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/address"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// exporterEnvVars are the env vars added to every container, in the order they have to be defined for the variable references to work
+func (mutator *OTelPodMutator) exporterEnvVars(request *dtwebhook.BaseRequest, workloadKind, workloadName string) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: podNameEnv, ValueFrom: kubeobjects.NewEnvVarSourceForField("metadata.name")},
+		{Name: podUIDEnv, ValueFrom: kubeobjects.NewEnvVarSourceForField("metadata.uid")},
+		{Name: otlpEndpointEnv, ValueFrom: endpointSecretKeyRef(dtingestendpoint.OtlpEndpointSecretField)},
+		{Name: otlpHeadersEnv, ValueFrom: endpointSecretKeyRef(dtingestendpoint.OtlpHeadersSecretField)},
+		{Name: otlpProtocolEnv, Value: otlpProtocol},
+		{Name: resourceAttributesEnv, Value: mutator.resourceAttributes(request, workloadKind, workloadName)},
+	}
+}
+
+// resourceAttributes uses the same metadata as the data-ingest enrichment, so the telemetry of the SDKs can be correlated with it
+func (mutator *OTelPodMutator) resourceAttributes(request *dtwebhook.BaseRequest, workloadKind, workloadName string) string {
+	attributes := []string{
+		fmt.Sprintf("k8s.pod.name=$(%s)", podNameEnv),
+		fmt.Sprintf("k8s.pod.uid=$(%s)", podUIDEnv),
+		resourceAttribute("k8s.namespace.name", request.Namespace.Name),
+		resourceAttribute("dt.kubernetes.workload.kind", workloadKind),
+		resourceAttribute("dt.kubernetes.workload.name", workloadName),
+		resourceAttribute("dt.kubernetes.cluster.id", mutator.clusterID),
+	}
+
+	enrichmentAttributes := request.DynaKube.MetadataEnrichmentAttributes(request.Namespace, *request.Pod)
+	keys := make([]string, 0, len(enrichmentAttributes))
+	for key := range enrichmentAttributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		attributes = append(attributes, resourceAttribute(key, enrichmentAttributes[key]))
+	}
+	return strings.Join(attributes, ",")
+}
+
+// resourceAttribute percent-encodes the value as required by OTEL_RESOURCE_ATTRIBUTES,
+// a '$' is escaped as well, so kubernetes doesn't treat it as a variable reference
+func resourceAttribute(key, value string) string {
+	return fmt.Sprintf("%s=%s", key, strings.ReplaceAll(url.PathEscape(value), "$", "$$"))
+}
+
+func endpointSecretKeyRef(key string) *corev1.EnvVarSource {
+	return &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: consts.EnrichmentEndpointSecretName},
+			Key:                  key,
+			// the secret may not have the OTLP fields yet, if it was created before openTelemetry was enabled
+			Optional: address.Of(true),
+		},
+	}
+}
+
+// addEnvVars doesn't overwrite the env vars set by the user, so the exporter can still be configured differently per container
+func addEnvVars(container *corev1.Container, envVars []corev1.EnvVar) {
+	for _, envVar := range envVars {
+		if kubeobjects.EnvVarIsIn(container.Env, envVar.Name) {
+			continue
+		}
+		container.Env = append(container.Env, envVar)
+	}
+}
+
+func containerIsInjected(container corev1.Container) bool {
+	return kubeobjects.EnvVarIsIn(container.Env, podNameEnv)
+}
+
+// injectedEnvVars returns the env vars of an injected container which were added by the mutator
+func injectedEnvVars(container corev1.Container) []corev1.EnvVar {
+	var envVars []corev1.EnvVar
+	for _, name := range []string{podNameEnv, podUIDEnv, otlpEndpointEnv, otlpHeadersEnv, otlpProtocolEnv, resourceAttributesEnv} {
+		if envVar := kubeobjects.FindEnvVar(container.Env, name); envVar != nil {
+			envVars = append(envVars, *envVar)
+		}
+	}
+	return envVars
+}
@@ -0,0 +1,127 @@
+package otel_mutation
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	dtingestendpoint "github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/ingestendpoint"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod_mutator/dataingest_mutation"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OTelPodMutator configures the OTLP exporter of the OpenTelemetry SDKs in the containers of a pod to export to Dynatrace
+type OTelPodMutator struct {
+	webhookNamespace string
+	clusterID        string
+	client           client.Client
+	apiReader        client.Reader
+	metaClient       client.Client
+	workloadKinds    dataingest_mutation.WorkloadKinds
+}
+
+func NewOTelPodMutator(webhookNamespace string, clusterID string, client client.Client, apiReader client.Reader, metaClient client.Client, workloadKinds dataingest_mutation.WorkloadKinds) *OTelPodMutator { //nolint:revive // argument-limit doesn't apply to constructors
+	return &OTelPodMutator{
+		webhookNamespace: webhookNamespace,
+		clusterID:        clusterID,
+		client:           client,
+		apiReader:        apiReader,
+		metaClient:       metaClient,
+		workloadKinds:    workloadKinds,
+	}
+}
+
+func (mutator *OTelPodMutator) Enabled(request *dtwebhook.BaseRequest) bool {
+	if request.DynaKube.Spec.OpenTelemetry == nil {
+		return false
+	}
+	return kubeobjects.GetFieldBool(request.Pod.Annotations, dtwebhook.AnnotationOTelInject, request.DynaKube.FeatureAutomaticInjection())
+}
+
+func (mutator *OTelPodMutator) Injected(request *dtwebhook.BaseRequest) bool {
+	return kubeobjects.GetFieldBool(request.Pod.Annotations, dtwebhook.AnnotationOTelInjected, false)
+}
+
+func (mutator *OTelPodMutator) Mutate(request *dtwebhook.MutationRequest) error {
+	log.Info("injecting OpenTelemetry exporter configuration into pod", "podName", request.PodName())
+	workloadKind, workloadName, err := dataingest_mutation.FindWorkload(request.Context, mutator.metaClient, mutator.workloadKinds, request.Pod, request.Namespace.Name)
+	if err != nil {
+		return err
+	}
+	if err := mutator.ensureEndpointSecret(request); err != nil {
+		return err
+	}
+
+	envVars := mutator.exporterEnvVars(request.BaseRequest, workloadKind, workloadName)
+	for i := range request.Pod.Spec.Containers {
+		addEnvVars(&request.Pod.Spec.Containers[i], envVars)
+	}
+	setInjectedAnnotation(request.Pod)
+	return nil
+}
+
+// Reinvoke configures the containers added after the injection, the same way as the containers which are already configured
+func (mutator *OTelPodMutator) Reinvoke(request *dtwebhook.ReinvocationRequest) bool {
+	if !mutator.Injected(request.BaseRequest) {
+		return false
+	}
+	log.Info("reinvoking", "podName", request.PodName())
+
+	var envVars []corev1.EnvVar
+	for _, container := range request.Pod.Spec.Containers {
+		if containerIsInjected(container) {
+			envVars = injectedEnvVars(container)
+			break
+		}
+	}
+	if envVars == nil {
+		return false
+	}
+
+	var updated bool
+	for i := range request.Pod.Spec.Containers {
+		container := &request.Pod.Spec.Containers[i]
+		if containerIsInjected(*container) {
+			continue
+		}
+		addEnvVars(container, envVars)
+		updated = true
+	}
+	return updated
+}
+
+// ensureEndpointSecret makes sure the OTLP endpoint and headers can be referenced, they are part of the data-ingest endpoint secret
+func (mutator *OTelPodMutator) ensureEndpointSecret(request *dtwebhook.MutationRequest) error {
+	var endpointSecret corev1.Secret
+	err := mutator.apiReader.Get(
+		request.Context,
+		client.ObjectKey{
+			Name:      consts.EnrichmentEndpointSecretName,
+			Namespace: request.Namespace.Name,
+		},
+		&endpointSecret)
+	switch {
+	case k8serrors.IsNotFound(err) && request.DryRun:
+		log.Info("the data-ingest endpoint secret would be created before pod injection, skipped for dry-run")
+	case k8serrors.IsNotFound(err):
+		endpointGenerator := dtingestendpoint.NewEndpointSecretGenerator(mutator.client, mutator.apiReader, mutator.webhookNamespace)
+		err := endpointGenerator.GenerateForNamespace(request.Context, request.DynaKube.Name, request.Namespace.Name)
+		if err != nil && !k8serrors.IsAlreadyExists(err) {
+			log.Info("failed to create the data-ingest endpoint secret before pod injection")
+			return err
+		}
+		log.Info("ensured that the data-ingest endpoint secret is present before pod injection")
+	case err != nil:
+		log.Info("failed to query the data-ingest endpoint secret before pod injection")
+		return err
+	}
+	return nil
+}
+
+func setInjectedAnnotation(pod *corev1.Pod) {
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[dtwebhook.AnnotationOTelInjected] = "true"
+}
@@ -0,0 +1,267 @@
+package otel_mutation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	dtingestendpoint "github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/ingestendpoint"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod_mutator/dataingest_mutation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testPodName       = "test-pod"
+	testNamespaceName = "test-namespace"
+	testDynakubeName  = "test-dynakube"
+	testClusterID     = "test-cluster-id"
+)
+
+func TestEnabled(t *testing.T) {
+	t.Run("off without openTelemetry", func(t *testing.T) {
+		mutator := createTestPodMutator(nil)
+		request := createTestMutationRequest(&dynatracev1beta1.DynaKube{}, nil)
+
+		require.False(t, mutator.Enabled(request.BaseRequest))
+	})
+	t.Run("on by default with openTelemetry", func(t *testing.T) {
+		mutator := createTestPodMutator(nil)
+		request := createTestMutationRequest(getTestDynakube(), nil)
+
+		require.True(t, mutator.Enabled(request.BaseRequest))
+	})
+	t.Run("turned off on the pod", func(t *testing.T) {
+		mutator := createTestPodMutator(nil)
+		request := createTestMutationRequest(getTestDynakube(), map[string]string{dtwebhook.AnnotationOTelInject: "false"})
+
+		require.False(t, mutator.Enabled(request.BaseRequest))
+	})
+	t.Run("turned on on the pod without automatic injection", func(t *testing.T) {
+		mutator := createTestPodMutator(nil)
+		dynakube := getTestDynakube()
+		dynakube.Annotations = map[string]string{dynatracev1beta1.AnnotationFeatureAutomaticInjection: "false"}
+		request := createTestMutationRequest(dynakube, map[string]string{dtwebhook.AnnotationOTelInject: "true"})
+
+		require.True(t, mutator.Enabled(request.BaseRequest))
+	})
+}
+
+func TestInjected(t *testing.T) {
+	t.Run("already marked", func(t *testing.T) {
+		mutator := createTestPodMutator(nil)
+		request := createTestMutationRequest(getTestDynakube(), map[string]string{dtwebhook.AnnotationOTelInjected: "true"})
+
+		require.True(t, mutator.Injected(request.BaseRequest))
+	})
+	t.Run("fresh", func(t *testing.T) {
+		mutator := createTestPodMutator(nil)
+		request := createTestMutationRequest(getTestDynakube(), nil)
+
+		require.False(t, mutator.Injected(request.BaseRequest))
+	})
+}
+
+func TestMutate(t *testing.T) {
+	t.Run("should configure the exporter of all containers", func(t *testing.T) {
+		mutator := createTestPodMutator([]client.Object{getTestEndpointSecret()})
+		request := createTestMutationRequest(getTestDynakube(), nil)
+
+		err := mutator.Mutate(request)
+		require.NoError(t, err)
+
+		container := request.Pod.Spec.Containers[0]
+		assert.Len(t, container.Env, 6)
+		assert.Equal(t, otlpProtocol, kubeobjects.FindEnvVar(container.Env, otlpProtocolEnv).Value)
+
+		endpoint := kubeobjects.FindEnvVar(container.Env, otlpEndpointEnv)
+		require.NotNil(t, endpoint.ValueFrom.SecretKeyRef)
+		assert.Equal(t, consts.EnrichmentEndpointSecretName, endpoint.ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, dtingestendpoint.OtlpEndpointSecretField, endpoint.ValueFrom.SecretKeyRef.Key)
+		assert.Equal(t, dtingestendpoint.OtlpHeadersSecretField, kubeobjects.FindEnvVar(container.Env, otlpHeadersEnv).ValueFrom.SecretKeyRef.Key)
+
+		assert.Equal(t,
+			"k8s.pod.name=$(DT_OTEL_POD_NAME),k8s.pod.uid=$(DT_OTEL_POD_UID),k8s.namespace.name=test-namespace,"+
+				"dt.kubernetes.workload.kind=Pod,dt.kubernetes.workload.name=test-pod,dt.kubernetes.cluster.id=test-cluster-id",
+			kubeobjects.FindEnvVar(container.Env, resourceAttributesEnv).Value)
+		assert.Equal(t, "true", request.Pod.Annotations[dtwebhook.AnnotationOTelInjected])
+	})
+	t.Run("should not overwrite the env vars of the user", func(t *testing.T) {
+		mutator := createTestPodMutator([]client.Object{getTestEndpointSecret()})
+		request := createTestMutationRequest(getTestDynakube(), nil)
+		request.Pod.Spec.Containers[0].Env = []corev1.EnvVar{{Name: otlpProtocolEnv, Value: "grpc"}}
+
+		err := mutator.Mutate(request)
+		require.NoError(t, err)
+
+		container := request.Pod.Spec.Containers[0]
+		assert.Len(t, container.Env, 6)
+		assert.Equal(t, "grpc", kubeobjects.FindEnvVar(container.Env, otlpProtocolEnv).Value)
+	})
+	t.Run("should add the metadata enrichment attributes", func(t *testing.T) {
+		mutator := createTestPodMutator([]client.Object{getTestEndpointSecret()})
+		dynakube := getTestDynakube()
+		dynakube.Spec.MetadataEnrichment = &dynatracev1beta1.MetadataEnrichmentSpec{
+			Rules: []dynatracev1beta1.EnrichmentRule{{Type: dynatracev1beta1.EnrichmentPodLabelRule, Keys: []string{"team"}, Prefix: "team."}},
+		}
+		request := createTestMutationRequest(dynakube, nil)
+		request.Pod.Labels = map[string]string{"team": "a,b $(X)"}
+
+		err := mutator.Mutate(request)
+		require.NoError(t, err)
+
+		assert.Contains(t, kubeobjects.FindEnvVar(request.Pod.Spec.Containers[0].Env, resourceAttributesEnv).Value, ",team.team=a%2Cb%20$$%28X%29")
+	})
+	t.Run("should create the endpoint secret", func(t *testing.T) {
+		objects := []client.Object{getTestDynakube(), getTestTokens()}
+		mutator := createTestPodMutator(objects)
+		request := createTestMutationRequest(getTestDynakube(), nil)
+
+		err := mutator.Mutate(request)
+		require.NoError(t, err)
+
+		var secret corev1.Secret
+		err = mutator.apiReader.Get(context.Background(), client.ObjectKey{Name: consts.EnrichmentEndpointSecretName, Namespace: testNamespaceName}, &secret)
+		require.NoError(t, err)
+		assert.Contains(t, secret.Data, dtingestendpoint.OtlpEndpointSecretField)
+	})
+	t.Run("should not create the endpoint secret for dry-run", func(t *testing.T) {
+		objects := []client.Object{getTestDynakube(), getTestTokens()}
+		mutator := createTestPodMutator(objects)
+		request := createTestMutationRequest(getTestDynakube(), nil)
+		request.DryRun = true
+
+		err := mutator.Mutate(request)
+		require.NoError(t, err)
+
+		var secret corev1.Secret
+		err = mutator.apiReader.Get(context.Background(), client.ObjectKey{Name: consts.EnrichmentEndpointSecretName, Namespace: testNamespaceName}, &secret)
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+}
+
+func TestReinvoke(t *testing.T) {
+	t.Run("should configure new containers like the injected ones", func(t *testing.T) {
+		mutator := createTestPodMutator([]client.Object{getTestEndpointSecret()})
+		mutationRequest := createTestMutationRequest(getTestDynakube(), nil)
+		require.NoError(t, mutator.Mutate(mutationRequest))
+		mutationRequest.Pod.Spec.Containers = append(mutationRequest.Pod.Spec.Containers, corev1.Container{Name: "sidecar"})
+
+		updated := mutator.Reinvoke(mutationRequest.ToReinvocationRequest())
+		require.True(t, updated)
+
+		containers := mutationRequest.Pod.Spec.Containers
+		assert.Equal(t, containers[0].Env, containers[1].Env)
+	})
+	t.Run("no change ==> no update", func(t *testing.T) {
+		mutator := createTestPodMutator([]client.Object{getTestEndpointSecret()})
+		mutationRequest := createTestMutationRequest(getTestDynakube(), nil)
+		require.NoError(t, mutator.Mutate(mutationRequest))
+
+		updated := mutator.Reinvoke(mutationRequest.ToReinvocationRequest())
+		require.False(t, updated)
+	})
+	t.Run("not injected pod is not updated", func(t *testing.T) {
+		mutator := createTestPodMutator(nil)
+		request := createTestMutationRequest(getTestDynakube(), nil).ToReinvocationRequest()
+
+		updated := mutator.Reinvoke(request)
+		require.False(t, updated)
+	})
+}
+
+func createTestMutationRequest(dynakube *dynatracev1beta1.DynaKube, annotations map[string]string) *dtwebhook.MutationRequest {
+	return dtwebhook.NewMutationRequest(
+		context.Background(),
+		*getTestNamespace(),
+		&corev1.Container{
+			Name: dtwebhook.InstallContainerName,
+		},
+		getTestPod(annotations),
+		*dynakube,
+	)
+}
+
+func createTestPodMutator(objects []client.Object) *OTelPodMutator {
+	fakeClient := fake.NewClient(objects...)
+	return NewOTelPodMutator(testNamespaceName, testClusterID, fakeClient, fakeClient, fakeClient, dataingest_mutation.DefaultWorkloadKinds())
+}
+
+func getTestPod(annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Pod",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testPodName,
+			Namespace:   testNamespaceName,
+			Annotations: annotations,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "container",
+					Image: "alpine",
+				},
+			},
+		},
+	}
+}
+
+func getTestEndpointSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      consts.EnrichmentEndpointSecretName,
+			Namespace: testNamespaceName,
+		},
+	}
+}
+
+func getTestTokens() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testDynakubeName,
+			Namespace: testNamespaceName,
+		},
+		Data: map[string][]byte{
+			"apiToken":        []byte("test-api-token"),
+			"dataIngestToken": []byte("test-data-ingest-token"),
+		},
+	}
+}
+
+func getTestDynakube() *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testDynakubeName,
+			Namespace: testNamespaceName,
+		},
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: "https://tenant.test/api",
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ApplicationMonitoring: &dynatracev1beta1.ApplicationMonitoringSpec{},
+			},
+			OpenTelemetry: &dynatracev1beta1.OpenTelemetrySpec{},
+		},
+	}
+}
+
+func getTestNamespace() *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testNamespaceName,
+			Labels: map[string]string{
+				dtwebhook.InjectionInstanceLabel: testDynakubeName,
+			},
+		},
+	}
+}
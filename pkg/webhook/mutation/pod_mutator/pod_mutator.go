@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
 	dtotel "github.com/Dynatrace/dynatrace-operator/pkg/util/otel"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
@@ -42,12 +44,18 @@ type podMutatorWebhook struct {
 	decoder   admission.Decoder
 	recorder  podMutatorEventRecorder
 
+	// policyReader lists the InjectionPolicies from the cache, nil if the CRD is not installed
+	policyReader client.Reader
+
 	webhookImage     string
 	webhookNamespace string
 	clusterID        string
 	apmExists        bool
 	deployedViaOLM   bool
 
+	nativeSidecarSupported bool
+	mutationTimeBudget     time.Duration
+
 	mutators   []dtwebhook.PodMutator
 	spanTracer trace.Tracer
 	otelMeter  metric.Meter
@@ -61,30 +69,63 @@ func (webhook *podMutatorWebhook) Handle(ctx context.Context, request admission.
 	ctx, span := dtotel.StartSpan(ctx, webhook.spanTracer, "podMutatorHandle")
 	defer span.End()
 
+	if request.DryRun != nil && *request.DryRun {
+		// no pod is created, so the mutation doesn't need to be protected by the time budget
+		return webhook.handleDryRun(ctx, request)
+	}
+	return webhook.handleWithinTimeBudget(ctx, request)
+}
+
+func (webhook *podMutatorWebhook) handle(ctx context.Context, request admission.Request) admission.Response {
 	emptyPatch := admission.Patched("")
 	mutationRequest, err := webhook.createMutationRequestBase(ctx, request)
 	if err != nil {
-		return silentErrorResponse(mutationRequest.Pod, err)
+		countInjection(ctx, injectionReasonError)
+		return silentErrorResponse(nil, err)
 	}
 
-	podName := mutationRequest.PodName()
-	if !mutationRequired(mutationRequest) || webhook.isOcDebugPod(mutationRequest.Pod) {
+	if mutationRequest == nil {
+		recordDecision(ctx, previewStepNamespace, "namespace %s isn't monitored by a DynaKube", request.Namespace)
+		countInjection(ctx, injectionReasonNotMonitored)
 		return emptyPatch
 	}
+	recordDynakube(ctx, mutationRequest)
 
+	switch {
+	case !mutationRequired(mutationRequest):
+		recordDecision(ctx, previewStepPod, "injection is disabled by the annotation %s", dtwebhook.AnnotationDynatraceInject)
+		countInjection(ctx, injectionReasonDisabled)
+		return emptyPatch
+	case webhook.isOcDebugPod(mutationRequest.Pod):
+		recordDecision(ctx, previewStepPod, "pods created by oc debug are never injected")
+		countInjection(ctx, injectionReasonOcDebugPod)
+		return emptyPatch
+	}
+
+	if err := webhook.applyInjectionPolicy(ctx, mutationRequest); err != nil {
+		countInjection(ctx, injectionReasonError)
+		return silentErrorResponse(mutationRequest.Pod, err)
+	}
+	webhook.applyNamespaceOverrides(ctx, mutationRequest)
+
+	podName := mutationRequest.PodName()
 	webhook.setupEventRecorder(ctx, mutationRequest)
 
 	if webhook.isInjected(ctx, mutationRequest) {
+		recordDecision(ctx, previewStepPod, "pod is already injected, only new containers are injected")
 		if webhook.handlePodReinvocation(ctx, mutationRequest) {
 			log.Info("reinvocation policy applied", "podName", podName)
 			webhook.recorder.sendPodUpdateEvent()
+			countInjection(ctx, injectionReasonReinvoked)
 			return createResponseForPod(ctx, mutationRequest.Pod, request)
 		}
 		log.Info("no change, all containers already injected", "podName", podName)
+		countInjection(ctx, injectionReasonAlreadyInjected)
 		return emptyPatch
 	}
 
 	if err := webhook.handlePodMutation(ctx, mutationRequest); err != nil {
+		countInjection(ctx, injectionReasonError)
 		return silentErrorResponse(mutationRequest.Pod, err)
 	}
 	log.Info("injection finished for pod", "podName", podName, "namespace", request.Namespace)
@@ -136,6 +177,9 @@ func (webhook *podMutatorWebhook) handlePodMutation(ctx context.Context, mutatio
 	defer span.End()
 
 	mutationRequest.InstallContainer = createInstallInitContainerBase(webhook.webhookImage, webhook.clusterID, mutationRequest.Pod, mutationRequest.DynaKube)
+	if err := addEnrichmentAttributesEnv(mutationRequest.InstallContainer, mutationRequest.BaseRequest); err != nil {
+		return err
+	}
 	isMutated := false
 	for _, mutator := range webhook.mutators {
 		if !mutator.Enabled(mutationRequest.BaseRequest) {
@@ -146,17 +190,32 @@ func (webhook *podMutatorWebhook) handlePodMutation(ctx context.Context, mutatio
 		}
 		isMutated = true
 	}
+	recordMutationDecisions(ctx, mutationRequest)
 	if !isMutated {
 		log.Info("no mutation is enabled")
+		countInjection(ctx, injectionReasonNoMutatorEnabled)
 		return nil
 	}
 
 	addInitContainerToPod(mutationRequest.Pod, mutationRequest.InstallContainer)
+	if webhook.useNativeSidecar(mutationRequest) {
+		addInitContainerToPod(mutationRequest.Pod, createRefreshSidecar(mutationRequest.InstallContainer))
+	}
 	webhook.recorder.sendPodInjectEvent()
 	setDynatraceInjectedAnnotation(mutationRequest)
+	countInjection(ctx, injectionReasonInjected)
 	return nil
 }
 
+// countInjection records the outcome of the mutation in the metrics, or in the decisions of a dry-run request
+func countInjection(ctx context.Context, reason string) {
+	if previewResultFromContext(ctx) != nil {
+		recordDecision(ctx, previewStepOutcome, reason)
+		return
+	}
+	injectionsMetric.WithLabelValues(reason).Inc()
+}
+
 func (webhook *podMutatorWebhook) handlePodReinvocation(ctx context.Context, mutationRequest *dtwebhook.MutationRequest) bool {
 	_, span := dtotel.StartSpan(ctx, webhook.spanTracer, "handlePodReinvocation")
 	defer span.End()
@@ -175,9 +234,29 @@ func (webhook *podMutatorWebhook) handlePodReinvocation(ctx context.Context, mut
 			}
 		}
 	}
+	if needsUpdate {
+		updateRefreshSidecar(mutationRequest.Pod)
+	}
 	return needsUpdate
 }
 
+// useNativeSidecar only adds the refresh sidecar to pods with OneAgent injection, the enrichment files never change
+func (webhook *podMutatorWebhook) useNativeSidecar(mutationRequest *dtwebhook.MutationRequest) bool {
+	dynakube := mutationRequest.DynaKube
+	if !dynakube.FeatureNativeSidecar() {
+		return false
+	}
+	oneAgentInjected := kubeobjects.FindEnvVar(mutationRequest.InstallContainer.Env, consts.AgentInjectedEnv)
+	if oneAgentInjected == nil || oneAgentInjected.Value != "true" {
+		return false
+	}
+	if !webhook.nativeSidecarSupported {
+		log.Info("native sidecars are not supported by the cluster, only the install container is injected", "dynakube", dynakube.Name)
+		return false
+	}
+	return true
+}
+
 func setDynatraceInjectedAnnotation(mutationRequest *dtwebhook.MutationRequest) {
 	if mutationRequest.Pod.Annotations == nil {
 		mutationRequest.Pod.Annotations = make(map[string]string)
@@ -199,7 +278,10 @@ func createResponseForPod(ctx context.Context, pod *corev1.Pod, req admission.Re
 
 func silentErrorResponse(pod *corev1.Pod, err error) admission.Response {
 	rsp := admission.Patched("")
-	podName := kubeobjects.GetPodName(*pod)
+	podName := ""
+	if pod != nil {
+		podName = kubeobjects.GetPodName(*pod)
+	}
 	log.Error(err, "failed to inject into pod", "podName", podName)
 	rsp.Result.Message = fmt.Sprintf("Failed to inject into pod: %s because %s", podName, err.Error())
 	return rsp
@@ -8,7 +8,9 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
 	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -40,16 +42,18 @@ type mutatorTest struct {
 	mutators       []dtwebhook.PodMutator
 	testPod        *corev1.Pod
 	objects        []client.Object
+	expectedReason string
 	expectedResult func(t *testing.T, response *admission.Response, mutators []dtwebhook.PodMutator)
 }
 
 func TestMutator(t *testing.T) {
 	tests := []mutatorTest{
 		{
-			name:     "happy path",
-			mutators: []dtwebhook.PodMutator{createSimplePodMutatorMock(), createSimplePodMutatorMock()},
-			testPod:  getTestPod(),
-			objects:  []client.Object{getTestDynakube(), getTestNamespace()},
+			name:           "happy path",
+			mutators:       []dtwebhook.PodMutator{createSimplePodMutatorMock(), createSimplePodMutatorMock()},
+			testPod:        getTestPod(),
+			objects:        []client.Object{getTestDynakube(), getTestNamespace()},
+			expectedReason: injectionReasonInjected,
 			expectedResult: func(t *testing.T, response *admission.Response, mutators []dtwebhook.PodMutator) {
 				require.NotNil(t, response)
 				assert.True(t, response.Allowed)
@@ -62,10 +66,11 @@ func TestMutator(t *testing.T) {
 			},
 		},
 		{
-			name:     "disable all mutators with dynatrace.com/inject",
-			mutators: []dtwebhook.PodMutator{createSimplePodMutatorMock(), createSimplePodMutatorMock()},
-			testPod:  getTestPodWithInjectionDisabled(),
-			objects:  []client.Object{getTestDynakube(), getTestNamespace()},
+			name:           "disable all mutators with dynatrace.com/inject",
+			mutators:       []dtwebhook.PodMutator{createSimplePodMutatorMock(), createSimplePodMutatorMock()},
+			testPod:        getTestPodWithInjectionDisabled(),
+			objects:        []client.Object{getTestDynakube(), getTestNamespace()},
+			expectedReason: injectionReasonDisabled,
 			expectedResult: func(t *testing.T, response *admission.Response, mutators []dtwebhook.PodMutator) {
 				require.NotNil(t, response)
 				assert.True(t, response.Allowed)
@@ -78,10 +83,11 @@ func TestMutator(t *testing.T) {
 			},
 		},
 		{
-			name:     "sad path",
-			mutators: []dtwebhook.PodMutator{createFailPodMutatorMock()},
-			testPod:  getTestPod(),
-			objects:  []client.Object{getTestDynakube(), getTestNamespace()},
+			name:           "sad path",
+			mutators:       []dtwebhook.PodMutator{createFailPodMutatorMock()},
+			testPod:        getTestPod(),
+			objects:        []client.Object{getTestDynakube(), getTestNamespace()},
+			expectedReason: injectionReasonError,
 			expectedResult: func(t *testing.T, response *admission.Response, mutators []dtwebhook.PodMutator) {
 				require.NotNil(t, response)
 				assert.True(t, response.Allowed)
@@ -97,10 +103,11 @@ func TestMutator(t *testing.T) {
 			},
 		},
 		{
-			name:     "oc debug pod",
-			mutators: []dtwebhook.PodMutator{createSimplePodMutatorMock()},
-			testPod:  getTestPodWithOcDebugPodAnnotations(),
-			objects:  []client.Object{getTestDynakube(), getTestNamespace()},
+			name:           "oc debug pod",
+			mutators:       []dtwebhook.PodMutator{createSimplePodMutatorMock()},
+			testPod:        getTestPodWithOcDebugPodAnnotations(),
+			objects:        []client.Object{getTestDynakube(), getTestNamespace()},
+			expectedReason: injectionReasonOcDebugPod,
 			expectedResult: func(t *testing.T, response *admission.Response, mutators []dtwebhook.PodMutator) {
 				require.NotNil(t, response)
 				assert.True(t, response.Allowed)
@@ -123,9 +130,12 @@ func TestMutator(t *testing.T) {
 			objects := test.objects
 			objects = append(objects, test.testPod)
 			podWebhook := createTestWebhook(test.mutators, objects)
+			injections := injectionsMetric.WithLabelValues(test.expectedReason)
+			injectionsBefore := testutil.ToFloat64(injections)
 
 			response := podWebhook.Handle(ctx, *request)
 			test.expectedResult(t, &response, test.mutators)
+			assert.Equal(t, injectionsBefore+1, testutil.ToFloat64(injections))
 		})
 	}
 }
@@ -165,6 +175,46 @@ func TestHandlePodMutation(t *testing.T) {
 		mutator2.(*dtwebhook.PodMutatorMock).AssertCalled(t, "Enabled", mutationRequest.BaseRequest)
 		mutator2.(*dtwebhook.PodMutatorMock).AssertCalled(t, "Mutate", mutationRequest)
 	})
+	t.Run("native sidecar is added after the install container", func(t *testing.T) {
+		dynakube := getTestDynakube()
+		dynakube.Annotations = map[string]string{dynatracev1beta1.AnnotationFeatureNativeSidecar: "true"}
+		podWebhook := createTestWebhook([]dtwebhook.PodMutator{createOneAgentPodMutatorMock()}, nil)
+		podWebhook.nativeSidecarSupported = true
+		mutationRequest := createTestMutationRequest(dynakube)
+
+		err := podWebhook.handlePodMutation(context.Background(), mutationRequest)
+		require.NoError(t, err)
+		require.Len(t, mutationRequest.Pod.Spec.InitContainers, 3)
+		assert.Equal(t, dtwebhook.InstallContainerName, mutationRequest.Pod.Spec.InitContainers[1].Name)
+
+		refreshSidecar := mutationRequest.Pod.Spec.InitContainers[2]
+		assert.Equal(t, dtwebhook.RefreshContainerName, refreshSidecar.Name)
+		assert.Equal(t, []string{"init", "--refresh"}, refreshSidecar.Args)
+		require.NotNil(t, refreshSidecar.RestartPolicy)
+		assert.Equal(t, corev1.ContainerRestartPolicyAlways, *refreshSidecar.RestartPolicy)
+		assert.Equal(t, mutationRequest.InstallContainer.Env, refreshSidecar.Env)
+	})
+	t.Run("native sidecar is not added if the cluster doesn't support it", func(t *testing.T) {
+		dynakube := getTestDynakube()
+		dynakube.Annotations = map[string]string{dynatracev1beta1.AnnotationFeatureNativeSidecar: "true"}
+		podWebhook := createTestWebhook([]dtwebhook.PodMutator{createOneAgentPodMutatorMock()}, nil)
+		mutationRequest := createTestMutationRequest(dynakube)
+
+		err := podWebhook.handlePodMutation(context.Background(), mutationRequest)
+		require.NoError(t, err)
+		assert.Len(t, mutationRequest.Pod.Spec.InitContainers, 2)
+	})
+	t.Run("native sidecar is not added without OneAgent injection", func(t *testing.T) {
+		dynakube := getTestDynakube()
+		dynakube.Annotations = map[string]string{dynatracev1beta1.AnnotationFeatureNativeSidecar: "true"}
+		podWebhook := createTestWebhook([]dtwebhook.PodMutator{createSimplePodMutatorMock()}, nil)
+		podWebhook.nativeSidecarSupported = true
+		mutationRequest := createTestMutationRequest(dynakube)
+
+		err := podWebhook.handlePodMutation(context.Background(), mutationRequest)
+		require.NoError(t, err)
+		assert.Len(t, mutationRequest.Pod.Spec.InitContainers, 2)
+	})
 	t.Run("should call 1 mutator, 1 error, no initContainer and annotation", func(t *testing.T) {
 		sadMutator := createFailPodMutatorMock()
 		happyMutator := createSimplePodMutatorMock()
@@ -273,6 +323,7 @@ func createTestWebhook(mutators []dtwebhook.PodMutator, objects []client.Object)
 
 	return &podMutatorWebhook{
 		apiReader:        fake.NewClient(objects...),
+		policyReader:     fake.NewClient(objects...),
 		decoder:          *decoder,
 		recorder:         podMutatorEventRecorder{recorder: record.NewFakeRecorder(10), pod: &corev1.Pod{}, dynakube: getTestDynakube()},
 		webhookImage:     testImage,
@@ -292,6 +343,19 @@ func createSimplePodMutatorMock() dtwebhook.PodMutator {
 	return &mutator
 }
 
+// createOneAgentPodMutatorMock configures the install container for OneAgent injection like the OneAgent mutator
+func createOneAgentPodMutatorMock() dtwebhook.PodMutator {
+	mutator := dtwebhook.PodMutatorMock{}
+	mutator.On("Enabled", mock.Anything).Return(true)
+	mutator.On("Injected", mock.Anything).Return(false)
+	mutator.On("Mutate", mock.Anything).Run(func(args mock.Arguments) {
+		mutationRequest := args.Get(0).(*dtwebhook.MutationRequest)
+		mutationRequest.InstallContainer.Env = append(mutationRequest.InstallContainer.Env, corev1.EnvVar{Name: consts.AgentInjectedEnv, Value: "true"})
+	}).Return(nil)
+	mutator.On("Reinvoke", mock.Anything).Return(true)
+	return &mutator
+}
+
 func createAlreadyInjectedPodMutatorMock() dtwebhook.PodMutator {
 	mutator := dtwebhook.PodMutatorMock{}
 	mutator.On("Enabled", mock.Anything).Return(true)
@@ -0,0 +1,136 @@
+package pod_mutator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// PreviewWarningPrefix marks the admission warnings explaining the mutation of dry-run requests
+	PreviewWarningPrefix = "dynatrace-webhook "
+
+	previewStepNamespace          = "namespace"
+	previewStepDynakube           = "dynakube"
+	previewStepPod                = "pod"
+	previewStepInjectionPolicy    = "injection-policy"
+	previewStepNamespaceOverrides = "namespace-overrides"
+	previewStepOneAgent           = "oneagent"
+	previewStepDataIngest         = "data-ingest"
+	previewStepOpenTelemetry      = "opentelemetry"
+	previewStepContainer          = "container"
+	previewStepOutcome            = "outcome"
+	previewStepError              = "error"
+)
+
+// PreviewDecision is a step of the mutation with the reason for it
+type PreviewDecision struct {
+	Step   string `json:"step"`
+	Reason string `json:"reason"`
+}
+
+// Warning formats the decision as admission warning, the api-server truncates warnings longer than 256 characters
+func (decision PreviewDecision) Warning() string {
+	return fmt.Sprintf("%s[%s] %s", PreviewWarningPrefix, decision.Step, decision.Reason)
+}
+
+// ParsePreviewWarning returns the decision of an admission warning, false if the warning isn't one of the webhook
+func ParsePreviewWarning(warning string) (PreviewDecision, bool) {
+	stepAndReason, isPreview := strings.CutPrefix(warning, PreviewWarningPrefix+"[")
+	if !isPreview {
+		return PreviewDecision{}, false
+	}
+	step, reason, found := strings.Cut(stepAndReason, "] ")
+	if !found {
+		return PreviewDecision{}, false
+	}
+	return PreviewDecision{Step: step, Reason: reason}, true
+}
+
+// previewResult collects the decisions of a dry-run request
+type previewResult struct {
+	decisions []PreviewDecision
+}
+
+type previewContextKey struct{}
+
+func withPreviewResult(ctx context.Context, result *previewResult) context.Context {
+	return context.WithValue(ctx, previewContextKey{}, result)
+}
+
+func previewResultFromContext(ctx context.Context) *previewResult {
+	result, _ := ctx.Value(previewContextKey{}).(*previewResult)
+	return result
+}
+
+// recordDecision adds a decision to the preview of the request, it does nothing for admission requests which aren't dry-run
+func recordDecision(ctx context.Context, step, reason string, args ...any) {
+	result := previewResultFromContext(ctx)
+	if result == nil {
+		return
+	}
+	result.decisions = append(result.decisions, PreviewDecision{Step: step, Reason: fmt.Sprintf(reason, args...)})
+}
+
+func recordDynakube(ctx context.Context, mutationRequest *dtwebhook.MutationRequest) {
+	recordDecision(ctx, previewStepDynakube, "namespace %s is monitored by DynaKube %s", mutationRequest.Namespace.Name, mutationRequest.DynaKube.Name)
+}
+
+// recordMutationDecisions explains the result of the mutators, based on the annotations they set on the pod
+func recordMutationDecisions(ctx context.Context, mutationRequest *dtwebhook.MutationRequest) {
+	if previewResultFromContext(ctx) == nil {
+		return
+	}
+	annotations := mutationRequest.Pod.Annotations
+
+	switch {
+	case annotations[dtwebhook.AnnotationOneAgentReason] != "":
+		recordDecision(ctx, previewStepOneAgent, "not injected: %s", annotations[dtwebhook.AnnotationOneAgentReason])
+	case kubeobjects.GetFieldBool(annotations, dtwebhook.AnnotationOneAgentInjected, false):
+		recordDecision(ctx, previewStepOneAgent, "injected with code modules version '%s'", annotations[dtwebhook.AnnotationOneAgentVersion])
+	default:
+		recordDecision(ctx, previewStepOneAgent, "disabled for the pod or the DynaKube")
+	}
+
+	if kubeobjects.GetFieldBool(annotations, dtwebhook.AnnotationDataIngestInjected, false) {
+		recordDecision(ctx, previewStepDataIngest, "injected")
+	} else {
+		recordDecision(ctx, previewStepDataIngest, "disabled for the pod or the DynaKube")
+	}
+
+	if kubeobjects.GetFieldBool(annotations, dtwebhook.AnnotationOTelInjected, false) {
+		recordDecision(ctx, previewStepOpenTelemetry, "OTLP exporter configured")
+	} else if mutationRequest.DynaKube.Spec.OpenTelemetry != nil {
+		recordDecision(ctx, previewStepOpenTelemetry, "disabled for the pod")
+	}
+
+	for _, container := range mutationRequest.Pod.Spec.Containers {
+		if !kubeobjects.GetFieldBool(annotations, dtwebhook.ContainerInjectionAnnotation(container.Name), true) {
+			recordDecision(ctx, previewStepContainer, "container %s is excluded from the OneAgent injection by %s", container.Name, dtwebhook.ContainerInjectionAnnotation(container.Name))
+		}
+	}
+}
+
+// handleDryRun explains the mutation of dry-run requests with admission warnings, so a server-side dry-run of the pod creation,
+// like the one of the inspect-pod command, shows why the pod is injected or not.
+// The api-server only sends dry-run requests to webhooks without side effects, so no events are sent and the injection isn't counted.
+func (webhook *podMutatorWebhook) handleDryRun(ctx context.Context, request admission.Request) admission.Response {
+	dryRunWebhook := *webhook
+	dryRunWebhook.recorder = newPodMutatorEventRecorder(&record.FakeRecorder{})
+
+	result := &previewResult{}
+	response := dryRunWebhook.handle(withPreviewResult(ctx, result), request)
+
+	if response.Result != nil && response.Result.Message != "" {
+		result.decisions = append(result.decisions, PreviewDecision{Step: previewStepError, Reason: response.Result.Message})
+	}
+	for _, decision := range result.decisions {
+		response.Warnings = append(response.Warnings, decision.Warning())
+	}
+	return response
+}
@@ -0,0 +1,95 @@
+package pod_mutator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/address"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestHandleDryRun(t *testing.T) {
+	t.Run("injected pod", func(t *testing.T) {
+		podWebhook := createTestWebhook([]dtwebhook.PodMutator{createSimplePodMutatorMock()}, []client.Object{getTestDynakube(), getTestNamespace()})
+		injections := injectionsMetric.WithLabelValues(injectionReasonInjected)
+		injectionsBefore := testutil.ToFloat64(injections)
+
+		response := podWebhook.Handle(context.Background(), createTestDryRunRequest(getTestPod()))
+
+		require.True(t, response.Allowed)
+		assert.Contains(t, response.Warnings, PreviewDecision{Step: previewStepDynakube, Reason: "namespace test-namespace is monitored by DynaKube test-dynakube"}.Warning())
+		assert.Contains(t, response.Warnings, PreviewDecision{Step: previewStepOutcome, Reason: injectionReasonInjected}.Warning())
+		assert.NotEmpty(t, response.Patches)
+		assert.Equal(t, injectionsBefore, testutil.ToFloat64(injections), "dry-run requests are not counted")
+	})
+	t.Run("pod with disabled injection", func(t *testing.T) {
+		podWebhook := createTestWebhook([]dtwebhook.PodMutator{createSimplePodMutatorMock()}, []client.Object{getTestDynakube(), getTestNamespace()})
+
+		response := podWebhook.Handle(context.Background(), createTestDryRunRequest(getTestPodWithInjectionDisabled()))
+
+		assert.Equal(t, []string{
+			PreviewDecision{Step: previewStepDynakube, Reason: "namespace test-namespace is monitored by DynaKube test-dynakube"}.Warning(),
+			PreviewDecision{Step: previewStepPod, Reason: "injection is disabled by the annotation dynatrace.com/inject"}.Warning(),
+			PreviewDecision{Step: previewStepOutcome, Reason: injectionReasonDisabled}.Warning(),
+		}, response.Warnings)
+		assert.Empty(t, response.Patches)
+	})
+	t.Run("failed mutation is reported", func(t *testing.T) {
+		podWebhook := createTestWebhook([]dtwebhook.PodMutator{createFailPodMutatorMock()}, []client.Object{getTestDynakube(), getTestNamespace()})
+
+		response := podWebhook.Handle(context.Background(), createTestDryRunRequest(getTestPod()))
+
+		require.NotEmpty(t, response.Warnings)
+		decision, isPreview := ParsePreviewWarning(response.Warnings[len(response.Warnings)-1])
+		require.True(t, isPreview)
+		assert.Equal(t, previewStepError, decision.Step)
+		assert.Contains(t, decision.Reason, "BOOM")
+		assert.Empty(t, response.Patches)
+	})
+	t.Run("no events are sent", func(t *testing.T) {
+		podWebhook := createTestWebhook([]dtwebhook.PodMutator{createSimplePodMutatorMock()}, []client.Object{getTestDynakube(), getTestNamespace()})
+		recorder := podWebhook.recorder.recorder.(*record.FakeRecorder)
+
+		podWebhook.Handle(context.Background(), createTestDryRunRequest(getTestPod()))
+
+		assert.Empty(t, recorder.Events)
+	})
+	t.Run("requests which aren't dry-run have no warnings", func(t *testing.T) {
+		podWebhook := createTestWebhook([]dtwebhook.PodMutator{createSimplePodMutatorMock()}, []client.Object{getTestDynakube(), getTestNamespace()})
+
+		response := podWebhook.Handle(context.Background(), *createTestAdmissionRequest(getTestPod()))
+
+		assert.Empty(t, response.Warnings)
+	})
+}
+
+func TestParsePreviewWarning(t *testing.T) {
+	t.Run("decision is parsed from its warning", func(t *testing.T) {
+		decision := PreviewDecision{Step: previewStepPod, Reason: "injection is disabled by the annotation dynatrace.com/inject"}
+
+		parsed, isPreview := ParsePreviewWarning(decision.Warning())
+
+		require.True(t, isPreview)
+		assert.Equal(t, decision, parsed)
+	})
+	t.Run("warnings of others are ignored", func(t *testing.T) {
+		for _, warning := range []string{"", "spec.containers[0]: deprecated", PreviewWarningPrefix + "no step"} {
+			_, isPreview := ParsePreviewWarning(warning)
+
+			assert.False(t, isPreview, warning)
+		}
+	})
+}
+
+func createTestDryRunRequest(pod *corev1.Pod) admission.Request {
+	request := createTestAdmissionRequest(pod)
+	request.DryRun = address.Of(true)
+	return *request
+}
@@ -9,6 +9,7 @@ import (
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod_mutator/dataingest_mutation"
 	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod_mutator/oneagent_mutation"
+	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod_mutator/otel_mutation"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
 	corev1 "k8s.io/api/core/v1"
@@ -59,19 +60,43 @@ func registerInjectEndpoint(mgr manager.Manager, webhookNamespace string, webhoo
 		return err
 	}
 
+	workloadKinds, err := dataingest_mutation.WorkloadKindsFromEnv()
+	if err != nil {
+		return err
+	}
+
+	mutationTimeBudget, err := MutationTimeBudgetFromEnv()
+	if err != nil {
+		return err
+	}
+
+	nativeSidecarSupported, err := kubesystem.SupportsNativeSidecars(kubeConfig)
+	if err != nil {
+		log.Info("failed to check if native sidecars are supported, assuming they are not", "error", err.Error())
+	}
+
+	policyReader, err := newInjectionPolicyReader(mgr)
+	if err != nil {
+		return err
+	}
+
 	otelMeter := otel.Meter(otelName)
 	requestCounter, err := otelMeter.Int64Counter("handledPodMutationRequests")
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	mgr.GetWebhookServer().Register("/inject", &webhook.Admission{Handler: &podMutatorWebhook{
+	podWebhook := &podMutatorWebhook{
 		apiReader:        apiReader,
+		policyReader:     policyReader,
 		webhookNamespace: webhookNamespace,
 		webhookImage:     webhookPodImage,
 		deployedViaOLM:   kubesystem.IsDeployedViaOlm(*webhookPod),
 		clusterID:        clusterID,
 		recorder:         eventRecorder,
+
+		nativeSidecarSupported: nativeSidecarSupported,
+		mutationTimeBudget:     mutationTimeBudget,
 		mutators: []dtwebhook.PodMutator{
 			oneagent_mutation.NewOneAgentPodMutator(
 				webhookPodImage,
@@ -85,6 +110,15 @@ func registerInjectEndpoint(mgr manager.Manager, webhookNamespace string, webhoo
 				kubeClient,
 				apiReader,
 				metaClient,
+				workloadKinds,
+			),
+			otel_mutation.NewOTelPodMutator(
+				webhookNamespace,
+				clusterID,
+				kubeClient,
+				apiReader,
+				metaClient,
+				workloadKinds,
 			),
 		},
 		decoder:    *admission.NewDecoder(mgr.GetScheme()),
@@ -92,7 +126,8 @@ func registerInjectEndpoint(mgr manager.Manager, webhookNamespace string, webhoo
 		otelMeter:  otel.Meter(otelName),
 
 		requestCounter: requestCounter,
-	}})
+	}
+	mgr.GetWebhookServer().Register("/inject", &webhook.Admission{Handler: podWebhook})
 	log.Info("registered /inject endpoint")
 	return nil
 }
@@ -40,6 +40,7 @@ func (webhook *podMutatorWebhook) createMutationRequestBase(ctx context.Context,
 		return nil, err
 	}
 	mutationRequest := dtwebhook.NewMutationRequest(ctx, *namespace, nil, pod, *dynakube)
+	mutationRequest.DryRun = request.DryRun != nil && *request.DryRun
 	return mutationRequest, nil
 }
 
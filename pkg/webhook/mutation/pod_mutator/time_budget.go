@@ -0,0 +1,85 @@
+package pod_mutator
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// MutationTimeBudgetEnv holds the duration a single pod mutation may take, e.g. "1500ms", "0" disables the time budget
+	MutationTimeBudgetEnv = "DT_MUTATION_TIME_BUDGET"
+
+	// defaultMutationTimeBudget stays below the timeoutSeconds of the MutatingWebhookConfiguration,
+	// so the webhook still answers before the api-server gives up on the request
+	defaultMutationTimeBudget = 1500 * time.Millisecond
+)
+
+// MutationTimeBudgetFromEnv returns the configured time budget of a pod mutation, or the default one if it isn't configured
+func MutationTimeBudgetFromEnv() (time.Duration, error) {
+	rawTimeBudget := os.Getenv(MutationTimeBudgetEnv)
+	if rawTimeBudget == "" {
+		return defaultMutationTimeBudget, nil
+	}
+
+	timeBudget, err := time.ParseDuration(rawTimeBudget)
+	if err != nil {
+		return 0, errors.WithMessagef(err, "invalid %s", MutationTimeBudgetEnv)
+	}
+	return timeBudget, nil
+}
+
+// handleWithinTimeBudget protects the pod creation from a slow api-server: if the mutation doesn't finish in time,
+// the pod is admitted without injection and marked, so the workload can be restarted once the api-server recovered.
+func (webhook *podMutatorWebhook) handleWithinTimeBudget(ctx context.Context, request admission.Request) admission.Response {
+	if webhook.mutationTimeBudget <= 0 {
+		return webhook.handle(ctx, request)
+	}
+
+	budgetCtx, cancel := context.WithTimeout(ctx, webhook.mutationTimeBudget)
+	defer cancel()
+
+	// the mutation decodes its own copy of the pod, so it can't interfere with the response once the budget is exceeded
+	responses := make(chan admission.Response, 1)
+	go func() {
+		responses <- webhook.handle(budgetCtx, request)
+	}()
+
+	select {
+	case response := <-responses:
+		return response
+	case <-budgetCtx.Done():
+		return webhook.timeBudgetExceededResponse(ctx, request)
+	}
+}
+
+func (webhook *podMutatorWebhook) timeBudgetExceededResponse(ctx context.Context, request admission.Request) admission.Response {
+	countInjection(ctx, injectionReasonTimeBudgetExceeded)
+
+	pod, err := getPodFromRequest(request, webhook.decoder)
+	if err != nil {
+		return silentErrorResponse(nil, err)
+	}
+
+	podName := kubeobjects.GetPodName(*pod)
+	log.Info("mutation exceeded its time budget, pod is admitted without injection",
+		"podName", podName, "namespace", request.Namespace, "timeBudget", webhook.mutationTimeBudget.String())
+
+	if kubeobjects.GetFieldBool(pod.Annotations, dtwebhook.AnnotationDynatraceInjected, false) ||
+		!kubeobjects.GetFieldBool(pod.Annotations, dtwebhook.AnnotationDynatraceInject, true) {
+		// an already injected pod keeps its injection, a pod without injection doesn't need to be restarted
+		return admission.Patched("")
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[dtwebhook.AnnotationOneAgentInjected] = "false"
+	pod.Annotations[dtwebhook.AnnotationOneAgentReason] = dtwebhook.TimeBudgetExceededReason
+	return createResponseForPod(ctx, pod, request)
+}
@@ -0,0 +1,97 @@
+package pod_mutator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestHandleWithinTimeBudget(t *testing.T) {
+	t.Run("pod is admitted without injection if the time budget is exceeded", func(t *testing.T) {
+		pod := getTestPod()
+		podWebhook := createTestWebhook([]dtwebhook.PodMutator{createSlowPodMutatorMock()}, []client.Object{getTestDynakube(), getTestNamespace(), pod})
+		podWebhook.mutationTimeBudget = 10 * time.Millisecond
+		injections := injectionsMetric.WithLabelValues(injectionReasonTimeBudgetExceeded)
+		injectionsBefore := testutil.ToFloat64(injections)
+
+		response := podWebhook.Handle(context.Background(), *createTestAdmissionRequest(pod))
+
+		assert.True(t, response.Allowed)
+		require.NotEmpty(t, response.Patches)
+		rawPatches, err := json.Marshal(response.Patches)
+		require.NoError(t, err)
+		assert.Contains(t, string(rawPatches), dtwebhook.TimeBudgetExceededReason)
+		assert.NotContains(t, string(rawPatches), "initContainers")
+		assert.Equal(t, injectionsBefore+1, testutil.ToFloat64(injections))
+	})
+	t.Run("already injected pod is not changed if the time budget is exceeded", func(t *testing.T) {
+		pod := getTestPod()
+		pod.Annotations = map[string]string{dtwebhook.AnnotationDynatraceInjected: "true"}
+		podWebhook := createTestWebhook([]dtwebhook.PodMutator{createSlowPodMutatorMock()}, []client.Object{getTestDynakube(), getTestNamespace(), pod})
+		podWebhook.mutationTimeBudget = 10 * time.Millisecond
+
+		response := podWebhook.Handle(context.Background(), *createTestAdmissionRequest(pod))
+
+		assert.True(t, response.Allowed)
+		assert.Empty(t, response.Patches)
+	})
+	t.Run("mutation within the time budget is returned", func(t *testing.T) {
+		pod := getTestPod()
+		podWebhook := createTestWebhook([]dtwebhook.PodMutator{createSimplePodMutatorMock()}, []client.Object{getTestDynakube(), getTestNamespace(), pod})
+		podWebhook.mutationTimeBudget = time.Minute
+
+		response := podWebhook.Handle(context.Background(), *createTestAdmissionRequest(pod))
+
+		assert.True(t, response.Allowed)
+		rawPatches, err := json.Marshal(response.Patches)
+		require.NoError(t, err)
+		assert.NotContains(t, string(rawPatches), dtwebhook.TimeBudgetExceededReason)
+		assert.Contains(t, string(rawPatches), dtwebhook.InstallContainerName)
+	})
+}
+
+func TestMutationTimeBudgetFromEnv(t *testing.T) {
+	t.Run("default time budget", func(t *testing.T) {
+		t.Setenv(MutationTimeBudgetEnv, "")
+
+		timeBudget, err := MutationTimeBudgetFromEnv()
+
+		require.NoError(t, err)
+		assert.Equal(t, defaultMutationTimeBudget, timeBudget)
+	})
+	t.Run("configured time budget", func(t *testing.T) {
+		t.Setenv(MutationTimeBudgetEnv, "500ms")
+
+		timeBudget, err := MutationTimeBudgetFromEnv()
+
+		require.NoError(t, err)
+		assert.Equal(t, 500*time.Millisecond, timeBudget)
+	})
+	t.Run("invalid time budget", func(t *testing.T) {
+		t.Setenv(MutationTimeBudgetEnv, "soon")
+
+		_, err := MutationTimeBudgetFromEnv()
+
+		require.Error(t, err)
+	})
+}
+
+// createSlowPodMutatorMock blocks the mutation until its context is done, like a mutator waiting for a slow api-server
+func createSlowPodMutatorMock() dtwebhook.PodMutator {
+	mutator := dtwebhook.PodMutatorMock{}
+	mutator.On("Enabled", mock.Anything).Return(true)
+	mutator.On("Injected", mock.Anything).Return(false)
+	mutator.On("Mutate", mock.Anything).Run(func(args mock.Arguments) {
+		<-args.Get(0).(*dtwebhook.MutationRequest).Context.Done()
+	}).Return(nil)
+	mutator.On("Reinvoke", mock.Anything).Return(false)
+	return &mutator
+}
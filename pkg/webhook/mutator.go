@@ -46,6 +46,8 @@ type MutationRequest struct {
 	*BaseRequest
 	Context          context.Context
 	InstallContainer *corev1.Container
+	// DryRun is set for previews and dry-run admission requests, mutators must not create or change any objects then
+	DryRun bool
 }
 
 // ReinvocationRequest contains all the information needed to reinvoke a pod
@@ -33,6 +33,18 @@ var validators = []validator{
 	conflictingOneAgentVolumeStorageSettings,
 	invalidSyntheticNodeType,
 	nameViolatesDNS1035,
+	unknownFeatureFlags,
+	malformedFeatureFlags,
+	invalidTokenSourcePath,
+	tokenSourceFileWithoutCSIDriver,
+	invalidOneAgentRollout,
+	invalidOneAgentNodeProfiles,
+	invalidUpdateWindow,
+	invalidMirrorRegistry,
+	invalidMirrorSyncWindow,
+	invalidMetadataEnrichmentRules,
+	openTelemetryWithoutAppInjection,
+	openTelemetryWithoutActiveGate,
 }
 
 var warnings = []validator{
@@ -47,6 +59,7 @@ var warnings = []validator{
 	ineffectiveReadOnlyHostFsFeatureFlag,
 	syntheticPreviewWarning,
 	deprecatedFeatureFlag,
+	tokenSourceFileWithoutDataIngestToken,
 }
 
 func SetLogger(logger logr.Logger) {
@@ -0,0 +1,53 @@
+package dynakube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/pkg/errors"
+)
+
+const (
+	errorUnknownFeatureFlags = `The DynaKube's specification contains unknown feature-flags: %s.
+Make sure there is no typo in the annotation names, see the documentation for the list of supported feature-flags.
+`
+
+	errorMalformedFeatureFlags = `The DynaKube's specification contains feature-flags with malformed values: %s.
+`
+)
+
+func unknownFeatureFlags(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	var unknownFlags []string
+	for annotation, value := range dynatracev1beta1.FlagsWithPrefix(dynakube, dynatracev1beta1.AnnotationFeaturePrefix) {
+		if errors.Is(dynatracev1beta1.ValidateFeatureFlag(annotation, value), dynatracev1beta1.ErrUnknownFeatureFlag) {
+			unknownFlags = append(unknownFlags, fmt.Sprintf("'%s'", annotation))
+		}
+	}
+
+	if len(unknownFlags) == 0 {
+		return ""
+	}
+	sort.Strings(unknownFlags)
+	log.Info("requested dynakube has unknown feature-flags", "name", dynakube.Name, "namespace", dynakube.Namespace, "flags", unknownFlags)
+	return fmt.Sprintf(errorUnknownFeatureFlags, strings.Join(unknownFlags, ", "))
+}
+
+func malformedFeatureFlags(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	var malformedFlags []string
+	for annotation, value := range dynatracev1beta1.FlagsWithPrefix(dynakube, dynatracev1beta1.AnnotationFeaturePrefix) {
+		err := dynatracev1beta1.ValidateFeatureFlag(annotation, value)
+		if err != nil && !errors.Is(err, dynatracev1beta1.ErrUnknownFeatureFlag) {
+			malformedFlags = append(malformedFlags, fmt.Sprintf("'%s' (%s)", annotation, err.Error()))
+		}
+	}
+
+	if len(malformedFlags) == 0 {
+		return ""
+	}
+	sort.Strings(malformedFlags)
+	log.Info("requested dynakube has malformed feature-flags", "name", dynakube.Name, "namespace", dynakube.Namespace, "flags", malformedFlags)
+	return fmt.Sprintf(errorMalformedFeatureFlags, strings.Join(malformedFlags, ", "))
+}
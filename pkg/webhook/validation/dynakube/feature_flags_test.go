@@ -0,0 +1,78 @@
+package dynakube
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnknownFeatureFlags(t *testing.T) {
+	t.Run(`known feature-flags are allowed`, func(t *testing.T) {
+		dynakubeMeta := defaultDynakubeObjectMeta
+		dynakubeMeta.Annotations = map[string]string{
+			dynatracev1beta1.AnnotationFeatureOneAgentMaxUnavailable: "2",
+			dynatracev1beta1.AnnotationFeatureIgnoredNamespaces:      `["^kube-.*"]`,
+			dynatracev1beta1.AnnotationInjectionFailurePolicy:        "fail",
+			"other.annotation/unrelated":                             "value",
+		}
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: dynakubeMeta,
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testApiUrl,
+			},
+		}
+
+		assert.Empty(t, unknownFeatureFlags(context.Background(), nil, dynakube))
+		assert.Empty(t, malformedFeatureFlags(context.Background(), nil, dynakube))
+	})
+	t.Run(`typo in feature-flag is denied`, func(t *testing.T) {
+		const typo = dynatracev1beta1.AnnotationFeaturePrefix + "oneagent-max-unavailble"
+
+		dynakubeMeta := defaultDynakubeObjectMeta
+		dynakubeMeta.Annotations = map[string]string{
+			typo: "2",
+		}
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: dynakubeMeta,
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testApiUrl,
+			},
+		}
+
+		assertDeniedResponse(t, []string{fmt.Sprintf(errorUnknownFeatureFlags, "'"+typo+"'")}, dynakube)
+	})
+}
+
+func TestMalformedFeatureFlags(t *testing.T) {
+	testMalformed := func(t *testing.T, annotation string, value string) {
+		dynakubeMeta := defaultDynakubeObjectMeta
+		dynakubeMeta.Annotations = map[string]string{
+			annotation: value,
+		}
+		dynakube := &dynatracev1beta1.DynaKube{
+			ObjectMeta: dynakubeMeta,
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testApiUrl,
+			},
+		}
+
+		assertDeniedResponse(t, []string{annotation}, dynakube)
+		assert.Empty(t, unknownFeatureFlags(context.Background(), nil, dynakube))
+	}
+
+	t.Run(`bool`, func(t *testing.T) {
+		testMalformed(t, dynatracev1beta1.AnnotationFeatureAutomaticInjection, "yes")
+	})
+	t.Run(`int`, func(t *testing.T) {
+		testMalformed(t, dynatracev1beta1.AnnotationFeatureOneAgentMaxUnavailable, "0")
+	})
+	t.Run(`json list`, func(t *testing.T) {
+		testMalformed(t, dynatracev1beta1.AnnotationFeatureIgnoredNamespaces, "kube-system")
+	})
+	t.Run(`enum`, func(t *testing.T) {
+		testMalformed(t, dynatracev1beta1.AnnotationInjectionFailurePolicy, "ignore")
+	})
+}
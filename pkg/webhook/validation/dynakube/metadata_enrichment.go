@@ -0,0 +1,41 @@
+package dynakube
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	errorInvalidMetadataEnrichmentKey = `The DynaKube's specification has an invalid key %q in the metadataEnrichment rules: %s.
+Make sure the keys are valid label or annotation keys, e.g. team or app.kubernetes.io/name.`
+
+	errorInvalidMetadataEnrichmentPrefix = `The DynaKube's specification has an invalid prefix %q in the metadataEnrichment rules.
+Make sure the prefix only consists of the characters of label or annotation keys (alphanumeric characters, '-', '_', '.' or '/'), e.g. k8s.pod.label.`
+)
+
+// metadataEnrichmentPrefixRegex allows the characters of qualified names, the prefix is prepended to the keys of the rule
+var metadataEnrichmentPrefixRegex = regexp.MustCompile(`^[A-Za-z0-9_./-]*$`)
+
+func invalidMetadataEnrichmentRules(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	if dynakube.Spec.MetadataEnrichment == nil {
+		return ""
+	}
+	for _, rule := range dynakube.Spec.MetadataEnrichment.Rules {
+		if !metadataEnrichmentPrefixRegex.MatchString(rule.Prefix) {
+			log.Info("requested dynakube has an invalid metadata enrichment prefix", "prefix", rule.Prefix)
+			return fmt.Sprintf(errorInvalidMetadataEnrichmentPrefix, rule.Prefix)
+		}
+		for _, key := range rule.Keys {
+			if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+				log.Info("requested dynakube has an invalid metadata enrichment key", "key", key)
+				return fmt.Sprintf(errorInvalidMetadataEnrichmentKey, key, strings.Join(errs, ", "))
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package dynakube
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestInvalidMetadataEnrichmentRules(t *testing.T) {
+	t.Run(`valid rules`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, dynakubeWithEnrichmentRules(
+			dynatracev1beta1.EnrichmentRule{Type: dynatracev1beta1.EnrichmentNamespaceLabelRule, Keys: []string{"team", "cost-center"}},
+			dynatracev1beta1.EnrichmentRule{Type: dynatracev1beta1.EnrichmentPodAnnotationRule, Keys: []string{"app.kubernetes.io/name"}, Prefix: "app."},
+		))
+	})
+	t.Run(`invalid key`, func(t *testing.T) {
+		key := "cost center"
+		assertDeniedResponse(t,
+			[]string{fmt.Sprintf(errorInvalidMetadataEnrichmentKey, key, strings.Join(validation.IsQualifiedName(key), ", "))},
+			dynakubeWithEnrichmentRules(dynatracev1beta1.EnrichmentRule{Type: dynatracev1beta1.EnrichmentPodLabelRule, Keys: []string{key}}))
+	})
+	t.Run(`invalid prefix`, func(t *testing.T) {
+		for _, prefix := range []string{"k8s pod.", "team=", "app:", "ünicode."} {
+			assertDeniedResponse(t,
+				[]string{fmt.Sprintf(errorInvalidMetadataEnrichmentPrefix, prefix)},
+				dynakubeWithEnrichmentRules(dynatracev1beta1.EnrichmentRule{Type: dynatracev1beta1.EnrichmentPodLabelRule, Keys: []string{"team"}, Prefix: prefix}))
+		}
+	})
+	t.Run(`prefix with the characters of keys`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, dynakubeWithEnrichmentRules(
+			dynatracev1beta1.EnrichmentRule{Type: dynatracev1beta1.EnrichmentPodLabelRule, Keys: []string{"team"}, Prefix: "Custom_k8s-pod/label."},
+		))
+	})
+}
+
+func dynakubeWithEnrichmentRules(rules ...dynatracev1beta1.EnrichmentRule) *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: defaultDynakubeObjectMeta,
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: testApiUrl,
+			MetadataEnrichment: &dynatracev1beta1.MetadataEnrichmentSpec{
+				Rules: rules,
+			},
+		},
+	}
+}
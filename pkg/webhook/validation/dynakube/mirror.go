@@ -0,0 +1,36 @@
+package dynakube
+
+import (
+	"context"
+	"fmt"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+const (
+	errorInvalidMirrorRegistry = `The DynaKube's specification has an invalid mirror registry: %s.
+Make sure the registry is a repository reference without tag or digest, e.g. registry.example.com:5000/dynatrace.`
+
+	errorInvalidMirrorSyncWindow = `The DynaKube's specification has an invalid mirror syncWindow: %s.
+Make sure the schedule is a cron expression with 5 fields (minute hour day-of-month month day-of-week), the duration is positive and at most 168h and the timeZone is a valid IANA time zone.`
+)
+
+func invalidMirrorRegistry(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	if dynakube.Spec.Mirror == nil {
+		return ""
+	}
+	if _, err := name.NewRepository(dynakube.Spec.Mirror.Registry); err != nil {
+		log.Info("requested dynakube has an invalid mirror registry", "registry", dynakube.Spec.Mirror.Registry, "error", err.Error())
+		return fmt.Sprintf(errorInvalidMirrorRegistry, err.Error())
+	}
+	return ""
+}
+
+func invalidMirrorSyncWindow(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	if _, err := dynakube.MirrorSyncWindow(); err != nil {
+		log.Info("requested dynakube has an invalid mirror sync window", "error", err.Error())
+		return fmt.Sprintf(errorInvalidMirrorSyncWindow, err.Error())
+	}
+	return ""
+}
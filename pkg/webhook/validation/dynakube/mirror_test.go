@@ -0,0 +1,52 @@
+package dynakube
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInvalidMirror(t *testing.T) {
+	t.Run(`valid mirror`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, dynakubeWithMirror("registry.example.com:5000/dynatrace", nil))
+	})
+	t.Run(`valid mirror with sync window`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, dynakubeWithMirror("registry.example.com:5000/dynatrace", &dynatracev1beta1.UpdateWindowSpec{
+			Schedule: "0 2 * * *",
+			Duration: metav1.Duration{Duration: time.Hour},
+		}))
+	})
+	t.Run(`invalid registry`, func(t *testing.T) {
+		registry := "registry.example.com:5000/Dynatrace:latest"
+		_, err := name.NewRepository(registry)
+		require.Error(t, err)
+		assertDeniedResponse(t, []string{fmt.Sprintf(errorInvalidMirrorRegistry, err.Error())}, dynakubeWithMirror(registry, nil))
+	})
+	t.Run(`invalid sync window`, func(t *testing.T) {
+		dynakube := dynakubeWithMirror("registry.example.com:5000/dynatrace", &dynatracev1beta1.UpdateWindowSpec{
+			Schedule: "0 2 * *",
+			Duration: metav1.Duration{Duration: time.Hour},
+		})
+		_, err := dynakube.MirrorSyncWindow()
+		require.Error(t, err)
+		assertDeniedResponse(t, []string{fmt.Sprintf(errorInvalidMirrorSyncWindow, err.Error())}, dynakube)
+	})
+}
+
+func dynakubeWithMirror(registry string, syncWindow *dynatracev1beta1.UpdateWindowSpec) *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: defaultDynakubeObjectMeta,
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: testApiUrl,
+			Mirror: &dynatracev1beta1.MirrorSpec{
+				Registry:   registry,
+				SyncWindow: syncWindow,
+			},
+		},
+	}
+}
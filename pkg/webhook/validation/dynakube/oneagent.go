@@ -9,6 +9,9 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 )
 
+// maxOneAgentNodeProfileSelectorTerms limits the node affinity of the OneAgent DaemonSet without a node profile
+const maxOneAgentNodeProfileSelectorTerms = 64
+
 const (
 	errorConflictingOneagentMode = `The DynaKube's specification tries to use multiple oneagent modes at the same time, which is not supported.
 `
@@ -19,6 +22,16 @@ The conflicting Dynakube: %s
 `
 	errorVolumeStorageReadOnlyModeConflict = `The DynaKube's specification specifies a read-only host file system and OneAgent has volume storage enabled.`
 
+	errorInvalidOneAgentRolloutCanary = `The DynaKube's specification has an invalid oneAgent.rollout.canary, exactly one of nodeSelector or percentage (1-100) must be set.`
+
+	errorOneAgentRolloutWithoutDaemonSet = `The DynaKube's specification configures a oneAgent.rollout, but the OneAgent mode doesn't deploy a OneAgent DaemonSet.`
+
+	errorOneAgentNodeProfilesWithoutDaemonSet = `The DynaKube's specification configures oneAgent.nodeProfiles, but the OneAgent mode doesn't deploy a OneAgent DaemonSet.`
+
+	errorDuplicateOneAgentNodeProfile = `The DynaKube's specification has multiple oneAgent.nodeProfiles named %s, the names of the node profiles must be unique.`
+
+	errorTooManyOneAgentNodeProfileLabels = `The DynaKube's specification has too many labels in the node selectors of its oneAgent.nodeProfiles, at most %d combinations of one label per node profile are supported.`
+
 	warningIneffectiveFeatureFlag = `Feature flag %s has no effect in classic full stack mode.`
 )
 
@@ -84,6 +97,55 @@ func imageFieldSetWithoutCSIFlag(_ context.Context, dv *dynakubeValidator, dynak
 	return ""
 }
 
+func invalidOneAgentRollout(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	rollout := dynakube.Spec.OneAgent.Rollout
+	if rollout == nil {
+		return ""
+	}
+	if !dynakube.NeedsOneAgent() {
+		return errorOneAgentRolloutWithoutDaemonSet
+	}
+
+	hasNodeSelector := len(rollout.Canary.NodeSelector) > 0
+	hasPercentage := rollout.Canary.Percentage != 0
+	if hasNodeSelector == hasPercentage || rollout.Canary.Percentage < 0 || rollout.Canary.Percentage > 100 {
+		log.Info("requested dynakube has an invalid oneagent rollout canary", "name", dynakube.Name, "namespace", dynakube.Namespace)
+		return errorInvalidOneAgentRolloutCanary
+	}
+	return ""
+}
+
+func invalidOneAgentNodeProfiles(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	profiles := dynakube.Spec.OneAgent.NodeProfiles
+	if len(profiles) == 0 {
+		return ""
+	}
+	if !dynakube.NeedsOneAgent() {
+		return errorOneAgentNodeProfilesWithoutDaemonSet
+	}
+
+	names := make(map[string]bool, len(profiles))
+	for _, profile := range profiles {
+		if names[profile.Name] {
+			log.Info("requested dynakube has duplicate oneagent node profiles", "name", dynakube.Name, "namespace", dynakube.Namespace, "profile", profile.Name)
+			return fmt.Sprintf(errorDuplicateOneAgentNodeProfile, profile.Name)
+		}
+		names[profile.Name] = true
+	}
+
+	// the DaemonSet without a node profile excludes the nodes of every profile, which takes one node selector term per combination of
+	// one label of each profile, so the size of its node affinity is the product of the label counts
+	selectorTerms := 1
+	for _, profile := range profiles {
+		selectorTerms *= len(profile.NodeSelector)
+		if selectorTerms > maxOneAgentNodeProfileSelectorTerms {
+			log.Info("requested dynakube has too many oneagent node profile labels", "name", dynakube.Name, "namespace", dynakube.Namespace)
+			return fmt.Sprintf(errorTooManyOneAgentNodeProfileLabels, maxOneAgentNodeProfileSelectorTerms)
+		}
+	}
+	return ""
+}
+
 func hasConflictingMatchLabels(labelMap, otherLabelMap map[string]string) bool {
 	if labelMap == nil || otherLabelMap == nil {
 		return true
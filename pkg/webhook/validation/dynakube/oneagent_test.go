@@ -621,3 +621,89 @@ func createClassicFullstackDynaKube(featureFlag string, featureFlagValue bool, o
 		},
 	}
 }
+
+func TestInvalidOneAgentRollout(t *testing.T) {
+	t.Run(`canary by percentage`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, dynakubeWithOneAgentRollout(dynatracev1beta1.OneAgentCanarySpec{Percentage: 10}))
+	})
+	t.Run(`canary by node selector`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, dynakubeWithOneAgentRollout(dynatracev1beta1.OneAgentCanarySpec{NodeSelector: map[string]string{"pool": "canary"}}))
+	})
+	t.Run(`canary without nodes`, func(t *testing.T) {
+		assertDeniedResponse(t, []string{errorInvalidOneAgentRolloutCanary}, dynakubeWithOneAgentRollout(dynatracev1beta1.OneAgentCanarySpec{}))
+	})
+	t.Run(`canary by node selector and percentage`, func(t *testing.T) {
+		assertDeniedResponse(t, []string{errorInvalidOneAgentRolloutCanary}, dynakubeWithOneAgentRollout(dynatracev1beta1.OneAgentCanarySpec{
+			NodeSelector: map[string]string{"pool": "canary"},
+			Percentage:   10,
+		}))
+	})
+	t.Run(`canary percentage out of range`, func(t *testing.T) {
+		assertDeniedResponse(t, []string{errorInvalidOneAgentRolloutCanary}, dynakubeWithOneAgentRollout(dynatracev1beta1.OneAgentCanarySpec{Percentage: 101}))
+	})
+	t.Run(`rollout without OneAgent DaemonSet`, func(t *testing.T) {
+		dynakube := dynakubeWithOneAgentRollout(dynatracev1beta1.OneAgentCanarySpec{Percentage: 10})
+		dynakube.Spec.OneAgent.ClassicFullStack = nil
+		assertDeniedResponse(t, []string{errorOneAgentRolloutWithoutDaemonSet}, dynakube)
+	})
+}
+
+func dynakubeWithOneAgentRollout(canary dynatracev1beta1.OneAgentCanarySpec) *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: defaultDynakubeObjectMeta,
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: testApiUrl,
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ClassicFullStack: &dynatracev1beta1.HostInjectSpec{},
+				Rollout: &dynatracev1beta1.OneAgentRolloutSpec{
+					Canary: canary,
+				},
+			},
+		},
+	}
+}
+
+func TestInvalidOneAgentNodeProfiles(t *testing.T) {
+	t.Run(`node profiles`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, dynakubeWithOneAgentNodeProfiles("gpu", "spot"))
+	})
+	t.Run(`duplicate node profile names`, func(t *testing.T) {
+		assertDeniedResponse(t, []string{fmt.Sprintf(errorDuplicateOneAgentNodeProfile, "gpu")}, dynakubeWithOneAgentNodeProfiles("gpu", "gpu"))
+	})
+	t.Run(`node profiles with too many labels`, func(t *testing.T) {
+		dynakube := dynakubeWithOneAgentNodeProfiles("a", "b", "c")
+		for i := range dynakube.Spec.OneAgent.NodeProfiles {
+			dynakube.Spec.OneAgent.NodeProfiles[i].NodeSelector = map[string]string{"pool": "p", "zone": "z", "lifecycle": "l", "arch": "a"}
+		}
+		assertAllowedResponseWithoutWarnings(t, dynakube)
+
+		dynakube.Spec.OneAgent.NodeProfiles[0].NodeSelector["os"] = "o"
+		assertDeniedResponse(t, []string{fmt.Sprintf(errorTooManyOneAgentNodeProfileLabels, maxOneAgentNodeProfileSelectorTerms)}, dynakube)
+	})
+	t.Run(`node profiles without OneAgent DaemonSet`, func(t *testing.T) {
+		dynakube := dynakubeWithOneAgentNodeProfiles("gpu")
+		dynakube.Spec.OneAgent.ClassicFullStack = nil
+		assertDeniedResponse(t, []string{errorOneAgentNodeProfilesWithoutDaemonSet}, dynakube)
+	})
+}
+
+func dynakubeWithOneAgentNodeProfiles(names ...string) *dynatracev1beta1.DynaKube {
+	profiles := make([]dynatracev1beta1.OneAgentNodeProfile, 0, len(names))
+	for _, name := range names {
+		profiles = append(profiles, dynatracev1beta1.OneAgentNodeProfile{
+			Name:         name,
+			NodeSelector: map[string]string{"pool": name},
+		})
+	}
+
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: defaultDynakubeObjectMeta,
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: testApiUrl,
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ClassicFullStack: &dynatracev1beta1.HostInjectSpec{},
+				NodeProfiles:     profiles,
+			},
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package dynakube
+
+import (
+	"context"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+)
+
+const (
+	errorOpenTelemetryWithoutAppInjection = `The DynaKube's specification enables openTelemetry, but no pods are injected.
+Use applicationMonitoring or cloudNativeFullStack to inject the OpenTelemetry exporter configuration into pods.`
+
+	errorOpenTelemetryWithoutActiveGate = `The DynaKube's specification enables openTelemetry.useActiveGate, but doesn't deploy an ActiveGate.
+Add capabilities to the activeGate section, or disable useActiveGate to export to the tenant directly.`
+)
+
+func openTelemetryWithoutAppInjection(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	if dynakube.Spec.OpenTelemetry != nil && !dynakube.NeedAppInjection() {
+		log.Info("requested dynakube enables openTelemetry without app injection")
+		return errorOpenTelemetryWithoutAppInjection
+	}
+	return ""
+}
+
+func openTelemetryWithoutActiveGate(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	if dynakube.Spec.OpenTelemetry != nil && dynakube.Spec.OpenTelemetry.UseActiveGate && !dynakube.ActiveGateMode() {
+		log.Info("requested dynakube exports OpenTelemetry through an ActiveGate, but doesn't deploy one")
+		return errorOpenTelemetryWithoutActiveGate
+	}
+	return ""
+}
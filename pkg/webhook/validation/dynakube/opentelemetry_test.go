@@ -0,0 +1,43 @@
+package dynakube
+
+import (
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+)
+
+func TestOpenTelemetry(t *testing.T) {
+	t.Run(`export to the tenant`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, dynakubeWithOpenTelemetry(&dynatracev1beta1.OpenTelemetrySpec{}, nil))
+	})
+	t.Run(`export through the ActiveGate`, func(t *testing.T) {
+		assertAllowedResponse(t, dynakubeWithOpenTelemetry(&dynatracev1beta1.OpenTelemetrySpec{UseActiveGate: true}, []dynatracev1beta1.CapabilityDisplayName{
+			dynatracev1beta1.RoutingCapability.DisplayName,
+		}))
+	})
+	t.Run(`export through the ActiveGate without an ActiveGate`, func(t *testing.T) {
+		assertDeniedResponse(t, []string{errorOpenTelemetryWithoutActiveGate},
+			dynakubeWithOpenTelemetry(&dynatracev1beta1.OpenTelemetrySpec{UseActiveGate: true}, nil))
+	})
+	t.Run(`openTelemetry without app injection`, func(t *testing.T) {
+		dynakube := dynakubeWithOpenTelemetry(&dynatracev1beta1.OpenTelemetrySpec{}, nil)
+		dynakube.Spec.OneAgent = dynatracev1beta1.OneAgentSpec{}
+		assertDeniedResponse(t, []string{errorOpenTelemetryWithoutAppInjection}, dynakube)
+	})
+}
+
+func dynakubeWithOpenTelemetry(openTelemetry *dynatracev1beta1.OpenTelemetrySpec, capabilities []dynatracev1beta1.CapabilityDisplayName) *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: defaultDynakubeObjectMeta,
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: testApiUrl,
+			OneAgent: dynatracev1beta1.OneAgentSpec{
+				ApplicationMonitoring: &dynatracev1beta1.ApplicationMonitoringSpec{},
+			},
+			ActiveGate: dynatracev1beta1.ActiveGateSpec{
+				Capabilities: capabilities,
+			},
+			OpenTelemetry: openTelemetry,
+		},
+	}
+}
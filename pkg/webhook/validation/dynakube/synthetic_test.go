@@ -38,6 +38,14 @@ func TestSyntheticInvalidSettings(t *testing.T) {
 	delete(
 		dynaKube.ObjectMeta.Annotations,
 		dynatracev1beta1.AnnotationFeatureSyntheticNodeType)
+	t.Run("invalid replicas", func(t *testing.T) {
+		assertDeniedResponse(
+			t,
+			[]string{dynatracev1beta1.AnnotationFeatureSyntheticReplicas},
+			&dynaKube)
+	})
+
+	dynaKube.ObjectMeta.Annotations[dynatracev1beta1.AnnotationFeatureSyntheticReplicas] = "2"
 	t.Run("valid replicas", func(t *testing.T) {
 		assertAllowedResponseWithWarnings(t, 2, &dynaKube)
 	})
@@ -0,0 +1,49 @@
+package dynakube
+
+import (
+	"context"
+	"fmt"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+)
+
+const (
+	errorInvalidTokenSourcePath = `The DynaKube's specification has an invalid tokenSource.file.path set. The path must be relative to ` + token.FileTokenSourceRootDir + ` and must not leave it, got '%s'.`
+
+	errorTokenSourceFileWithoutCSIDriver = `The DynaKube's specification reads the tokens from files, but injects pods without the CSI driver.
+The init containers would need the tokens to download the code modules, they aren't copied into the monitored namespaces. Use cloudNativeFullStack, or enable useCSIDriver for applicationMonitoring.`
+
+	warningTokenSourceFileWithoutDataIngestToken = `The DynaKube's specification reads the tokens from files, the dataIngestToken isn't provided to the injected pods.
+Metrics and OpenTelemetry data sent by the pods have to be authenticated by other means.`
+)
+
+func invalidTokenSourcePath(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	if dynakube.Spec.TokenSource == nil || dynakube.Spec.TokenSource.File == nil {
+		return ""
+	}
+	if err := token.ValidateFileTokenSourcePath(dynakube.Spec.TokenSource.File.Path); err != nil {
+		log.Info("requested dynakube has an invalid token source path", "path", dynakube.Spec.TokenSource.File.Path)
+		return fmt.Sprintf(errorInvalidTokenSourcePath, dynakube.Spec.TokenSource.File.Path)
+	}
+	return ""
+}
+
+func tokenSourceFileWithoutCSIDriver(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	if _, isFileTokenSource := token.FileTokenSourceDirectory(dynakube); isFileTokenSource && dynakube.NeedAppInjection() && !dynakube.NeedsCSIDriver() {
+		log.Info("requested dynakube reads the tokens from files, but injects pods without the csi driver")
+		return errorTokenSourceFileWithoutCSIDriver
+	}
+	return ""
+}
+
+func tokenSourceFileWithoutDataIngestToken(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	_, isFileTokenSource := token.FileTokenSourceDirectory(dynakube)
+	if !isFileTokenSource || !dynakube.NeedAppInjection() {
+		return ""
+	}
+	if !dynakube.FeatureDisableMetadataEnrichment() || dynakube.Spec.OpenTelemetry != nil {
+		return warningTokenSourceFileWithoutDataIngestToken
+	}
+	return ""
+}
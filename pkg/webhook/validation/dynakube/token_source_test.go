@@ -0,0 +1,65 @@
+package dynakube
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvalidTokenSourcePath(t *testing.T) {
+	t.Run(`valid token source path`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, dynakubeWithTokenSourcePath("dynakube"))
+	})
+	t.Run(`empty token source path`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, dynakubeWithTokenSourcePath(""))
+	})
+	t.Run(`absolute token source path`, func(t *testing.T) {
+		assertDeniedResponse(t,
+			[]string{fmt.Sprintf(errorInvalidTokenSourcePath, "/var/run/secrets/kubernetes.io/serviceaccount")},
+			dynakubeWithTokenSourcePath("/var/run/secrets/kubernetes.io/serviceaccount"))
+	})
+	t.Run(`token source path leaving root directory`, func(t *testing.T) {
+		assertDeniedResponse(t,
+			[]string{fmt.Sprintf(errorInvalidTokenSourcePath, "../../secrets")},
+			dynakubeWithTokenSourcePath("../../secrets"))
+	})
+}
+
+func TestTokenSourceFileWithInjection(t *testing.T) {
+	t.Run(`application monitoring without csi driver`, func(t *testing.T) {
+		dynakube := dynakubeWithTokenSourcePath("")
+		dynakube.Spec.OneAgent.ApplicationMonitoring = &dynatracev1beta1.ApplicationMonitoringSpec{}
+
+		assertDeniedResponse(t, []string{errorTokenSourceFileWithoutCSIDriver}, dynakube)
+	})
+	t.Run(`cloud native fullstack warns about the data ingest token`, func(t *testing.T) {
+		dynakube := dynakubeWithTokenSourcePath("")
+		dynakube.Spec.OneAgent.CloudNativeFullStack = &dynatracev1beta1.CloudNativeFullStackSpec{}
+
+		assertAllowedResponseWithWarnings(t, 1, dynakube, &defaultCSIDaemonSet)
+	})
+	t.Run(`cloud native fullstack without metadata enrichment`, func(t *testing.T) {
+		dynakube := dynakubeWithTokenSourcePath("")
+		dynakube.Annotations = map[string]string{dynatracev1beta1.AnnotationFeatureMetadataEnrichment: "false"}
+		dynakube.Spec.OneAgent.CloudNativeFullStack = &dynatracev1beta1.CloudNativeFullStackSpec{}
+
+		assert.Empty(t, tokenSourceFileWithoutDataIngestToken(context.Background(), nil, dynakube))
+	})
+}
+
+func dynakubeWithTokenSourcePath(path string) *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: defaultDynakubeObjectMeta,
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: testApiUrl,
+			TokenSource: &dynatracev1beta1.TokenSourceSpec{
+				File: &dynatracev1beta1.FileTokenSource{
+					Path: path,
+				},
+			},
+		},
+	}
+}
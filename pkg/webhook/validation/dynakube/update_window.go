@@ -0,0 +1,21 @@
+package dynakube
+
+import (
+	"context"
+	"fmt"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+)
+
+const (
+	errorInvalidUpdateWindow = `The DynaKube's specification has an invalid updateWindow: %s.
+Make sure the schedule is a cron expression with 5 fields (minute hour day-of-month month day-of-week), the duration is positive and at most 168h and the timeZone is a valid IANA time zone.`
+)
+
+func invalidUpdateWindow(_ context.Context, _ *dynakubeValidator, dynakube *dynatracev1beta1.DynaKube) string {
+	if _, err := dynakube.UpdateWindow(); err != nil {
+		log.Info("requested dynakube has an invalid update window", "error", err.Error())
+		return fmt.Sprintf(errorInvalidUpdateWindow, err.Error())
+	}
+	return ""
+}
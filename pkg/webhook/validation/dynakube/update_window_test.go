@@ -0,0 +1,54 @@
+package dynakube
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	dynatracev1beta1 "github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta1/dynakube"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInvalidUpdateWindow(t *testing.T) {
+	t.Run(`valid update window`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, dynakubeWithUpdateWindow("0 22 * * mon-fri", 4*time.Hour, "Europe/Vienna"))
+	})
+	t.Run(`no update window`, func(t *testing.T) {
+		assertAllowedResponseWithoutWarnings(t, &dynatracev1beta1.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynatracev1beta1.DynaKubeSpec{
+				APIURL: testApiUrl,
+			},
+		})
+	})
+	t.Run(`invalid schedule`, func(t *testing.T) {
+		assertDeniedUpdateWindow(t, dynakubeWithUpdateWindow("0 22 * *", 4*time.Hour, ""))
+	})
+	t.Run(`invalid duration`, func(t *testing.T) {
+		assertDeniedUpdateWindow(t, dynakubeWithUpdateWindow("0 22 * * *", 0, ""))
+	})
+	t.Run(`invalid time zone`, func(t *testing.T) {
+		assertDeniedUpdateWindow(t, dynakubeWithUpdateWindow("0 22 * * *", 4*time.Hour, "Vienna"))
+	})
+}
+
+func assertDeniedUpdateWindow(t *testing.T, dynakube *dynatracev1beta1.DynaKube) {
+	_, err := dynakube.UpdateWindow()
+	require.Error(t, err)
+	assertDeniedResponse(t, []string{fmt.Sprintf(errorInvalidUpdateWindow, err.Error())}, dynakube)
+}
+
+func dynakubeWithUpdateWindow(schedule string, duration time.Duration, timeZone string) *dynatracev1beta1.DynaKube {
+	return &dynatracev1beta1.DynaKube{
+		ObjectMeta: defaultDynakubeObjectMeta,
+		Spec: dynatracev1beta1.DynaKubeSpec{
+			APIURL: testApiUrl,
+			UpdateWindow: &dynatracev1beta1.UpdateWindowSpec{
+				Schedule: schedule,
+				Duration: metav1.Duration{Duration: duration},
+				TimeZone: timeZone,
+			},
+		},
+	}
+}
@@ -13,4 +13,5 @@ type validator func(ctx context.Context, dv *edgeconnectValidator, edgeConnect *
 
 var validators = []validator{
 	IsInvalidApiServer,
+	invalidUpdateWindow,
 }
@@ -0,0 +1,22 @@
+package edgeconnect
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha1/edgeconnect"
+)
+
+const (
+	errorInvalidUpdateWindow = `The EdgeConnect's specification has an invalid updateWindow: %s.
+	Make sure the schedule is a cron expression with 5 fields (minute hour day-of-month month day-of-week), the duration is positive and at most 168h and the timeZone is a valid IANA time zone.
+	`
+)
+
+func invalidUpdateWindow(_ context.Context, _ *edgeconnectValidator, edgeConnect *edgeconnect.EdgeConnect) string {
+	if _, err := edgeConnect.UpdateWindow(); err != nil {
+		log.Info("requested edgeconnect has an invalid update window", "error", err.Error())
+		return fmt.Sprintf(errorInvalidUpdateWindow, err.Error())
+	}
+	return ""
+}
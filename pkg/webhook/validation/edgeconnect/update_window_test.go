@@ -0,0 +1,42 @@
+package edgeconnect
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha1/edgeconnect"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateWindow(t *testing.T) {
+	t.Run(`valid update window`, func(t *testing.T) {
+		assertAllowedResponse(t, edgeConnectWithUpdateWindow("0 22 * * sat-sun", 8*time.Hour, "America/New_York"))
+	})
+	t.Run(`invalid update window`, func(t *testing.T) {
+		edgeConnect := edgeConnectWithUpdateWindow("0 22 * * *", 8*time.Hour, "Mars/Olympus_Mons")
+		_, err := edgeConnect.UpdateWindow()
+		require.Error(t, err)
+
+		assertDeniedResponse(t, []string{fmt.Sprintf(errorInvalidUpdateWindow, err.Error())}, edgeConnect)
+	})
+}
+
+func edgeConnectWithUpdateWindow(schedule string, duration time.Duration, timeZone string) *edgeconnect.EdgeConnect {
+	return &edgeconnect.EdgeConnect{
+		Spec: edgeconnect.EdgeConnectSpec{
+			ApiServer: "tenantid" + allowedSuffix[0],
+			OAuth: edgeconnect.OAuthSpec{
+				ClientSecret: "secret",
+				Endpoint:     "endpoint",
+				Resource:     "resource",
+			},
+			UpdateWindow: &edgeconnect.UpdateWindowSpec{
+				Schedule: schedule,
+				Duration: metav1.Duration{Duration: duration},
+				TimeZone: timeZone,
+			},
+		},
+	}
+}